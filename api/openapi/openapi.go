@@ -0,0 +1,28 @@
+// Package openapi содержит спецификацию OpenAPI 3 REST API сервиса заказов
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Spec исходный текст спецификации (YAML)
+//
+//go:embed openapi.yaml
+var Spec []byte
+
+// Load разбирает и проверяет встроенную спецификацию
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse openapi spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+	return doc, nil
+}
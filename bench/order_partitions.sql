@@ -0,0 +1,133 @@
+-- bench/order_partitions.sql
+--
+-- Сравнение запросов к секционированным orders/order_items и к тем же данным в обычных таблицах.
+-- Данные генерируются за 24 месяца внутри транзакции и откатываются в конце; рабочие заказы
+-- не меняются. Триггеры и внешние ключи на время генерации отключены (нужен суперпользователь).
+--
+--   make bench-partitions ORDERS=1000000
+--   psql -U postgres -d orders -v orders=1000000 -f bench/order_partitions.sql
+
+\if :{?orders}
+\else
+\set orders 500000
+\endif
+\timing on
+\set ON_ERROR_STOP on
+
+BEGIN;
+SET LOCAL session_replication_role = replica;
+SET LOCAL work_mem = '64MB';
+
+-- Партиции на 24 месяца назад: миграция создает их только от первого заказа
+SELECT count(*) FILTER (WHERE created) AS partitions_created
+FROM (
+    SELECT create_order_partition((date_trunc('month', now() AT TIME ZONE 'UTC') - make_interval(months => m))::date) AS created
+    FROM generate_series(1, 24) AS m
+) p;
+
+CREATE TEMP TABLE bench_source ON COMMIT DROP AS
+SELECT
+    gen_random_uuid() AS id,
+    md5('bench-customer-' || (g % GREATEST(:orders / 10, 1)))::uuid AS customer_id,
+    (ARRAY['pending', 'confirmed', 'shipped', 'delivered', 'cancelled'])[1 + g % 5] AS status,
+    date_trunc('milliseconds', now() - random() * INTERVAL '730 days') AS created_at
+FROM generate_series(1, :orders) AS g;
+
+INSERT INTO orders (id, customer_id, email, status, subtotal, total_amount, currency, created_at, updated_at)
+SELECT id, customer_id, 'bench@example.com', status, 20.00, 20.00, 'USD', created_at, created_at
+FROM bench_source;
+
+INSERT INTO order_keys (id, created_at, tenant_id)
+SELECT id, created_at, 'default' FROM bench_source;
+
+INSERT INTO order_items (id, order_id, product_id, name, price, quantity, total, order_created_at)
+SELECT gen_random_uuid(), s.id, md5('bench-product-' || n)::uuid, 'Bench item ' || n, 10.00, 1, 10.00, s.created_at
+FROM bench_source s
+CROSS JOIN generate_series(1, 2) AS n;
+
+-- Те же данные без секционирования, с индексами прежней схемы
+CREATE TEMP TABLE bench_orders ON COMMIT DROP AS SELECT * FROM orders;
+CREATE TEMP TABLE bench_order_items ON COMMIT DROP AS SELECT * FROM order_items;
+ALTER TABLE bench_orders ADD PRIMARY KEY (id);
+ALTER TABLE bench_order_items ADD PRIMARY KEY (id);
+CREATE INDEX ON bench_orders(customer_id);
+CREATE INDEX ON bench_orders(created_at);
+CREATE INDEX ON bench_orders(status, created_at DESC);
+CREATE INDEX ON bench_order_items(order_id);
+
+ANALYZE orders;
+ANALYZE order_items;
+ANALYZE order_keys;
+ANALYZE bench_orders;
+ANALYZE bench_order_items;
+
+SELECT id AS probe_id, created_at AS probe_created_at, customer_id AS probe_customer_id
+FROM bench_source
+OFFSET :orders / 2 LIMIT 1
+\gset
+
+\echo '=== 1. Заказ по ID: партиция по реестру order_keys (GetByID)'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT * FROM orders WHERE id = :'probe_id' AND created_at = (SELECT created_at FROM order_keys WHERE id = :'probe_id');
+
+\echo '=== 1a. Заказ по ID без ключа партиции (индекс каждой партиции)'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT * FROM orders WHERE id = :'probe_id';
+
+\echo '=== 1b. Заказ по ID: обычная таблица'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT * FROM bench_orders WHERE id = :'probe_id';
+
+\echo '=== 2. Позиции заказа: партиция месяца заказа'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT * FROM order_items WHERE order_id = :'probe_id' AND order_created_at = :'probe_created_at';
+
+\echo '=== 2a. Позиции заказа: обычная таблица'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT * FROM bench_order_items WHERE order_id = :'probe_id';
+
+\echo '=== 3. Последние заказы за 30 дней (List с created_from)'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT id, status, total_amount, created_at FROM orders
+WHERE created_at >= now() - INTERVAL '30 days' ORDER BY created_at DESC LIMIT 20;
+
+\echo '=== 3a. Последние заказы за 30 дней: обычная таблица'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT id, status, total_amount, created_at FROM bench_orders
+WHERE created_at >= now() - INTERVAL '30 days' ORDER BY created_at DESC LIMIT 20;
+
+\echo '=== 4. Заказы и выручка за прошлый месяц по статусам (Count/Statistics за период)'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT status, count(*), sum(total_amount) FROM orders
+WHERE created_at >= date_trunc('month', now()) - INTERVAL '1 month' AND created_at < date_trunc('month', now())
+GROUP BY status;
+
+\echo '=== 4a. Заказы и выручка за прошлый месяц: обычная таблица'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT status, count(*), sum(total_amount) FROM bench_orders
+WHERE created_at >= date_trunc('month', now()) - INTERVAL '1 month' AND created_at < date_trunc('month', now())
+GROUP BY status;
+
+\echo '=== 5. Заказы клиента без периода: отсечения партиций нет'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT id, status, created_at FROM orders
+WHERE customer_id = :'probe_customer_id' ORDER BY created_at DESC LIMIT 20;
+
+\echo '=== 5a. Заказы клиента: обычная таблица'
+EXPLAIN (ANALYZE, BUFFERS, COSTS OFF)
+SELECT id, status, created_at FROM bench_orders
+WHERE customer_id = :'probe_customer_id' ORDER BY created_at DESC LIMIT 20;
+
+\echo '=== 6. Удаление самого старого месяца: DROP партиций'
+SELECT detach_order_partition((date_trunc('month', now() AT TIME ZONE 'UTC') - INTERVAL '24 months')::date, TRUE);
+
+\echo '=== 6a. Удаление самого старого месяца: DELETE из обычных таблиц'
+DELETE FROM bench_order_items i USING bench_orders o
+WHERE o.id = i.order_id
+  AND o.created_at >= (date_trunc('month', now() AT TIME ZONE 'UTC') - INTERVAL '24 months') AT TIME ZONE 'UTC'
+  AND o.created_at < (date_trunc('month', now() AT TIME ZONE 'UTC') - INTERVAL '23 months') AT TIME ZONE 'UTC';
+DELETE FROM bench_orders
+WHERE created_at >= (date_trunc('month', now() AT TIME ZONE 'UTC') - INTERVAL '24 months') AT TIME ZONE 'UTC'
+  AND created_at < (date_trunc('month', now() AT TIME ZONE 'UTC') - INTERVAL '23 months') AT TIME ZONE 'UTC';
+
+ROLLBACK;
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/infrastructure/archive"
+	"kafka-order-service/internal/infrastructure/postgres"
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/config"
+	"kafka-order-service/pkg/logger"
+)
+
+const usage = `Usage: archive <command> [arguments]
+
+Commands:
+  run                          archive old final orders now (ignores ARCHIVE_ENABLED)
+  list [prefix]                list archive files, e.g. "list 2024-"
+  verify <file|prefix>         read and check archive files without loading them
+  restore <file|prefix>        load orders from archive files back into the database
+
+Storage and policy are taken from ARCHIVE_* environment variables.
+`
+
+func main() {
+	_ = godotenv.Load()
+
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	log, err := logger.New("info", true)
+	if err != nil {
+		fmt.Printf("Logger init error: %v\n", err)
+		os.Exit(1)
+	}
+	defer log.Sync()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Config load error", "error", err)
+	}
+
+	storage, err := newArchiveStorage(cfg.Archive)
+	if err != nil {
+		log.Fatal("Archive storage init error", "error", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	command, arg := flag.Arg(0), flag.Arg(1)
+	if command == "list" {
+		names, err := storage.List(ctx, arg)
+		if err != nil {
+			log.Fatal("Archive list failed", "error", err)
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	db, err := sql.Open("postgres", cfg.Database.DSN())
+	if err != nil {
+		log.Fatal("DB open error", "error", err)
+	}
+	defer db.Close()
+
+	// Jobs work across tenants: the owner role must bypass row-level security
+	if bypass, err := postgres.BypassesRowSecurity(ctx, db); err != nil {
+		log.Fatal("DB role check error", "error", err)
+	} else if !bypass {
+		log.Fatal("DB_USER must be a superuser or have BYPASSRLS")
+	}
+	archiveRepo := postgres.NewArchiveRepository(db)
+
+	var result interface{}
+	switch command {
+	case "run":
+		result, err = usecase.NewArchiveOrdersUseCase(archiveRepo, storage, archivePolicy(cfg.Archive), log).Execute(ctx)
+	case "verify", "restore":
+		if arg == "" {
+			flag.Usage()
+			os.Exit(2)
+		}
+		result, err = usecase.NewRestoreOrdersUseCase(archiveRepo, storage, log).Execute(ctx, &usecase.RestoreOrdersRequest{
+			Name:   arg,
+			DryRun: command == "verify",
+		})
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(result)
+	if err != nil {
+		log.Fatal("Archive command failed", "command", command, "error", err)
+	}
+}
+
+// newArchiveStorage creates the configured archive storage: S3-compatible bucket or local directory
+func newArchiveStorage(cfg config.ArchiveConfig) (usecase.ArchiveStorage, error) {
+	if cfg.S3Endpoint != "" {
+		return archive.NewS3Storage(archive.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Prefix:    cfg.S3Prefix,
+		}, nil)
+	}
+	return archive.NewFileStorage(cfg.Dir)
+}
+
+// archivePolicy builds the archive policy from configuration
+func archivePolicy(cfg config.ArchiveConfig) usecase.ArchivePolicy {
+	statuses := make([]entities.OrderStatus, 0, len(cfg.Statuses))
+	for _, status := range cfg.Statuses {
+		statuses = append(statuses, entities.OrderStatus(strings.TrimSpace(status)))
+	}
+	return usecase.ArchivePolicy{
+		After:      cfg.After,
+		Statuses:   statuses,
+		BatchSize:  cfg.BatchSize,
+		MaxBatches: cfg.MaxBatches,
+	}
+}
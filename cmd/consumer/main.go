@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -13,8 +14,16 @@ import (
 	_ "github.com/lib/pq"
 
 	kafkaHandlers "kafka-order-service/internal/delivery/kafka"
+	"kafka-order-service/internal/delivery/scheduler"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/infrastructure/archive"
 	kafkaInfra "kafka-order-service/internal/infrastructure/kafka"
+	"kafka-order-service/internal/infrastructure/notification"
+	"kafka-order-service/internal/infrastructure/pii"
 	"kafka-order-service/internal/infrastructure/postgres"
+	"kafka-order-service/internal/infrastructure/statemachine"
+	"kafka-order-service/internal/infrastructure/tenants"
+	"kafka-order-service/internal/infrastructure/webhook"
 	"kafka-order-service/internal/usecase"
 	"kafka-order-service/pkg/config"
 	"kafka-order-service/pkg/logger"
@@ -47,8 +56,25 @@ func main() {
 	}
 	defer db.Close()
 
+	// Jobs work across tenants: the owner role must bypass row-level security
+	if bypass, err := postgres.BypassesRowSecurity(context.Background(), db); err != nil {
+		log.Fatal("DB role check error", "error", err)
+	} else if !bypass {
+		log.Fatal("DB_USER must be a superuser or have BYPASSRLS")
+	}
+
+	// PII keyring is validated at startup; without it PII is stored in plain text
+	keyring, err := pii.LoadKeyring(cfg.PII.KeyringFile)
+	if err != nil {
+		log.Fatal("PII keyring load error", "error", err)
+	}
+
 	// Initialize repository and producer (for event chaining)
-	orderRepo := postgres.NewOrderRepository(db)
+	orderRepo := postgres.NewOrderRepository(db, keyring)
+	shipmentRepo := postgres.NewShipmentRepository(db)
+	returnRepo := postgres.NewReturnRepository(db)
+	inventoryRepo := postgres.NewInventoryRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db, keyring)
 	producer := kafkaInfra.NewProducer(kafkaInfra.ProducerConfig{
 		Brokers:      cfg.Kafka.Brokers,
 		Topic:        cfg.Kafka.Topic,
@@ -56,13 +82,44 @@ func main() {
 		BatchTimeout: 10 * time.Millisecond,
 	})
 	defer producer.Close()
+	// Events leave the service with PII encrypted
+	events := usecase.NewPIIProtectingPublisher(producer, keyring)
+
+	// Tenants are validated at startup; their own state machines override the shared ones
+	tenantDirectory, err := newTenantDirectory(cfg.Tenants)
+	if err != nil {
+		log.Fatal("Tenants load error", "error", err)
+	}
+
+	// Order state machines are validated at startup
+	stateMachines, err := statemachine.LoadRegistry(cfg.Orders.StateMachineFile)
+	if err != nil {
+		log.Fatal("State machine load error", "error", err)
+	}
+	stateMachines = stateMachines.WithTenants(tenantDirectory.StateMachines())
+
+	// Email notifications: templates and transport are validated at startup
+	renderer, err := notification.NewTemplateRenderer(cfg.Notifications.TemplatesDir)
+	if err != nil {
+		log.Fatal("Notification templates load error", "error", err)
+	}
+	emailSender, err := newEmailSender(cfg.Notifications)
+	if err != nil {
+		log.Fatal("Notification transport error", "error", err)
+	}
 
 	// Initialize use cases
-	updateUC := usecase.NewUpdateOrderStatusUseCase(orderRepo, producer, log)
+	updateUC := usecase.NewUpdateOrderStatusUseCase(orderRepo, events, stateMachines, log)
 	getUC := usecase.NewGetOrderUseCase(orderRepo, log)
+	listShipmentsUC := usecase.NewListShipmentsUseCase(orderRepo, shipmentRepo, log)
+	listReturnsUC := usecase.NewListReturnsUseCase(orderRepo, returnRepo, log)
+	inventoryService := usecase.NewInventoryService(inventoryRepo, orderRepo, updateUC, log)
+	notificationService := usecase.NewNotificationService(orderRepo, notificationRepo, renderer, emailSender, log)
 
 	// Initialize Kafka event handler
-	handler := kafkaHandlers.NewOrderEventHandler(updateUC, getUC, log)
+	warehouseHandler := kafkaHandlers.NewWarehouseHandler(inventoryService, log)
+	notificationHandler := kafkaHandlers.NewNotificationHandler(notificationService, log)
+	handler := kafkaHandlers.NewOrderEventHandler(updateUC, getUC, listShipmentsUC, listReturnsUC, warehouseHandler, notificationHandler, log)
 
 	// Initialize Kafka consumer
 	consumer := kafkaInfra.NewConsumer(kafkaInfra.ConsumerConfig{
@@ -72,6 +129,7 @@ func main() {
 		MinBytes:       1,
 		MaxBytes:       10e6,
 		CommitInterval: 1 * time.Second,
+		Tenants:        tenantDirectory,
 	}, handler)
 	defer consumer.Close()
 
@@ -86,6 +144,80 @@ func main() {
 		}
 	}()
 
+	// Deliver order events to webhook subscribers in a separate consumer group
+	if cfg.Webhooks.Enabled {
+		dispatcher := usecase.NewWebhookDispatcher(
+			postgres.NewWebhookRepository(db),
+			webhook.NewSender(cfg.Webhooks.Timeout),
+			usecase.WebhookRetryPolicy{
+				MaxAttempts:    cfg.Webhooks.MaxAttempts,
+				InitialBackoff: cfg.Webhooks.InitialBackoff,
+				MaxBackoff:     cfg.Webhooks.MaxBackoff,
+				DisableAfter:   cfg.Webhooks.DisableAfter,
+			},
+			keyring,
+			log,
+		)
+		webhookConsumer := kafkaInfra.NewConsumer(kafkaInfra.ConsumerConfig{
+			Brokers:        cfg.Kafka.Brokers,
+			Topic:          cfg.Kafka.Topic,
+			GroupID:        cfg.Webhooks.GroupID,
+			MinBytes:       1,
+			MaxBytes:       10e6,
+			CommitInterval: 1 * time.Second,
+			Tenants:        tenantDirectory,
+		}, kafkaHandlers.NewWebhookEventHandler(dispatcher, log))
+		defer webhookConsumer.Close()
+
+		go func() {
+			log.Info("Webhook consumer running...")
+			if err := webhookConsumer.Start(ctx); err != nil && err != context.Canceled {
+				log.Error("Webhook consumer error", "error", err)
+			}
+		}()
+	}
+
+	// Run pending orders expiry (only the replica holding the advisory lock does the work)
+	if cfg.Expiry.Enabled {
+		expiryPolicy := usecase.NewExpiryPolicy(cfg.Expiry.DefaultTTL, cfg.Expiry.CurrencyTTL, cfg.Expiry.ChannelTTL)
+		expireUC := usecase.NewExpirePendingOrdersUseCase(orderRepo, updateUC, expiryPolicy, cfg.Expiry.BatchSize, log)
+		expiryLock := postgres.NewAdvisoryLock(db, cfg.Expiry.LockKey)
+		expiryScheduler := scheduler.NewOrderExpiryScheduler(expireUC, expiryLock, cfg.Expiry.Interval, log)
+		go expiryScheduler.Run(ctx)
+	}
+
+	// Refresh the daily report rollup (only the replica holding the advisory lock does the work)
+	if cfg.Reports.RollupEnabled {
+		refreshReportsUC := usecase.NewRefreshOrderReportsUseCase(postgres.NewReportRepository(db), log)
+		rollupLock := postgres.NewAdvisoryLock(db, cfg.Reports.LockKey)
+		rollupScheduler := scheduler.NewReportRollupScheduler(refreshReportsUC, rollupLock, cfg.Reports.RollupInterval, log)
+		go rollupScheduler.Run(ctx)
+	}
+
+	// Archive old final orders to files, then delete them (only the replica holding the advisory lock does the work)
+	if cfg.Archive.Enabled {
+		archiveStorage, err := newArchiveStorage(cfg.Archive)
+		if err != nil {
+			log.Fatal("Archive storage init error", "error", err)
+		}
+		archiveUC := usecase.NewArchiveOrdersUseCase(postgres.NewArchiveRepository(db), archiveStorage, archivePolicy(cfg.Archive), log)
+		archiveLock := postgres.NewAdvisoryLock(db, cfg.Archive.LockKey)
+		archiveScheduler := scheduler.NewOrderArchiveScheduler(archiveUC, archiveLock, cfg.Archive.Interval, log)
+		go archiveScheduler.Run(ctx)
+	}
+
+	// Create future order partitions and expire old ones (only the replica holding the advisory lock does the work)
+	if cfg.Partitions.Enabled {
+		partitionUC := usecase.NewMaintainOrderPartitionsUseCase(postgres.NewOrderPartitionRepository(db), usecase.PartitionPolicy{
+			MonthsAhead:     cfg.Partitions.MonthsAhead,
+			RetentionMonths: cfg.Partitions.RetentionMonths,
+			DropExpired:     cfg.Partitions.DropExpired,
+		}, log)
+		partitionLock := postgres.NewAdvisoryLock(db, cfg.Partitions.LockKey)
+		partitionScheduler := scheduler.NewOrderPartitionScheduler(partitionUC, partitionLock, cfg.Partitions.Interval, log)
+		go partitionScheduler.Run(ctx)
+	}
+
 	// Wait for termination signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -97,6 +229,55 @@ func main() {
 	log.Info("Consumer stopped")
 }
 
+// newEmailSender creates the configured email transport
+func newEmailSender(cfg config.NotificationConfig) (usecase.EmailSender, error) {
+	switch cfg.Transport {
+	case notification.TransportNoop, "":
+		return notification.NewNopSender(), nil
+	case notification.TransportFile:
+		return notification.NewFileSender(cfg.MailDir, cfg.From)
+	case notification.TransportSMTP:
+		return notification.NewSMTPSender(notification.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown notification transport: %s", cfg.Transport)
+	}
+}
+
+// newArchiveStorage creates the configured archive storage: S3-compatible bucket or local directory
+func newArchiveStorage(cfg config.ArchiveConfig) (usecase.ArchiveStorage, error) {
+	if cfg.S3Endpoint != "" {
+		return archive.NewS3Storage(archive.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Prefix:    cfg.S3Prefix,
+		}, nil)
+	}
+	return archive.NewFileStorage(cfg.Dir)
+}
+
+// archivePolicy builds the archive policy from configuration
+func archivePolicy(cfg config.ArchiveConfig) usecase.ArchivePolicy {
+	statuses := make([]entities.OrderStatus, 0, len(cfg.Statuses))
+	for _, status := range cfg.Statuses {
+		statuses = append(statuses, entities.OrderStatus(strings.TrimSpace(status)))
+	}
+	return usecase.ArchivePolicy{
+		After:      cfg.After,
+		Statuses:   statuses,
+		BatchSize:  cfg.BatchSize,
+		MaxBatches: cfg.MaxBatches,
+	}
+}
+
 // connectDatabase attempts to connect with retries
 func connectDatabase(dsn string) (*sql.DB, error) {
 	var db *sql.DB
@@ -113,3 +294,23 @@ func connectDatabase(dsn string) (*sql.DB, error) {
 	}
 	return nil, fmt.Errorf("DB connect failed: %w", err)
 }
+
+// newTenantDirectory loads tenants and the JWT verifier; without them every request
+// belongs to the default tenant
+func newTenantDirectory(cfg config.TenantConfig) (*entities.TenantDirectory, error) {
+	list, err := tenants.LoadTenants(cfg.File)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifier entities.TenantTokenVerifier
+	if cfg.JWTSecret != "" {
+		jwtVerifier, err := tenants.NewJWTVerifier(cfg.JWTSecret, cfg.JWTClaim)
+		if err != nil {
+			return nil, err
+		}
+		verifier = jwtVerifier
+	}
+
+	return entities.NewTenantDirectory(list, verifier)
+}
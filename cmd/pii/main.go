@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/infrastructure/pii"
+	"kafka-order-service/internal/infrastructure/postgres"
+	"kafka-order-service/pkg/config"
+	"kafka-order-service/pkg/logger"
+)
+
+const usage = `Usage: pii [-batch-size N] <command>
+
+Commands:
+  genkey     print a new random key for the keyring file
+  rewrap     encrypt plain text PII, move ciphertext to the primary key and rebuild the email index
+  decrypt    write PII back as plain text (before rolling back migration 019)
+
+The keyring is read from PII_KEYRING_FILE. Both rewrap and decrypt are safe to rerun.
+`
+
+func main() {
+	_ = godotenv.Load()
+
+	batchSize := flag.Int("batch-size", 500, "rows per transaction")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	command := flag.Arg(0)
+	if command == "genkey" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			fmt.Fprintf(os.Stderr, "Key generation error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(base64.StdEncoding.EncodeToString(key))
+		return
+	}
+
+	log, err := logger.New("info", true)
+	if err != nil {
+		fmt.Printf("Logger init error: %v\n", err)
+		os.Exit(1)
+	}
+	defer log.Sync()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Config load error", "error", err)
+	}
+
+	keyring, err := pii.LoadKeyring(cfg.PII.KeyringFile)
+	if err != nil {
+		log.Fatal("PII keyring load error", "error", err)
+	}
+	if keyring == nil {
+		log.Fatal("PII_KEYRING_FILE is not set")
+	}
+
+	var rewrite postgres.FieldRewrite
+	switch command {
+	case "rewrap":
+		rewrite = rewrapFields{keyring: keyring}
+	case "decrypt":
+		rewrite = decryptFields{keyring: keyring}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := sql.Open("postgres", cfg.Database.DSN())
+	if err != nil {
+		log.Fatal("DB open error", "error", err)
+	}
+	defer db.Close()
+
+	// Jobs work across tenants: the owner role must bypass row-level security
+	if bypass, err := postgres.BypassesRowSecurity(ctx, db); err != nil {
+		log.Fatal("DB role check error", "error", err)
+	} else if !bypass {
+		log.Fatal("DB_USER must be a superuser or have BYPASSRLS")
+	}
+
+	result, err := postgres.NewPIIRewriter(db).Rewrite(ctx, rewrite, *batchSize)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(result)
+	if err != nil {
+		log.Fatal("PII command failed", "command", command, "error", err)
+	}
+	log.Info("PII rewritten", "command", command, "primary_key", keyring.Primary(),
+		"orders", result.Orders, "addresses", result.Addresses,
+		"customers", result.Customers, "notifications", result.Notifications)
+}
+
+// rewrapFields encrypts plain text values and moves ciphertext to the primary key.
+// Erased values carry no personal data and stay as they are.
+type rewrapFields struct {
+	keyring *pii.Keyring
+}
+
+func (f rewrapFields) Rewrite(stored string) (string, bool, error) {
+	if entities.IsErasedEmail(stored) || stored == entities.ErasedValue {
+		return stored, false, nil
+	}
+	return f.keyring.Rewrap(stored)
+}
+
+func (f rewrapFields) EmailIndex(stored string) (string, error) {
+	if !pii.IsEncrypted(stored) {
+		return "", nil
+	}
+	email, err := f.keyring.Decrypt(stored)
+	if err != nil {
+		return "", err
+	}
+	return f.keyring.BlindIndex(entities.NormalizeEmail(email)), nil
+}
+
+// decryptFields writes values back as plain text and drops the email index
+type decryptFields struct {
+	keyring *pii.Keyring
+}
+
+func (f decryptFields) Rewrite(stored string) (string, bool, error) {
+	if !pii.IsEncrypted(stored) {
+		return stored, false, nil
+	}
+	plaintext, err := f.keyring.Decrypt(stored)
+	return plaintext, err == nil, err
+}
+
+func (f decryptFields) EmailIndex(string) (string, error) {
+	return "", nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -19,11 +20,24 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
 
+	"kafka-order-service/api/openapi"
+	ordersv1 "kafka-order-service/api/proto/orders/v1"
+	grpcHandlers "kafka-order-service/internal/delivery/grpc"
 	httpHandlers "kafka-order-service/internal/delivery/http"
 	"kafka-order-service/internal/delivery/http/middleware"
+	kafkaHandlers "kafka-order-service/internal/delivery/kafka"
+	"kafka-order-service/internal/domain/entities"
 	kafkaInfra "kafka-order-service/internal/infrastructure/kafka"
+	"kafka-order-service/internal/infrastructure/pii"
 	"kafka-order-service/internal/infrastructure/postgres"
+	"kafka-order-service/internal/infrastructure/catalog"
+	"kafka-order-service/internal/infrastructure/exchangerates"
+	"kafka-order-service/internal/infrastructure/shippingrates"
+	"kafka-order-service/internal/infrastructure/statemachine"
+	"kafka-order-service/internal/infrastructure/tenants"
+	"kafka-order-service/internal/infrastructure/taxrates"
 	"kafka-order-service/internal/usecase"
 	"kafka-order-service/pkg/config"
 	"kafka-order-service/pkg/logger"
@@ -56,8 +70,56 @@ func main() {
 
 	log.Info("Migrations applied successfully")
 
+	// Tenant requests use a role without BYPASSRLS, so row-level security applies to them;
+	// the schema owner only runs migrations
+	appDB := db
+	if cfg.Database.AppUser != "" {
+		appDB, err = sql.Open("postgres", cfg.Database.AppDSN())
+		if err != nil {
+			log.Fatal("DB app role open error", "error", err)
+		}
+		defer appDB.Close()
+	}
+	checkRowSecurity(appDB, cfg.Database.AppUser != "", log)
+
+	// PII keyring is validated at startup; without it PII is stored in plain text
+	keyring, err := pii.LoadKeyring(cfg.PII.KeyringFile)
+	if err != nil {
+		log.Fatal("PII keyring load error", "error", err)
+	}
+
 	// Init repos and infrastructure
-	orderRepo := postgres.NewOrderRepository(db)
+	orderRepo := postgres.NewOrderRepository(appDB, keyring)
+
+	// Order reads go to healthy read replicas; writes and reads of mutating requests stay on the primary
+	replicaCtx, stopReplicaChecks := context.WithCancel(context.Background())
+	defer stopReplicaChecks()
+	var dbRouter *postgres.DBRouter
+	if len(cfg.Database.ReplicaDSNs) > 0 {
+		dbRouter, err = newDBRouter(cfg.Database, appDB)
+		if err != nil {
+			log.Fatal("DB replicas open error", "error", err)
+		}
+		defer dbRouter.Close()
+
+		checkReplicas(replicaCtx, dbRouter, log)
+		go runReplicaChecks(replicaCtx, dbRouter, cfg.Database.ReplicaCheckInterval, log)
+		orderRepo = orderRepo.WithReplicas(dbRouter)
+	}
+	// Out-of-stock orders are rejected by reserving their items in the order-create transaction
+	if cfg.Orders.RejectOutOfStock {
+		orderRepo = orderRepo.WithStockReservation()
+	}
+	// Order changes made by shipments, refunds and erasures also keep the next reads on the primary
+	shipmentRepo := postgres.NewShipmentRepository(appDB).WithReadYourWrites(dbRouter)
+	returnRepo := postgres.NewReturnRepository(appDB).WithReadYourWrites(dbRouter)
+	promotionRepo := postgres.NewPromotionRepository(appDB)
+	exchangeRateRepo := postgres.NewExchangeRateRepository(appDB)
+	inventoryRepo := postgres.NewInventoryRepository(appDB)
+	productRepo := postgres.NewProductRepository(appDB)
+	customerRepo := postgres.NewCustomerRepository(appDB, keyring).WithReadYourWrites(dbRouter)
+	reportRepo := postgres.NewReportRepository(appDB)
+	webhookRepo := postgres.NewWebhookRepository(appDB)
 	producer := kafkaInfra.NewProducer(kafkaInfra.ProducerConfig{
 		Brokers:      cfg.Kafka.Brokers,
 		Topic:        cfg.Kafka.Topic,
@@ -65,18 +127,174 @@ func main() {
 		BatchTimeout: 10 * time.Millisecond,
 	})
 	defer producer.Close()
+	// Events leave the service with PII encrypted
+	events := usecase.NewPIIProtectingPublisher(producer, keyring)
+
+	// Tenants are validated at startup; their own state machines override the shared ones
+	tenantDirectory, err := newTenantDirectory(cfg.Tenants)
+	if err != nil {
+		log.Fatal("Tenants load error", "error", err)
+	}
+
+	// Order state machines are validated at startup
+	stateMachines, err := statemachine.LoadRegistry(cfg.Orders.StateMachineFile)
+	if err != nil {
+		log.Fatal("State machine load error", "error", err)
+	}
+	stateMachines = stateMachines.WithTenants(tenantDirectory.StateMachines())
+
+	// Tax rate tables are validated at startup
+	taxTable, err := taxrates.LoadTable(cfg.Orders.TaxRatesFile)
+	if err != nil {
+		log.Fatal("Tax rates load error", "error", err)
+	}
+
+	// Shipping rates are validated at startup
+	shippingTable, err := shippingrates.LoadTable(cfg.Orders.ShippingRatesFile)
+	if err != nil {
+		log.Fatal("Shipping rates load error", "error", err)
+	}
+
+	// Exchange rates from file are imported into the exchange_rates table
+	currencyService := usecase.NewCurrencyService(exchangeRateRepo, cfg.Currency.ReportingCurrency, log)
+	if cfg.Currency.ExchangeRatesFile != "" {
+		rates, err := exchangerates.LoadFile(cfg.Currency.ExchangeRatesFile, currencyService.ReportingCurrency())
+		if err != nil {
+			log.Fatal("Exchange rates load error", "error", err)
+		}
+		if err := currencyService.ImportRates(context.Background(), rates); err != nil {
+			log.Fatal("Exchange rates import error", "error", err)
+		}
+	}
+
+	// Product catalog from file is upserted into the products table by SKU
+	catalogService := usecase.NewCatalogService(productRepo, log)
+	if cfg.Catalog.ProductsFile != "" {
+		products, err := catalog.LoadCSV(cfg.Catalog.ProductsFile)
+		if err != nil {
+			log.Fatal("Product catalog load error", "error", err)
+		}
+		if err := catalogService.ImportProducts(context.Background(), products); err != nil {
+			log.Fatal("Product catalog import error", "error", err)
+		}
+	}
 
 	// Init usecases
-	createUC := usecase.NewCreateOrderUseCase(orderRepo, producer, log)
-	updateUC := usecase.NewUpdateOrderStatusUseCase(orderRepo, producer, log)
+	var riskEngine *usecase.RiskEngine
+	if cfg.Fraud.Enabled {
+		// Risky orders are held for review, so every state machine must allow pending -> on_hold
+		if err := stateMachines.RequireTransition(entities.OrderStatusPending, entities.OrderStatusOnHold); err != nil {
+			log.Fatal("Fraud checks need an on_hold state in every state machine", "error", err)
+		}
+		riskEngine = newRiskEngine(cfg.Fraud, orderRepo)
+	}
+
+	promotionService := usecase.NewPromotionService(promotionRepo, log)
+
+	var inventoryService *usecase.InventoryService
+	if cfg.Orders.RejectOutOfStock {
+		inventoryService = usecase.NewInventoryService(inventoryRepo, orderRepo, nil, log)
+	}
+
+	var orderCatalog *usecase.CatalogService
+	if cfg.Orders.RequireCatalog {
+		orderCatalog = catalogService
+	}
+
+	var customerService *usecase.CustomerService
+	if cfg.Orders.AutoCreateCustomers {
+		customerService = usecase.NewCustomerService(customerRepo, log)
+	}
+
+	createUC := usecase.NewCreateOrderUseCase(orderRepo, events, riskEngine, customerService, orderCatalog, promotionService, taxTable, shippingTable, currencyService, inventoryService, stateMachines, log)
+	updateUC := usecase.NewUpdateOrderStatusUseCase(orderRepo, events, stateMachines, log)
 	getUC := usecase.NewGetOrderUseCase(orderRepo, log)
 	listUC := usecase.NewListOrdersUseCase(orderRepo, log)
+	exportUC := usecase.NewExportOrdersUseCase(orderRepo, cfg.Orders.ExportBatchSize, log)
+	statsUC := usecase.NewOrderStatsUseCase(orderRepo, currencyService.ReportingCurrency(), log)
+	reportUC := usecase.NewOrderReportUseCase(reportRepo, currencyService.ReportingCurrency(), log)
+	statesUC := usecase.NewGetOrderStatesUseCase(stateMachines, log)
+	createShipmentUC := usecase.NewCreateShipmentUseCase(orderRepo, shipmentRepo, events, stateMachines, log)
+	listShipmentsUC := usecase.NewListShipmentsUseCase(orderRepo, shipmentRepo, log)
+	updateShipmentStatusUC := usecase.NewUpdateShipmentStatusUseCase(orderRepo, shipmentRepo, events, stateMachines, log)
+	createReturnUC := usecase.NewCreateReturnUseCase(orderRepo, returnRepo, events, log)
+	resolveReturnUC := usecase.NewResolveReturnUseCase(orderRepo, returnRepo, log)
+	issueRefundUC := usecase.NewIssueRefundUseCase(orderRepo, returnRepo, events, stateMachines, log)
+	listReturnsUC := usecase.NewListReturnsUseCase(orderRepo, returnRepo, log)
+	createPromotionUC := usecase.NewCreatePromotionUseCase(promotionRepo, log)
+	listPromotionsUC := usecase.NewListPromotionsUseCase(promotionRepo, log)
+	quoteShippingUC := usecase.NewQuoteShippingUseCase(shippingTable, log)
+	getStockUC := usecase.NewGetStockUseCase(inventoryRepo, log)
+	adjustStockUC := usecase.NewAdjustStockUseCase(inventoryRepo, log)
+	createProductUC := usecase.NewCreateProductUseCase(productRepo, log)
+	updateProductUC := usecase.NewUpdateProductUseCase(productRepo, log)
+	getProductUC := usecase.NewGetProductUseCase(productRepo, log)
+	listProductsUC := usecase.NewListProductsUseCase(productRepo, log)
+	deleteProductUC := usecase.NewDeleteProductUseCase(productRepo, log)
+	createCustomerUC := usecase.NewCreateCustomerUseCase(customerRepo, log)
+	getCustomerUC := usecase.NewGetCustomerUseCase(customerRepo, log)
+	listCustomerOrdersUC := usecase.NewListCustomerOrdersUseCase(orderRepo, log)
+	eraseCustomerUC := usecase.NewEraseCustomerUseCase(customerRepo, events, log)
+	exportCustomerDataUC := usecase.NewExportCustomerDataUseCase(customerRepo, orderRepo, log)
+	createWebhookUC := usecase.NewCreateWebhookUseCase(webhookRepo, log)
+	manageWebhooksUC := usecase.NewManageWebhooksUseCase(webhookRepo, log)
 
 	// Handlers
-	handler := httpHandlers.NewOrderHandler(createUC, updateUC, getUC, listUC, log)
+	handler := httpHandlers.NewOrderHandler(createUC, updateUC, getUC, listUC, statsUC, log)
+	exportHandler := httpHandlers.NewOrderExportHandler(exportUC, log)
+	stateHandler := httpHandlers.NewOrderStateHandler(statesUC, log)
+	shipmentHandler := httpHandlers.NewShipmentHandler(createShipmentUC, listShipmentsUC, updateShipmentStatusUC, log)
+	returnHandler := httpHandlers.NewReturnHandler(createReturnUC, resolveReturnUC, issueRefundUC, listReturnsUC, log)
+	promotionHandler := httpHandlers.NewPromotionHandler(createPromotionUC, listPromotionsUC, log)
+	shippingHandler := httpHandlers.NewShippingHandler(quoteShippingUC, log)
+	inventoryHandler := httpHandlers.NewInventoryHandler(getStockUC, adjustStockUC, log)
+	productHandler := httpHandlers.NewProductHandler(createProductUC, updateProductUC, getProductUC, listProductsUC, deleteProductUC, log)
+	customerHandler := httpHandlers.NewCustomerHandler(createCustomerUC, getCustomerUC, listCustomerOrdersUC, eraseCustomerUC, exportCustomerDataUC, log)
+	webhookHandler := httpHandlers.NewWebhookHandler(createWebhookUC, manageWebhooksUC, log)
+	reportHandler := httpHandlers.NewReportHandler(reportUC, log)
+
+	// Order events stream: a per-instance consumer feeds the broker shared by SSE and gRPC WatchOrder
+	streamCtx, stopStream := context.WithCancel(context.Background())
+	defer stopStream()
+	var broker *usecase.OrderEventBroker
+	var streamHandler *httpHandlers.OrderStreamHandler
+	if cfg.Stream.Enabled {
+		broker = usecase.NewOrderEventBroker(cfg.Stream.HistorySize, cfg.Stream.BufferSize, log)
+		streamHandler = httpHandlers.NewOrderStreamHandler(broker, getUC, cfg.Stream.Heartbeat, log)
+		streamConsumer := kafkaInfra.NewConsumer(kafkaInfra.ConsumerConfig{
+			Brokers:        cfg.Kafka.Brokers,
+			Topic:          cfg.Kafka.Topic,
+			GroupID:        streamGroupID(cfg.Stream.GroupID),
+			MinBytes:       1,
+			MaxBytes:       10e6,
+			CommitInterval: 1 * time.Second,
+			Tenants:        tenantDirectory,
+		}, kafkaHandlers.NewOrderStreamHandler(broker, keyring, log))
+		defer streamConsumer.Close()
+
+		go func() {
+			if err := streamConsumer.Start(streamCtx); err != nil && err != context.Canceled {
+				log.Error("Order stream consumer error", "error", err)
+			}
+		}()
+	}
+
+	// OpenAPI spec: request validation and interactive docs
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatal("Failed to load OpenAPI spec", "error", err)
+	}
+	validateRequests, err := middleware.ValidateRequests(spec)
+	if err != nil {
+		log.Fatal("Failed to create request validator", "error", err)
+	}
+	docsHandler, err := httpHandlers.NewDocsHandler(spec)
+	if err != nil {
+		log.Fatal("Failed to create docs handler", "error", err)
+	}
 
 	// Router and middleware
-	router := setupRouter(handler, log)
+	router := setupRouter(handler, exportHandler, stateHandler, shipmentHandler, returnHandler, promotionHandler, shippingHandler, inventoryHandler, productHandler, customerHandler, webhookHandler, reportHandler, streamHandler, docsHandler, validateRequests, tenantDirectory, log)
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -85,6 +303,9 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	if streamHandler != nil {
+		server.RegisterOnShutdown(streamHandler.Close)
+	}
 
 	go func() {
 		log.Info("HTTP server starting", "port", cfg.Server.Port)
@@ -93,6 +314,30 @@ func main() {
 		}
 	}()
 
+	// gRPC API
+	var grpcServer *grpc.Server
+	var orderServer *grpcHandlers.OrderServer
+	if cfg.GRPC.Enabled {
+		if len(cfg.GRPC.AuthTokens) == 0 {
+			log.Warn("gRPC authentication is disabled: GRPC_AUTH_TOKENS is empty")
+		}
+		listener, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			log.Fatal("Failed to listen gRPC port", "port", cfg.GRPC.Port, "error", err)
+		}
+
+		orderServer = grpcHandlers.NewOrderServer(createUC, updateUC, getUC, listUC, broker, log)
+		grpcServer = grpcHandlers.NewServer(log, cfg.GRPC.AuthTokens, tenantDirectory)
+		ordersv1.RegisterOrderServiceServer(grpcServer, orderServer)
+
+		go func() {
+			log.Info("gRPC server starting", "port", cfg.GRPC.Port)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal("gRPC server error", "error", err)
+			}
+		}()
+	}
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -101,10 +346,104 @@ func main() {
 	log.Info("Shutting down HTTP server...")
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
+	stopStream()
+	if grpcServer != nil {
+		orderServer.Close()
+		grpcServer.GracefulStop()
+		log.Info("gRPC server stopped")
+	}
 	_ = server.Shutdown(ctx)
 	log.Info("HTTP server stopped")
 }
 
+// streamGroupID makes the stream consumer group unique per instance so every instance sees all events
+func streamGroupID(prefix string) string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = fmt.Sprintf("pid-%d", os.Getpid())
+	}
+	return prefix + "-" + hostname
+}
+
+// newRiskEngine builds the fraud rule engine from config thresholds
+func newRiskEngine(cfg config.FraudConfig, orderRepo *postgres.OrderRepository) *usecase.RiskEngine {
+	return usecase.NewRiskEngine(cfg.HoldScore,
+		usecase.AmountRiskRule{MaxAmount: cfg.MaxAmount, Score: cfg.AmountScore},
+		usecase.QuantityRiskRule{MaxQuantity: cfg.MaxQuantity, Score: cfg.QuantityScore},
+		usecase.EmailDomainRiskRule{Domains: cfg.EmailDomains, Score: cfg.EmailDomainScore},
+		usecase.CountryMismatchRiskRule{Score: cfg.CountryMismatchScore},
+		usecase.VelocityRiskRule{
+			OrderRepo: orderRepo,
+			Window:    cfg.VelocityWindow,
+			MaxOrders: cfg.VelocityMaxOrders,
+			Score:     cfg.VelocityScore,
+		},
+	)
+}
+
+// checkRowSecurity makes sure tenant requests are isolated by row-level security:
+// a dedicated app role must not bypass it, the owner role fallback is only reported
+func checkRowSecurity(db *sql.DB, dedicated bool, log *logger.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	bypass, err := postgres.BypassesRowSecurity(ctx, db)
+	switch {
+	case err != nil:
+		log.Fatal("DB role check error", "error", err)
+	case bypass && dedicated:
+		log.Fatal("DB_APP_USER must not be a superuser or have BYPASSRLS")
+	case bypass:
+		log.Warn("Row-level security is not enforced for tenant requests: set DB_APP_USER to a role without BYPASSRLS")
+	}
+}
+
+// newDBRouter opens the read replicas lazily: an unavailable replica must not block startup,
+// health checks keep it out of rotation instead
+func newDBRouter(cfg config.DatabaseConfig, primary *sql.DB) (*postgres.DBRouter, error) {
+	replicas := make([]*sql.DB, 0, len(cfg.ReplicaDSNs))
+	for _, dsn := range cfg.ReplicaDSNs {
+		replica, err := sql.Open("postgres", dsn)
+		if err != nil {
+			for _, opened := range replicas {
+				_ = opened.Close()
+			}
+			return nil, err
+		}
+		replica.SetMaxOpenConns(25)
+		replica.SetMaxIdleConns(5)
+		replica.SetConnMaxLifetime(5 * time.Minute)
+		replicas = append(replicas, replica)
+	}
+	return postgres.NewDBRouter(primary, replicas, cfg.ReplicaMaxLag, cfg.ReadYourWritesWindow), nil
+}
+
+// runReplicaChecks periodically checks replica health and lag until ctx is cancelled
+func runReplicaChecks(ctx context.Context, router *postgres.DBRouter, interval time.Duration, log *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkReplicas(ctx, router, log)
+		}
+	}
+}
+
+// checkReplicas logs replicas that went in or out of rotation
+func checkReplicas(ctx context.Context, router *postgres.DBRouter, log *logger.Logger) {
+	for _, health := range router.CheckReplicas(ctx) {
+		switch {
+		case health.Healthy && health.Changed:
+			log.Info("DB replica is in rotation", "replica", health.Name, "lag", health.Lag.String())
+		case !health.Healthy && health.Changed:
+			log.Warn("DB replica is out of rotation", "replica", health.Name, "error", health.Err)
+		}
+	}
+}
+
 func connectDatabase(dsn string) (*sql.DB, error) {
 	var db *sql.DB
 	var err error
@@ -162,7 +501,25 @@ func migrationsDirPath() string {
 	return "file:///" + strings.ReplaceAll(absPath, "\\", "/")
 }
 
-func setupRouter(handler *httpHandlers.OrderHandler, log *logger.Logger) *mux.Router {
+func setupRouter(
+	handler *httpHandlers.OrderHandler,
+	exportHandler *httpHandlers.OrderExportHandler,
+	stateHandler *httpHandlers.OrderStateHandler,
+	shipmentHandler *httpHandlers.ShipmentHandler,
+	returnHandler *httpHandlers.ReturnHandler,
+	promotionHandler *httpHandlers.PromotionHandler,
+	shippingHandler *httpHandlers.ShippingHandler,
+	inventoryHandler *httpHandlers.InventoryHandler,
+	productHandler *httpHandlers.ProductHandler,
+	customerHandler *httpHandlers.CustomerHandler,
+	webhookHandler *httpHandlers.WebhookHandler,
+	reportHandler *httpHandlers.ReportHandler,
+	streamHandler *httpHandlers.OrderStreamHandler,
+	docsHandler *httpHandlers.DocsHandler,
+	validateRequests func(http.Handler) http.Handler,
+	tenantDirectory *entities.TenantDirectory,
+	log *logger.Logger,
+) *mux.Router {
 	r := mux.NewRouter()
 	r.Use(middleware.Chain(
 		middleware.Recovery(log),
@@ -170,15 +527,76 @@ func setupRouter(handler *httpHandlers.OrderHandler, log *logger.Logger) *mux.Ro
 		middleware.CORS(),
 		middleware.Security(),
 		middleware.Metrics(log),
-		middleware.Timeout(30*time.Second),
+		middleware.Timeout(30*time.Second, "/api/v1/orders/export"),
+		middleware.PrimaryReads(),
 	))
 	api := r.PathPrefix("/api/v1").Subrouter()
-	api.Use(middleware.JSONOnly())
+	api.Use(middleware.Tenant(tenantDirectory, "/api/v1/openapi.json", "/api/v1/docs"), middleware.JSONOnly(), validateRequests)
+	api.HandleFunc("/openapi.json", docsHandler.OpenAPISpec).Methods("GET")
+	api.HandleFunc("/docs", docsHandler.Docs).Methods("GET")
+	api.PathPrefix("/docs/").HandlerFunc(docsHandler.Docs).Methods("GET")
 	api.HandleFunc("/orders", handler.CreateOrder).Methods("POST")
 	api.HandleFunc("/orders", handler.ListOrders).Methods("GET")
+	api.HandleFunc("/orders/stats", handler.OrderStats).Methods("GET")
+	api.HandleFunc("/orders/export", exportHandler.ExportOrders).Methods("GET")
 	api.HandleFunc("/orders/{id}", handler.GetOrder).Methods("GET")
 	api.HandleFunc("/orders/{id}/status", handler.UpdateOrderStatus).Methods("PUT")
+	api.HandleFunc("/orders/{id}/shipments", shipmentHandler.CreateShipment).Methods("POST")
+	api.HandleFunc("/orders/{id}/shipments", shipmentHandler.ListShipments).Methods("GET")
+	api.HandleFunc("/orders/{id}/shipments/{shipment_id}", shipmentHandler.UpdateShipmentStatus).Methods("PATCH")
+	api.HandleFunc("/orders/{id}/returns", returnHandler.CreateReturn).Methods("POST")
+	api.HandleFunc("/orders/{id}/returns", returnHandler.ListReturns).Methods("GET")
+	api.HandleFunc("/orders/{id}/returns/{return_id}/approve", returnHandler.ApproveReturn).Methods("POST")
+	api.HandleFunc("/orders/{id}/returns/{return_id}/reject", returnHandler.RejectReturn).Methods("POST")
+	api.HandleFunc("/orders/{id}/refunds", returnHandler.IssueRefund).Methods("POST")
+	api.HandleFunc("/promotions", promotionHandler.CreatePromotion).Methods("POST")
+	api.HandleFunc("/promotions", promotionHandler.ListPromotions).Methods("GET")
+	api.HandleFunc("/shipping/quote", shippingHandler.QuoteShipping).Methods("GET")
+	api.HandleFunc("/products", productHandler.CreateProduct).Methods("POST")
+	api.HandleFunc("/products", productHandler.ListProducts).Methods("GET")
+	api.HandleFunc("/products/{id}", productHandler.GetProduct).Methods("GET")
+	api.HandleFunc("/products/{id}", productHandler.UpdateProduct).Methods("PUT")
+	api.HandleFunc("/products/{id}", productHandler.DeleteProduct).Methods("DELETE")
+	api.HandleFunc("/customers", customerHandler.CreateCustomer).Methods("POST")
+	api.HandleFunc("/customers/{id}", customerHandler.GetCustomer).Methods("GET")
+	api.HandleFunc("/customers/{id}/orders", customerHandler.ListCustomerOrders).Methods("GET")
+	api.HandleFunc("/customers/{id}/erase", customerHandler.EraseCustomer).Methods("POST")
+	api.HandleFunc("/customers/{id}/data-export", customerHandler.ExportCustomerData).Methods("GET")
+	api.HandleFunc("/webhooks", webhookHandler.CreateWebhook).Methods("POST")
+	api.HandleFunc("/webhooks", webhookHandler.ListWebhooks).Methods("GET")
+	api.HandleFunc("/webhooks/{id}", webhookHandler.GetWebhook).Methods("GET")
+	api.HandleFunc("/webhooks/{id}", webhookHandler.DeleteWebhook).Methods("DELETE")
+	api.HandleFunc("/webhooks/{id}/enable", webhookHandler.EnableWebhook).Methods("POST")
+	api.HandleFunc("/webhooks/{id}/deliveries", webhookHandler.ListDeliveries).Methods("GET")
+	api.HandleFunc("/reports/orders", reportHandler.OrderReport).Methods("GET")
+	api.HandleFunc("/inventory/{product_id}", inventoryHandler.GetStock).Methods("GET")
+	api.HandleFunc("/inventory/{product_id}/adjust", inventoryHandler.AdjustStock).Methods("POST")
+	api.HandleFunc("/order-states", stateHandler.GetOrderStates).Methods("GET")
+	if streamHandler != nil {
+		api.HandleFunc("/orders/{id}/events", streamHandler.StreamOrderEvents).Methods("GET")
+		api.HandleFunc("/customers/{id}/events", streamHandler.StreamCustomerEvents).Methods("GET")
+	}
 	r.HandleFunc("/health", handler.HealthCheck).Methods("GET")
 	r.HandleFunc("/metrics", handler.Metrics).Methods("GET")
 	return r
 }
+
+// newTenantDirectory loads tenants and the JWT verifier; without them every request
+// belongs to the default tenant
+func newTenantDirectory(cfg config.TenantConfig) (*entities.TenantDirectory, error) {
+	list, err := tenants.LoadTenants(cfg.File)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifier entities.TenantTokenVerifier
+	if cfg.JWTSecret != "" {
+		jwtVerifier, err := tenants.NewJWTVerifier(cfg.JWTSecret, cfg.JWTClaim)
+		if err != nil {
+			return nil, err
+		}
+		verifier = jwtVerifier.WithCustomerClaim(cfg.JWTCustomerClaim)
+	}
+
+	return entities.NewTenantDirectory(list, verifier)
+}
@@ -0,0 +1,5 @@
+-- Роли для docker-compose: postgres (суперпользователь) владеет схемой и выполняет фоновые задачи,
+-- order_app обслуживает запросы арендаторов под row-level security.
+-- Права групповой роли order_service_app выдает миграция 024
+CREATE ROLE order_service_app NOLOGIN NOBYPASSRLS;
+CREATE ROLE order_app LOGIN PASSWORD 'order_app' IN ROLE order_service_app;
@@ -0,0 +1,216 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "kafka-order-service/api/proto/orders/v1"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/usecase"
+)
+
+// parseUUID разбирает ID из запроса, ошибка - ошибка валидации
+func parseUUID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, entities.NewValidationError("invalid %s: %q", field, value)
+	}
+	return id, nil
+}
+
+// toCreateOrderRequest переводит запрос создания заказа в запрос use case
+func toCreateOrderRequest(req *ordersv1.CreateOrderRequest) (*usecase.CreateOrderRequest, error) {
+	result := &usecase.CreateOrderRequest{
+		Email:           req.GetEmail(),
+		Currency:        req.GetCurrency(),
+		CouponCodes:     req.GetCouponCodes(),
+		ShippingMethod:  entities.ShippingMethod(req.GetShippingMethod()),
+		ShippingAddress: toAddressRequest(req.GetShippingAddress()),
+		BillingAddress:  toAddressRequest(req.GetBillingAddress()),
+	}
+
+	if req.GetCustomerId() != "" {
+		customerID, err := parseUUID("customer_id", req.GetCustomerId())
+		if err != nil {
+			return nil, err
+		}
+		result.CustomerID = customerID
+	}
+
+	if len(req.GetMetadata()) > 0 {
+		result.Metadata = make(map[string]interface{}, len(req.GetMetadata()))
+		for key, value := range req.GetMetadata() {
+			result.Metadata[key] = value
+		}
+	}
+
+	for _, item := range req.GetItems() {
+		productID, err := parseUUID("product_id", item.GetProductId())
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, usecase.CreateOrderItemRequest{
+			ProductID:   productID,
+			Quantity:    int(item.GetQuantity()),
+			Name:        item.GetName(),
+			Price:       item.GetPrice(),
+			TaxCategory: item.GetTaxCategory(),
+			Weight:      item.GetWeight(),
+		})
+	}
+
+	return result, nil
+}
+
+// toListOrdersRequest переводит фильтры списка заказов; пустые поля не фильтруют
+func toListOrdersRequest(req *ordersv1.ListOrdersRequest) (*usecase.ListOrdersRequest, error) {
+	result := &usecase.ListOrdersRequest{
+		Limit:     int(req.GetLimit()),
+		Offset:    int(req.GetOffset()),
+		SortBy:    req.GetSortBy(),
+		SortOrder: req.GetSortOrder(),
+	}
+
+	if req.GetCustomerId() != "" {
+		customerID, err := parseUUID("customer_id", req.GetCustomerId())
+		if err != nil {
+			return nil, err
+		}
+		result.CustomerID = &customerID
+	}
+	if status := req.GetStatus(); status != "" {
+		orderStatus := entities.OrderStatus(status)
+		result.Status = &orderStatus
+	}
+	if email := req.GetEmail(); email != "" {
+		result.Email = &email
+	}
+	if currency := req.GetCurrency(); currency != "" {
+		currency = entities.NormalizeCurrency(currency)
+		result.Currency = &currency
+	}
+	if minAmount := req.GetMinAmount(); minAmount > 0 {
+		result.MinAmount = &minAmount
+	}
+	if maxAmount := req.GetMaxAmount(); maxAmount > 0 {
+		result.MaxAmount = &maxAmount
+	}
+	if dateFrom := req.GetDateFrom(); dateFrom != "" {
+		result.DateFrom = &dateFrom
+	}
+	if dateTo := req.GetDateTo(); dateTo != "" {
+		result.DateTo = &dateTo
+	}
+
+	return result, nil
+}
+
+// toAddressRequest переводит адрес запроса
+func toAddressRequest(address *ordersv1.Address) *usecase.CreateAddressRequest {
+	if address == nil {
+		return nil
+	}
+	return &usecase.CreateAddressRequest{
+		Street:  address.GetStreet(),
+		City:    address.GetCity(),
+		State:   address.GetState(),
+		Country: address.GetCountry(),
+		ZipCode: address.GetZipCode(),
+	}
+}
+
+// toProtoOrder переводит заказ в сообщение API
+func toProtoOrder(order *entities.Order) *ordersv1.Order {
+	if order == nil {
+		return nil
+	}
+
+	result := &ordersv1.Order{
+		Id:              order.ID.String(),
+		CustomerId:      order.CustomerID.String(),
+		Email:           order.Email,
+		Status:          string(order.Status),
+		Subtotal:        order.Subtotal,
+		DiscountAmount:  order.Discount,
+		TaxAmount:       order.TaxAmount,
+		ShippingAmount:  order.ShippingAmount,
+		TotalAmount:     order.TotalAmount,
+		Currency:        order.Currency,
+		ShippingAddress: toProtoAddress(order.ShippingAddress),
+		BillingAddress:  toProtoAddress(order.BillingAddress),
+		ShippingMethod:  string(order.ShippingMethod),
+		CreatedAt:       timestamppb.New(order.CreatedAt),
+		UpdatedAt:       timestamppb.New(order.UpdatedAt),
+	}
+
+	for _, item := range order.Items {
+		result.Items = append(result.Items, &ordersv1.OrderItem{
+			Id:             item.ID.String(),
+			ProductId:      item.ProductID.String(),
+			Name:           item.Name,
+			Price:          item.Price,
+			Quantity:       int32(item.Quantity),
+			Total:          item.Total,
+			DiscountAmount: item.DiscountAmount,
+			TaxAmount:      item.TaxAmount,
+		})
+	}
+
+	if len(order.Metadata) > 0 {
+		result.Metadata = make(map[string]string, len(order.Metadata))
+		for key, value := range order.Metadata {
+			if s, ok := value.(string); ok {
+				result.Metadata[key] = s
+				continue
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				encoded = []byte(fmt.Sprint(value))
+			}
+			result.Metadata[key] = string(encoded)
+		}
+	}
+
+	return result
+}
+
+// toProtoAddress переводит адрес в сообщение API
+func toProtoAddress(address *entities.Address) *ordersv1.Address {
+	if address == nil {
+		return nil
+	}
+	return &ordersv1.Address{
+		Street:  address.Street,
+		City:    address.City,
+		State:   address.State,
+		Country: address.Country,
+		ZipCode: address.ZipCode,
+	}
+}
+
+// toProtoEvent переводит событие заказа в сообщение API
+func toProtoEvent(event *entities.OrderEvent) (*ordersv1.OrderEvent, error) {
+	result := &ordersv1.OrderEvent{
+		EventId:     event.EventID.String(),
+		EventType:   event.EventType,
+		OrderId:     event.OrderID.String(),
+		CustomerId:  event.CustomerID.String(),
+		Status:      string(event.Status),
+		TotalAmount: event.TotalAmount,
+		Currency:    event.Currency,
+		Timestamp:   timestamppb.New(event.Timestamp),
+	}
+
+	if len(event.Data) > 0 {
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event data: %w", err)
+		}
+		result.DataJson = string(data)
+	}
+
+	return result, nil
+}
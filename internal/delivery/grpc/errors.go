@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// statusFromError подбирает gRPC статус по типу доменной ошибки
+func statusFromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var validationErr entities.ValidationError
+	var transitionErr entities.InvalidStatusTransitionError
+	var guardErr entities.TransitionGuardError
+	var notFoundErr entities.OrderNotFoundError
+	var returnNotFoundErr entities.ReturnNotFoundError
+	var stockErr entities.InsufficientStockError
+	var productNotFoundErr entities.ProductNotFoundError
+	var customerNotFoundErr entities.CustomerNotFoundError
+	var webhookNotFoundErr entities.WebhookNotFoundError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &transitionErr), errors.As(err, &guardErr), errors.As(err, &stockErr):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &notFoundErr), errors.As(err, &returnNotFoundErr), errors.As(err, &productNotFoundErr),
+		errors.As(err, &customerNotFoundErr), errors.As(err, &webhookNotFoundErr):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,264 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/pkg/logger"
+)
+
+// requestIDHeader - ключ метаданных с ID запроса, как X-Request-ID в HTTP API
+const requestIDHeader = "x-request-id"
+
+// Ключи метаданных арендатора: API ключ (как X-API-Key в HTTP API) или JWT.
+// authorization занят токенами доступа к gRPC API
+const (
+	apiKeyHeader      = "x-api-key"
+	tenantTokenHeader = "x-tenant-token"
+)
+
+// RequestIDKey - ключ ID запроса в контексте
+type RequestIDKey struct{}
+
+// RequestIDFromContext возвращает ID запроса из контекста
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDKey{}).(string)
+	return requestID
+}
+
+// NewServer создает gRPC сервер с цепочкой interceptors: request ID, логирование,
+// восстановление после паники, аутентификация по токенам и определение арендатора
+func NewServer(log *logger.Logger, authTokens []string, tenants *entities.TenantDirectory, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(
+			UnaryRequestID(),
+			UnaryLogging(log),
+			UnaryRecovery(log),
+			UnaryAuth(authTokens),
+			UnaryTenant(tenants),
+		),
+		grpc.ChainStreamInterceptor(
+			StreamRequestID(),
+			StreamLogging(log),
+			StreamRecovery(log),
+			StreamAuth(authTokens),
+			StreamTenant(tenants),
+		),
+	)
+	return grpc.NewServer(opts...)
+}
+
+// UnaryRequestID берет ID запроса из метаданных или создает новый и возвращает его в заголовке ответа
+func UnaryRequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withRequestID(ctx), req)
+	}
+}
+
+// StreamRequestID берет ID запроса из метаданных или создает новый и возвращает его в заголовке ответа
+func StreamRequestID() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+	}
+}
+
+// withRequestID кладет ID запроса в контекст и в заголовок ответа
+func withRequestID(ctx context.Context) context.Context {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
+	// Ошибка возможна только вне gRPC вызова, например в тестах
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+
+	return context.WithValue(ctx, RequestIDKey{}, requestID)
+}
+
+// UnaryLogging логирует gRPC вызовы
+func UnaryLogging(log *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(log, ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamLogging логирует потоковые gRPC вызовы
+func StreamLogging(log *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		log.Info("gRPC stream started", "method", info.FullMethod, "request_id", RequestIDFromContext(ss.Context()))
+		err := handler(srv, ss)
+		logCall(log, ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+// logCall пишет итог вызова; внутренние ошибки логируются как ошибки
+func logCall(log *logger.Logger, ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []interface{}{
+		"method", method,
+		"code", code.String(),
+		"duration_ms", time.Since(start).Milliseconds(),
+		"request_id", RequestIDFromContext(ctx),
+	}
+
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		log.Error("gRPC request failed", append(fields, "error", err)...)
+	default:
+		log.Info("gRPC request completed", fields...)
+	}
+}
+
+// UnaryRecovery восстанавливается после паники в обработчике
+func UnaryRecovery(log *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("Panic recovered", "method", info.FullMethod, "error", fmt.Sprintf("%v", r), "request_id", RequestIDFromContext(ctx))
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecovery восстанавливается после паники в потоковом обработчике
+func StreamRecovery(log *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("Panic recovered", "method", info.FullMethod, "error", fmt.Sprintf("%v", r), "request_id", RequestIDFromContext(ss.Context()))
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// UnaryAuth проверяет токен из метаданных "authorization: Bearer <token>".
+// Пустой список токенов отключает проверку.
+func UnaryAuth(tokens []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, tokens); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth проверяет токен из метаданных "authorization: Bearer <token>".
+// Пустой список токенов отключает проверку.
+func StreamAuth(tokens []string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), tokens); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authorize сравнивает токен запроса с разрешенными за постоянное время
+func authorize(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+
+	token, found := strings.CutPrefix(values[0], "Bearer ")
+	if !found || token == "" {
+		return status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	valid := 0
+	for _, allowed := range tokens {
+		valid |= subtle.ConstantTimeCompare([]byte(token), []byte(allowed))
+	}
+	if valid != 1 {
+		return status.Error(codes.Unauthenticated, "invalid authorization token")
+	}
+
+	return nil
+}
+
+// UnaryTenant определяет арендатора по метаданным "x-api-key" или "x-tenant-token" (JWT)
+func UnaryTenant(tenants *entities.TenantDirectory) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := withTenant(ctx, tenants)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamTenant определяет арендатора по метаданным "x-api-key" или "x-tenant-token" (JWT)
+func StreamTenant(tenants *entities.TenantDirectory) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := withTenant(ss.Context(), tenants)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withTenant кладет в контекст арендатора запроса и клиента, которому выдан токен
+func withTenant(ctx context.Context, tenants *entities.TenantDirectory) (context.Context, error) {
+	var apiKey, token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(apiKeyHeader); len(values) > 0 {
+			apiKey = values[0]
+		}
+		if values := md.Get(tenantTokenHeader); len(values) > 0 {
+			token = values[0]
+		}
+	}
+
+	tenant, err := tenants.Authenticate(apiKey, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "api key or tenant token is missing or invalid")
+	}
+	ctx = entities.ContextWithTenant(ctx, tenant)
+	if customerID, ok := tenants.AuthenticateCustomer(apiKey, token); ok {
+		ctx = entities.ContextWithCustomerID(ctx, customerID)
+	}
+	return ctx, nil
+}
+
+// contextStream подменяет контекст потока
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
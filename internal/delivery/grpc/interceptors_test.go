@@ -0,0 +1,144 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	ordersv1 "kafka-order-service/api/proto/orders/v1"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/pkg/logger"
+)
+
+// stubOrderServer отвечает на GetOrder ошибкой "заказ не найден" и запоминает ID запроса
+type stubOrderServer struct {
+	ordersv1.UnimplementedOrderServiceServer
+	requestID string
+	tenantID  string
+}
+
+func (s *stubOrderServer) GetOrder(ctx context.Context, req *ordersv1.GetOrderRequest) (*ordersv1.GetOrderResponse, error) {
+	s.requestID = RequestIDFromContext(ctx)
+	s.tenantID = entities.TenantIDFromContext(ctx)
+	return nil, statusFromError(entities.NewOrderNotFoundError(req.GetOrderId()))
+}
+
+func startTestServer(t *testing.T, tokens []string, tenants *entities.TenantDirectory) (ordersv1.OrderServiceClient, *stubOrderServer) {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	stub := &stubOrderServer{}
+	server := NewServer(logger.NewNoOp(), tokens, tenants)
+	ordersv1.RegisterOrderServiceServer(server, stub)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial test server: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return ordersv1.NewOrderServiceClient(conn), stub
+}
+
+func TestAuth_RejectsMissingAndInvalidTokens(t *testing.T) {
+	client, _ := startTestServer(t, []string{"secret"}, nil)
+
+	_, err := client.GetOrder(context.Background(), &ordersv1.GetOrderRequest{OrderId: "42"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without token, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	_, err = client.GetOrder(ctx, &ordersv1.GetOrderRequest{OrderId: "42"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated with invalid token, got %v", err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	_, err = client.GetOrder(ctx, &ordersv1.GetOrderRequest{OrderId: "42"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected domain error to map to NotFound, got %v", err)
+	}
+}
+
+func TestRequestID_PropagatedAndReturned(t *testing.T) {
+	client, stub := startTestServer(t, nil, nil)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), requestIDHeader, "req-123")
+	_, _ = client.GetOrder(ctx, &ordersv1.GetOrderRequest{OrderId: "42"}, grpc.Header(&header))
+
+	if stub.requestID != "req-123" {
+		t.Errorf("Expected request ID in handler context, got %q", stub.requestID)
+	}
+	if values := header.Get(requestIDHeader); len(values) != 1 || values[0] != "req-123" {
+		t.Errorf("Expected request ID in response header, got %v", values)
+	}
+
+	header = nil
+	_, _ = client.GetOrder(context.Background(), &ordersv1.GetOrderRequest{OrderId: "42"}, grpc.Header(&header))
+	if values := header.Get(requestIDHeader); len(values) != 1 || values[0] == "" {
+		t.Errorf("Expected generated request ID in response header, got %v", values)
+	}
+}
+
+func TestStatusFromError(t *testing.T) {
+	cases := []struct {
+		err  error
+		code codes.Code
+	}{
+		{entities.NewValidationError("bad"), codes.InvalidArgument},
+		{entities.NewOrderNotFoundError("42"), codes.NotFound},
+		{entities.NewInvalidStatusTransitionError(entities.OrderStatusDelivered, entities.OrderStatusPending), codes.FailedPrecondition},
+		{entities.NewInsufficientStockError("p", 2, 1), codes.FailedPrecondition},
+		{context.Canceled, codes.Canceled},
+		{net.ErrClosed, codes.Internal},
+	}
+
+	for _, tc := range cases {
+		if code := status.Code(statusFromError(tc.err)); code != tc.code {
+			t.Errorf("statusFromError(%v) = %v, want %v", tc.err, code, tc.code)
+		}
+	}
+}
+
+func TestTenant_ResolvedFromAPIKey(t *testing.T) {
+	sum := sha256.Sum256([]byte("acme-key"))
+	tenants, err := entities.NewTenantDirectory([]*entities.Tenant{
+		{ID: "acme", APIKeyHashes: []string{hex.EncodeToString(sum[:])}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tenant directory: %v", err)
+	}
+	client, stub := startTestServer(t, nil, tenants)
+
+	_, err = client.GetOrder(context.Background(), &ordersv1.GetOrderRequest{OrderId: "42"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without api key, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyHeader, "acme-key")
+	_, err = client.GetOrder(ctx, &ordersv1.GetOrderRequest{OrderId: "42"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected request to reach the handler, got %v", err)
+	}
+	if stub.tenantID != "acme" {
+		t.Errorf("Expected tenant acme in handler context, got %q", stub.tenantID)
+	}
+}
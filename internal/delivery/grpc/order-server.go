@@ -0,0 +1,205 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ordersv1 "kafka-order-service/api/proto/orders/v1"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// OrderServer реализует gRPC сервис заказов поверх тех же use cases, что и HTTP API
+type OrderServer struct {
+	ordersv1.UnimplementedOrderServiceServer
+
+	createOrderUC  *usecase.CreateOrderUseCase
+	updateStatusUC *usecase.UpdateOrderStatusUseCase
+	getOrderUC     *usecase.GetOrderUseCase
+	listOrdersUC   *usecase.ListOrdersUseCase
+	broker         *usecase.OrderEventBroker
+	logger         *logger.Logger
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewOrderServer создает новый gRPC сервис заказов.
+// broker может быть nil: тогда WatchOrder недоступен.
+func NewOrderServer(
+	createOrderUC *usecase.CreateOrderUseCase,
+	updateStatusUC *usecase.UpdateOrderStatusUseCase,
+	getOrderUC *usecase.GetOrderUseCase,
+	listOrdersUC *usecase.ListOrdersUseCase,
+	broker *usecase.OrderEventBroker,
+	logger *logger.Logger,
+) *OrderServer {
+	return &OrderServer{
+		createOrderUC:  createOrderUC,
+		updateStatusUC: updateStatusUC,
+		getOrderUC:     getOrderUC,
+		listOrdersUC:   listOrdersUC,
+		broker:         broker,
+		logger:         logger,
+		done:           make(chan struct{}),
+	}
+}
+
+// Close завершает открытые потоки WatchOrder, чтобы GracefulStop их не дожидался
+func (s *OrderServer) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// CreateOrder создает новый заказ
+func (s *OrderServer) CreateOrder(ctx context.Context, req *ordersv1.CreateOrderRequest) (*ordersv1.CreateOrderResponse, error) {
+	createReq, err := toCreateOrderRequest(req)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	// Чтения при изменении заказа идут в основную БД
+	ctx = repositories.WithPrimaryReads(ctx)
+	resp, err := s.createOrderUC.Execute(ctx, createReq)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &ordersv1.CreateOrderResponse{Order: toProtoOrder(resp.Order)}, nil
+}
+
+// GetOrder возвращает заказ по ID
+func (s *OrderServer) GetOrder(ctx context.Context, req *ordersv1.GetOrderRequest) (*ordersv1.GetOrderResponse, error) {
+	orderID, err := parseUUID("order_id", req.GetOrderId())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	resp, err := s.getOrderUC.Execute(ctx, &usecase.GetOrderRequest{OrderID: orderID})
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &ordersv1.GetOrderResponse{Order: toProtoOrder(resp.Order)}, nil
+}
+
+// ListOrders возвращает список заказов с фильтрацией и пагинацией
+func (s *OrderServer) ListOrders(ctx context.Context, req *ordersv1.ListOrdersRequest) (*ordersv1.ListOrdersResponse, error) {
+	listReq, err := toListOrdersRequest(req)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	resp, err := s.listOrdersUC.Execute(ctx, listReq)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	result := &ordersv1.ListOrdersResponse{
+		TotalCount: resp.TotalCount,
+		Limit:      int32(resp.Limit),
+		Offset:     int32(resp.Offset),
+	}
+	for _, order := range resp.Orders {
+		result.Orders = append(result.Orders, toProtoOrder(order))
+	}
+
+	return result, nil
+}
+
+// UpdateOrderStatus меняет статус заказа
+func (s *OrderServer) UpdateOrderStatus(ctx context.Context, req *ordersv1.UpdateOrderStatusRequest) (*ordersv1.UpdateOrderStatusResponse, error) {
+	orderID, err := parseUUID("order_id", req.GetOrderId())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	// Чтения при изменении заказа идут в основную БД
+	ctx = repositories.WithPrimaryReads(ctx)
+	resp, err := s.updateStatusUC.Execute(ctx, &usecase.UpdateOrderStatusRequest{
+		OrderID:   orderID,
+		NewStatus: entities.OrderStatus(req.GetNewStatus()),
+		Reason:    req.GetReason(),
+	})
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &ordersv1.UpdateOrderStatusResponse{
+		Order:     toProtoOrder(resp.Order),
+		OldStatus: string(resp.OldStatus),
+		NewStatus: string(resp.NewStatus),
+	}, nil
+}
+
+// WatchOrder отдает поток событий заказа.
+// last_event_id - EventID последнего полученного события, события после него
+// отправляются из истории брокера до новых.
+func (s *OrderServer) WatchOrder(req *ordersv1.WatchOrderRequest, stream ordersv1.OrderService_WatchOrderServer) error {
+	if s.broker == nil {
+		return status.Error(codes.Unavailable, "order event stream is disabled")
+	}
+
+	orderID, err := parseUUID("order_id", req.GetOrderId())
+	if err != nil {
+		return statusFromError(err)
+	}
+
+	var lastEventID uuid.UUID
+	if req.GetLastEventId() != "" {
+		lastEventID, err = parseUUID("last_event_id", req.GetLastEventId())
+		if err != nil {
+			return statusFromError(err)
+		}
+	}
+
+	ctx := stream.Context()
+	if _, err := s.getOrderUC.Execute(ctx, &usecase.GetOrderRequest{OrderID: orderID}); err != nil {
+		return statusFromError(err)
+	}
+
+	subscription := s.broker.Subscribe(usecase.OrderStreamFilter{TenantID: entities.TenantIDFromContext(ctx), OrderID: orderID}, lastEventID)
+	defer subscription.Close()
+
+	if subscription.Reset {
+		// Событий после last_event_id уже нет в истории: клиент перечитывает заказ через GetOrder
+		return status.Error(codes.OutOfRange, "last_event_id is no longer available, reload the order")
+	}
+	for _, event := range subscription.Replay {
+		if err := s.sendEvent(stream, event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case event, ok := <-subscription.Events:
+			if !ok {
+				// Брокер отключил отстающего клиента, он переподключится с last_event_id
+				return status.Error(codes.Unavailable, "order event stream overflowed, reconnect with last_event_id")
+			}
+			if err := s.sendEvent(stream, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendEvent отправляет событие заказа в поток
+func (s *OrderServer) sendEvent(stream ordersv1.OrderService_WatchOrderServer, event *entities.OrderEvent) error {
+	message, err := toProtoEvent(event)
+	if err != nil {
+		s.logger.Error("Failed to convert order event", "event_id", event.EventID, "error", err)
+		return status.Error(codes.Internal, err.Error())
+	}
+	return stream.Send(message)
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// CustomerHandler обрабатывает HTTP запросы для клиентов
+type CustomerHandler struct {
+	createCustomerUC     *usecase.CreateCustomerUseCase
+	getCustomerUC        *usecase.GetCustomerUseCase
+	listCustomerOrdersUC *usecase.ListCustomerOrdersUseCase
+	eraseCustomerUC      *usecase.EraseCustomerUseCase
+	exportCustomerDataUC *usecase.ExportCustomerDataUseCase
+	logger               *logger.Logger
+}
+
+// NewCustomerHandler создает новый handler для клиентов
+func NewCustomerHandler(
+	createCustomerUC *usecase.CreateCustomerUseCase,
+	getCustomerUC *usecase.GetCustomerUseCase,
+	listCustomerOrdersUC *usecase.ListCustomerOrdersUseCase,
+	eraseCustomerUC *usecase.EraseCustomerUseCase,
+	exportCustomerDataUC *usecase.ExportCustomerDataUseCase,
+	logger *logger.Logger,
+) *CustomerHandler {
+	return &CustomerHandler{
+		createCustomerUC:     createCustomerUC,
+		getCustomerUC:        getCustomerUC,
+		listCustomerOrdersUC: listCustomerOrdersUC,
+		eraseCustomerUC:      eraseCustomerUC,
+		exportCustomerDataUC: exportCustomerDataUC,
+		logger:               logger,
+	}
+}
+
+// CreateCustomer создает клиента
+// POST /api/v1/customers
+func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	var req usecase.CreateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode create customer request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	response, err := h.createCustomerUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to create customer", "error", err)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to create customer", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusCreated, response)
+}
+
+// GetCustomer возвращает клиента со статистикой заказов
+// GET /api/v1/customers/{id}
+func (h *CustomerHandler) GetCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := h.parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.getCustomerUC.Execute(r.Context(), customerID)
+	if err != nil {
+		h.logger.Error("Failed to get customer", "error", err, "customer_id", customerID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to get customer", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// ListCustomerOrders возвращает заказы клиента
+// GET /api/v1/customers/{id}/orders?limit=20&offset=0
+func (h *CustomerHandler) ListCustomerOrders(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := h.parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	req := &usecase.ListCustomerOrdersRequest{CustomerID: customerID}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 {
+		req.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		req.Offset = offset
+	}
+
+	response, err := h.listCustomerOrdersUC.Execute(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to list customer orders", "error", err, "customer_id", customerID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to list customer orders", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// EraseCustomer обезличивает персональные данные клиента
+// POST /api/v1/customers/{id}/erase
+func (h *CustomerHandler) EraseCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := h.parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	// Тело с причиной необязательно
+	req := usecase.EraseCustomerRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to decode erase customer request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	req.CustomerID = customerID
+
+	response, err := h.eraseCustomerUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to erase customer data", "error", err, "customer_id", customerID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to erase customer data", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// ExportCustomerData отдает все данные клиента и его заказы одним JSON файлом
+// GET /api/v1/customers/{id}/data-export
+func (h *CustomerHandler) ExportCustomerData(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := h.parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	export, err := h.exportCustomerDataUC.Execute(r.Context(), customerID)
+	if err != nil {
+		h.logger.Error("Failed to export customer data", "error", err, "customer_id", customerID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to export customer data", err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="customer-%s.json"`, customerID))
+	writeJSONResponse(w, h.logger, http.StatusOK, export)
+}
+
+// parseCustomerID извлекает ID клиента из пути, при ошибке пишет ответ 400
+func (h *CustomerHandler) parseCustomerID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	customerIDStr := mux.Vars(r)["id"]
+	customerID, err := uuid.Parse(customerIDStr)
+	if err != nil {
+		h.logger.Error("Invalid customer ID format", "customer_id", customerIDStr, "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid customer ID format", err)
+		return uuid.Nil, false
+	}
+	return customerID, true
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed docs
+var docsAssets embed.FS
+
+// DocsHandler отдает спецификацию OpenAPI и страницу интерактивной документации
+type DocsHandler struct {
+	spec   []byte
+	assets http.Handler
+}
+
+// NewDocsHandler создает новый handler документации API
+func NewDocsHandler(doc *openapi3.T) (*DocsHandler, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal openapi spec: %w", err)
+	}
+
+	assets, err := fs.Sub(docsAssets, "docs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docs assets: %w", err)
+	}
+
+	return &DocsHandler{
+		spec:   spec,
+		assets: http.StripPrefix("/api/v1/docs/", http.FileServer(http.FS(assets))),
+	}, nil
+}
+
+// OpenAPISpec отдает спецификацию API
+// GET /api/v1/openapi.json
+func (h *DocsHandler) OpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(h.spec)
+}
+
+// Docs отдает страницу документации и ее статику
+// GET /api/v1/docs/
+func (h *DocsHandler) Docs(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v1/docs" {
+		http.Redirect(w, r, "/api/v1/docs/", http.StatusMovedPermanently)
+		return
+	}
+	h.assets.ServeHTTP(w, r)
+}
@@ -0,0 +1,169 @@
+// Интерактивная документация: рендерит openapi.json и отправляет запросы к API
+(function () {
+  'use strict';
+
+  var spec;
+
+  function el(tag, attrs, children) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (key) {
+      if (key === 'text') node.textContent = attrs[key];
+      else node.setAttribute(key, attrs[key]);
+    });
+    (children || []).forEach(function (child) { if (child) node.appendChild(child); });
+    return node;
+  }
+
+  function resolve(obj) {
+    var seen = 0;
+    while (obj && obj.$ref && seen++ < 16) {
+      obj = obj.$ref.replace(/^#\//, '').split('/').reduce(function (acc, part) { return acc[part]; }, spec);
+    }
+    return obj;
+  }
+
+  // example строит пример значения по схеме
+  function example(schema, depth) {
+    schema = resolve(schema) || {};
+    if (depth > 6) return null;
+    if (schema.example !== undefined) return schema.example;
+    if (schema.enum) return schema.enum[0];
+    if (schema.allOf) {
+      return schema.allOf.reduce(function (acc, part) { return Object.assign(acc, example(part, depth + 1)); }, {});
+    }
+    switch (schema.type) {
+      case 'object':
+        var result = {};
+        Object.keys(schema.properties || {}).forEach(function (name) {
+          var required = (schema.required || []).indexOf(name) >= 0;
+          if (required || depth < 1) result[name] = example(schema.properties[name], depth + 1);
+        });
+        return result;
+      case 'array': return [example(schema.items, depth + 1)];
+      case 'integer': return schema.minimum || 0;
+      case 'number': return schema.minimum || 0;
+      case 'boolean': return true;
+      default:
+        if (schema.format === 'uuid') return '00000000-0000-0000-0000-000000000000';
+        if (schema.format === 'email') return 'customer@example.com';
+        if (schema.format === 'uri') return 'https://example.com/hook';
+        if (schema.format === 'date-time') return new Date().toISOString();
+        return '';
+    }
+  }
+
+  function parametersOf(pathItem, operation) {
+    return (pathItem.parameters || []).concat(operation.parameters || []).map(resolve);
+  }
+
+  function renderOperation(path, method, pathItem, operation) {
+    var params = parametersOf(pathItem, operation);
+    var inputs = {};
+
+    var rows = params.map(function (param) {
+      var schema = resolve(param.schema) || {};
+      var input = el('input', { placeholder: schema.format || schema.type || '' });
+      inputs[param.name] = { param: param, input: input };
+      return el('tr', {}, [
+        el('td', { text: param.name + (param.required ? ' *' : '') }),
+        el('td', { text: param.in }),
+        el('td', { text: (schema.type || '') + (schema.enum ? ' [' + schema.enum.join(', ') + ']' : '') }),
+        el('td', {}, [input])
+      ]);
+    });
+
+    var bodyInput = null;
+    var requestBody = resolve(operation.requestBody);
+    if (requestBody && requestBody.content && requestBody.content['application/json']) {
+      bodyInput = el('textarea');
+      bodyInput.value = JSON.stringify(example(requestBody.content['application/json'].schema, 0), null, 2);
+    }
+
+    var output = el('pre', { text: '' });
+    output.style.display = 'none';
+
+    var send = el('button', { type: 'button', text: 'Отправить' });
+    send.addEventListener('click', function () {
+      var url = path;
+      var query = [];
+      Object.keys(inputs).forEach(function (name) {
+        var value = inputs[name].input.value;
+        if (inputs[name].param.in === 'path') url = url.replace('{' + name + '}', encodeURIComponent(value));
+        else if (inputs[name].param.in === 'query' && value !== '') query.push(encodeURIComponent(name) + '=' + encodeURIComponent(value));
+      });
+      var base = (spec.servers && spec.servers[0] && spec.servers[0].url) || '';
+      var options = { method: method.toUpperCase(), headers: {} };
+      if (bodyInput) {
+        options.headers['Content-Type'] = 'application/json';
+        options.body = bodyInput.value;
+      }
+      output.style.display = 'block';
+      output.className = '';
+      output.textContent = '…';
+      fetch(base + url + (query.length ? '?' + query.join('&') : ''), options).then(function (response) {
+        return response.text().then(function (text) {
+          try { text = JSON.stringify(JSON.parse(text), null, 2); } catch (e) { /* не JSON */ }
+          output.className = response.ok ? 'status-ok' : 'status-error';
+          output.textContent = response.status + ' ' + response.statusText + '\n\n' + text;
+        });
+      }).catch(function (err) {
+        output.className = 'status-error';
+        output.textContent = String(err);
+      });
+    });
+
+    var responses = Object.keys(operation.responses || {}).map(function (code) {
+      var response = resolve(operation.responses[code]);
+      return el('tr', {}, [el('td', { text: code }), el('td', { text: response.description || '' })]);
+    });
+
+    var body = el('div', { class: 'body' }, [
+      operation.description ? el('p', { text: operation.description }) : null,
+      rows.length ? el('table', {}, [el('tr', {}, [el('th', { text: 'Параметр' }), el('th', { text: 'Где' }), el('th', { text: 'Тип' }), el('th', { text: 'Значение' })])].concat(rows)) : null,
+      bodyInput ? el('p', { text: 'Тело запроса' }) : null,
+      bodyInput,
+      send,
+      output,
+      el('table', {}, [el('tr', {}, [el('th', { text: 'Код' }), el('th', { text: 'Ответ' })])].concat(responses))
+    ]);
+
+    return el('details', { class: 'op' }, [
+      el('summary', {}, [
+        el('span', { class: 'method ' + method, text: method.toUpperCase() }),
+        el('span', { class: 'path', text: path }),
+        el('span', { class: 'muted', text: operation.summary || '' })
+      ]),
+      body
+    ]);
+  }
+
+  function render() {
+    document.getElementById('title').textContent = spec.info.title + ' ' + spec.info.version;
+    document.getElementById('description').textContent = spec.info.description || '';
+
+    var groups = {};
+    Object.keys(spec.paths).forEach(function (path) {
+      var pathItem = spec.paths[path];
+      ['get', 'post', 'put', 'patch', 'delete'].forEach(function (method) {
+        var operation = pathItem[method];
+        if (!operation) return;
+        var tag = (operation.tags && operation.tags[0]) || 'default';
+        (groups[tag] = groups[tag] || []).push(renderOperation(path, method, pathItem, operation));
+      });
+    });
+
+    var container = document.getElementById('operations');
+    container.textContent = '';
+    Object.keys(groups).forEach(function (tag) {
+      container.appendChild(el('h2', { text: tag }));
+      groups[tag].forEach(function (node) { container.appendChild(node); });
+    });
+  }
+
+  fetch('../openapi.json').then(function (response) { return response.json(); }).then(function (data) {
+    spec = data;
+    render();
+  }).catch(function (err) {
+    document.getElementById('operations').textContent = 'Не удалось загрузить спецификацию: ' + err;
+  });
+})();
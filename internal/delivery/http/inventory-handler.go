@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// InventoryHandler обрабатывает административные HTTP запросы складских остатков
+type InventoryHandler struct {
+	getStockUC    *usecase.GetStockUseCase
+	adjustStockUC *usecase.AdjustStockUseCase
+	logger        *logger.Logger
+}
+
+// NewInventoryHandler создает новый handler для складских остатков
+func NewInventoryHandler(
+	getStockUC *usecase.GetStockUseCase,
+	adjustStockUC *usecase.AdjustStockUseCase,
+	logger *logger.Logger,
+) *InventoryHandler {
+	return &InventoryHandler{
+		getStockUC:    getStockUC,
+		adjustStockUC: adjustStockUC,
+		logger:        logger,
+	}
+}
+
+// GetStock возвращает остатки товара по складам
+// GET /api/v1/inventory/{product_id}
+func (h *InventoryHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+	productID, ok := h.parseProductID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.getStockUC.Execute(r.Context(), &usecase.GetStockRequest{ProductID: productID})
+	if err != nil {
+		h.logger.Error("Failed to get stock", "error", err, "product_id", productID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to get stock", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// AdjustStock изменяет остаток товара на складе (приемка, списание, инвентаризация)
+// POST /api/v1/inventory/{product_id}/adjust
+func (h *InventoryHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	productID, ok := h.parseProductID(w, r)
+	if !ok {
+		return
+	}
+
+	var req usecase.AdjustStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode adjust stock request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	req.ProductID = productID
+
+	response, err := h.adjustStockUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to adjust stock", "error", err, "product_id", productID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to adjust stock", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// parseProductID извлекает ID товара из пути, при ошибке пишет ответ 400
+func (h *InventoryHandler) parseProductID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	productIDStr := mux.Vars(r)["product_id"]
+	productID, err := uuid.Parse(productIDStr)
+	if err != nil {
+		h.logger.Error("Invalid product ID format", "product_id", productIDStr, "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid product ID format", err)
+		return uuid.Nil, false
+	}
+	return productID, true
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
+	"kafka-order-service/internal/domain/repositories"
 	"kafka-order-service/pkg/logger"
 
 	"github.com/google/uuid"
@@ -44,6 +46,10 @@ func Recovery(log *logger.Logger) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
+					if err == http.ErrAbortHandler {
+						// deliberate abort of a streaming response, let net/http drop the connection
+						panic(err)
+					}
 					reqID, _ := r.Context().Value(RequestIDKey{}).(string)
 					log.Error("Panic recovered", "error", fmt.Sprintf("%v", err), "request_id", reqID)
 					w.Header().Set("Content-Type", "application/json")
@@ -61,8 +67,8 @@ func CORS() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, X-API-Key")
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
 				return
@@ -72,10 +78,18 @@ func CORS() func(http.Handler) http.Handler {
 	}
 }
 
-// Timeout sets a timeout for requests
-func Timeout(duration time.Duration) func(http.Handler) http.Handler {
+// Timeout sets a timeout for requests. Server-Sent Events streams and the given streaming paths
+// (large downloads) are long-lived and are not limited.
+func Timeout(duration time.Duration, streamingPaths ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
-		return http.TimeoutHandler(next, duration, "request timeout")
+		limited := http.TimeoutHandler(next, duration, "request timeout")
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") == "text/event-stream" || slices.Contains(streamingPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			limited.ServeHTTP(w, r)
+		})
 	}
 }
 
@@ -108,6 +122,21 @@ func Metrics(log *logger.Logger) func(http.Handler) http.Handler {
 	}
 }
 
+// PrimaryReads sends reads of mutating requests to the primary database so that the
+// read before a change never sees a lagging replica
+func PrimaryReads() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+			default:
+				r = r.WithContext(repositories.WithPrimaryReads(r.Context()))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // Chain combines multiple middleware
 func Chain(mw ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
 	return func(final http.Handler) http.Handler {
@@ -145,3 +174,15 @@ func (rw *responseWrapper) WriteHeader(status int) {
 	rw.statusCode = status
 	rw.ResponseWriter.WriteHeader(status)
 }
+
+// Flush lets streaming handlers flush through the wrapper
+func (rw *responseWrapper) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWrapper) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// APIKeyHeader - header with the tenant API key
+const APIKeyHeader = "X-API-Key"
+
+// Tenant resolves the tenant of a request from the X-API-Key header or an
+// "Authorization: Bearer <jwt>" token and puts it into the request context together
+// with the customer the token was issued to, if any.
+// Public paths (and everything below them) are served without credentials.
+func Tenant(directory *entities.TenantDirectory, publicPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPublicPath(r.URL.Path, publicPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			apiKey := r.Header.Get(APIKeyHeader)
+			tenant, err := directory.Authenticate(apiKey, token)
+			if err != nil {
+				reqID, _ := r.Context().Value(RequestIDKey{}).(string)
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("WWW-Authenticate", `Bearer realm="orders"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprintf(w, `{"error":"Unauthorized","message":"API key or tenant token is missing or invalid","request_id":"%s"}`, reqID)
+				return
+			}
+
+			ctx := entities.ContextWithTenant(r.Context(), tenant)
+			// A token issued to a customer also identifies that customer
+			if customerID, ok := directory.AuthenticateCustomer(apiKey, token); ok {
+				ctx = entities.ContextWithCustomerID(ctx, customerID)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func isPublicPath(path string, publicPaths []string) bool {
+	for _, public := range publicPaths {
+		if path == public || strings.HasPrefix(path, public+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+func TestTenant(t *testing.T) {
+	sum := sha256.Sum256([]byte("acme-key"))
+	directory, err := entities.NewTenantDirectory([]*entities.Tenant{
+		{ID: "acme", APIKeyHashes: []string{hex.EncodeToString(sum[:])}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	var tenantID string
+	handler := Tenant(directory, "/api/v1/docs")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID = entities.TenantIDFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	testCases := []struct {
+		name       string
+		path       string
+		apiKey     string
+		wantStatus int
+		wantTenant string
+	}{
+		{"valid api key", "/api/v1/orders", "acme-key", http.StatusNoContent, "acme"},
+		{"invalid api key", "/api/v1/orders", "other-key", http.StatusUnauthorized, ""},
+		{"missing credentials", "/api/v1/orders", "", http.StatusUnauthorized, ""},
+		{"public path", "/api/v1/docs/index.css", "", http.StatusNoContent, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tenantID = ""
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tc.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tenantID != tc.wantTenant {
+				t.Errorf("Expected tenant %q, got %q", tc.wantTenant, tenantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/google/uuid"
+)
+
+// FieldError ошибка проверки одного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+var defineFormatsOnce sync.Once
+
+// defineFormats включает проверку форматов, которые kin-openapi по умолчанию пропускает
+func defineFormats() {
+	defineFormatsOnce.Do(func() {
+		openapi3.DefineStringFormatValidator("email", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForEmail))
+		openapi3.DefineStringFormatValidator("uuid", openapi3.NewCallbackValidator(func(value string) error {
+			if _, err := uuid.Parse(value); err != nil {
+				return errors.New("must be a valid UUID")
+			}
+			return nil
+		}))
+		openapi3.DefineStringFormatValidator("uri", openapi3.NewCallbackValidator(func(value string) error {
+			parsed, err := url.ParseRequestURI(value)
+			if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+				return errors.New("must be an absolute URL")
+			}
+			return nil
+		}))
+	})
+}
+
+// ValidateRequests проверяет запросы по спецификации OpenAPI до обработчиков.
+// Запросы к путям, которых нет в спецификации, пропускаются без проверки.
+func ValidateRequests(doc *openapi3.T) (func(http.Handler) http.Handler, error) {
+	defineFormats()
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build openapi router: %w", err)
+	}
+
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				// 404 и 405 возвращает основной роутер
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			})
+			if err != nil {
+				writeValidationError(w, fieldErrors(err, ""))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// writeValidationError пишет ответ 400 со списком ошибок по полям
+func writeValidationError(w http.ResponseWriter, fields []FieldError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":     "Request validation failed",
+		"fields":    fields,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// missingPropertyRe сообщение об отсутствующем обязательном свойстве; путь уже указывает на свойство
+var missingPropertyRe = regexp.MustCompile(`^property "[^"]+" is missing$`)
+
+// fieldErrors раскладывает ошибку kin-openapi на ошибки по полям вида items[0].quantity
+func fieldErrors(err error, field string) []FieldError {
+	// Ошибки разбираются по точному типу: errors.As пропустил бы RequestError с именем параметра
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var result []FieldError
+		for _, inner := range e {
+			result = append(result, fieldErrors(inner, field)...)
+		}
+		return result
+	case *openapi3filter.RequestError:
+		if e.Parameter != nil {
+			field = e.Parameter.Name
+		}
+		if e.Err == nil {
+			return []FieldError{{Field: fieldOrBody(field), Message: e.Reason}}
+		}
+		return fieldErrors(e.Err, field)
+	case *openapi3.SchemaError:
+		message := e.Reason
+		switch {
+		case e.SchemaField == "format" && e.Schema != nil:
+			message = "must be a valid " + e.Schema.Format
+		case missingPropertyRe.MatchString(message):
+			message = "is required"
+		case message == "":
+			message = fmt.Sprintf("does not match %q constraint", e.SchemaField)
+		}
+		return []FieldError{{Field: fieldOrBody(joinFieldPath(field, e.JSONPointer())), Message: message}}
+	case *openapi3filter.ParseError:
+		message := e.Reason
+		if message == "" && e.Cause != nil {
+			message = e.Cause.Error()
+		}
+		return []FieldError{{Field: fieldOrBody(field), Message: message}}
+	}
+
+	if unwrapped := errors.Unwrap(err); unwrapped != nil {
+		return fieldErrors(unwrapped, field)
+	}
+	return []FieldError{{Field: fieldOrBody(field), Message: err.Error()}}
+}
+
+// joinFieldPath собирает путь поля: ключи через точку, индексы массивов в скобках
+func joinFieldPath(prefix string, pointer []string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	for _, part := range pointer {
+		if _, err := strconv.Atoi(part); err == nil {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+func fieldOrBody(field string) string {
+	if field == "" {
+		return "body"
+	}
+	return field
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kafka-order-service/api/openapi"
+)
+
+func newValidatedHandler(t *testing.T) (http.Handler, *bool) {
+	t.Helper()
+
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	validate, err := ValidateRequests(doc)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	called := new(bool)
+	return validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*called = true
+		w.WriteHeader(http.StatusNoContent)
+	})), called
+}
+
+func validationFields(t *testing.T, body string) map[string]string {
+	t.Helper()
+
+	var response struct {
+		Fields []FieldError `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatalf("Failed to decode response %q: %v", body, err)
+	}
+	fields := make(map[string]string, len(response.Fields))
+	for _, f := range response.Fields {
+		fields[f.Field] = f.Message
+	}
+	return fields
+}
+
+func TestValidateRequests_CreateOrderFieldPaths(t *testing.T) {
+	handler, called := newValidatedHandler(t)
+
+	body := `{"email":"not-an-email","items":[{"product_id":"5f1c2b8e-6f0a-4c1e-9a51-7c3c9a0e2f10","quantity":0},{"quantity":1}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest || *called {
+		t.Fatalf("Expected 400 before handler, got %d (handler called: %v)", rec.Code, *called)
+	}
+	fields := validationFields(t, rec.Body.String())
+	for _, field := range []string{"email", "items[0].quantity", "items[1].product_id"} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("Expected error for %s, got %v", field, fields)
+		}
+	}
+}
+
+func TestValidateRequests_ParamsAndValidRequest(t *testing.T) {
+	handler, called := newValidatedHandler(t)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/not-a-uuid", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for invalid path param, got %d", rec.Code)
+	}
+	if _, ok := validationFields(t, rec.Body.String())["id"]; !ok {
+		t.Errorf("Expected error for id, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders?limit=abc&sort_order=up", nil))
+	fields := validationFields(t, rec.Body.String())
+	if _, ok := fields["limit"]; !ok {
+		t.Errorf("Expected error for limit, got %v", fields)
+	}
+	if _, ok := fields["sort_order"]; !ok {
+		t.Errorf("Expected error for sort_order, got %v", fields)
+	}
+
+	body := `{"email":"buyer@example.com","items":[{"product_id":"5f1c2b8e-6f0a-4c1e-9a51-7c3c9a0e2f10","quantity":2}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || !*called {
+		t.Errorf("Expected valid request to reach handler, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	*called = false
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/unknown", nil))
+	if !*called {
+		t.Errorf("Expected paths outside the spec to pass through")
+	}
+}
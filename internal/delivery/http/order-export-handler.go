@@ -0,0 +1,100 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/infrastructure/export"
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// OrderExportHandler отдает выгрузку заказов файлом
+type OrderExportHandler struct {
+	exportOrdersUC *usecase.ExportOrdersUseCase
+	logger         *logger.Logger
+}
+
+// NewOrderExportHandler создает новый handler выгрузки заказов
+func NewOrderExportHandler(exportOrdersUC *usecase.ExportOrdersUseCase, logger *logger.Logger) *OrderExportHandler {
+	return &OrderExportHandler{
+		exportOrdersUC: exportOrdersUC,
+		logger:         logger,
+	}
+}
+
+// ExportOrders выгружает заказы по фильтрам списка заказов
+// GET /api/v1/orders/export?format=csv|ndjson|excel&columns=id,email&items=true
+func (h *OrderExportHandler) ExportOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format, err := usecase.ParseExportFormat(query.Get("format"))
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid export format", err)
+		return
+	}
+
+	req := &usecase.ExportOrdersRequest{
+		Filters:      *parseListOrdersRequest(r),
+		FlattenItems: query.Get("items") == "true",
+	}
+	if columns := query.Get("columns"); columns != "" {
+		req.Columns = strings.Split(columns, ",")
+	}
+
+	// Выгрузка может идти дольше write timeout сервера
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("Failed to clear write deadline for export", "error", err)
+	}
+
+	out := &exportResponseWriter{ResponseWriter: w}
+	var writer usecase.OrderExportWriter
+	contentType, extension := "text/csv; charset=utf-8", "csv"
+	switch format {
+	case usecase.ExportFormatNDJSON:
+		writer = export.NewNDJSONWriter(out)
+		contentType, extension = "application/x-ndjson", "ndjson"
+	default:
+		writer = export.NewCSVWriter(out, format == usecase.ExportFormatExcel)
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="orders-%s.%s"`, time.Now().UTC().Format("20060102-150405"), extension))
+
+	rows, err := h.exportOrdersUC.Execute(r.Context(), req, writer)
+	if err == nil {
+		h.logger.Info("Orders export completed", "format", format, "rows", rows)
+		return
+	}
+
+	if !out.written {
+		w.Header().Del("Content-Disposition")
+		var validationErr entities.ValidationError
+		if errors.As(err, &validationErr) {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid export request", err)
+			return
+		}
+		h.logger.Error("Failed to export orders", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, "Failed to export orders", err)
+		return
+	}
+
+	// Часть файла уже отправлена: обрываем соединение, чтобы клиент не принял обрезанный файл за полный
+	h.logger.Error("Orders export aborted", "format", format, "rows", rows, "error", err)
+	panic(http.ErrAbortHandler)
+}
+
+// exportResponseWriter отмечает, началась ли отправка тела ответа
+type exportResponseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(p)
+}
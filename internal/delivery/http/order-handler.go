@@ -20,6 +20,7 @@ type OrderHandler struct {
 	updateStatusUC *usecase.UpdateOrderStatusUseCase
 	getOrderUC     *usecase.GetOrderUseCase
 	listOrdersUC   *usecase.ListOrdersUseCase
+	orderStatsUC   *usecase.OrderStatsUseCase
 	logger         *logger.Logger
 }
 
@@ -29,6 +30,7 @@ func NewOrderHandler(
 	updateStatusUC *usecase.UpdateOrderStatusUseCase,
 	getOrderUC *usecase.GetOrderUseCase,
 	listOrdersUC *usecase.ListOrdersUseCase,
+	orderStatsUC *usecase.OrderStatsUseCase,
 	logger *logger.Logger,
 ) *OrderHandler {
 	return &OrderHandler{
@@ -36,6 +38,7 @@ func NewOrderHandler(
 		updateStatusUC: updateStatusUC,
 		getOrderUC:     getOrderUC,
 		listOrdersUC:   listOrdersUC,
+		orderStatsUC:   orderStatsUC,
 		logger:         logger,
 	}
 }
@@ -147,6 +150,36 @@ func (h *OrderHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request)
 func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("Listing orders request received")
 
+	req := parseListOrdersRequest(r)
+
+	response, err := h.listOrdersUC.Execute(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to list orders", "error", err)
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to list orders", err)
+		return
+	}
+
+	h.logger.Info("Orders listed successfully",
+		"count", len(response.Orders),
+		"total_count", response.TotalCount)
+	h.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// OrderStats возвращает статистику заказов по статусам в отчетной валюте
+// GET /api/v1/orders/stats (фильтры как у списка заказов)
+func (h *OrderHandler) OrderStats(w http.ResponseWriter, r *http.Request) {
+	response, err := h.orderStatsUC.Execute(r.Context(), parseListOrdersRequest(r))
+	if err != nil {
+		h.logger.Error("Failed to get order stats", "error", err)
+		h.writeErrorResponse(w, statusCodeForError(err, http.StatusInternalServerError), "Failed to get order stats", err)
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// parseListOrdersRequest разбирает фильтры списка заказов из query параметров
+func parseListOrdersRequest(r *http.Request) *usecase.ListOrdersRequest {
 	req := &usecase.ListOrdersRequest{}
 
 	// Парсинг query параметров
@@ -172,6 +205,7 @@ func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
 
 	// Currency
 	if currency := query.Get("currency"); currency != "" {
+		currency = entities.NormalizeCurrency(currency)
 		req.Currency = &currency
 	}
 
@@ -219,17 +253,7 @@ func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
 		req.SortOrder = sortOrder
 	}
 
-	response, err := h.listOrdersUC.Execute(r.Context(), req)
-	if err != nil {
-		h.logger.Error("Failed to list orders", "error", err)
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to list orders", err)
-		return
-	}
-
-	h.logger.Info("Orders listed successfully",
-		"count", len(response.Orders),
-		"total_count", response.TotalCount)
-	h.writeJSONResponse(w, http.StatusOK, response)
+	return req
 }
 
 // HealthCheck проверка здоровья сервиса
@@ -264,26 +288,12 @@ func (h *OrderHandler) Metrics(w http.ResponseWriter, r *http.Request) {
 
 // writeJSONResponse записывает JSON ответ
 func (h *OrderHandler) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.logger.Error("Failed to encode JSON response", "error", err)
-	}
+	writeJSONResponse(w, h.logger, statusCode, data)
 }
 
 // writeErrorResponse записывает ошибку в JSON формате
 func (h *OrderHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
-	response := map[string]interface{}{
-		"error":     message,
-		"timestamp": time.Now().Format(time.RFC3339),
-	}
-
-	if err != nil {
-		response["details"] = err.Error()
-	}
-
-	h.writeJSONResponse(w, statusCode, response)
+	writeErrorResponse(w, h.logger, statusCode, message, err)
 }
 
 // ErrorResponse структура для ошибок API
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// OrderStateHandler обрабатывает HTTP запросы к машинам состояний заказов
+type OrderStateHandler struct {
+	getOrderStatesUC *usecase.GetOrderStatesUseCase
+	logger           *logger.Logger
+}
+
+// NewOrderStateHandler создает новый handler для машин состояний
+func NewOrderStateHandler(getOrderStatesUC *usecase.GetOrderStatesUseCase, logger *logger.Logger) *OrderStateHandler {
+	return &OrderStateHandler{
+		getOrderStatesUC: getOrderStatesUC,
+		logger:           logger,
+	}
+}
+
+// GetOrderStates возвращает состояния и допустимые переходы заказов
+// GET /api/v1/order-states?channel=digital&from=confirmed
+func (h *OrderStateHandler) GetOrderStates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	req := &usecase.GetOrderStatesRequest{
+		Channel: query.Get("channel"),
+		From:    entities.OrderStatus(query.Get("from")),
+	}
+
+	response, err := h.getOrderStatesUC.Execute(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to get order states", "error", err, "channel", req.Channel)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to get order states", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
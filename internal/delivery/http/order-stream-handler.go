@@ -0,0 +1,174 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// streamRetry интервал переподключения, который SSE клиент использует после обрыва
+const streamRetry = 3 * time.Second
+
+// OrderStreamHandler отдает события заказов как Server-Sent Events
+type OrderStreamHandler struct {
+	broker     *usecase.OrderEventBroker
+	getOrderUC *usecase.GetOrderUseCase
+	heartbeat  time.Duration
+	logger     *logger.Logger
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewOrderStreamHandler создает новый handler потоков событий
+func NewOrderStreamHandler(
+	broker *usecase.OrderEventBroker,
+	getOrderUC *usecase.GetOrderUseCase,
+	heartbeat time.Duration,
+	logger *logger.Logger,
+) *OrderStreamHandler {
+	return &OrderStreamHandler{
+		broker:     broker,
+		getOrderUC: getOrderUC,
+		heartbeat:  heartbeat,
+		logger:     logger,
+		done:       make(chan struct{}),
+	}
+}
+
+// Close завершает открытые потоки, чтобы остановка сервера их не дожидалась
+func (h *OrderStreamHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+// StreamOrderEvents отдает поток событий заказа
+// GET /api/v1/orders/{id}/events
+func (h *OrderStreamHandler) StreamOrderEvents(w http.ResponseWriter, r *http.Request) {
+	orderIDStr := mux.Vars(r)["id"]
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		h.logger.Error("Invalid order ID format", "order_id", orderIDStr, "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	if _, err := h.getOrderUC.Execute(r.Context(), &usecase.GetOrderRequest{OrderID: orderID}); err != nil {
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to get order", err)
+		return
+	}
+
+	h.serve(w, r, usecase.OrderStreamFilter{TenantID: entities.TenantIDFromContext(r.Context()), OrderID: orderID})
+}
+
+// StreamCustomerEvents отдает поток событий всех заказов клиента
+// GET /api/v1/customers/{id}/events
+func (h *OrderStreamHandler) StreamCustomerEvents(w http.ResponseWriter, r *http.Request) {
+	customerIDStr := mux.Vars(r)["id"]
+	customerID, err := uuid.Parse(customerIDStr)
+	if err != nil {
+		h.logger.Error("Invalid customer ID format", "customer_id", customerIDStr, "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid customer ID format", err)
+		return
+	}
+
+	// Клиенты общие для всех арендаторов: поток ограничен заказами арендатора запроса
+	h.serve(w, r, usecase.OrderStreamFilter{TenantID: entities.TenantIDFromContext(r.Context()), CustomerID: customerID})
+}
+
+// serve подписывает клиента на брокер и пишет события до отключения.
+// Last-Event-ID (заголовок или параметр lastEventId) - EventID последнего полученного события.
+func (h *OrderStreamHandler) serve(w http.ResponseWriter, r *http.Request, filter usecase.OrderStreamFilter) {
+	var lastEventID uuid.UUID
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid Last-Event-ID", err)
+			return
+		}
+		lastEventID = parsed
+	}
+
+	// Поток живет дольше WriteTimeout сервера
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("Failed to clear write deadline for event stream", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	subscription := h.broker.Subscribe(filter, lastEventID)
+	defer subscription.Close()
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds()); err != nil {
+		return
+	}
+	if subscription.Reset {
+		// Событий после Last-Event-ID уже нет в истории: клиент перечитывает заказ через REST
+		if _, err := fmt.Fprint(w, "event: reset\ndata: {}\n\n"); err != nil {
+			return
+		}
+	}
+	for _, event := range subscription.Replay {
+		if err := writeStreamEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := controller.Flush(); err != nil {
+		h.logger.Error("Event stream does not support flushing", "error", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.done:
+			return
+		case event, ok := <-subscription.Events:
+			if !ok {
+				// Брокер отключил отстающего клиента, он переподключится с Last-Event-ID
+				return
+			}
+			if err := writeStreamEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeStreamEvent пишет событие заказа в формате SSE
+func writeStreamEvent(w http.ResponseWriter, event *entities.OrderEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.EventID, event.EventType, data)
+	return err
+}
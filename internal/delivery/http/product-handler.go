@@ -0,0 +1,161 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ProductHandler обрабатывает HTTP запросы каталога товаров
+type ProductHandler struct {
+	createProductUC *usecase.CreateProductUseCase
+	updateProductUC *usecase.UpdateProductUseCase
+	getProductUC    *usecase.GetProductUseCase
+	listProductsUC  *usecase.ListProductsUseCase
+	deleteProductUC *usecase.DeleteProductUseCase
+	logger          *logger.Logger
+}
+
+// NewProductHandler создает новый handler для каталога товаров
+func NewProductHandler(
+	createProductUC *usecase.CreateProductUseCase,
+	updateProductUC *usecase.UpdateProductUseCase,
+	getProductUC *usecase.GetProductUseCase,
+	listProductsUC *usecase.ListProductsUseCase,
+	deleteProductUC *usecase.DeleteProductUseCase,
+	logger *logger.Logger,
+) *ProductHandler {
+	return &ProductHandler{
+		createProductUC: createProductUC,
+		updateProductUC: updateProductUC,
+		getProductUC:    getProductUC,
+		listProductsUC:  listProductsUC,
+		deleteProductUC: deleteProductUC,
+		logger:          logger,
+	}
+}
+
+// CreateProduct создает товар
+// POST /api/v1/products
+func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	var req usecase.SaveProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode create product request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	response, err := h.createProductUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to create product", "error", err)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to create product", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusCreated, response)
+}
+
+// UpdateProduct заменяет данные и цены товара
+// PUT /api/v1/products/{id}
+func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	productID, ok := h.parseProductID(w, r)
+	if !ok {
+		return
+	}
+
+	var req usecase.SaveProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode update product request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	req.ID = productID
+
+	response, err := h.updateProductUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to update product", "error", err, "product_id", productID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to update product", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// GetProduct возвращает товар
+// GET /api/v1/products/{id}
+func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
+	productID, ok := h.parseProductID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.getProductUC.Execute(r.Context(), productID)
+	if err != nil {
+		h.logger.Error("Failed to get product", "error", err, "product_id", productID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to get product", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// ListProducts возвращает список товаров
+// GET /api/v1/products?active=true&limit=20&offset=0
+func (h *ProductHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &usecase.ListProductsRequest{}
+
+	if active, err := strconv.ParseBool(query.Get("active")); err == nil {
+		req.ActiveOnly = active
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 {
+		req.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		req.Offset = offset
+	}
+
+	response, err := h.listProductsUC.Execute(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to list products", "error", err)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to list products", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// DeleteProduct удаляет товар
+// DELETE /api/v1/products/{id}
+func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	productID, ok := h.parseProductID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.deleteProductUC.Execute(r.Context(), productID); err != nil {
+		h.logger.Error("Failed to delete product", "error", err, "product_id", productID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to delete product", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseProductID извлекает ID товара из пути, при ошибке пишет ответ 400
+func (h *ProductHandler) parseProductID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	productIDStr := mux.Vars(r)["id"]
+	productID, err := uuid.Parse(productIDStr)
+	if err != nil {
+		h.logger.Error("Invalid product ID format", "product_id", productIDStr, "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid product ID format", err)
+		return uuid.Nil, false
+	}
+	return productID, true
+}
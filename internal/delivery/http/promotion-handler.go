@@ -0,0 +1,76 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// PromotionHandler обрабатывает HTTP запросы для промоакций
+type PromotionHandler struct {
+	createPromotionUC *usecase.CreatePromotionUseCase
+	listPromotionsUC  *usecase.ListPromotionsUseCase
+	logger            *logger.Logger
+}
+
+// NewPromotionHandler создает новый handler для промоакций
+func NewPromotionHandler(
+	createPromotionUC *usecase.CreatePromotionUseCase,
+	listPromotionsUC *usecase.ListPromotionsUseCase,
+	logger *logger.Logger,
+) *PromotionHandler {
+	return &PromotionHandler{
+		createPromotionUC: createPromotionUC,
+		listPromotionsUC:  listPromotionsUC,
+		logger:            logger,
+	}
+}
+
+// CreatePromotion создает промоакцию
+// POST /api/v1/promotions
+func (h *PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	var req usecase.CreatePromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode create promotion request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	response, err := h.createPromotionUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to create promotion", "error", err)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to create promotion", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusCreated, response)
+}
+
+// ListPromotions возвращает список промоакций
+// GET /api/v1/promotions?active=true&limit=20&offset=0
+func (h *PromotionHandler) ListPromotions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &usecase.ListPromotionsRequest{}
+
+	if active, err := strconv.ParseBool(query.Get("active")); err == nil {
+		req.ActiveOnly = active
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 {
+		req.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		req.Offset = offset
+	}
+
+	response, err := h.listPromotionsUC.Execute(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to list promotions", "error", err)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to list promotions", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
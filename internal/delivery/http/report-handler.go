@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// ReportHandler обрабатывает HTTP запросы отчетов
+type ReportHandler struct {
+	orderReportUC *usecase.OrderReportUseCase
+	logger        *logger.Logger
+}
+
+// NewReportHandler создает новый handler отчетов
+func NewReportHandler(orderReportUC *usecase.OrderReportUseCase, logger *logger.Logger) *ReportHandler {
+	return &ReportHandler{
+		orderReportUC: orderReportUC,
+		logger:        logger,
+	}
+}
+
+// OrderReport возвращает количество заказов, выручку, средний чек и количество товаров по группам
+// GET /api/v1/reports/orders?group_by=month,status&date_from=2025-01-01&date_to=2025-03-31&currency=EUR&country=DE
+func (h *ReportHandler) OrderReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &usecase.OrderReportRequest{}
+
+	if groupBy := query.Get("group_by"); groupBy != "" {
+		req.GroupBy = strings.Split(groupBy, ",")
+	}
+	for name, target := range map[string]**string{
+		"date_from": &req.DateFrom,
+		"date_to":   &req.DateTo,
+		"status":    &req.Status,
+		"currency":  &req.Currency,
+		"country":   &req.Country,
+	} {
+		if value := query.Get(name); value != "" {
+			*target = &value
+		}
+	}
+
+	response, err := h.orderReportUC.Execute(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to get order report", "error", err)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to get order report", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/pkg/logger"
+)
+
+// writeJSONResponse записывает JSON ответ
+func writeJSONResponse(w http.ResponseWriter, log *logger.Logger, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Error("Failed to encode JSON response", "error", err)
+	}
+}
+
+// writeErrorResponse записывает ошибку в JSON формате
+func writeErrorResponse(w http.ResponseWriter, log *logger.Logger, statusCode int, message string, err error) {
+	response := map[string]interface{}{
+		"error":     message,
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+
+	if err != nil {
+		response["details"] = err.Error()
+	}
+
+	writeJSONResponse(w, log, statusCode, response)
+}
+
+// statusCodeForError подбирает HTTP статус по типу доменной ошибки
+func statusCodeForError(err error, fallback int) int {
+	var validationErr entities.ValidationError
+	var transitionErr entities.InvalidStatusTransitionError
+	var guardErr entities.TransitionGuardError
+	var notFoundErr entities.OrderNotFoundError
+	var returnNotFoundErr entities.ReturnNotFoundError
+	var stockErr entities.InsufficientStockError
+	var productNotFoundErr entities.ProductNotFoundError
+	var customerNotFoundErr entities.CustomerNotFoundError
+	var webhookNotFoundErr entities.WebhookNotFoundError
+	var forbiddenErr entities.ForbiddenError
+	var shipmentNotFoundErr entities.ShipmentNotFoundError
+	var shipmentTransitionErr entities.InvalidShipmentTransitionError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.As(err, &transitionErr), errors.As(err, &guardErr), errors.As(err, &stockErr),
+		errors.As(err, &shipmentTransitionErr):
+		return http.StatusConflict
+	case errors.As(err, &notFoundErr), errors.As(err, &returnNotFoundErr), errors.As(err, &productNotFoundErr),
+		errors.As(err, &customerNotFoundErr), errors.As(err, &webhookNotFoundErr), errors.As(err, &shipmentNotFoundErr):
+		return http.StatusNotFound
+	case errors.As(err, &forbiddenErr):
+		return http.StatusForbidden
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ReturnHandler обрабатывает HTTP запросы для возвратов товаров и средств
+type ReturnHandler struct {
+	createReturnUC  *usecase.CreateReturnUseCase
+	resolveReturnUC *usecase.ResolveReturnUseCase
+	issueRefundUC   *usecase.IssueRefundUseCase
+	listReturnsUC   *usecase.ListReturnsUseCase
+	logger          *logger.Logger
+}
+
+// NewReturnHandler создает новый handler для возвратов
+func NewReturnHandler(
+	createReturnUC *usecase.CreateReturnUseCase,
+	resolveReturnUC *usecase.ResolveReturnUseCase,
+	issueRefundUC *usecase.IssueRefundUseCase,
+	listReturnsUC *usecase.ListReturnsUseCase,
+	logger *logger.Logger,
+) *ReturnHandler {
+	return &ReturnHandler{
+		createReturnUC:  createReturnUC,
+		resolveReturnUC: resolveReturnUC,
+		issueRefundUC:   issueRefundUC,
+		listReturnsUC:   listReturnsUC,
+		logger:          logger,
+	}
+}
+
+// CreateReturn создает заявку на возврат
+// POST /api/v1/orders/{id}/returns
+func (h *ReturnHandler) CreateReturn(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.parseUUID(w, r, "id", "Invalid order ID format")
+	if !ok {
+		return
+	}
+
+	var req usecase.CreateReturnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode create return request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	req.OrderID = orderID
+
+	response, err := h.createReturnUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to create return", "error", err, "order_id", orderID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to create return", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusCreated, response)
+}
+
+// ApproveReturn одобряет заявку на возврат
+// POST /api/v1/orders/{id}/returns/{return_id}/approve
+func (h *ReturnHandler) ApproveReturn(w http.ResponseWriter, r *http.Request) {
+	h.resolveReturn(w, r, true)
+}
+
+// RejectReturn отклоняет заявку на возврат
+// POST /api/v1/orders/{id}/returns/{return_id}/reject
+func (h *ReturnHandler) RejectReturn(w http.ResponseWriter, r *http.Request) {
+	h.resolveReturn(w, r, false)
+}
+
+// IssueRefund возвращает средства по заказу
+// POST /api/v1/orders/{id}/refunds
+func (h *ReturnHandler) IssueRefund(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.parseUUID(w, r, "id", "Invalid order ID format")
+	if !ok {
+		return
+	}
+
+	var req usecase.IssueRefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode refund request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	req.OrderID = orderID
+
+	response, err := h.issueRefundUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to issue refund", "error", err, "order_id", orderID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to issue refund", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusCreated, response)
+}
+
+// ListReturns возвращает заявки на возврат и возвраты средств по заказу
+// GET /api/v1/orders/{id}/returns
+func (h *ReturnHandler) ListReturns(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.parseUUID(w, r, "id", "Invalid order ID format")
+	if !ok {
+		return
+	}
+
+	response, err := h.listReturnsUC.Execute(r.Context(), &usecase.ListReturnsRequest{OrderID: orderID})
+	if err != nil {
+		h.logger.Error("Failed to list returns", "error", err, "order_id", orderID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to list returns", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// resolveReturn выполняет решение по заявке на возврат
+func (h *ReturnHandler) resolveReturn(w http.ResponseWriter, r *http.Request, approve bool) {
+	orderID, ok := h.parseUUID(w, r, "id", "Invalid order ID format")
+	if !ok {
+		return
+	}
+	returnID, ok := h.parseUUID(w, r, "return_id", "Invalid return ID format")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Note string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			h.logger.Error("Failed to decode resolve return request", "error", err)
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	response, err := h.resolveReturnUC.Execute(r.Context(), &usecase.ResolveReturnRequest{
+		OrderID:  orderID,
+		ReturnID: returnID,
+		Approve:  approve,
+		Note:     body.Note,
+	})
+	if err != nil {
+		h.logger.Error("Failed to resolve return", "error", err, "return_id", returnID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to resolve return", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// parseUUID разбирает UUID из параметра пути и пишет ошибку при неудаче
+func (h *ReturnHandler) parseUUID(w http.ResponseWriter, r *http.Request, name, message string) (uuid.UUID, bool) {
+	value := mux.Vars(r)[name]
+	id, err := uuid.Parse(value)
+	if err != nil {
+		h.logger.Error(message, name, value, "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, message, err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
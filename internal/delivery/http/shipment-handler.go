@@ -0,0 +1,121 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ShipmentHandler обрабатывает HTTP запросы для отправлений заказов
+type ShipmentHandler struct {
+	createShipmentUC       *usecase.CreateShipmentUseCase
+	listShipmentsUC        *usecase.ListShipmentsUseCase
+	updateShipmentStatusUC *usecase.UpdateShipmentStatusUseCase
+	logger                 *logger.Logger
+}
+
+// NewShipmentHandler создает новый handler для отправлений
+func NewShipmentHandler(
+	createShipmentUC *usecase.CreateShipmentUseCase,
+	listShipmentsUC *usecase.ListShipmentsUseCase,
+	updateShipmentStatusUC *usecase.UpdateShipmentStatusUseCase,
+	logger *logger.Logger,
+) *ShipmentHandler {
+	return &ShipmentHandler{
+		createShipmentUC:       createShipmentUC,
+		listShipmentsUC:        listShipmentsUC,
+		updateShipmentStatusUC: updateShipmentStatusUC,
+		logger:                 logger,
+	}
+}
+
+// CreateShipment создает отправление для части или всех товаров заказа
+// POST /api/v1/orders/{id}/shipments
+func (h *ShipmentHandler) CreateShipment(w http.ResponseWriter, r *http.Request) {
+	orderIDStr := mux.Vars(r)["id"]
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		h.logger.Error("Invalid order ID format", "order_id", orderIDStr, "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	var req usecase.CreateShipmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode create shipment request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	req.OrderID = orderID
+
+	response, err := h.createShipmentUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to create shipment", "error", err, "order_id", orderID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to create shipment", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusCreated, response)
+}
+
+// ListShipments возвращает отправления заказа
+// GET /api/v1/orders/{id}/shipments
+func (h *ShipmentHandler) ListShipments(w http.ResponseWriter, r *http.Request) {
+	orderIDStr := mux.Vars(r)["id"]
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		h.logger.Error("Invalid order ID format", "order_id", orderIDStr, "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	response, err := h.listShipmentsUC.Execute(r.Context(), &usecase.ListShipmentsRequest{OrderID: orderID})
+	if err != nil {
+		h.logger.Error("Failed to list shipments", "error", err, "order_id", orderID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to list shipments", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// UpdateShipmentStatus изменяет статус отправления
+// PATCH /api/v1/orders/{id}/shipments/{shipment_id}
+func (h *ShipmentHandler) UpdateShipmentStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.logger.Error("Invalid order ID format", "order_id", vars["id"], "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+	shipmentID, err := uuid.Parse(vars["shipment_id"])
+	if err != nil {
+		h.logger.Error("Invalid shipment ID format", "shipment_id", vars["shipment_id"], "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid shipment ID format", err)
+		return
+	}
+
+	var req usecase.UpdateShipmentStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode update shipment request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	req.OrderID = orderID
+	req.ShipmentID = shipmentID
+
+	response, err := h.updateShipmentStatusUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to update shipment status", "error", err, "order_id", orderID, "shipment_id", shipmentID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to update shipment status", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// ShippingHandler обрабатывает HTTP запросы расчета доставки
+type ShippingHandler struct {
+	quoteShippingUC *usecase.QuoteShippingUseCase
+	logger          *logger.Logger
+}
+
+// NewShippingHandler создает новый handler для расчета доставки
+func NewShippingHandler(quoteShippingUC *usecase.QuoteShippingUseCase, logger *logger.Logger) *ShippingHandler {
+	return &ShippingHandler{
+		quoteShippingUC: quoteShippingUC,
+		logger:          logger,
+	}
+}
+
+// QuoteShipping рассчитывает стоимость доставки корзины до оформления заказа
+// GET /api/v1/shipping/quote?country=US&state=CA&weight=2.5&items=3&method=express
+func (h *ShippingHandler) QuoteShipping(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &usecase.QuoteShippingRequest{
+		Country: query.Get("country"),
+		State:   query.Get("state"),
+		Method:  entities.ShippingMethod(query.Get("method")),
+	}
+
+	if value := query.Get("weight"); value != "" {
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid weight", err)
+			return
+		}
+		req.Parcel.Weight = weight
+	}
+	if value := query.Get("items"); value != "" {
+		items, err := strconv.Atoi(value)
+		if err != nil {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid item count", err)
+			return
+		}
+		req.Parcel.ItemCount = items
+	}
+
+	response, err := h.quoteShippingUC.Execute(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to quote shipping", "error", err)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to quote shipping", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// WebhookHandler обрабатывает HTTP запросы для подписок на webhooks
+type WebhookHandler struct {
+	createWebhookUC  *usecase.CreateWebhookUseCase
+	manageWebhooksUC *usecase.ManageWebhooksUseCase
+	logger           *logger.Logger
+}
+
+// NewWebhookHandler создает новый handler для подписок на webhooks
+func NewWebhookHandler(
+	createWebhookUC *usecase.CreateWebhookUseCase,
+	manageWebhooksUC *usecase.ManageWebhooksUseCase,
+	logger *logger.Logger,
+) *WebhookHandler {
+	return &WebhookHandler{
+		createWebhookUC:  createWebhookUC,
+		manageWebhooksUC: manageWebhooksUC,
+		logger:           logger,
+	}
+}
+
+// CreateWebhook создает подписку
+// POST /api/v1/webhooks
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req usecase.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("Failed to decode create webhook request", "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	response, err := h.createWebhookUC.Execute(r.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to create webhook subscription", "error", err)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to create webhook subscription", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusCreated, response)
+}
+
+// ListWebhooks возвращает подписки
+// GET /api/v1/webhooks?limit=20&offset=0
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	response, err := h.manageWebhooksUC.List(r.Context(), limit, offset)
+	if err != nil {
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to list webhook subscriptions", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// GetWebhook возвращает подписку
+// GET /api/v1/webhooks/{id}
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.parseSubscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.manageWebhooksUC.Get(r.Context(), subscriptionID)
+	if err != nil {
+		h.logger.Error("Failed to get webhook subscription", "error", err, "subscription_id", subscriptionID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to get webhook subscription", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// DeleteWebhook удаляет подписку
+// DELETE /api/v1/webhooks/{id}
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.parseSubscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.manageWebhooksUC.Delete(r.Context(), subscriptionID); err != nil {
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to delete webhook subscription", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EnableWebhook включает подписку, отключенную после неудачных доставок
+// POST /api/v1/webhooks/{id}/enable
+func (h *WebhookHandler) EnableWebhook(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.parseSubscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.manageWebhooksUC.Enable(r.Context(), subscriptionID)
+	if err != nil {
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to enable webhook subscription", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// ListDeliveries возвращает журнал доставки подписки, новые попытки первыми
+// GET /api/v1/webhooks/{id}/deliveries?limit=50&offset=0
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.parseSubscriptionID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	req := &usecase.ListWebhookDeliveriesRequest{SubscriptionID: subscriptionID}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 {
+		req.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		req.Offset = offset
+	}
+
+	response, err := h.manageWebhooksUC.Deliveries(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to list webhook deliveries", "error", err, "subscription_id", subscriptionID)
+		writeErrorResponse(w, h.logger, statusCodeForError(err, http.StatusInternalServerError), "Failed to list webhook deliveries", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// parseSubscriptionID извлекает ID подписки из пути, при ошибке пишет ответ 400
+func (h *WebhookHandler) parseSubscriptionID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	subscriptionIDStr := mux.Vars(r)["id"]
+	subscriptionID, err := uuid.Parse(subscriptionIDStr)
+	if err != nil {
+		h.logger.Error("Invalid webhook subscription ID format", "subscription_id", subscriptionIDStr, "error", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, "Invalid webhook subscription ID format", err)
+		return uuid.Nil, false
+	}
+	return subscriptionID, true
+}
@@ -12,21 +12,33 @@ import (
 
 // OrderEventHandler обрабатывает события заказов из Kafka
 type OrderEventHandler struct {
-	updateStatusUC *usecase.UpdateOrderStatusUseCase
-	getOrderUC     *usecase.GetOrderUseCase
-	logger         *logger.Logger
+	updateStatusUC  *usecase.UpdateOrderStatusUseCase
+	getOrderUC      *usecase.GetOrderUseCase
+	listShipmentsUC *usecase.ListShipmentsUseCase
+	listReturnsUC   *usecase.ListReturnsUseCase
+	warehouse       *WarehouseHandler
+	notifications   *NotificationHandler
+	logger          *logger.Logger
 }
 
 // NewOrderEventHandler создает новый обработчик событий заказов
 func NewOrderEventHandler(
 	updateStatusUC *usecase.UpdateOrderStatusUseCase,
 	getOrderUC *usecase.GetOrderUseCase,
+	listShipmentsUC *usecase.ListShipmentsUseCase,
+	listReturnsUC *usecase.ListReturnsUseCase,
+	warehouse *WarehouseHandler,
+	notifications *NotificationHandler,
 	logger *logger.Logger,
 ) *OrderEventHandler {
 	return &OrderEventHandler{
-		updateStatusUC: updateStatusUC,
-		getOrderUC:     getOrderUC,
-		logger:         logger,
+		updateStatusUC:  updateStatusUC,
+		getOrderUC:      getOrderUC,
+		listShipmentsUC: listShipmentsUC,
+		listReturnsUC:   listReturnsUC,
+		warehouse:       warehouse,
+		notifications:   notifications,
+		logger:          logger,
 	}
 }
 
@@ -40,10 +52,16 @@ func (h *OrderEventHandler) HandleOrderCreated(ctx context.Context, event *entit
 
 	// Здесь можно добавить бизнес-логику для обработки созданного заказа:
 	// - Отправка уведомления клиенту
-	// - Резервирование товаров на складе
 	// - Создание задач для менеджеров
 	// - Интеграция с системой платежей
 
+	// Резервирование товаров на складе
+	if err := h.warehouse.ReserveItems(ctx, event); err != nil {
+		return err
+	}
+
+	h.notifications.SendNotification(ctx, event)
+
 	// Пример: логирование для аудита
 	h.logger.Info("Order created successfully processed",
 		"order_id", event.OrderID,
@@ -65,6 +83,8 @@ func (h *OrderEventHandler) HandleOrderConfirmed(ctx context.Context, event *ent
 	// - Отправка в службу доставки
 	// - Уведомление клиента о подтверждении
 
+	h.notifications.SendNotification(ctx, event)
+
 	// Пример: автоматический переход к обработке через некоторое время
 	go func() {
 		// В реальном приложении это может быть отдельный процесс или задача в очереди
@@ -82,11 +102,15 @@ func (h *OrderEventHandler) HandleOrderCancelled(ctx context.Context, event *ent
 		"customer_id", event.CustomerID)
 
 	// Бизнес-логика для отмененного заказа:
-	// - Возврат зарезервированных товаров
 	// - Возврат средств на карту
 	// - Отправка уведомления клиенту
 	// - Обновление статистики
 
+	// Возврат зарезервированных товаров в остаток
+	if err := h.warehouse.ReleaseItems(ctx, event); err != nil {
+		return err
+	}
+
 	// Получаем подробную информацию о заказе для возврата
 	orderReq := &usecase.GetOrderRequest{OrderID: event.OrderID}
 	orderResp, err := h.getOrderUC.Execute(ctx, orderReq)
@@ -102,6 +126,8 @@ func (h *OrderEventHandler) HandleOrderCancelled(ctx context.Context, event *ent
 		"refund_amount", orderResp.Order.TotalAmount,
 		"currency", orderResp.Order.Currency)
 
+	h.notifications.SendNotification(ctx, event)
+
 	return nil
 }
 
@@ -113,18 +139,51 @@ func (h *OrderEventHandler) HandleOrderShipped(ctx context.Context, event *entit
 		"customer_id", event.CustomerID)
 
 	// Бизнес-логика для отправленного заказа:
-	// - Получение трек-номера от курьерской службы
-	// - Отправка SMS/email с трек-номером
+	// - Отправка SMS/email с трек-номерами
 	// - Обновление статуса в системе доставки
 	// - Планирование автоматического обновления статуса при доставке
 
-	// Имитация получения трек-номера
-	trackingNumber := fmt.Sprintf("TRK-%s", event.OrderID.String()[:8])
-	
-	h.logger.Info("Order shipping details",
+	// Списание отгруженных товаров с остатков
+	if err := h.warehouse.CommitItems(ctx, event); err != nil {
+		return err
+	}
+
+	// Трек-номера берем из отправлений заказа
+	shipmentsResp, err := h.listShipmentsUC.Execute(ctx, &usecase.ListShipmentsRequest{OrderID: event.OrderID})
+	if err != nil {
+		h.logger.Error("Failed to get order shipments",
+			"error", err,
+			"order_id", event.OrderID)
+		return fmt.Errorf("failed to get order shipments: %w", err)
+	}
+
+	for _, shipment := range shipmentsResp.Shipments {
+		h.logger.Info("Order shipping details",
+			"order_id", event.OrderID,
+			"shipment_id", shipment.ID,
+			"carrier", shipment.Carrier,
+			"tracking_number", shipment.TrackingNumber,
+			"shipment_status", shipment.Status)
+	}
+
+	h.notifications.SendNotification(ctx, event)
+
+	return nil
+}
+
+// HandleShipmentCreated обрабатывает событие создания отправления
+func (h *OrderEventHandler) HandleShipmentCreated(ctx context.Context, event *entities.OrderEvent) error {
+	h.logger.Info("Processing shipment created event",
+		"event_id", event.EventID,
 		"order_id", event.OrderID,
-		"tracking_number", trackingNumber,
-		"estimated_delivery", "3-5 business days")
+		"customer_id", event.CustomerID,
+		"status", event.Status,
+		"shipment_id", event.Data["shipment_id"],
+		"carrier", event.Data["carrier"],
+		"tracking_number", event.Data["tracking_number"])
+
+	// Здесь можно уведомить клиента о частичной отправке
+	// и передать трек-номер в систему отслеживания
 
 	return nil
 }
@@ -143,6 +202,8 @@ func (h *OrderEventHandler) HandleOrderDelivered(ctx context.Context, event *ent
 	// - Обновление рейтинга товаров
 	// - Обновление статистики доставки
 
+	h.notifications.SendNotification(ctx, event)
+
 	h.logger.Info("Order delivery completed successfully",
 		"order_id", event.OrderID,
 		"delivery_timestamp", event.Timestamp)
@@ -164,21 +225,56 @@ func (h *OrderEventHandler) HandleOrderRefunded(ctx context.Context, event *enti
 	// - Обновление инвентаря
 	// - Анализ причин возврата
 
-	// Получаем детали заказа для обработки возврата
-	orderReq := &usecase.GetOrderRequest{OrderID: event.OrderID}
-	orderResp, err := h.getOrderUC.Execute(ctx, orderReq)
+	// Сумма берется из фактических возвратов средств, а не из суммы заказа
+	returnsResp, err := h.listReturnsUC.Execute(ctx, &usecase.ListReturnsRequest{OrderID: event.OrderID})
 	if err != nil {
-		h.logger.Error("Failed to get order details for refund processing",
+		h.logger.Error("Failed to get refunds for refund processing",
 			"error", err,
 			"order_id", event.OrderID)
-		return fmt.Errorf("failed to get order details: %w", err)
+		return fmt.Errorf("failed to get refunds: %w", err)
 	}
 
 	h.logger.Info("Processing refund",
 		"order_id", event.OrderID,
-		"refund_amount", orderResp.Order.TotalAmount,
-		"currency", orderResp.Order.Currency,
-		"items_count", len(orderResp.Order.Items))
+		"refunded_amount", returnsResp.RefundedAmount,
+		"currency", event.Currency,
+		"refunds_count", len(returnsResp.Refunds),
+		"returns_count", len(returnsResp.Returns))
+
+	h.notifications.SendNotification(ctx, event)
+
+	return nil
+}
+
+// HandleReturnRequested обрабатывает событие создания заявки на возврат
+func (h *OrderEventHandler) HandleReturnRequested(ctx context.Context, event *entities.OrderEvent) error {
+	h.logger.Info("Processing return requested event",
+		"event_id", event.EventID,
+		"order_id", event.OrderID,
+		"customer_id", event.CustomerID,
+		"return_id", event.Data["return_id"],
+		"return_amount", event.Data["return_amount"])
+
+	// Здесь можно создать задачу на приемку товара на складе
+	// и отправить клиенту инструкцию по возврату
+
+	return nil
+}
+
+// HandleRefundIssued обрабатывает событие возврата средств
+func (h *OrderEventHandler) HandleRefundIssued(ctx context.Context, event *entities.OrderEvent) error {
+	h.logger.Info("Processing refund issued event",
+		"event_id", event.EventID,
+		"order_id", event.OrderID,
+		"customer_id", event.CustomerID,
+		"status", event.Status,
+		"refund_id", event.Data["refund_id"],
+		"refund_amount", event.Data["refund_amount"],
+		"refunded_total", event.Data["refunded_total"],
+		"currency", event.Currency)
+
+	// Здесь можно передать возврат в платежную систему
+	// и уведомить клиента
 
 	return nil
 }
@@ -216,69 +312,84 @@ func (h *OrderEventHandler) extractHeaders(headers []kafka.Header) map[string]st
 
 // NotificationHandler обрабатывает отправку уведомлений
 type NotificationHandler struct {
-	logger *logger.Logger
+	notifications *usecase.NotificationService
+	logger        *logger.Logger
 }
 
 // NewNotificationHandler создает новый обработчик уведомлений
-func NewNotificationHandler(logger *logger.Logger) *NotificationHandler {
+func NewNotificationHandler(notifications *usecase.NotificationService, logger *logger.Logger) *NotificationHandler {
 	return &NotificationHandler{
-		logger: logger,
+		notifications: notifications,
+		logger:        logger,
 	}
 }
 
-// SendOrderCreatedNotification отправляет уведомление о создании заказа
-func (n *NotificationHandler) SendOrderCreatedNotification(ctx context.Context, event *entities.OrderEvent) error {
-	n.logger.Info("Sending order created notification",
-		"order_id", event.OrderID,
-		"customer_id", event.CustomerID)
-
-	// Здесь может быть интеграция с:
-	// - Email сервисом
-	// - SMS сервисом  
-	// - Push уведомлениями
-	// - Webhook'ами
-
-	// Имитация отправки email
-	if email, exists := event.Data["email"]; exists {
-		n.logger.Info("Email notification sent",
+// SendNotification отправляет клиенту email уведомление о событии заказа.
+// Ошибка отправки логируется и не прерывает обработку события.
+func (n *NotificationHandler) SendNotification(ctx context.Context, event *entities.OrderEvent) {
+	if err := n.notifications.Notify(ctx, event); err != nil {
+		n.logger.Error("Failed to send notification",
+			"error", err,
 			"order_id", event.OrderID,
-			"email", email,
-			"template", "order_created")
+			"event_type", event.EventType,
+			"event_id", event.EventID)
 	}
-
-	return nil
 }
 
 // WarehouseHandler обрабатывает интеграцию со складом
 type WarehouseHandler struct {
-	logger *logger.Logger
+	inventory *usecase.InventoryService
+	logger    *logger.Logger
 }
 
 // NewWarehouseHandler создает новый обработчик складских операций
-func NewWarehouseHandler(logger *logger.Logger) *WarehouseHandler {
+func NewWarehouseHandler(inventory *usecase.InventoryService, logger *logger.Logger) *WarehouseHandler {
 	return &WarehouseHandler{
-		logger: logger,
+		inventory: inventory,
+		logger:    logger,
 	}
 }
 
-// ReserveItems резервирует товары на складе
+// ReserveItems резервирует товары созданного заказа на складе
 func (w *WarehouseHandler) ReserveItems(ctx context.Context, event *entities.OrderEvent) error {
 	w.logger.Info("Reserving items in warehouse",
 		"order_id", event.OrderID,
 		"total_amount", event.TotalAmount)
 
-	// Интеграция с системой управления складом:
-	// - Проверка наличия товаров
-	// - Резервирование товаров
-	// - Обновление остатков
-	// - Планирование комплектации
+	reservations, err := w.inventory.ReserveOrder(ctx, event.OrderID)
+	if err != nil {
+		w.logger.Error("Failed to reserve items",
+			"error", err,
+			"order_id", event.OrderID)
+		return fmt.Errorf("failed to reserve items: %w", err)
+	}
 
-	if itemCount, exists := event.Data["item_count"]; exists {
-		w.logger.Info("Items reserved successfully",
-			"order_id", event.OrderID,
-			"items_count", itemCount)
+	w.logger.Info("Items reserved successfully",
+		"order_id", event.OrderID,
+		"reservations_count", len(reservations))
+
+	return nil
+}
+
+// CommitItems списывает зарезервированные товары отгруженного заказа
+func (w *WarehouseHandler) CommitItems(ctx context.Context, event *entities.OrderEvent) error {
+	if err := w.inventory.CommitOrder(ctx, event.OrderID); err != nil {
+		w.logger.Error("Failed to commit reserved items",
+			"error", err,
+			"order_id", event.OrderID)
+		return fmt.Errorf("failed to commit reserved items: %w", err)
 	}
+	return nil
+}
 
+// ReleaseItems возвращает зарезервированные товары отмененного заказа в остаток
+func (w *WarehouseHandler) ReleaseItems(ctx context.Context, event *entities.OrderEvent) error {
+	if err := w.inventory.ReleaseOrder(ctx, event.OrderID); err != nil {
+		w.logger.Error("Failed to release reserved items",
+			"error", err,
+			"order_id", event.OrderID)
+		return fmt.Errorf("failed to release reserved items: %w", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/segmentio/kafka-go"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// OrderStreamHandler передает события заказов из Kafka в брокер SSE потоков.
+// Каждый экземпляр producer читает топик в своей consumer group, чтобы видеть все события.
+type OrderStreamHandler struct {
+	broker *usecase.OrderEventBroker
+	pii    usecase.PIIProtector
+	logger *logger.Logger
+}
+
+// NewOrderStreamHandler создает новый обработчик потока событий.
+// pii расшифровывает персональные данные: клиенты потока видят их и через API заказов.
+func NewOrderStreamHandler(broker *usecase.OrderEventBroker, pii usecase.PIIProtector, logger *logger.Logger) *OrderStreamHandler {
+	return &OrderStreamHandler{
+		broker: broker,
+		pii:    pii,
+		logger: logger,
+	}
+}
+
+// HandleOrderCreated передает событие создания заказа
+func (h *OrderStreamHandler) HandleOrderCreated(ctx context.Context, event *entities.OrderEvent) error {
+	return h.publish(event)
+}
+
+// HandleOrderConfirmed передает событие подтверждения заказа
+func (h *OrderStreamHandler) HandleOrderConfirmed(ctx context.Context, event *entities.OrderEvent) error {
+	return h.publish(event)
+}
+
+// HandleOrderCancelled передает событие отмены заказа
+func (h *OrderStreamHandler) HandleOrderCancelled(ctx context.Context, event *entities.OrderEvent) error {
+	return h.publish(event)
+}
+
+// HandleOrderShipped передает событие отправки заказа
+func (h *OrderStreamHandler) HandleOrderShipped(ctx context.Context, event *entities.OrderEvent) error {
+	return h.publish(event)
+}
+
+// HandleShipmentCreated передает событие создания отправления
+func (h *OrderStreamHandler) HandleShipmentCreated(ctx context.Context, event *entities.OrderEvent) error {
+	return h.publish(event)
+}
+
+// HandleOrderDelivered передает событие доставки заказа
+func (h *OrderStreamHandler) HandleOrderDelivered(ctx context.Context, event *entities.OrderEvent) error {
+	return h.publish(event)
+}
+
+// HandleOrderRefunded передает событие возврата средств по заказу
+func (h *OrderStreamHandler) HandleOrderRefunded(ctx context.Context, event *entities.OrderEvent) error {
+	return h.publish(event)
+}
+
+// HandleReturnRequested передает событие заявки на возврат
+func (h *OrderStreamHandler) HandleReturnRequested(ctx context.Context, event *entities.OrderEvent) error {
+	return h.publish(event)
+}
+
+// HandleRefundIssued передает событие выплаты по возврату
+func (h *OrderStreamHandler) HandleRefundIssued(ctx context.Context, event *entities.OrderEvent) error {
+	return h.publish(event)
+}
+
+// HandleGenericMessage передает прочие события заказов (held, status_changed и т.п.)
+func (h *OrderStreamHandler) HandleGenericMessage(ctx context.Context, message kafka.Message) error {
+	var event entities.OrderEvent
+	if err := json.Unmarshal(message.Value, &event); err != nil || event.EventType == "" {
+		h.logger.Debug("Skipping non-order message for order stream",
+			"partition", message.Partition,
+			"offset", message.Offset)
+		return nil
+	}
+	if event.EventType == entities.EventCustomerErased {
+		purged := h.broker.PurgeCustomer(event.CustomerID)
+		h.logger.Info("Customer events purged from order stream",
+			"customer_id", event.CustomerID,
+			"events", purged)
+		return nil
+	}
+	return h.publish(&event)
+}
+
+// publish передает событие брокеру без служебных метаданных Kafka.
+// Событие, которое не удалось расшифровать, в поток не попадает.
+func (h *OrderStreamHandler) publish(event *entities.OrderEvent) error {
+	payload := withoutKafkaMetadata(event)
+	if h.pii != nil {
+		revealed, err := usecase.RevealEventPII(payload, h.pii)
+		if err != nil {
+			h.logger.Error("Failed to decrypt order event for stream", "error", err, "event_id", event.EventID)
+			return nil
+		}
+		payload = revealed
+	}
+	h.broker.Publish(payload)
+	return nil
+}
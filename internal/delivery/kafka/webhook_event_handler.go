@@ -0,0 +1,108 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// WebhookEventHandler пересылает события заказов подписчикам webhooks.
+// Работает в отдельной consumer group, чтобы медленные подписчики не задерживали обработку заказов.
+type WebhookEventHandler struct {
+	dispatcher *usecase.WebhookDispatcher
+	logger     *logger.Logger
+}
+
+// NewWebhookEventHandler создает новый обработчик рассылки webhooks
+func NewWebhookEventHandler(dispatcher *usecase.WebhookDispatcher, logger *logger.Logger) *WebhookEventHandler {
+	return &WebhookEventHandler{
+		dispatcher: dispatcher,
+		logger:     logger,
+	}
+}
+
+// HandleOrderCreated рассылает событие создания заказа
+func (h *WebhookEventHandler) HandleOrderCreated(ctx context.Context, event *entities.OrderEvent) error {
+	return h.dispatch(ctx, event)
+}
+
+// HandleOrderConfirmed рассылает событие подтверждения заказа
+func (h *WebhookEventHandler) HandleOrderConfirmed(ctx context.Context, event *entities.OrderEvent) error {
+	return h.dispatch(ctx, event)
+}
+
+// HandleOrderCancelled рассылает событие отмены заказа
+func (h *WebhookEventHandler) HandleOrderCancelled(ctx context.Context, event *entities.OrderEvent) error {
+	return h.dispatch(ctx, event)
+}
+
+// HandleOrderShipped рассылает событие отправки заказа
+func (h *WebhookEventHandler) HandleOrderShipped(ctx context.Context, event *entities.OrderEvent) error {
+	return h.dispatch(ctx, event)
+}
+
+// HandleShipmentCreated рассылает событие создания отправления
+func (h *WebhookEventHandler) HandleShipmentCreated(ctx context.Context, event *entities.OrderEvent) error {
+	return h.dispatch(ctx, event)
+}
+
+// HandleOrderDelivered рассылает событие доставки заказа
+func (h *WebhookEventHandler) HandleOrderDelivered(ctx context.Context, event *entities.OrderEvent) error {
+	return h.dispatch(ctx, event)
+}
+
+// HandleOrderRefunded рассылает событие возврата средств по заказу
+func (h *WebhookEventHandler) HandleOrderRefunded(ctx context.Context, event *entities.OrderEvent) error {
+	return h.dispatch(ctx, event)
+}
+
+// HandleReturnRequested рассылает событие заявки на возврат
+func (h *WebhookEventHandler) HandleReturnRequested(ctx context.Context, event *entities.OrderEvent) error {
+	return h.dispatch(ctx, event)
+}
+
+// HandleRefundIssued рассылает событие выплаты по возврату
+func (h *WebhookEventHandler) HandleRefundIssued(ctx context.Context, event *entities.OrderEvent) error {
+	return h.dispatch(ctx, event)
+}
+
+// HandleGenericMessage рассылает прочие события заказов (held, status_changed и т.п.)
+func (h *WebhookEventHandler) HandleGenericMessage(ctx context.Context, message kafka.Message) error {
+	var event entities.OrderEvent
+	if err := json.Unmarshal(message.Value, &event); err != nil || event.EventType == "" {
+		h.logger.Debug("Skipping non-order message for webhooks",
+			"partition", message.Partition,
+			"offset", message.Offset)
+		return nil
+	}
+
+	return h.dispatch(ctx, &event)
+}
+
+// dispatch передает событие диспетчеру без служебных метаданных Kafka
+func (h *WebhookEventHandler) dispatch(ctx context.Context, event *entities.OrderEvent) error {
+	if err := h.dispatcher.Dispatch(ctx, withoutKafkaMetadata(event)); err != nil {
+		return fmt.Errorf("failed to dispatch webhooks: %w", err)
+	}
+	return nil
+}
+
+// withoutKafkaMetadata возвращает копию события без ключей kafka_*, добавленных consumer'ом
+func withoutKafkaMetadata(event *entities.OrderEvent) *entities.OrderEvent {
+	payload := *event
+	if event.Data != nil {
+		payload.Data = make(map[string]interface{}, len(event.Data))
+		for key, value := range event.Data {
+			if !strings.HasPrefix(key, "kafka_") {
+				payload.Data[key] = value
+			}
+		}
+	}
+	return &payload
+}
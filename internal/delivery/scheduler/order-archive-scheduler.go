@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// OrderArchiveScheduler периодически архивирует старые заказы в финальных статусах.
+// Работает только на реплике, удерживающей LeaderLock.
+type OrderArchiveScheduler struct {
+	archiveUC *usecase.ArchiveOrdersUseCase
+	lock      LeaderLock
+	interval  time.Duration
+	logger    *logger.Logger
+}
+
+// NewOrderArchiveScheduler создает новый планировщик архивации заказов
+func NewOrderArchiveScheduler(
+	archiveUC *usecase.ArchiveOrdersUseCase,
+	lock LeaderLock,
+	interval time.Duration,
+	logger *logger.Logger,
+) *OrderArchiveScheduler {
+	return &OrderArchiveScheduler{
+		archiveUC: archiveUC,
+		lock:      lock,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// Run запускает планировщик и блокируется до отмены контекста
+func (s *OrderArchiveScheduler) Run(ctx context.Context) {
+	s.logger.Info("Order archive scheduler started", "interval", s.interval.String())
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	isLeader := false
+	for {
+		select {
+		case <-ctx.Done():
+			if isLeader {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := s.lock.Release(releaseCtx); err != nil {
+					s.logger.Error("Failed to release order archive leader lock", "error", err)
+				}
+				cancel()
+			}
+			s.logger.Info("Order archive scheduler stopped")
+			return
+		case <-ticker.C:
+			acquired, err := s.lock.TryAcquire(ctx)
+			if err != nil {
+				s.logger.Error("Failed to acquire order archive leader lock", "error", err)
+				continue
+			}
+
+			if acquired != isLeader {
+				s.logger.Info("Order archive leadership changed", "is_leader", acquired)
+				isLeader = acquired
+			}
+
+			if !isLeader {
+				continue
+			}
+
+			if _, err := s.archiveUC.Execute(ctx); err != nil {
+				s.logger.Error("Order archive run failed", "error", err)
+			}
+		}
+	}
+}
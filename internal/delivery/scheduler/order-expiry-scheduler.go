@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// LeaderLock интерфейс выбора лидера между репликами
+type LeaderLock interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+// OrderExpiryScheduler периодически отменяет просроченные pending заказы.
+// Работает только на реплике, удерживающей LeaderLock.
+type OrderExpiryScheduler struct {
+	expireUC *usecase.ExpirePendingOrdersUseCase
+	lock     LeaderLock
+	interval time.Duration
+	logger   *logger.Logger
+}
+
+// NewOrderExpiryScheduler создает новый планировщик отмены просроченных заказов
+func NewOrderExpiryScheduler(
+	expireUC *usecase.ExpirePendingOrdersUseCase,
+	lock LeaderLock,
+	interval time.Duration,
+	logger *logger.Logger,
+) *OrderExpiryScheduler {
+	return &OrderExpiryScheduler{
+		expireUC: expireUC,
+		lock:     lock,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run запускает планировщик и блокируется до отмены контекста
+func (s *OrderExpiryScheduler) Run(ctx context.Context) {
+	s.logger.Info("Order expiry scheduler started", "interval", s.interval.String())
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	isLeader := false
+	for {
+		select {
+		case <-ctx.Done():
+			if isLeader {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := s.lock.Release(releaseCtx); err != nil {
+					s.logger.Error("Failed to release expiry leader lock", "error", err)
+				}
+				cancel()
+			}
+			s.logger.Info("Order expiry scheduler stopped")
+			return
+		case <-ticker.C:
+			acquired, err := s.lock.TryAcquire(ctx)
+			if err != nil {
+				s.logger.Error("Failed to acquire expiry leader lock", "error", err)
+				continue
+			}
+
+			if acquired != isLeader {
+				s.logger.Info("Order expiry leadership changed", "is_leader", acquired)
+				isLeader = acquired
+			}
+
+			if !isLeader {
+				continue
+			}
+
+			if _, err := s.expireUC.Execute(ctx); err != nil {
+				s.logger.Error("Order expiry run failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// OrderPartitionScheduler периодически создает партиции заказов на будущие месяцы
+// и отсоединяет партиции с истекшим сроком хранения.
+// Работает только на реплике, удерживающей LeaderLock.
+type OrderPartitionScheduler struct {
+	partitionUC *usecase.MaintainOrderPartitionsUseCase
+	lock        LeaderLock
+	interval    time.Duration
+	logger      *logger.Logger
+}
+
+// NewOrderPartitionScheduler создает новый планировщик обслуживания партиций заказов
+func NewOrderPartitionScheduler(
+	partitionUC *usecase.MaintainOrderPartitionsUseCase,
+	lock LeaderLock,
+	interval time.Duration,
+	logger *logger.Logger,
+) *OrderPartitionScheduler {
+	return &OrderPartitionScheduler{
+		partitionUC: partitionUC,
+		lock:        lock,
+		interval:    interval,
+		logger:      logger,
+	}
+}
+
+// Run запускает планировщик и блокируется до отмены контекста
+func (s *OrderPartitionScheduler) Run(ctx context.Context) {
+	s.logger.Info("Order partition scheduler started", "interval", s.interval.String())
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	isLeader := false
+	for {
+		select {
+		case <-ctx.Done():
+			if isLeader {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := s.lock.Release(releaseCtx); err != nil {
+					s.logger.Error("Failed to release order partition leader lock", "error", err)
+				}
+				cancel()
+			}
+			s.logger.Info("Order partition scheduler stopped")
+			return
+		case <-ticker.C:
+			acquired, err := s.lock.TryAcquire(ctx)
+			if err != nil {
+				s.logger.Error("Failed to acquire order partition leader lock", "error", err)
+				continue
+			}
+
+			if acquired != isLeader {
+				s.logger.Info("Order partition leadership changed", "is_leader", acquired)
+				isLeader = acquired
+			}
+
+			if !isLeader {
+				continue
+			}
+
+			if _, err := s.partitionUC.Execute(ctx); err != nil {
+				s.logger.Error("Order partition maintenance failed", "error", err)
+			}
+		}
+	}
+}
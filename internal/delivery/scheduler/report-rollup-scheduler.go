@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"kafka-order-service/internal/usecase"
+	"kafka-order-service/pkg/logger"
+)
+
+// ReportRollupScheduler периодически пересчитывает дневной срез отчетов по заказам.
+// Работает только на реплике, удерживающей LeaderLock.
+type ReportRollupScheduler struct {
+	refreshUC *usecase.RefreshOrderReportsUseCase
+	lock      LeaderLock
+	interval  time.Duration
+	logger    *logger.Logger
+}
+
+// NewReportRollupScheduler создает новый планировщик пересчета дневного среза
+func NewReportRollupScheduler(
+	refreshUC *usecase.RefreshOrderReportsUseCase,
+	lock LeaderLock,
+	interval time.Duration,
+	logger *logger.Logger,
+) *ReportRollupScheduler {
+	return &ReportRollupScheduler{
+		refreshUC: refreshUC,
+		lock:      lock,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// Run запускает планировщик и блокируется до отмены контекста
+func (s *ReportRollupScheduler) Run(ctx context.Context) {
+	s.logger.Info("Report rollup scheduler started", "interval", s.interval.String())
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	isLeader := false
+	for {
+		select {
+		case <-ctx.Done():
+			if isLeader {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := s.lock.Release(releaseCtx); err != nil {
+					s.logger.Error("Failed to release report rollup leader lock", "error", err)
+				}
+				cancel()
+			}
+			s.logger.Info("Report rollup scheduler stopped")
+			return
+		case <-ticker.C:
+			acquired, err := s.lock.TryAcquire(ctx)
+			if err != nil {
+				s.logger.Error("Failed to acquire report rollup leader lock", "error", err)
+				continue
+			}
+
+			if acquired != isLeader {
+				s.logger.Info("Report rollup leadership changed", "is_leader", acquired)
+				isLeader = acquired
+			}
+
+			if !isLeader {
+				continue
+			}
+
+			if _, err := s.refreshUC.Execute(ctx); err != nil {
+				s.logger.Error("Report rollup run failed", "error", err)
+			}
+		}
+	}
+}
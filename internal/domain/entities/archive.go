@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArchivedOrder заказ в архиве: строка заказа и строки связанных таблиц как JSON,
+// чтобы восстановление вернуло их без потерь при любом наборе колонок
+type ArchivedOrder struct {
+	ID        uuid.UUID   `json:"id"`
+	Status    OrderStatus `json:"status"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	// Строка таблицы orders
+	Order json.RawMessage `json:"order"`
+	// Строки связанных таблиц по имени таблицы: позиции, адреса, отправления, возвраты и т.д.
+	Related map[string][]json.RawMessage `json:"related,omitempty"`
+}
+
+// ArchivePartition возвращает месячный раздел архива по дате создания заказа: YYYY-MM
+func ArchivePartition(createdAt time.Time) string {
+	return createdAt.UTC().Format("2006-01")
+}
+
+// Validate проверяет, что запись архива можно восстановить
+func (a *ArchivedOrder) Validate() error {
+	if a.ID == uuid.Nil {
+		return NewValidationError("archived order has no id")
+	}
+	if len(a.Order) == 0 {
+		return NewValidationError("archived order %s has no order row", a.ID)
+	}
+	return nil
+}
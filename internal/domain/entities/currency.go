@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultCurrency валюта заказа по умолчанию
+const DefaultCurrency = "USD"
+
+// isoCurrencies поддерживаемые коды валют ISO 4217
+var isoCurrencies = map[string]bool{
+	"AED": true, "ARS": true, "AUD": true, "BGN": true, "BRL": true, "BYN": true,
+	"CAD": true, "CHF": true, "CLP": true, "CNY": true, "COP": true, "CZK": true,
+	"DKK": true, "EGP": true, "EUR": true, "GBP": true, "HKD": true, "HUF": true,
+	"IDR": true, "ILS": true, "INR": true, "JPY": true, "KES": true, "KRW": true,
+	"KZT": true, "MXN": true, "MYR": true, "NGN": true, "NOK": true, "NZD": true,
+	"PEN": true, "PHP": true, "PLN": true, "RON": true, "RUB": true, "SAR": true,
+	"SEK": true, "SGD": true, "THB": true, "TRY": true, "TWD": true, "UAH": true,
+	"USD": true, "VND": true, "ZAR": true,
+}
+
+// NormalizeCurrency приводит код валюты к каноническому виду
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ValidateCurrency проверяет, что код валюты - поддерживаемый код ISO 4217
+func ValidateCurrency(code string) error {
+	if !isoCurrencies[code] {
+		return NewValidationError("unsupported currency: %q", code)
+	}
+	return nil
+}
+
+// ExchangeRate курс валюты к базовой (отчетной) валюте:
+// 1 единица Currency = Rate единиц BaseCurrency с момента EffectiveAt
+type ExchangeRate struct {
+	Currency     string    `json:"currency" db:"currency"`
+	BaseCurrency string    `json:"base_currency" db:"base_currency"`
+	Rate         float64   `json:"rate" db:"rate"`
+	EffectiveAt  time.Time `json:"effective_at" db:"effective_at"`
+}
+
+// IdentityRate возвращает курс валюты к самой себе
+func IdentityRate(currency string) *ExchangeRate {
+	return &ExchangeRate{Currency: currency, BaseCurrency: currency, Rate: 1}
+}
+
+// Validate выполняет валидацию курса
+func (r *ExchangeRate) Validate() error {
+	if err := ValidateCurrency(r.Currency); err != nil {
+		return err
+	}
+	if err := ValidateCurrency(r.BaseCurrency); err != nil {
+		return err
+	}
+	if r.Rate <= 0 {
+		return NewValidationError("exchange rate %s/%s must be greater than zero", r.Currency, r.BaseCurrency)
+	}
+	return nil
+}
+
+// Convert переводит сумму в базовую валюту с округлением до копеек
+func (r *ExchangeRate) Convert(amount float64) float64 {
+	return fromCents(toCents(amount * r.Rate))
+}
+
+// ReportingAmount возвращает итог в отчетной валюте, а если он не зафиксирован - в валюте заказа
+func (o *Order) ReportingAmount() float64 {
+	if o.BaseCurrency == "" {
+		return o.TotalAmount
+	}
+	return o.TotalAmountBase
+}
+
+// ApplyExchangeRate фиксирует итог заказа в отчетной валюте по курсу на момент заказа
+func (o *Order) ApplyExchangeRate(rate *ExchangeRate) error {
+	if rate.Currency != o.Currency {
+		return NewValidationError("exchange rate is for %s, order currency is %s", rate.Currency, o.Currency)
+	}
+
+	o.BaseCurrency = rate.BaseCurrency
+	o.ExchangeRate = rate.Rate
+	o.TotalAmountBase = rate.Convert(o.TotalAmount)
+	return nil
+}
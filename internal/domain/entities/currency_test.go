@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateCurrency(t *testing.T) {
+	if err := ValidateCurrency("EUR"); err != nil {
+		t.Errorf("Expected EUR to be valid, got %v", err)
+	}
+
+	for _, code := range []string{"eur", "EURO", "XXX", ""} {
+		if err := ValidateCurrency(code); err == nil {
+			t.Errorf("Expected %q to be rejected", code)
+		}
+	}
+}
+
+func TestApplyExchangeRate(t *testing.T) {
+	order := NewOrder(uuid.New(), "test@example.com")
+	order.Currency = "EUR"
+	order.AddItem(uuid.New(), "Product 1", 33.33, 3)
+
+	rate := &ExchangeRate{Currency: "EUR", BaseCurrency: "USD", Rate: 1.08}
+	if err := order.ApplyExchangeRate(rate); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if order.TotalAmountBase != 107.99 {
+		t.Errorf("Expected base total 107.99, got %.2f", order.TotalAmountBase)
+	}
+	if order.ReportingAmount() != 107.99 {
+		t.Errorf("Expected reporting amount in USD, got %.2f", order.ReportingAmount())
+	}
+
+	if err := order.ApplyExchangeRate(&ExchangeRate{Currency: "GBP", BaseCurrency: "USD", Rate: 1.27}); err == nil {
+		t.Error("Expected rate for another currency to be rejected")
+	}
+}
+
+func TestOrderValidate_RejectsUnknownCurrency(t *testing.T) {
+	order := NewOrder(uuid.New(), "test@example.com")
+	order.AddItem(uuid.New(), "Product 1", 10.0, 1)
+	order.Currency = "ABC"
+
+	if err := order.Validate(); err == nil {
+		t.Error("Expected unknown currency to fail validation")
+	}
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Customer представляет клиента, оформляющего заказы
+type Customer struct {
+	ID    uuid.UUID `json:"id" db:"id"`
+	Email string    `json:"email" db:"email"`
+	Name  string    `json:"name,omitempty" db:"name"`
+
+	// Адреса по умолчанию для новых заказов
+	DefaultShippingAddress *Address `json:"default_shipping_address,omitempty" db:"default_shipping_address"`
+	DefaultBillingAddress  *Address `json:"default_billing_address,omitempty" db:"default_billing_address"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CustomerStats статистика клиента за все время в отчетной валюте.
+// Отмененные заказы не учитываются, возвраты средств вычитаются из суммы.
+type CustomerStats struct {
+	OrderCount   int64      `json:"order_count"`
+	TotalSpent   float64    `json:"total_spent"`
+	FirstOrderAt *time.Time `json:"first_order_at,omitempty"`
+	LastOrderAt  *time.Time `json:"last_order_at,omitempty"`
+}
+
+// NormalizeEmail приводит email к каноническому виду
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NewCustomer создает нового клиента
+func NewCustomer(email, name string) *Customer {
+	now := time.Now()
+	return &Customer{
+		ID:        uuid.New(),
+		Email:     NormalizeEmail(email),
+		Name:      strings.TrimSpace(name),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate выполняет валидацию клиента
+func (c *Customer) Validate() error {
+	if c.ID == uuid.Nil {
+		return NewValidationError("customer ID is required")
+	}
+	if c.Email == "" || !strings.Contains(c.Email, "@") {
+		return NewValidationError("valid customer email is required")
+	}
+	for _, address := range []*Address{c.DefaultShippingAddress, c.DefaultBillingAddress} {
+		if address != nil && (address.Street == "" || address.City == "" || address.Country == "") {
+			return NewValidationError("default address requires street, city and country")
+		}
+	}
+	return nil
+}
+
+type customerContextKey struct{}
+
+// ContextWithCustomerID кладет в контекст ID аутентифицированного клиента
+func ContextWithCustomerID(ctx context.Context, customerID uuid.UUID) context.Context {
+	return context.WithValue(ctx, customerContextKey{}, customerID)
+}
+
+// CustomerIDFromContext возвращает ID аутентифицированного клиента; false - запрос
+// выполняется от имени витрины, а не клиента
+func CustomerIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	customerID, ok := ctx.Value(customerContextKey{}).(uuid.UUID)
+	return customerID, ok && customerID != uuid.Nil
+}
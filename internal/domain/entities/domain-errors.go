@@ -46,6 +46,27 @@ func NewInvalidStatusTransitionError(from, to OrderStatus) error {
 	}
 }
 
+// TransitionGuardError представляет ошибку невыполненного условия перехода
+type TransitionGuardError struct {
+	DomainError
+	FromStatus OrderStatus
+	ToStatus   OrderStatus
+	Guard      string
+}
+
+// NewTransitionGuardError создает новую ошибку условия перехода
+func NewTransitionGuardError(from, to OrderStatus, guard string, cause error) error {
+	return TransitionGuardError{
+		DomainError: DomainError{
+			Type:    "TRANSITION_GUARD_FAILED",
+			Message: fmt.Sprintf("cannot transition from %s to %s: guard %s failed: %v", from, to, guard, cause),
+		},
+		FromStatus: from,
+		ToStatus:   to,
+		Guard:      guard,
+	}
+}
+
 // OrderNotFoundError представляет ошибку "заказ не найден"
 type OrderNotFoundError struct {
 	DomainError
@@ -61,4 +82,143 @@ func NewOrderNotFoundError(orderID string) error {
 		},
 		OrderID: orderID,
 	}
-}
\ No newline at end of file
+}
+// ReturnNotFoundError представляет ошибку "заявка на возврат не найдена"
+type ReturnNotFoundError struct {
+	DomainError
+	ReturnID string
+}
+
+// NewReturnNotFoundError создает новую ошибку "заявка на возврат не найдена"
+func NewReturnNotFoundError(returnID string) error {
+	return ReturnNotFoundError{
+		DomainError: DomainError{
+			Type:    "RETURN_NOT_FOUND",
+			Message: fmt.Sprintf("return with ID %s not found", returnID),
+		},
+		ReturnID: returnID,
+	}
+}
+
+// InsufficientStockError представляет ошибку нехватки товара на складе
+type InsufficientStockError struct {
+	DomainError
+	ProductID string
+	Requested int
+	Available int
+}
+
+// NewInsufficientStockError создает новую ошибку нехватки товара
+func NewInsufficientStockError(productID string, requested, available int) error {
+	return InsufficientStockError{
+		DomainError: DomainError{
+			Type:    "INSUFFICIENT_STOCK",
+			Message: fmt.Sprintf("product %s: requested %d, available %d", productID, requested, available),
+		},
+		ProductID: productID,
+		Requested: requested,
+		Available: available,
+	}
+}
+
+// ProductNotFoundError представляет ошибку "товар не найден"
+type ProductNotFoundError struct {
+	DomainError
+	ProductID string
+}
+
+// NewProductNotFoundError создает новую ошибку "товар не найден"
+func NewProductNotFoundError(productID string) error {
+	return ProductNotFoundError{
+		DomainError: DomainError{
+			Type:    "PRODUCT_NOT_FOUND",
+			Message: fmt.Sprintf("product with ID %s not found", productID),
+		},
+		ProductID: productID,
+	}
+}
+
+// CustomerNotFoundError представляет ошибку "клиент не найден"
+type CustomerNotFoundError struct {
+	DomainError
+	CustomerID string
+}
+
+// NewCustomerNotFoundError создает новую ошибку "клиент не найден"
+func NewCustomerNotFoundError(customerID string) error {
+	return CustomerNotFoundError{
+		DomainError: DomainError{
+			Type:    "CUSTOMER_NOT_FOUND",
+			Message: fmt.Sprintf("customer %s not found", customerID),
+		},
+		CustomerID: customerID,
+	}
+}
+
+// WebhookNotFoundError представляет ошибку "подписка на webhooks не найдена"
+type WebhookNotFoundError struct {
+	DomainError
+	SubscriptionID string
+}
+
+// NewWebhookNotFoundError создает новую ошибку "подписка на webhooks не найдена"
+func NewWebhookNotFoundError(subscriptionID string) error {
+	return WebhookNotFoundError{
+		DomainError: DomainError{
+			Type:    "WEBHOOK_NOT_FOUND",
+			Message: fmt.Sprintf("webhook subscription %s not found", subscriptionID),
+		},
+		SubscriptionID: subscriptionID,
+	}
+}
+
+// ForbiddenError представляет ошибку недостаточных прав арендатора
+type ForbiddenError struct {
+	DomainError
+}
+
+// NewForbiddenError создает новую ошибку недостаточных прав
+func NewForbiddenError(format string, args ...interface{}) error {
+	return ForbiddenError{
+		DomainError: DomainError{
+			Type:    "FORBIDDEN",
+			Message: fmt.Sprintf(format, args...),
+		},
+	}
+}
+
+// ShipmentNotFoundError представляет ошибку "отправление не найдено"
+type ShipmentNotFoundError struct {
+	DomainError
+	ShipmentID string
+}
+
+// NewShipmentNotFoundError создает новую ошибку "отправление не найдено"
+func NewShipmentNotFoundError(shipmentID string) error {
+	return ShipmentNotFoundError{
+		DomainError: DomainError{
+			Type:    "SHIPMENT_NOT_FOUND",
+			Message: fmt.Sprintf("shipment with ID %s not found", shipmentID),
+		},
+		ShipmentID: shipmentID,
+	}
+}
+
+// InvalidShipmentTransitionError представляет ошибку недопустимого перехода статуса отправления
+type InvalidShipmentTransitionError struct {
+	DomainError
+	From ShipmentStatus
+	To   ShipmentStatus
+}
+
+// NewInvalidShipmentTransitionError создает новую ошибку перехода статуса отправления
+func NewInvalidShipmentTransitionError(from, to ShipmentStatus) error {
+	return InvalidShipmentTransitionError{
+		DomainError: DomainError{
+			Type:    "INVALID_SHIPMENT_TRANSITION",
+			Message: fmt.Sprintf("cannot change shipment status from %s to %s", from, to),
+		},
+		From: from,
+		To:   to,
+	}
+}
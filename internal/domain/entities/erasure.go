@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventCustomerErased событие удаления персональных данных клиента: получатели
+// должны удалить свои копии email и адресов клиента
+const EventCustomerErased = "customer.erased"
+
+// ErasedEmailDomain домен обезличенных email
+const ErasedEmailDomain = "@erased.invalid"
+
+// ErasedValue заменяет удаленные строковые персональные данные (улица, город)
+const ErasedValue = "[erased]"
+
+// CustomerErasure запись журнала удаления персональных данных клиента
+type CustomerErasure struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	CustomerID uuid.UUID `json:"customer_id" db:"customer_id"`
+	Reason     string    `json:"reason,omitempty" db:"reason"`
+	// Количество обезличенных заказов и адресов
+	OrdersAnonymized    int `json:"orders_anonymized" db:"orders_anonymized"`
+	AddressesAnonymized int `json:"addresses_anonymized" db:"addresses_anonymized"`
+	// EventID событие customer.erased, опубликованное для этого удаления
+	EventID  uuid.UUID `json:"event_id" db:"event_id"`
+	ErasedAt time.Time `json:"erased_at" db:"erased_at"`
+}
+
+// NewCustomerErasure создает запись журнала удаления данных клиента
+func NewCustomerErasure(customerID uuid.UUID, reason string) *CustomerErasure {
+	return &CustomerErasure{
+		ID:         uuid.New(),
+		CustomerID: customerID,
+		Reason:     reason,
+		EventID:    uuid.New(),
+		ErasedAt:   time.Now(),
+	}
+}
+
+// ErasedEmail возвращает обезличенный email клиента: уникальный для клиента
+// и в зарезервированном домене .invalid, чтобы на него ничего не отправлялось
+func ErasedEmail(customerID uuid.UUID) string {
+	return "erased-" + customerID.String() + ErasedEmailDomain
+}
+
+// IsErasedEmail проверяет, что email обезличен
+func IsErasedEmail(email string) bool {
+	return strings.HasSuffix(email, ErasedEmailDomain)
+}
+
+// ToEvent создает событие customer.erased. Событие относится к клиенту, а не к заказу,
+// поэтому OrderID пустой
+func (e *CustomerErasure) ToEvent() *OrderEvent {
+	return &OrderEvent{
+		EventType:  EventCustomerErased,
+		EventID:    e.EventID,
+		CustomerID: e.CustomerID,
+		Timestamp:  e.ErasedAt,
+		Data: map[string]interface{}{
+			"erasure_id":        e.ID,
+			"orders_anonymized": e.OrdersAnonymized,
+		},
+	}
+}
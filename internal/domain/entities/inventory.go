@@ -0,0 +1,143 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultWarehouse склад по умолчанию
+const DefaultWarehouse = "main"
+
+// ReservationStatus представляет статус резерва товара
+type ReservationStatus string
+
+// Возможные статусы резерва
+const (
+	ReservationStatusReserved  ReservationStatus = "reserved"  // Товар зарезервирован под заказ
+	ReservationStatusCommitted ReservationStatus = "committed" // Товар отгружен и списан
+	ReservationStatusReleased  ReservationStatus = "released"  // Резерв снят
+)
+
+// StockLevel остаток товара на складе
+type StockLevel struct {
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	Warehouse string    `json:"warehouse" db:"warehouse"`
+	OnHand    int       `json:"on_hand" db:"on_hand"`
+	Reserved  int       `json:"reserved" db:"reserved"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Available возвращает количество, доступное для резервирования
+func (s *StockLevel) Available() int {
+	if available := s.OnHand - s.Reserved; available > 0 {
+		return available
+	}
+	return 0
+}
+
+// StockReservation резерв количества товара позиции заказа на складе
+type StockReservation struct {
+	ID          uuid.UUID         `json:"id" db:"id"`
+	OrderID     uuid.UUID         `json:"order_id" db:"order_id"`
+	OrderItemID uuid.UUID         `json:"order_item_id" db:"order_item_id"`
+	ProductID   uuid.UUID         `json:"product_id" db:"product_id"`
+	Warehouse   string            `json:"warehouse" db:"warehouse"`
+	Quantity    int               `json:"quantity" db:"quantity"`
+	Status      ReservationStatus `json:"status" db:"status"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// NormalizeWarehouse приводит код склада к каноническому виду; пусто - склад по умолчанию
+func NormalizeWarehouse(warehouse string) string {
+	if warehouse = strings.ToLower(strings.TrimSpace(warehouse)); warehouse == "" {
+		return DefaultWarehouse
+	}
+	return warehouse
+}
+
+// RequestedQuantities суммирует количество товаров в позициях заказа
+func (o *Order) RequestedQuantities() map[uuid.UUID]int {
+	requested := make(map[uuid.UUID]int)
+	for _, item := range o.Items {
+		requested[item.ProductID] += item.Quantity
+	}
+	return requested
+}
+
+// CheckStock проверяет, что отслеживаемых товаров хватает на всех складах.
+// Товары без записей об остатках не отслеживаются и не ограничиваются.
+func (o *Order) CheckStock(levels []*StockLevel) error {
+	available := make(map[uuid.UUID]int)
+	for _, level := range levels {
+		available[level.ProductID] += level.Available()
+	}
+
+	requested := o.RequestedQuantities()
+	productIDs := make([]uuid.UUID, 0, len(requested))
+	for productID := range requested {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Slice(productIDs, func(i, j int) bool {
+		return productIDs[i].String() < productIDs[j].String()
+	})
+
+	for _, productID := range productIDs {
+		left, tracked := available[productID]
+		if tracked && requested[productID] > left {
+			return NewInsufficientStockError(productID.String(), requested[productID], left)
+		}
+	}
+
+	return nil
+}
+
+// AllocateStock распределяет количество позиции по складам: сначала склад
+// с наибольшим доступным остатком. Уменьшает Reserved у переданных остатков.
+func AllocateStock(item OrderItem, levels []*StockLevel) ([]*StockReservation, error) {
+	sorted := make([]*StockLevel, len(levels))
+	copy(sorted, levels)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Available() > sorted[j].Available()
+	})
+
+	total := 0
+	for _, level := range sorted {
+		total += level.Available()
+	}
+	if total < item.Quantity {
+		return nil, NewInsufficientStockError(item.ProductID.String(), item.Quantity, total)
+	}
+
+	now := time.Now()
+	remaining := item.Quantity
+	var reservations []*StockReservation
+	for _, level := range sorted {
+		if remaining == 0 {
+			break
+		}
+		quantity := min(remaining, level.Available())
+		if quantity == 0 {
+			continue
+		}
+
+		level.Reserved += quantity
+		remaining -= quantity
+		reservations = append(reservations, &StockReservation{
+			ID:          uuid.New(),
+			OrderID:     item.OrderID,
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			Warehouse:   level.Warehouse,
+			Quantity:    quantity,
+			Status:      ReservationStatusReserved,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+
+	return reservations, nil
+}
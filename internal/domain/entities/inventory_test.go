@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCheckStock(t *testing.T) {
+	order := NewOrder(uuid.New(), "test@example.com")
+	tracked := uuid.New()
+	order.AddItem(tracked, "Tracked", 10.0, 3)
+	order.AddItem(tracked, "Tracked again", 10.0, 2)
+	order.AddItem(uuid.New(), "Untracked", 5.0, 100)
+
+	levels := []*StockLevel{
+		{ProductID: tracked, Warehouse: "main", OnHand: 4, Reserved: 1},
+		{ProductID: tracked, Warehouse: "east", OnHand: 2},
+	}
+
+	if err := order.CheckStock(levels); err != nil {
+		t.Fatalf("Expected 5 units to be available, got error: %v", err)
+	}
+
+	levels[1].Reserved = 1
+	err := order.CheckStock(levels)
+	var stockErr InsufficientStockError
+	if !errors.As(err, &stockErr) {
+		t.Fatalf("Expected InsufficientStockError, got %v", err)
+	}
+	if stockErr.Requested != 5 || stockErr.Available != 4 {
+		t.Errorf("Expected requested 5 and available 4, got %d and %d", stockErr.Requested, stockErr.Available)
+	}
+}
+
+func TestAllocateStock(t *testing.T) {
+	order := NewOrder(uuid.New(), "test@example.com")
+	productID := uuid.New()
+	order.AddItem(productID, "Product", 10.0, 5)
+
+	mainLevel := &StockLevel{ProductID: productID, Warehouse: "main", OnHand: 3}
+	eastLevel := &StockLevel{ProductID: productID, Warehouse: "east", OnHand: 10, Reserved: 6}
+
+	reservations, err := AllocateStock(order.Items[0], []*StockLevel{mainLevel, eastLevel})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Сначала склад с наибольшим доступным остатком
+	if len(reservations) != 2 || reservations[0].Warehouse != "east" || reservations[0].Quantity != 4 {
+		t.Fatalf("Expected 4 units from east then main, got %+v", reservations)
+	}
+	if reservations[1].Warehouse != "main" || reservations[1].Quantity != 1 {
+		t.Errorf("Expected 1 unit from main, got %+v", reservations[1])
+	}
+	if eastLevel.Available() != 0 || mainLevel.Available() != 2 {
+		t.Errorf("Expected levels to be reserved, got east %d main %d", eastLevel.Available(), mainLevel.Available())
+	}
+
+	if _, err := AllocateStock(order.Items[0], []*StockLevel{mainLevel}); err == nil {
+		t.Error("Expected error when stock is insufficient")
+	}
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "math"
+
+// toCents переводит денежную сумму в копейки, чтобы сравнивать без ошибок округления
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// fromCents переводит копейки обратно в денежную сумму
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
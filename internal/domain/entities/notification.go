@@ -0,0 +1,91 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MetadataLocale ключ метаданных заказа с языком уведомлений клиента
+const MetadataLocale = "locale"
+
+// DefaultLocale язык уведомлений по умолчанию
+const DefaultLocale = "en"
+
+// Шаблоны email уведомлений о заказе
+const (
+	NotificationOrderCreated   = "order_created"
+	NotificationOrderConfirmed = "order_confirmed"
+	NotificationOrderShipped   = "order_shipped"
+	NotificationOrderDelivered = "order_delivered"
+	NotificationOrderCancelled = "order_cancelled"
+	NotificationOrderRefunded  = "order_refunded"
+)
+
+// notificationTemplates шаблоны уведомлений по типам событий
+var notificationTemplates = map[string]string{
+	EventOrderCreated:   NotificationOrderCreated,
+	EventOrderConfirmed: NotificationOrderConfirmed,
+	EventOrderShipped:   NotificationOrderShipped,
+	EventOrderDelivered: NotificationOrderDelivered,
+	EventOrderCancelled: NotificationOrderCancelled,
+	EventOrderRefunded:  NotificationOrderRefunded,
+}
+
+// NotificationTemplateFor возвращает шаблон уведомления для типа события
+func NotificationTemplateFor(eventType string) (string, bool) {
+	template, ok := notificationTemplates[eventType]
+	return template, ok
+}
+
+// NotificationTemplates возвращает все шаблоны уведомлений
+func NotificationTemplates() []string {
+	return []string{
+		NotificationOrderCreated,
+		NotificationOrderConfirmed,
+		NotificationOrderShipped,
+		NotificationOrderDelivered,
+		NotificationOrderCancelled,
+		NotificationOrderRefunded,
+	}
+}
+
+// NormalizeLocale приводит язык к коду из двух букв: "ru-RU" -> "ru"
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// Locale возвращает язык уведомлений из метаданных заказа
+func (o *Order) Locale() string {
+	if locale, ok := o.Metadata[MetadataLocale].(string); ok {
+		if locale = NormalizeLocale(locale); locale != "" {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// EmailMessage письмо, подготовленное к отправке
+type EmailMessage struct {
+	To       string
+	Subject  string
+	HTMLBody string
+}
+
+// Notification отправленное уведомление о заказе.
+// Каждый шаблон отправляется по заказу один раз, повторная доставка события из Kafka его не дублирует.
+type Notification struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	OrderID   uuid.UUID `json:"order_id" db:"order_id"`
+	EventID   uuid.UUID `json:"event_id" db:"event_id"`
+	Template  string    `json:"template" db:"template"`
+	Recipient string    `json:"recipient" db:"recipient"`
+	Locale    string    `json:"locale" db:"locale"`
+	Subject   string    `json:"subject" db:"subject"`
+	SentAt    time.Time `json:"sent_at" db:"sent_at"`
+}
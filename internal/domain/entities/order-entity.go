@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +19,11 @@ const (
 	OrderStatusDelivered  OrderStatus = "delivered"   // Доставлен
 	OrderStatusCancelled  OrderStatus = "cancelled"   // Отменен
 	OrderStatusRefunded   OrderStatus = "refunded"    // Возврат
+	OrderStatusOnHold     OrderStatus = "on_hold"     // Задержан до проверки
+	OrderStatusInReview   OrderStatus = "in_review"   // На ручной проверке
+
+	OrderStatusPartiallyShipped  OrderStatus = "partially_shipped"  // Отправлен частично
+	OrderStatusPartiallyRefunded OrderStatus = "partially_refunded" // Возвращен частично
 )
 
 // OrderItem представляет элемент заказа
@@ -29,14 +35,28 @@ type OrderItem struct {
 	Price     float64   `json:"price" db:"price"`
 	Quantity  int       `json:"quantity" db:"quantity"`
 	Total     float64   `json:"total" db:"total"`
+
+	// Сумма скидок по позиции, детализация в Order.Discounts
+	DiscountAmount float64 `json:"discount_amount" db:"discount_amount"`
+
+	// Налоги позиции: категория товара, сумма и детализация по ставкам.
+	// При TaxInclusive налог уже входит в Total и не добавляется к итогу.
+	TaxCategory  string    `json:"tax_category,omitempty" db:"tax_category"`
+	TaxAmount    float64   `json:"tax_amount" db:"tax_amount"`
+	TaxInclusive bool      `json:"tax_inclusive" db:"tax_inclusive"`
+	TaxLines     []TaxLine `json:"tax_lines,omitempty" db:"-"`
 }
 
 // Order представляет заказ в системе
 type Order struct {
 	ID          uuid.UUID     `json:"id" db:"id"`
+	TenantID    string        `json:"tenant_id" db:"tenant_id"`
 	CustomerID  uuid.UUID     `json:"customer_id" db:"customer_id"`
 	Email       string        `json:"email" db:"email"`
 	Status      OrderStatus   `json:"status" db:"status"`
+	Subtotal    float64       `json:"subtotal" db:"subtotal"`
+	Discount    float64       `json:"discount_amount" db:"discount_amount"`
+	TaxAmount   float64       `json:"tax_amount" db:"tax_amount"`
 	TotalAmount float64       `json:"total_amount" db:"total_amount"`
 	Currency    string        `json:"currency" db:"currency"`
 	Items       []OrderItem   `json:"items" db:"-"`
@@ -49,6 +69,24 @@ type Order struct {
 	
 	// Метаданные
 	Metadata map[string]interface{} `json:"metadata,omitempty" db:"-"`
+
+	// Результат оценки риска при создании
+	RiskAssessment *RiskAssessment `json:"risk_assessment,omitempty" db:"-"`
+
+	// Строки скидок по примененным промокодам
+	Discounts []DiscountLine `json:"discounts,omitempty" db:"-"`
+
+	// Способ и начисленная стоимость доставки
+	ShippingMethod ShippingMethod `json:"shipping_method,omitempty" db:"shipping_method"`
+	ShippingAmount float64        `json:"shipping_amount" db:"shipping_amount"`
+
+	// Итог в отчетной валюте по курсу на момент заказа
+	BaseCurrency    string  `json:"base_currency,omitempty" db:"base_currency"`
+	ExchangeRate    float64 `json:"exchange_rate,omitempty" db:"exchange_rate"`
+	TotalAmountBase float64 `json:"total_amount_base,omitempty" db:"total_amount_base"`
+
+	// Таблица ставок для пересчета налогов при изменении заказа
+	taxTable *TaxTable
 }
 
 // Address представляет адрес доставки/выставления счета
@@ -67,6 +105,7 @@ type Address struct {
 type OrderEvent struct {
 	EventType   string                 `json:"event_type"`
 	EventID     uuid.UUID              `json:"event_id"`
+	TenantID    string                 `json:"tenant_id,omitempty"`
 	OrderID     uuid.UUID              `json:"order_id"`
 	CustomerID  uuid.UUID              `json:"customer_id"`
 	Status      OrderStatus            `json:"status"`
@@ -84,6 +123,14 @@ const (
 	EventOrderShipped   = "order.shipped"
 	EventOrderDelivered = "order.delivered"
 	EventOrderRefunded  = "order.refunded"
+	EventOrderHeld      = "order.held"
+
+	EventOrderShipmentCreated = "order.shipment_created"
+	EventOrderShipmentUpdated = "order.shipment_updated"
+	EventOrderReturnRequested = "order.return_requested"
+	EventOrderRefundIssued    = "order.refund_issued"
+
+	EventOrderStatusChanged = "order.status_changed"
 )
 
 // NewOrder создает новый заказ
@@ -94,7 +141,7 @@ func NewOrder(customerID uuid.UUID, email string) *Order {
 		CustomerID:  customerID,
 		Email:       email,
 		Status:      OrderStatusPending,
-		Currency:    "USD",
+		Currency:    DefaultCurrency,
 		Items:       make([]OrderItem, 0),
 		CreatedAt:   now,
 		UpdatedAt:   now,
@@ -104,18 +151,28 @@ func NewOrder(customerID uuid.UUID, email string) *Order {
 
 // AddItem добавляет элемент к заказу
 func (o *Order) AddItem(productID uuid.UUID, name string, price float64, quantity int) {
+	o.AddTaxableItem(productID, name, price, quantity, "")
+}
+
+// AddTaxableItem добавляет элемент заказа с налоговой категорией товара
+func (o *Order) AddTaxableItem(productID uuid.UUID, name string, price float64, quantity int, taxCategory string) {
+	if taxCategory = strings.ToLower(strings.TrimSpace(taxCategory)); taxCategory == "" {
+		taxCategory = DefaultTaxCategory
+	}
+
 	item := OrderItem{
-		ID:        uuid.New(),
-		OrderID:   o.ID,
-		ProductID: productID,
-		Name:      name,
-		Price:     price,
-		Quantity:  quantity,
-		Total:     price * float64(quantity),
+		ID:          uuid.New(),
+		OrderID:     o.ID,
+		ProductID:   productID,
+		Name:        name,
+		Price:       price,
+		Quantity:    quantity,
+		Total:       price * float64(quantity),
+		TaxCategory: taxCategory,
 	}
-	
+
 	o.Items = append(o.Items, item)
-	o.calculateTotal()
+	o.recalculate()
 	o.UpdatedAt = time.Now()
 }
 
@@ -124,7 +181,8 @@ func (o *Order) RemoveItem(itemID uuid.UUID) bool {
 	for i, item := range o.Items {
 		if item.ID == itemID {
 			o.Items = append(o.Items[:i], o.Items[i+1:]...)
-			o.calculateTotal()
+			o.removeItemDiscounts(itemID)
+			o.recalculate()
 			o.UpdatedAt = time.Now()
 			return true
 		}
@@ -132,68 +190,67 @@ func (o *Order) RemoveItem(itemID uuid.UUID) bool {
 	return false
 }
 
-// UpdateStatus обновляет статус заказа
+// UpdateStatus обновляет статус заказа по стандартной машине состояний
 func (o *Order) UpdateStatus(newStatus OrderStatus) error {
-	if !o.canTransitionTo(newStatus) {
-		return NewInvalidStatusTransitionError(o.Status, newStatus)
+	_, err := o.TransitionTo(DefaultStateMachine(), newStatus)
+	return err
+}
+
+// TransitionTo переводит заказ в новый статус по указанной машине состояний
+// и возвращает выполненный переход
+func (o *Order) TransitionTo(sm *StateMachine, newStatus OrderStatus) (TransitionDefinition, error) {
+	transition, err := sm.CheckTransition(o, newStatus)
+	if err != nil {
+		return TransitionDefinition{}, err
 	}
-	
+
 	o.Status = newStatus
 	o.UpdatedAt = time.Now()
-	return nil
+	return transition, nil
 }
 
-// canTransitionTo проверяет возможность перехода к новому статусу
-func (o *Order) canTransitionTo(newStatus OrderStatus) bool {
-	validTransitions := map[OrderStatus][]OrderStatus{
-		OrderStatusPending: {
-			OrderStatusConfirmed,
-			OrderStatusCancelled,
-		},
-		OrderStatusConfirmed: {
-			OrderStatusProcessing,
-			OrderStatusCancelled,
-		},
-		OrderStatusProcessing: {
-			OrderStatusShipped,
-			OrderStatusCancelled,
-		},
-		OrderStatusShipped: {
-			OrderStatusDelivered,
-		},
-		OrderStatusDelivered: {
-			OrderStatusRefunded,
-		},
-		OrderStatusCancelled: {}, // Финальный статус
-		OrderStatusRefunded:  {}, // Финальный статус
-	}
-
-	allowedStatuses, exists := validTransitions[o.Status]
-	if !exists {
-		return false
+// removeItemDiscounts удаляет строки скидок удаленной позиции
+func (o *Order) removeItemDiscounts(itemID uuid.UUID) {
+	kept := o.Discounts[:0]
+	for _, line := range o.Discounts {
+		if line.OrderItemID == nil || *line.OrderItemID != itemID {
+			kept = append(kept, line)
+		}
 	}
+	o.Discounts = kept
+}
 
-	for _, status := range allowedStatuses {
-		if status == newStatus {
-			return true
-		}
+// recalculate пересчитывает налоги (если задана таблица ставок) и итог заказа
+func (o *Order) recalculate() {
+	if o.taxTable != nil {
+		o.calculateTaxes()
 	}
-	return false
+	o.calculateTotal()
 }
 
-// calculateTotal пересчитывает общую сумму заказа
+// calculateTotal пересчитывает промежуточную сумму, скидку, налог и итог заказа.
+// Налог, включенный в цену, в итог повторно не добавляется; доставка добавляется к итогу.
 func (o *Order) calculateTotal() {
-	total := 0.0
+	var subtotal, discount, tax, exclusiveTax int64
 	for _, item := range o.Items {
-		total += item.Total
+		subtotal += toCents(item.Total)
+		discount += toCents(item.DiscountAmount)
+		tax += toCents(item.TaxAmount)
+		if !item.TaxInclusive {
+			exclusiveTax += toCents(item.TaxAmount)
+		}
 	}
-	o.TotalAmount = total
+	o.Subtotal = fromCents(subtotal)
+	o.Discount = fromCents(discount)
+	o.TaxAmount = fromCents(tax)
+	o.TotalAmount = fromCents(subtotal - discount + exclusiveTax + toCents(o.ShippingAmount))
 }
 
 // IsActive проверяет, активен ли заказ (не отменен и не завершен)
 func (o *Order) IsActive() bool {
 	return o.Status != OrderStatusCancelled && 
 		   o.Status != OrderStatusRefunded &&
+		   o.Status != OrderStatusPartiallyRefunded &&
 		   o.Status != OrderStatusDelivered
 }
 
@@ -201,6 +258,7 @@ func (o *Order) IsActive() bool {
 func (o *Order) IsFinal() bool {
 	return o.Status == OrderStatusCancelled ||
 		   o.Status == OrderStatusRefunded ||
+		   o.Status == OrderStatusPartiallyRefunded ||
 		   o.Status == OrderStatusDelivered
 }
 
@@ -219,6 +277,7 @@ func (o *Order) SetShippingAddress(address *Address) {
 	address.OrderID = o.ID
 	address.Type = "shipping"
 	o.ShippingAddress = address
+	o.recalculate()
 	o.UpdatedAt = time.Now()
 }
 
@@ -228,14 +287,16 @@ func (o *Order) SetBillingAddress(address *Address) {
 	address.OrderID = o.ID
 	address.Type = "billing"
 	o.BillingAddress = address
+	o.recalculate()
 	o.UpdatedAt = time.Now()
 }
 
 // ToEvent создает событие заказа для отправки в Kafka
 func (o *Order) ToEvent(eventType string) *OrderEvent {
-	return &OrderEvent{
+	event := &OrderEvent{
 		EventType:   eventType,
 		EventID:     uuid.New(),
+		TenantID:    o.TenantID,
 		OrderID:     o.ID,
 		CustomerID:  o.CustomerID,
 		Status:      o.Status,
@@ -247,6 +308,23 @@ func (o *Order) ToEvent(eventType string) *OrderEvent {
 			"item_count": o.GetItemCount(),
 		},
 	}
+
+	if o.BaseCurrency != "" {
+		event.Data["base_currency"] = o.BaseCurrency
+		event.Data["total_amount_base"] = o.TotalAmountBase
+	}
+
+	if o.ShippingMethod != "" {
+		event.Data["shipping_method"] = o.ShippingMethod
+		event.Data["shipping_amount"] = o.ShippingAmount
+	}
+
+	if o.TaxAmount > 0 {
+		event.Data["tax_amount"] = o.TaxAmount
+		event.Data["tax_breakdown"] = o.TaxBreakdown()
+	}
+
+	return event
 }
 
 // Validate выполняет валидацию заказа
@@ -270,6 +348,10 @@ func (o *Order) Validate() error {
 	if o.TotalAmount <= 0 {
 		return NewValidationError("total amount must be greater than zero")
 	}
+
+	if err := ValidateCurrency(o.Currency); err != nil {
+		return err
+	}
 	
 	// Валидация элементов заказа
 	for i, item := range o.Items {
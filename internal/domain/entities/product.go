@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Product товар каталога. Цены задаются отдельно для каждой валюты.
+type Product struct {
+	ID          uuid.UUID          `json:"id" db:"id"`
+	SKU         string             `json:"sku" db:"sku"`
+	Name        string             `json:"name" db:"name"`
+	Prices      map[string]float64 `json:"prices" db:"-"`
+	Active      bool               `json:"active" db:"active"`
+	TaxCategory string             `json:"tax_category,omitempty" db:"tax_category"`
+	Weight      float64            `json:"weight,omitempty" db:"weight"` // Вес единицы в кг
+	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// NormalizeSKU приводит артикул к каноническому виду
+func NormalizeSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
+
+// NewProduct создает новый активный товар
+func NewProduct(sku, name string) *Product {
+	now := time.Now()
+	return &Product{
+		ID:        uuid.New(),
+		SKU:       NormalizeSKU(sku),
+		Name:      strings.TrimSpace(name),
+		Prices:    make(map[string]float64),
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// SetPrice устанавливает цену товара в валюте
+func (p *Product) SetPrice(currency string, price float64) {
+	if p.Prices == nil {
+		p.Prices = make(map[string]float64)
+	}
+	p.Prices[NormalizeCurrency(currency)] = price
+}
+
+// PriceIn возвращает цену товара в валюте
+func (p *Product) PriceIn(currency string) (float64, bool) {
+	price, exists := p.Prices[NormalizeCurrency(currency)]
+	return price, exists
+}
+
+// Validate выполняет валидацию товара
+func (p *Product) Validate() error {
+	if p.SKU == "" {
+		return NewValidationError("product sku is required")
+	}
+	if p.Name == "" {
+		return NewValidationError("product name is required")
+	}
+	if len(p.Prices) == 0 {
+		return NewValidationError("product %s has no prices", p.SKU)
+	}
+	for currency, price := range p.Prices {
+		if err := ValidateCurrency(currency); err != nil {
+			return err
+		}
+		if price <= 0 {
+			return NewValidationError("product %s price in %s must be greater than zero", p.SKU, currency)
+		}
+	}
+	if p.Weight < 0 {
+		return NewValidationError("product %s weight cannot be negative", p.SKU)
+	}
+	return nil
+}
+
+// CheckOrderable проверяет, что товар можно заказать в валюте, и возвращает цену.
+// Переданная клиентом цена (0 - не указана) должна совпадать с ценой каталога.
+func (p *Product) CheckOrderable(currency string, clientPrice float64) (float64, error) {
+	if !p.Active {
+		return 0, NewValidationError("product %s is not available", p.SKU)
+	}
+
+	price, exists := p.PriceIn(currency)
+	if !exists {
+		return 0, NewValidationError("product %s has no price in %s", p.SKU, currency)
+	}
+
+	if clientPrice != 0 && toCents(clientPrice) != toCents(price) {
+		return 0, NewValidationError("product %s price mismatch: expected %.2f, got %.2f", p.SKU, price, clientPrice)
+	}
+
+	return price, nil
+}
@@ -0,0 +1,31 @@
+package entities
+
+import "testing"
+
+func TestProductCheckOrderable(t *testing.T) {
+	product := NewProduct(" mug-1 ", "Mug")
+	product.SetPrice("usd", 9.99)
+
+	if product.SKU != "MUG-1" {
+		t.Errorf("Expected normalized SKU MUG-1, got %s", product.SKU)
+	}
+
+	price, err := product.CheckOrderable("USD", 0)
+	if err != nil || price != 9.99 {
+		t.Fatalf("Expected catalog price 9.99, got %.2f (%v)", price, err)
+	}
+	if _, err := product.CheckOrderable("USD", 9.990000001); err != nil {
+		t.Errorf("Expected matching price in cents to pass, got %v", err)
+	}
+	if _, err := product.CheckOrderable("USD", 0.01); err == nil {
+		t.Error("Expected price mismatch to be rejected")
+	}
+	if _, err := product.CheckOrderable("EUR", 0); err == nil {
+		t.Error("Expected missing currency price to be rejected")
+	}
+
+	product.Active = false
+	if _, err := product.CheckOrderable("USD", 0); err == nil {
+		t.Error("Expected inactive product to be rejected")
+	}
+}
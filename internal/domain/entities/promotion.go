@@ -0,0 +1,325 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PromotionType представляет тип правила скидки
+type PromotionType string
+
+// Поддерживаемые типы скидок
+const (
+	PromotionTypePercentage   PromotionType = "percentage"    // Процент от стоимости позиций
+	PromotionTypeFixedAmount  PromotionType = "fixed_amount"  // Фиксированная сумма на заказ
+	PromotionTypeBuyXGetY     PromotionType = "buy_x_get_y"   // Купи X, получи Y бесплатно
+	PromotionTypeFreeShipping PromotionType = "free_shipping" // Бесплатная доставка
+)
+
+// promotionTypeOrder порядок применения скидок: сначала бесплатные единицы,
+// затем процент и только потом фиксированная сумма от остатка
+var promotionTypeOrder = map[PromotionType]int{
+	PromotionTypeBuyXGetY:     0,
+	PromotionTypePercentage:   1,
+	PromotionTypeFixedAmount:  2,
+	PromotionTypeFreeShipping: 3,
+}
+
+// Promotion представляет промоакцию с кодом купона
+type Promotion struct {
+	ID   uuid.UUID     `json:"id" db:"id"`
+	Code string        `json:"code" db:"code"`
+	Name string        `json:"name" db:"name"`
+	Type PromotionType `json:"type" db:"type"`
+
+	// Value - процент для percentage или сумма для fixed_amount
+	Value float64 `json:"value,omitempty" db:"value"`
+	// BuyQuantity и GetQuantity для buy_x_get_y
+	BuyQuantity int `json:"buy_quantity,omitempty" db:"buy_quantity"`
+	GetQuantity int `json:"get_quantity,omitempty" db:"get_quantity"`
+
+	// ProductIDs ограничивает скидку товарами; пусто - все товары
+	ProductIDs  []uuid.UUID `json:"product_ids,omitempty" db:"product_ids"`
+	MinSubtotal float64     `json:"min_subtotal,omitempty" db:"min_subtotal"`
+
+	StartsAt *time.Time `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+
+	// Лимиты использования; 0 - без ограничений
+	UsageLimit       int `json:"usage_limit" db:"usage_limit"`
+	PerCustomerLimit int `json:"per_customer_limit" db:"per_customer_limit"`
+	UsageCount       int `json:"usage_count" db:"usage_count"`
+
+	// Stackable разрешает комбинировать купон с другими купонами
+	Stackable bool `json:"stackable" db:"stackable"`
+	Active    bool `json:"active" db:"active"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DiscountLine строка скидки заказа. Для скидок на позицию заполнен OrderItemID,
+// бесплатная доставка записывается строкой заказа с нулевой суммой
+type DiscountLine struct {
+	ID          uuid.UUID     `json:"id" db:"id"`
+	OrderID     uuid.UUID     `json:"order_id" db:"order_id"`
+	OrderItemID *uuid.UUID    `json:"order_item_id,omitempty" db:"order_item_id"`
+	PromotionID uuid.UUID     `json:"promotion_id" db:"promotion_id"`
+	Code        string        `json:"code" db:"code"`
+	Type        PromotionType `json:"type" db:"type"`
+	Amount      float64       `json:"amount" db:"amount"`
+}
+
+// NormalizeCouponCode приводит код купона к каноническому виду
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// NewPromotion создает новую активную промоакцию
+func NewPromotion(code, name string, promotionType PromotionType) *Promotion {
+	now := time.Now()
+	return &Promotion{
+		ID:        uuid.New(),
+		Code:      NormalizeCouponCode(code),
+		Name:      strings.TrimSpace(name),
+		Type:      promotionType,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate выполняет валидацию промоакции
+func (p *Promotion) Validate() error {
+	if p.Code == "" {
+		return NewValidationError("promotion code is required")
+	}
+
+	switch p.Type {
+	case PromotionTypePercentage:
+		if p.Value <= 0 || p.Value > 100 {
+			return NewValidationError("percentage must be between 0 and 100")
+		}
+	case PromotionTypeFixedAmount:
+		if p.Value <= 0 {
+			return NewValidationError("fixed amount must be greater than zero")
+		}
+	case PromotionTypeBuyXGetY:
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return NewValidationError("buy and get quantities must be greater than zero")
+		}
+	case PromotionTypeFreeShipping:
+	default:
+		return NewValidationError("unknown promotion type: %s", p.Type)
+	}
+
+	if p.UsageLimit < 0 || p.PerCustomerLimit < 0 {
+		return NewValidationError("usage limits cannot be negative")
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return NewValidationError("promotion must end after it starts")
+	}
+
+	return nil
+}
+
+// CheckAvailable проверяет активность, окно действия и общий лимит купона
+func (p *Promotion) CheckAvailable(now time.Time) error {
+	if !p.Active {
+		return NewValidationError("coupon %s is not active", p.Code)
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return NewValidationError("coupon %s is not valid yet", p.Code)
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return NewValidationError("coupon %s has expired", p.Code)
+	}
+	if p.UsageLimit > 0 && p.UsageCount >= p.UsageLimit {
+		return NewValidationError("coupon %s usage limit reached", p.Code)
+	}
+	return nil
+}
+
+// appliesTo проверяет, распространяется ли скидка на товар
+func (p *Promotion) appliesTo(productID uuid.UUID) bool {
+	if len(p.ProductIDs) == 0 {
+		return true
+	}
+	for _, id := range p.ProductIDs {
+		if id == productID {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyPromotions применяет купоны к позициям заказа и пересчитывает итог.
+// Ранее примененные скидки сбрасываются.
+func (o *Order) ApplyPromotions(promotions []*Promotion) error {
+	seen := make(map[string]bool, len(promotions))
+	for _, p := range promotions {
+		if seen[p.Code] {
+			return NewValidationError("coupon %s is applied more than once", p.Code)
+		}
+		seen[p.Code] = true
+
+		if !p.Stackable && len(promotions) > 1 {
+			return NewValidationError("coupon %s cannot be combined with other coupons", p.Code)
+		}
+	}
+
+	ordered := make([]*Promotion, len(promotions))
+	copy(ordered, promotions)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return promotionTypeOrder[ordered[i].Type] < promotionTypeOrder[ordered[j].Type]
+	})
+
+	for i := range o.Items {
+		o.Items[i].DiscountAmount = 0
+	}
+	o.Discounts = make([]DiscountLine, 0)
+	o.calculateTotal()
+
+	for _, p := range ordered {
+		if p.MinSubtotal > 0 && toCents(o.Subtotal) < toCents(p.MinSubtotal) {
+			return NewValidationError("coupon %s requires a minimum subtotal of %.2f", p.Code, p.MinSubtotal)
+		}
+
+		if p.Type == PromotionTypeFreeShipping {
+			o.Discounts = append(o.Discounts, o.newDiscountLine(p, nil, 0))
+			o.ShippingAmount = 0
+			continue
+		}
+
+		discounts := o.itemDiscounts(p)
+		applied := false
+		for i := range o.Items {
+			cents := discounts[o.Items[i].ID]
+			if cents <= 0 {
+				continue
+			}
+			itemID := o.Items[i].ID
+			o.Items[i].DiscountAmount = fromCents(toCents(o.Items[i].DiscountAmount) + cents)
+			o.Discounts = append(o.Discounts, o.newDiscountLine(p, &itemID, fromCents(cents)))
+			applied = true
+		}
+
+		if !applied {
+			return NewValidationError("coupon %s is not applicable to the order items", p.Code)
+		}
+	}
+
+	o.recalculate()
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// itemDiscounts рассчитывает скидку промоакции по позициям в копейках
+func (o *Order) itemDiscounts(p *Promotion) map[uuid.UUID]int64 {
+	discounts := make(map[uuid.UUID]int64)
+
+	// Остаток стоимости позиций после ранее примененных скидок
+	remaining := make(map[uuid.UUID]int64)
+	var eligibleTotal int64
+	for _, item := range o.Items {
+		if !p.appliesTo(item.ProductID) {
+			continue
+		}
+		left := toCents(item.Total) - toCents(item.DiscountAmount)
+		if left > 0 {
+			remaining[item.ID] = left
+			eligibleTotal += left
+		}
+	}
+
+	switch p.Type {
+	case PromotionTypePercentage:
+		for id, left := range remaining {
+			discounts[id] = min(left, toCents(fromCents(left)*p.Value/100))
+		}
+
+	case PromotionTypeBuyXGetY:
+		for _, item := range o.Items {
+			left, ok := remaining[item.ID]
+			if !ok {
+				continue
+			}
+			free := item.Quantity / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+			discounts[item.ID] = min(left, toCents(item.Price)*int64(free))
+		}
+
+	case PromotionTypeFixedAmount:
+		// Сумма распределяется пропорционально остатку, округление - на последнюю позицию
+		amount := min(toCents(p.Value), eligibleTotal)
+		var allocated int64
+		var last uuid.UUID
+		for _, item := range o.Items {
+			left, ok := remaining[item.ID]
+			if !ok {
+				continue
+			}
+			share := amount * left / eligibleTotal
+			discounts[item.ID] = share
+			allocated += share
+			last = item.ID
+		}
+		if rest := amount - allocated; rest > 0 && last != uuid.Nil {
+			discounts[last] = min(remaining[last], discounts[last]+rest)
+		}
+	}
+
+	return discounts
+}
+
+// newDiscountLine создает строку скидки заказа
+func (o *Order) newDiscountLine(p *Promotion, orderItemID *uuid.UUID, amount float64) DiscountLine {
+	return DiscountLine{
+		ID:          uuid.New(),
+		OrderID:     o.ID,
+		OrderItemID: orderItemID,
+		PromotionID: p.ID,
+		Code:        p.Code,
+		Type:        p.Type,
+		Amount:      amount,
+	}
+}
+
+// AppliedPromotionIDs возвращает промоакции, примененные к заказу, без повторов
+func (o *Order) AppliedPromotionIDs() []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	ids := make([]uuid.UUID, 0)
+	for _, line := range o.Discounts {
+		if !seen[line.PromotionID] {
+			seen[line.PromotionID] = true
+			ids = append(ids, line.PromotionID)
+		}
+	}
+	return ids
+}
+
+// CouponCodes возвращает коды купонов, примененных к заказу
+func (o *Order) CouponCodes() []string {
+	seen := make(map[string]bool)
+	codes := make([]string, 0)
+	for _, line := range o.Discounts {
+		if !seen[line.Code] {
+			seen[line.Code] = true
+			codes = append(codes, line.Code)
+		}
+	}
+	return codes
+}
+
+// HasFreeShipping проверяет, применена ли к заказу бесплатная доставка
+func (o *Order) HasFreeShipping() bool {
+	for _, line := range o.Discounts {
+		if line.Type == PromotionTypeFreeShipping {
+			return true
+		}
+	}
+	return false
+}
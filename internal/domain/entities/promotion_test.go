@@ -0,0 +1,102 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newPromotionTestOrder() *Order {
+	order := NewOrder(uuid.New(), "test@example.com")
+	order.AddItem(uuid.New(), "Product 1", 10.0, 3)
+	order.AddItem(uuid.New(), "Product 2", 20.0, 1)
+	return order
+}
+
+func TestApplyPromotions_StackedPercentageAndFixed(t *testing.T) {
+	order := newPromotionTestOrder()
+
+	percent := NewPromotion("save10", "10% off", PromotionTypePercentage)
+	percent.Value = 10
+	percent.Stackable = true
+
+	fixed := NewPromotion("minus5", "5 off", PromotionTypeFixedAmount)
+	fixed.Value = 5
+	fixed.Stackable = true
+
+	// Фиксированная скидка применяется после процентной независимо от порядка кодов
+	if err := order.ApplyPromotions([]*Promotion{fixed, percent}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if order.Subtotal != 50.0 {
+		t.Errorf("Expected subtotal 50.00, got %.2f", order.Subtotal)
+	}
+	if order.Discount != 10.0 {
+		t.Errorf("Expected discount 10.00, got %.2f", order.Discount)
+	}
+	if order.TotalAmount != 40.0 {
+		t.Errorf("Expected total 40.00, got %.2f", order.TotalAmount)
+	}
+
+	var lines float64
+	for _, line := range order.Discounts {
+		if line.OrderItemID == nil {
+			t.Errorf("Expected discount line linked to an order item")
+		}
+		lines += line.Amount
+	}
+	if lines != order.Discount {
+		t.Errorf("Expected discount lines to sum to %.2f, got %.2f", order.Discount, lines)
+	}
+}
+
+func TestApplyPromotions_BuyXGetY(t *testing.T) {
+	order := newPromotionTestOrder()
+
+	promo := NewPromotion("b2g1", "Buy 2 get 1", PromotionTypeBuyXGetY)
+	promo.BuyQuantity = 2
+	promo.GetQuantity = 1
+	promo.ProductIDs = []uuid.UUID{order.Items[0].ProductID}
+
+	if err := order.ApplyPromotions([]*Promotion{promo}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if order.Items[0].DiscountAmount != 10.0 {
+		t.Errorf("Expected one free unit worth 10.00, got %.2f", order.Items[0].DiscountAmount)
+	}
+	if order.Items[1].DiscountAmount != 0 {
+		t.Errorf("Expected no discount on other products, got %.2f", order.Items[1].DiscountAmount)
+	}
+}
+
+func TestApplyPromotions_Rules(t *testing.T) {
+	order := newPromotionTestOrder()
+
+	exclusive := NewPromotion("vip", "VIP", PromotionTypePercentage)
+	exclusive.Value = 20
+	shipping := NewPromotion("freeship", "Free shipping", PromotionTypeFreeShipping)
+	shipping.Stackable = true
+
+	if err := order.ApplyPromotions([]*Promotion{exclusive, shipping}); err == nil {
+		t.Error("Expected error when combining a non-stackable coupon")
+	}
+
+	minimum := NewPromotion("big", "Big orders", PromotionTypeFixedAmount)
+	minimum.Value = 5
+	minimum.MinSubtotal = 100
+	if err := order.ApplyPromotions([]*Promotion{minimum}); err == nil {
+		t.Error("Expected error when subtotal is below the minimum")
+	}
+
+	if err := order.ApplyPromotions([]*Promotion{shipping}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !order.HasFreeShipping() {
+		t.Error("Expected free shipping to be applied")
+	}
+	if order.TotalAmount != order.Subtotal {
+		t.Errorf("Expected free shipping to keep total %.2f, got %.2f", order.Subtotal, order.TotalAmount)
+	}
+}
@@ -0,0 +1,247 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReturnStatus представляет статус заявки на возврат
+type ReturnStatus string
+
+// Возможные статусы заявки на возврат
+const (
+	ReturnStatusRequested ReturnStatus = "requested" // Создана клиентом
+	ReturnStatusApproved  ReturnStatus = "approved"  // Одобрена, ожидает возврата средств
+	ReturnStatusRejected  ReturnStatus = "rejected"  // Отклонена
+	ReturnStatusRefunded  ReturnStatus = "refunded"  // Средства возвращены
+)
+
+// ReturnItem количество позиции заказа в заявке на возврат
+type ReturnItem struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	ReturnID    uuid.UUID `json:"return_id" db:"return_id"`
+	OrderItemID uuid.UUID `json:"order_item_id" db:"order_item_id"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+	Reason      string    `json:"reason" db:"reason"`
+}
+
+// ReturnRequest представляет заявку на возврат (RMA) части товаров заказа
+type ReturnRequest struct {
+	ID             uuid.UUID    `json:"id" db:"id"`
+	OrderID        uuid.UUID    `json:"order_id" db:"order_id"`
+	Status         ReturnStatus `json:"status" db:"status"`
+	Reason         string       `json:"reason,omitempty" db:"reason"`
+	ResolutionNote string       `json:"resolution_note,omitempty" db:"resolution_note"`
+	Items          []ReturnItem `json:"items" db:"-"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// Refund представляет возврат денежных средств по заказу
+type Refund struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	OrderID   uuid.UUID  `json:"order_id" db:"order_id"`
+	ReturnID  *uuid.UUID `json:"return_id,omitempty" db:"return_id"`
+	Amount    float64    `json:"amount" db:"amount"`
+	Currency  string     `json:"currency" db:"currency"`
+	Reason    string     `json:"reason,omitempty" db:"reason"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NewReturnRequest создает новую заявку на возврат
+func NewReturnRequest(orderID uuid.UUID, reason string) *ReturnRequest {
+	now := time.Now()
+	return &ReturnRequest{
+		ID:        uuid.New(),
+		OrderID:   orderID,
+		Status:    ReturnStatusRequested,
+		Reason:    strings.TrimSpace(reason),
+		Items:     make([]ReturnItem, 0),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// AddItem добавляет позицию заказа в заявку.
+// Если причина не указана, используется причина заявки.
+func (r *ReturnRequest) AddItem(orderItemID uuid.UUID, quantity int, reason string) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = r.Reason
+	}
+
+	r.Items = append(r.Items, ReturnItem{
+		ID:          uuid.New(),
+		ReturnID:    r.ID,
+		OrderItemID: orderItemID,
+		Quantity:    quantity,
+		Reason:      reason,
+	})
+}
+
+// Validate выполняет валидацию заявки на возврат
+func (r *ReturnRequest) Validate() error {
+	if len(r.Items) == 0 {
+		return NewValidationError("return must have at least one item")
+	}
+
+	for i, item := range r.Items {
+		if item.OrderItemID == uuid.Nil {
+			return NewValidationError("return item %d: order item ID cannot be empty", i)
+		}
+		if item.Quantity <= 0 {
+			return NewValidationError("return item %d: quantity must be greater than zero", i)
+		}
+		if item.Reason == "" {
+			return NewValidationError("return item %d: reason is required", i)
+		}
+	}
+
+	return nil
+}
+
+// Approve одобряет заявку на возврат
+func (r *ReturnRequest) Approve(note string) error {
+	if r.Status != ReturnStatusRequested {
+		return NewValidationError("return %s is already %s", r.ID, r.Status)
+	}
+	r.Status = ReturnStatusApproved
+	r.ResolutionNote = strings.TrimSpace(note)
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Reject отклоняет заявку на возврат
+func (r *ReturnRequest) Reject(note string) error {
+	if r.Status != ReturnStatusRequested {
+		return NewValidationError("return %s is already %s", r.ID, r.Status)
+	}
+	r.Status = ReturnStatusRejected
+	r.ResolutionNote = strings.TrimSpace(note)
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// RefundAmount считает сумму к возврату по оплаченной стоимости позиций заказа
+// (с учетом скидок и налога, начисленного сверху цены)
+func (r *ReturnRequest) RefundAmount(order *Order) float64 {
+	items := make(map[uuid.UUID]OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		items[item.ID] = item
+	}
+
+	var cents int64
+	for _, returned := range r.Items {
+		item, exists := items[returned.OrderItemID]
+		if !exists || item.Quantity == 0 {
+			continue
+		}
+		paid := toCents(item.Total) - toCents(item.DiscountAmount)
+		if !item.TaxInclusive {
+			paid += toCents(item.TaxAmount)
+		}
+		cents += paid * int64(returned.Quantity) / int64(item.Quantity)
+	}
+	return fromCents(cents)
+}
+
+// NewRefund создает возврат средств по заказу
+func NewRefund(order *Order, returnID *uuid.UUID, amount float64, reason string) *Refund {
+	return &Refund{
+		ID:        uuid.New(),
+		OrderID:   order.ID,
+		ReturnID:  returnID,
+		Amount:    fromCents(toCents(amount)),
+		Currency:  order.Currency,
+		Reason:    strings.TrimSpace(reason),
+		CreatedAt: time.Now(),
+	}
+}
+
+// CanRequestReturn проверяет, можно ли оформить возврат по заказу
+func (o *Order) CanRequestReturn() bool {
+	return o.Status == OrderStatusDelivered || o.Status == OrderStatusPartiallyRefunded
+}
+
+// ReturnedQuantities суммирует количество в заявках на возврат, кроме отклоненных
+func ReturnedQuantities(returns []*ReturnRequest) map[uuid.UUID]int {
+	returned := make(map[uuid.UUID]int)
+	for _, ret := range returns {
+		if ret.Status == ReturnStatusRejected {
+			continue
+		}
+		for _, item := range ret.Items {
+			returned[item.OrderItemID] += item.Quantity
+		}
+	}
+	return returned
+}
+
+// ReturnableQuantities возвращает количество, которое еще можно вернуть по каждой позиции
+func (o *Order) ReturnableQuantities(returns []*ReturnRequest) map[uuid.UUID]int {
+	returned := ReturnedQuantities(returns)
+	returnable := make(map[uuid.UUID]int, len(o.Items))
+	for _, item := range o.Items {
+		if left := item.Quantity - returned[item.ID]; left > 0 {
+			returnable[item.ID] = left
+		}
+	}
+	return returnable
+}
+
+// ValidateReturn проверяет заявку на возврат против уже оформленных
+func (o *Order) ValidateReturn(existing []*ReturnRequest, ret *ReturnRequest) error {
+	returnable := o.ReturnableQuantities(existing)
+
+	for i, item := range ret.Items {
+		left, exists := returnable[item.OrderItemID]
+		if !exists {
+			return NewValidationError("return item %d: order item %s is not part of the order or already returned", i, item.OrderItemID)
+		}
+		if item.Quantity > left {
+			return NewValidationError("return item %d: quantity %d exceeds returnable %d", i, item.Quantity, left)
+		}
+		returnable[item.OrderItemID] = left - item.Quantity
+	}
+
+	return nil
+}
+
+// RefundedAmount суммирует уже возвращенные средства
+func RefundedAmount(refunds []*Refund) float64 {
+	var cents int64
+	for _, refund := range refunds {
+		cents += toCents(refund.Amount)
+	}
+	return fromCents(cents)
+}
+
+// RefundableAmount возвращает сумму, которую еще можно вернуть по заказу
+func (o *Order) RefundableAmount(refunds []*Refund) float64 {
+	left := toCents(o.TotalAmount) - toCents(RefundedAmount(refunds))
+	if left < 0 {
+		return 0
+	}
+	return fromCents(left)
+}
+
+// RefundStatusAfter проверяет сумму возврата против оплаченной и возвращает
+// производный статус заказа: refunded или partially_refunded
+func (o *Order) RefundStatusAfter(existing []*Refund, amount float64) (OrderStatus, error) {
+	requested := toCents(amount)
+	if requested <= 0 {
+		return "", NewValidationError("refund amount must be greater than zero")
+	}
+
+	left := toCents(o.RefundableAmount(existing))
+	if requested > left {
+		return "", NewValidationError("refund amount %.2f exceeds refundable %.2f", amount, fromCents(left))
+	}
+
+	if requested == left {
+		return OrderStatusRefunded, nil
+	}
+	return OrderStatusPartiallyRefunded, nil
+}
@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newReturnTestOrder() *Order {
+	order := NewOrder(uuid.New(), "test@example.com")
+	order.AddItem(uuid.New(), "Product 1", 10.0, 2)
+	order.AddItem(uuid.New(), "Product 2", 5.5, 1)
+	order.Status = OrderStatusDelivered
+	return order
+}
+
+func TestValidateReturn(t *testing.T) {
+	order := newReturnTestOrder()
+
+	first := NewReturnRequest(order.ID, "damaged")
+	first.AddItem(order.Items[0].ID, 2, "")
+	if err := first.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Items[0].Reason != "damaged" {
+		t.Errorf("Expected item reason to default to request reason, got %q", first.Items[0].Reason)
+	}
+	if amount := first.RefundAmount(order); amount != 20.0 {
+		t.Errorf("Expected refund amount 20.00, got %.2f", amount)
+	}
+
+	second := NewReturnRequest(order.ID, "")
+	second.AddItem(order.Items[0].ID, 1, "wrong size")
+	if err := order.ValidateReturn([]*ReturnRequest{first}, second); err == nil {
+		t.Error("Expected error when returning more than ordered")
+	}
+
+	// Отклоненная заявка не занимает количество
+	first.Status = ReturnStatusRejected
+	if err := order.ValidateReturn([]*ReturnRequest{first}, second); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRefundStatusAfter(t *testing.T) {
+	order := newReturnTestOrder()
+
+	status, err := order.RefundStatusAfter(nil, 10.0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != OrderStatusPartiallyRefunded {
+		t.Errorf("Expected status %s, got %s", OrderStatusPartiallyRefunded, status)
+	}
+
+	refunds := []*Refund{NewRefund(order, nil, 10.0, "")}
+	if _, err := order.RefundStatusAfter(refunds, 15.51); err == nil {
+		t.Error("Expected error when refunding more than paid")
+	}
+
+	status, err = order.RefundStatusAfter(refunds, 15.5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != OrderStatusRefunded {
+		t.Errorf("Expected status %s, got %s", OrderStatusRefunded, status)
+	}
+
+	if _, err := order.RefundStatusAfter(refunds, 0); err == nil {
+		t.Error("Expected error for zero refund amount")
+	}
+}
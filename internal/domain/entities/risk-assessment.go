@@ -0,0 +1,48 @@
+package entities
+
+import "time"
+
+// RiskRuleHit описывает сработавшее правило антифрода
+type RiskRuleHit struct {
+	Rule   string `json:"rule"`
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+// RiskAssessment результат оценки риска заказа
+type RiskAssessment struct {
+	Score      int           `json:"score"`
+	Cutoff     int           `json:"cutoff"`
+	Triggered  []RiskRuleHit `json:"triggered_rules"`
+	AssessedAt time.Time     `json:"assessed_at"`
+}
+
+// NewRiskAssessment создает оценку риска по сработавшим правилам
+func NewRiskAssessment(cutoff int, hits []RiskRuleHit) *RiskAssessment {
+	assessment := &RiskAssessment{
+		Cutoff:     cutoff,
+		Triggered:  make([]RiskRuleHit, 0, len(hits)),
+		AssessedAt: time.Now(),
+	}
+
+	for _, hit := range hits {
+		assessment.Score += hit.Score
+		assessment.Triggered = append(assessment.Triggered, hit)
+	}
+
+	return assessment
+}
+
+// ShouldHold проверяет, превышает ли риск порог задержки заказа
+func (a *RiskAssessment) ShouldHold() bool {
+	return a.Cutoff > 0 && a.Score >= a.Cutoff
+}
+
+// TriggeredRuleNames возвращает имена сработавших правил
+func (a *RiskAssessment) TriggeredRuleNames() []string {
+	names := make([]string, 0, len(a.Triggered))
+	for _, hit := range a.Triggered {
+		names = append(names, hit.Rule)
+	}
+	return names
+}
@@ -0,0 +1,195 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShipmentStatus представляет статус отправления
+type ShipmentStatus string
+
+// Возможные статусы отправления
+const (
+	ShipmentStatusCreated   ShipmentStatus = "created"    // Создано, передано перевозчику
+	ShipmentStatusInTransit ShipmentStatus = "in_transit" // В пути
+	ShipmentStatusDelivered ShipmentStatus = "delivered"  // Доставлено
+	ShipmentStatusReturned  ShipmentStatus = "returned"   // Возвращено отправителю
+)
+
+// shipmentTransitions допустимые переходы статусов отправления; delivered и returned - финальные
+var shipmentTransitions = map[ShipmentStatus][]ShipmentStatus{
+	ShipmentStatusCreated:   {ShipmentStatusInTransit, ShipmentStatusDelivered, ShipmentStatusReturned},
+	ShipmentStatusInTransit: {ShipmentStatusDelivered, ShipmentStatusReturned},
+}
+
+// IsValid проверяет, что статус отправления известен
+func (s ShipmentStatus) IsValid() bool {
+	switch s {
+	case ShipmentStatusCreated, ShipmentStatusInTransit, ShipmentStatusDelivered, ShipmentStatusReturned:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo проверяет допустимость перехода в статус next
+func (s ShipmentStatus) CanTransitionTo(next ShipmentStatus) bool {
+	for _, allowed := range shipmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ShipmentItem количество позиции заказа в отправлении
+type ShipmentItem struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	ShipmentID  uuid.UUID `json:"shipment_id" db:"shipment_id"`
+	OrderItemID uuid.UUID `json:"order_item_id" db:"order_item_id"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+}
+
+// Shipment представляет посылку с частью товаров заказа
+type Shipment struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	OrderID        uuid.UUID      `json:"order_id" db:"order_id"`
+	Carrier        string         `json:"carrier" db:"carrier"`
+	TrackingNumber string         `json:"tracking_number" db:"tracking_number"`
+	Status         ShipmentStatus `json:"status" db:"status"`
+	Items          []ShipmentItem `json:"items" db:"-"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// NewShipment создает новое отправление заказа
+func NewShipment(orderID uuid.UUID, carrier, trackingNumber string) *Shipment {
+	now := time.Now()
+	return &Shipment{
+		ID:             uuid.New(),
+		OrderID:        orderID,
+		Carrier:        strings.TrimSpace(carrier),
+		TrackingNumber: strings.TrimSpace(trackingNumber),
+		Status:         ShipmentStatusCreated,
+		Items:          make([]ShipmentItem, 0),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// AddItem добавляет позицию заказа в отправление
+func (s *Shipment) AddItem(orderItemID uuid.UUID, quantity int) {
+	s.Items = append(s.Items, ShipmentItem{
+		ID:          uuid.New(),
+		ShipmentID:  s.ID,
+		OrderItemID: orderItemID,
+		Quantity:    quantity,
+	})
+}
+
+// UpdateStatus переводит отправление в новый статус
+func (s *Shipment) UpdateStatus(status ShipmentStatus) error {
+	if !status.IsValid() {
+		return NewValidationError("unknown shipment status %q", status)
+	}
+	if !s.Status.CanTransitionTo(status) {
+		return NewInvalidShipmentTransitionError(s.Status, status)
+	}
+	s.Status = status
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// Validate выполняет валидацию отправления
+func (s *Shipment) Validate() error {
+	if s.Carrier == "" {
+		return NewValidationError("carrier is required")
+	}
+
+	if s.TrackingNumber == "" {
+		return NewValidationError("tracking number is required")
+	}
+
+	if len(s.Items) == 0 {
+		return NewValidationError("shipment must have at least one item")
+	}
+
+	for i, item := range s.Items {
+		if item.OrderItemID == uuid.Nil {
+			return NewValidationError("shipment item %d: order item ID cannot be empty", i)
+		}
+		if item.Quantity <= 0 {
+			return NewValidationError("shipment item %d: quantity must be greater than zero", i)
+		}
+	}
+
+	return nil
+}
+
+// ShippedQuantities суммирует отправленное количество по позициям заказа
+func ShippedQuantities(shipments []*Shipment) map[uuid.UUID]int {
+	shipped := make(map[uuid.UUID]int)
+	for _, shipment := range shipments {
+		if shipment.Status == ShipmentStatusReturned {
+			continue
+		}
+		for _, item := range shipment.Items {
+			shipped[item.OrderItemID] += item.Quantity
+		}
+	}
+	return shipped
+}
+
+// AllShipmentsDelivered проверяет, что все отправления, кроме возвращенных, доставлены
+// и хотя бы одно доставлено
+func AllShipmentsDelivered(shipments []*Shipment) bool {
+	delivered := false
+	for _, shipment := range shipments {
+		switch shipment.Status {
+		case ShipmentStatusReturned:
+		case ShipmentStatusDelivered:
+			delivered = true
+		default:
+			return false
+		}
+	}
+	return delivered
+}
+
+// RemainingToShip возвращает неотправленное количество по каждой позиции заказа
+func (o *Order) RemainingToShip(shipments []*Shipment) map[uuid.UUID]int {
+	shipped := ShippedQuantities(shipments)
+	remaining := make(map[uuid.UUID]int, len(o.Items))
+	for _, item := range o.Items {
+		if left := item.Quantity - shipped[item.ID]; left > 0 {
+			remaining[item.ID] = left
+		}
+	}
+	return remaining
+}
+
+// ShipmentStatusAfter проверяет новое отправление против уже отправленного
+// и возвращает производный статус заказа: shipped или partially_shipped
+func (o *Order) ShipmentStatusAfter(existing []*Shipment, shipment *Shipment) (OrderStatus, error) {
+	remaining := o.RemainingToShip(existing)
+
+	for i, item := range shipment.Items {
+		left, exists := remaining[item.OrderItemID]
+		if !exists {
+			return "", NewValidationError("shipment item %d: order item %s is not part of the order or already shipped", i, item.OrderItemID)
+		}
+		if item.Quantity > left {
+			return "", NewValidationError("shipment item %d: quantity %d exceeds remaining %d", i, item.Quantity, left)
+		}
+		remaining[item.OrderItemID] = left - item.Quantity
+	}
+
+	for _, left := range remaining {
+		if left > 0 {
+			return OrderStatusPartiallyShipped, nil
+		}
+	}
+
+	return OrderStatusShipped, nil
+}
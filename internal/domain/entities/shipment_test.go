@@ -0,0 +1,102 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newShipmentTestOrder(t *testing.T) *Order {
+	t.Helper()
+
+	order := NewOrder(uuid.New(), "test@example.com")
+	order.AddItem(uuid.New(), "Product 1", 10.0, 2)
+	order.AddItem(uuid.New(), "Product 2", 5.0, 1)
+	order.Status = OrderStatusProcessing
+	return order
+}
+
+func TestShipmentStatusAfter(t *testing.T) {
+	order := newShipmentTestOrder(t)
+
+	first := NewShipment(order.ID, "DHL", "TRK-1")
+	first.AddItem(order.Items[0].ID, 1)
+
+	status, err := order.ShipmentStatusAfter(nil, first)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != OrderStatusPartiallyShipped {
+		t.Errorf("Expected status %s, got %s", OrderStatusPartiallyShipped, status)
+	}
+
+	second := NewShipment(order.ID, "DHL", "TRK-2")
+	second.AddItem(order.Items[0].ID, 1)
+	second.AddItem(order.Items[1].ID, 1)
+
+	status, err = order.ShipmentStatusAfter([]*Shipment{first}, second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != OrderStatusShipped {
+		t.Errorf("Expected status %s, got %s", OrderStatusShipped, status)
+	}
+}
+
+func TestShipmentStatusAfterRejectsOverShipping(t *testing.T) {
+	order := newShipmentTestOrder(t)
+
+	first := NewShipment(order.ID, "DHL", "TRK-1")
+	first.AddItem(order.Items[0].ID, 2)
+
+	second := NewShipment(order.ID, "DHL", "TRK-2")
+	second.AddItem(order.Items[0].ID, 1)
+
+	if _, err := order.ShipmentStatusAfter([]*Shipment{first}, second); err == nil {
+		t.Error("Expected error when shipping more than ordered")
+	} else if _, ok := err.(ValidationError); !ok {
+		t.Errorf("Expected ValidationError, got %T", err)
+	}
+
+	// Возвращенная посылка освобождает количество
+	first.Status = ShipmentStatusReturned
+	if _, err := order.ShipmentStatusAfter([]*Shipment{first}, second); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestShipmentUpdateStatus(t *testing.T) {
+	shipment := NewShipment(uuid.New(), "DHL", "TRK-1")
+
+	if err := shipment.UpdateStatus("lost"); err == nil {
+		t.Fatal("expected error for unknown status")
+	}
+	if err := shipment.UpdateStatus(ShipmentStatusInTransit); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := shipment.UpdateStatus(ShipmentStatusCreated); err == nil {
+		t.Fatal("expected error moving back to created")
+	}
+	if err := shipment.UpdateStatus(ShipmentStatusDelivered); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := shipment.UpdateStatus(ShipmentStatusReturned); err == nil {
+		t.Fatal("expected delivered to be final")
+	}
+}
+
+func TestAllShipmentsDelivered(t *testing.T) {
+	delivered := &Shipment{Status: ShipmentStatusDelivered}
+	returned := &Shipment{Status: ShipmentStatusReturned}
+	inTransit := &Shipment{Status: ShipmentStatusInTransit}
+
+	if !AllShipmentsDelivered([]*Shipment{delivered, returned}) {
+		t.Error("expected returned shipments to be ignored")
+	}
+	if AllShipmentsDelivered([]*Shipment{delivered, inTransit}) {
+		t.Error("expected shipment in transit to block delivery")
+	}
+	if AllShipmentsDelivered([]*Shipment{returned}) {
+		t.Error("expected at least one delivered shipment")
+	}
+}
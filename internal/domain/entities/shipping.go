@@ -0,0 +1,222 @@
+package entities
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ShippingMethod представляет способ доставки заказа
+type ShippingMethod string
+
+// Стандартные способы доставки
+const (
+	ShippingMethodStandard ShippingMethod = "standard" // Обычная доставка
+	ShippingMethodExpress  ShippingMethod = "express"  // Ускоренная доставка
+	ShippingMethodPickup   ShippingMethod = "pickup"   // Самовывоз
+)
+
+// ShippingBasis единица, по которой тарифицируется доставка
+type ShippingBasis string
+
+// Поддерживаемые основания тарифа
+const (
+	ShippingBasisWeight ShippingBasis = "weight" // По весу посылки, кг
+	ShippingBasisItems  ShippingBasis = "items"  // По количеству единиц товара
+)
+
+// ShippingRate правило тарифа: страны назначения и диапазон [Min, Max) веса
+// или количества. Пустой список стран - тариф для остальных стран, Max = 0 - без
+// верхней границы. Стоимость = Amount + PerUnit * вес/количество.
+type ShippingRate struct {
+	Countries []string      `json:"countries,omitempty" yaml:"countries"`
+	Basis     ShippingBasis `json:"basis" yaml:"basis"`
+	Min       float64       `json:"min,omitempty" yaml:"min"`
+	Max       float64       `json:"max,omitempty" yaml:"max"`
+	Amount    float64       `json:"amount" yaml:"amount"`
+	PerUnit   float64       `json:"per_unit,omitempty" yaml:"per_unit"`
+}
+
+// ShippingMethodDefinition способ доставки с тарифами
+type ShippingMethodDefinition struct {
+	Method ShippingMethod `json:"method" yaml:"method"`
+	Name   string         `json:"name" yaml:"name"`
+	// RequiresAddress - для расчета нужен адрес доставки (не нужен для самовывоза)
+	RequiresAddress bool           `json:"requires_address" yaml:"requires_address"`
+	Rates           []ShippingRate `json:"rates" yaml:"rates"`
+}
+
+// ShippingParcel параметры посылки для расчета доставки
+type ShippingParcel struct {
+	Weight    float64 `json:"weight"`
+	ItemCount int     `json:"item_count"`
+}
+
+// ShippingQuote рассчитанная стоимость доставки способом
+type ShippingQuote struct {
+	Method   ShippingMethod `json:"method"`
+	Name     string         `json:"name"`
+	Amount   float64        `json:"amount"`
+	Currency string         `json:"currency"`
+}
+
+// ShippingTable тарифы доставки в валюте таблицы
+type ShippingTable struct {
+	currency string
+	methods  map[ShippingMethod]ShippingMethodDefinition
+	order    []ShippingMethod
+}
+
+// NewShippingTable создает и проверяет таблицу тарифов доставки
+func NewShippingTable(currency string, methods []ShippingMethodDefinition) (*ShippingTable, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return nil, NewValidationError("shipping currency is required")
+	}
+
+	table := &ShippingTable{
+		currency: currency,
+		methods:  make(map[ShippingMethod]ShippingMethodDefinition, len(methods)),
+	}
+
+	for _, m := range methods {
+		m.Method = ShippingMethod(strings.ToLower(strings.TrimSpace(string(m.Method))))
+		if m.Method == "" {
+			return nil, NewValidationError("shipping method is required")
+		}
+		if _, exists := table.methods[m.Method]; exists {
+			return nil, NewValidationError("duplicate shipping method: %s", m.Method)
+		}
+		if len(m.Rates) == 0 {
+			return nil, NewValidationError("shipping method %s has no rates", m.Method)
+		}
+
+		for i, rate := range m.Rates {
+			if rate.Basis != ShippingBasisWeight && rate.Basis != ShippingBasisItems {
+				return nil, NewValidationError("shipping method %s rate %d: unknown basis %s", m.Method, i, rate.Basis)
+			}
+			if rate.Min < 0 || (rate.Max != 0 && rate.Max <= rate.Min) {
+				return nil, NewValidationError("shipping method %s rate %d: invalid range", m.Method, i)
+			}
+			if rate.Amount < 0 || rate.PerUnit < 0 {
+				return nil, NewValidationError("shipping method %s rate %d: amount cannot be negative", m.Method, i)
+			}
+			countries := make([]string, 0, len(rate.Countries))
+			for _, country := range rate.Countries {
+				countries = append(countries, strings.ToUpper(strings.TrimSpace(country)))
+			}
+			m.Rates[i].Countries = countries
+		}
+
+		table.methods[m.Method] = m
+		table.order = append(table.order, m.Method)
+	}
+
+	return table, nil
+}
+
+// Currency возвращает валюту тарифов
+func (t *ShippingTable) Currency() string {
+	return t.currency
+}
+
+// Quote рассчитывает стоимость доставки способом method.
+// Тарифы с указанной страной имеют приоритет над тарифами для остальных стран.
+func (t *ShippingTable) Quote(method ShippingMethod, address *Address, parcel ShippingParcel) (*ShippingQuote, error) {
+	method = ShippingMethod(strings.ToLower(strings.TrimSpace(string(method))))
+	def, exists := t.methods[method]
+	if !exists {
+		return nil, NewValidationError("unknown shipping method: %s", method)
+	}
+
+	country := ""
+	if address != nil {
+		country = strings.ToUpper(strings.TrimSpace(address.Country))
+	}
+	if def.RequiresAddress && country == "" {
+		return nil, NewValidationError("shipping method %s requires a shipping address", method)
+	}
+
+	rate, found := def.matchRate(country, parcel, true)
+	if !found {
+		rate, found = def.matchRate(country, parcel, false)
+	}
+	if !found {
+		return nil, NewValidationError("shipping method %s is not available for this destination and parcel", method)
+	}
+
+	amount := rate.Amount + rate.PerUnit*rate.measure(parcel)
+	return &ShippingQuote{
+		Method:   def.Method,
+		Name:     def.Name,
+		Amount:   math.Round(amount*100) / 100,
+		Currency: t.currency,
+	}, nil
+}
+
+// Quotes рассчитывает стоимость всеми доступными способами, от дешевого к дорогому
+func (t *ShippingTable) Quotes(address *Address, parcel ShippingParcel) []ShippingQuote {
+	quotes := make([]ShippingQuote, 0, len(t.order))
+	for _, method := range t.order {
+		quote, err := t.Quote(method, address, parcel)
+		if err != nil {
+			continue
+		}
+		quotes = append(quotes, *quote)
+	}
+
+	sort.SliceStable(quotes, func(i, j int) bool {
+		return quotes[i].Amount < quotes[j].Amount
+	})
+	return quotes
+}
+
+// matchRate ищет тариф для страны (specific) или общий тариф, подходящий по диапазону
+func (m ShippingMethodDefinition) matchRate(country string, parcel ShippingParcel, specific bool) (ShippingRate, bool) {
+	for _, rate := range m.Rates {
+		if specific != (len(rate.Countries) > 0) {
+			continue
+		}
+		if specific && !containsString(rate.Countries, country) {
+			continue
+		}
+
+		value := rate.measure(parcel)
+		if value < rate.Min || (rate.Max != 0 && value >= rate.Max) {
+			continue
+		}
+		return rate, true
+	}
+	return ShippingRate{}, false
+}
+
+// measure возвращает вес или количество посылки в зависимости от основания тарифа
+func (r ShippingRate) measure(parcel ShippingParcel) float64 {
+	if r.Basis == ShippingBasisItems {
+		return float64(parcel.ItemCount)
+	}
+	return parcel.Weight
+}
+
+// containsString проверяет наличие строки в списке
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyShipping устанавливает способ и стоимость доставки заказа.
+// При купоне бесплатной доставки стоимость не начисляется.
+func (o *Order) ApplyShipping(quote *ShippingQuote) {
+	o.ShippingMethod = quote.Method
+	o.ShippingAmount = quote.Amount
+	if o.HasFreeShipping() {
+		o.ShippingAmount = 0
+	}
+	o.recalculate()
+	o.UpdatedAt = time.Now()
+}
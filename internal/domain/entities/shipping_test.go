@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTestShippingTable(t *testing.T) *ShippingTable {
+	table, err := NewShippingTable("usd", []ShippingMethodDefinition{
+		{Method: ShippingMethodStandard, RequiresAddress: true, Rates: []ShippingRate{
+			{Countries: []string{"us"}, Basis: ShippingBasisWeight, Max: 5, Amount: 5},
+			{Countries: []string{"us"}, Basis: ShippingBasisWeight, Min: 5, Amount: 5, PerUnit: 1},
+			{Basis: ShippingBasisWeight, Amount: 15},
+		}},
+		{Method: ShippingMethodPickup, Rates: []ShippingRate{{Basis: ShippingBasisItems}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return table
+}
+
+func TestShippingTable_QuoteByCountryAndWeight(t *testing.T) {
+	table := newTestShippingTable(t)
+	us := &Address{Country: "US"}
+
+	quote, err := table.Quote(ShippingMethodStandard, us, ShippingParcel{Weight: 2})
+	if err != nil || quote.Amount != 5 {
+		t.Fatalf("Expected 5.00 for light US parcel, got %+v, %v", quote, err)
+	}
+
+	quote, err = table.Quote(ShippingMethodStandard, us, ShippingParcel{Weight: 7.5})
+	if err != nil || quote.Amount != 12.5 {
+		t.Fatalf("Expected 12.50 for heavy US parcel, got %+v, %v", quote, err)
+	}
+
+	// Для остальных стран действует общий тариф
+	quote, err = table.Quote(ShippingMethodStandard, &Address{Country: "FR"}, ShippingParcel{Weight: 2})
+	if err != nil || quote.Amount != 15 {
+		t.Fatalf("Expected fallback rate 15.00, got %+v, %v", quote, err)
+	}
+
+	if _, err := table.Quote(ShippingMethodStandard, nil, ShippingParcel{Weight: 2}); err == nil {
+		t.Error("Expected standard shipping to require an address")
+	}
+
+	quotes := table.Quotes(nil, ShippingParcel{ItemCount: 1})
+	if len(quotes) != 1 || quotes[0].Method != ShippingMethodPickup {
+		t.Errorf("Expected only pickup without address, got %+v", quotes)
+	}
+}
+
+func TestApplyShipping_AddsToTotalUnlessFree(t *testing.T) {
+	order := NewOrder(uuid.New(), "test@example.com")
+	order.AddItem(uuid.New(), "Product 1", 20.0, 2)
+
+	order.ApplyShipping(&ShippingQuote{Method: ShippingMethodExpress, Amount: 9.99})
+	if order.ShippingAmount != 9.99 || order.TotalAmount != 49.99 {
+		t.Errorf("Expected shipping 9.99 and total 49.99, got %.2f and %.2f", order.ShippingAmount, order.TotalAmount)
+	}
+
+	free := NewPromotion("shipfree", "Free shipping", PromotionTypeFreeShipping)
+	if err := order.ApplyPromotions([]*Promotion{free}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.ShippingAmount != 0 || order.TotalAmount != 40.0 {
+		t.Errorf("Expected free shipping and total 40.00, got %.2f and %.2f", order.ShippingAmount, order.TotalAmount)
+	}
+}
@@ -0,0 +1,353 @@
+package entities
+
+import (
+	"sort"
+)
+
+// MetadataSalesChannel ключ метаданных заказа с каналом продаж
+const MetadataSalesChannel = "sales_channel"
+
+// StateDefinition описывает состояние заказа в машине состояний
+type StateDefinition struct {
+	Name        OrderStatus `json:"name" yaml:"name"`
+	Final       bool        `json:"final,omitempty" yaml:"final"`
+	Description string      `json:"description,omitempty" yaml:"description"`
+}
+
+// TransitionDefinition описывает допустимый переход между состояниями
+type TransitionDefinition struct {
+	From   OrderStatus `json:"from" yaml:"from"`
+	To     OrderStatus `json:"to" yaml:"to"`
+	Action string      `json:"action,omitempty" yaml:"action"` // Название действия для UI (confirm, ship, ...)
+	Event  string      `json:"event" yaml:"event"`             // Тип события, публикуемого при переходе
+	Guards []string    `json:"guards,omitempty" yaml:"guards"` // Имена условий, которые должны выполняться
+}
+
+// StateMachineDefinition декларативное описание машины состояний заказа
+type StateMachineDefinition struct {
+	Initial     OrderStatus            `json:"initial" yaml:"initial"`
+	States      []StateDefinition      `json:"states" yaml:"states"`
+	Transitions []TransitionDefinition `json:"transitions" yaml:"transitions"`
+}
+
+// TransitionGuard проверяет, может ли заказ выполнить переход
+type TransitionGuard func(order *Order) error
+
+// transitionGuards зарегистрированные условия переходов
+var transitionGuards = map[string]TransitionGuard{
+	"has_items": func(order *Order) error {
+		if len(order.Items) == 0 {
+			return NewValidationError("order has no items")
+		}
+		return nil
+	},
+	"has_shipping_address": func(order *Order) error {
+		if order.ShippingAddress == nil {
+			return NewValidationError("order has no shipping address")
+		}
+		return nil
+	},
+	"has_billing_address": func(order *Order) error {
+		if order.BillingAddress == nil {
+			return NewValidationError("order has no billing address")
+		}
+		return nil
+	},
+}
+
+// RegisterTransitionGuard регистрирует условие перехода под указанным именем.
+// Вызывается при инициализации, до загрузки машин состояний.
+func RegisterTransitionGuard(name string, guard TransitionGuard) {
+	transitionGuards[name] = guard
+}
+
+// StateMachine проверенная машина состояний заказа
+type StateMachine struct {
+	definition  StateMachineDefinition
+	states      map[OrderStatus]StateDefinition
+	transitions map[OrderStatus][]TransitionDefinition
+}
+
+// NewStateMachine создает машину состояний и проверяет корректность описания
+func NewStateMachine(def StateMachineDefinition) (*StateMachine, error) {
+	sm := &StateMachine{
+		definition:  def,
+		states:      make(map[OrderStatus]StateDefinition, len(def.States)),
+		transitions: make(map[OrderStatus][]TransitionDefinition),
+	}
+
+	if len(def.States) == 0 {
+		return nil, NewValidationError("state machine must define at least one state")
+	}
+
+	for _, state := range def.States {
+		if state.Name == "" {
+			return nil, NewValidationError("state name cannot be empty")
+		}
+		if _, exists := sm.states[state.Name]; exists {
+			return nil, NewValidationError("duplicate state %s", state.Name)
+		}
+		sm.states[state.Name] = state
+	}
+
+	if _, exists := sm.states[def.Initial]; !exists {
+		return nil, NewValidationError("initial state %q is not defined", def.Initial)
+	}
+	// Заказ создается в pending: на этом статусе основаны автоотмена, резервы и антифрод
+	if def.Initial != OrderStatusPending {
+		return nil, NewValidationError("initial state must be %s, got %q", OrderStatusPending, def.Initial)
+	}
+
+	seen := make(map[[2]OrderStatus]bool)
+	for i, tr := range def.Transitions {
+		from, fromExists := sm.states[tr.From]
+		if !fromExists {
+			return nil, NewValidationError("transition %d: unknown source state %q", i, tr.From)
+		}
+		if _, exists := sm.states[tr.To]; !exists {
+			return nil, NewValidationError("transition %d: unknown target state %q", i, tr.To)
+		}
+		if from.Final {
+			return nil, NewValidationError("transition %d: final state %s cannot have outgoing transitions", i, tr.From)
+		}
+		if tr.Event == "" {
+			return nil, NewValidationError("transition %d (%s -> %s): event is required", i, tr.From, tr.To)
+		}
+		for _, guard := range tr.Guards {
+			if _, exists := transitionGuards[guard]; !exists {
+				return nil, NewValidationError("transition %d (%s -> %s): unknown guard %q", i, tr.From, tr.To, guard)
+			}
+		}
+
+		key := [2]OrderStatus{tr.From, tr.To}
+		if seen[key] {
+			return nil, NewValidationError("duplicate transition %s -> %s", tr.From, tr.To)
+		}
+		seen[key] = true
+
+		sm.transitions[tr.From] = append(sm.transitions[tr.From], tr)
+	}
+
+	// Все состояния должны быть достижимы из начального
+	reachable := map[OrderStatus]bool{def.Initial: true}
+	queue := []OrderStatus{def.Initial}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, tr := range sm.transitions[current] {
+			if !reachable[tr.To] {
+				reachable[tr.To] = true
+				queue = append(queue, tr.To)
+			}
+		}
+	}
+	for _, state := range def.States {
+		if !reachable[state.Name] {
+			return nil, NewValidationError("state %s is not reachable from %s", state.Name, def.Initial)
+		}
+	}
+
+	return sm, nil
+}
+
+// Definition возвращает описание машины состояний
+func (sm *StateMachine) Definition() StateMachineDefinition {
+	return sm.definition
+}
+
+// InitialState возвращает начальное состояние
+func (sm *StateMachine) InitialState() OrderStatus {
+	return sm.definition.Initial
+}
+
+// HasState проверяет, определено ли состояние
+func (sm *StateMachine) HasState(status OrderStatus) bool {
+	_, exists := sm.states[status]
+	return exists
+}
+
+// IsFinal проверяет, является ли состояние финальным
+func (sm *StateMachine) IsFinal(status OrderStatus) bool {
+	return sm.states[status].Final
+}
+
+// AllowedTransitions возвращает переходы, доступные из состояния
+func (sm *StateMachine) AllowedTransitions(from OrderStatus) []TransitionDefinition {
+	return sm.transitions[from]
+}
+
+// Transition возвращает описание перехода, если он разрешен
+func (sm *StateMachine) Transition(from, to OrderStatus) (TransitionDefinition, bool) {
+	for _, tr := range sm.transitions[from] {
+		if tr.To == to {
+			return tr, true
+		}
+	}
+	return TransitionDefinition{}, false
+}
+
+// CheckTransition проверяет возможность перехода заказа, включая условия
+func (sm *StateMachine) CheckTransition(order *Order, to OrderStatus) (TransitionDefinition, error) {
+	if !sm.HasState(to) {
+		return TransitionDefinition{}, NewValidationError("invalid status: %s", to)
+	}
+
+	tr, allowed := sm.Transition(order.Status, to)
+	if !allowed {
+		return TransitionDefinition{}, NewInvalidStatusTransitionError(order.Status, to)
+	}
+
+	for _, name := range tr.Guards {
+		if err := transitionGuards[name](order); err != nil {
+			return TransitionDefinition{}, NewTransitionGuardError(order.Status, to, name, err)
+		}
+	}
+
+	return tr, nil
+}
+
+// StateMachineRegistry хранит машину состояний по умолчанию и машины для каналов продаж
+type StateMachineRegistry struct {
+	defaultMachine *StateMachine
+	channels       map[string]*StateMachine
+	tenants        map[string]*StateMachineRegistry
+}
+
+// NewStateMachineRegistry создает реестр машин состояний
+func NewStateMachineRegistry(defaultMachine *StateMachine, channels map[string]*StateMachine) *StateMachineRegistry {
+	if channels == nil {
+		channels = make(map[string]*StateMachine)
+	}
+	return &StateMachineRegistry{
+		defaultMachine: defaultMachine,
+		channels:       channels,
+	}
+}
+
+// Default возвращает машину состояний по умолчанию
+func (r *StateMachineRegistry) Default() *StateMachine {
+	return r.defaultMachine
+}
+
+// ForChannel возвращает машину состояний канала продаж (или по умолчанию)
+func (r *StateMachineRegistry) ForChannel(channel string) *StateMachine {
+	if sm, exists := r.channels[channel]; exists {
+		return sm
+	}
+	return r.defaultMachine
+}
+
+// WithTenants возвращает реестр, в котором у арендаторов собственные машины состояний
+func (r *StateMachineRegistry) WithTenants(tenants map[string]*StateMachineRegistry) *StateMachineRegistry {
+	return &StateMachineRegistry{
+		defaultMachine: r.defaultMachine,
+		channels:       r.channels,
+		tenants:        tenants,
+	}
+}
+
+// ForTenant возвращает реестр арендатора (или общий)
+func (r *StateMachineRegistry) ForTenant(tenantID string) *StateMachineRegistry {
+	if registry, exists := r.tenants[tenantID]; exists {
+		return registry
+	}
+	return r
+}
+
+// ForOrder возвращает машину состояний для заказа по его арендатору и каналу продаж
+func (r *StateMachineRegistry) ForOrder(order *Order) *StateMachine {
+	channel, _ := order.Metadata[MetadataSalesChannel].(string)
+	return r.ForTenant(order.TenantID).ForChannel(channel)
+}
+
+// Channels возвращает отсортированный список каналов с собственными машинами состояний
+func (r *StateMachineRegistry) Channels() []string {
+	channels := make([]string, 0, len(r.channels))
+	for channel := range r.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// RequireTransition проверяет, что переход from -> to есть в каждой машине состояний реестра,
+// включая машины каналов и арендаторов
+func (r *StateMachineRegistry) RequireTransition(from, to OrderStatus) error {
+	if _, allowed := r.defaultMachine.Transition(from, to); !allowed {
+		return NewValidationError("default state machine has no %s -> %s transition", from, to)
+	}
+	for _, channel := range r.Channels() {
+		if _, allowed := r.channels[channel].Transition(from, to); !allowed {
+			return NewValidationError("state machine of channel %s has no %s -> %s transition", channel, from, to)
+		}
+	}
+
+	tenants := make([]string, 0, len(r.tenants))
+	for tenantID := range r.tenants {
+		tenants = append(tenants, tenantID)
+	}
+	sort.Strings(tenants)
+	for _, tenantID := range tenants {
+		if err := r.tenants[tenantID].RequireTransition(from, to); err != nil {
+			return NewValidationError("tenant %s: %v", tenantID, err)
+		}
+	}
+	return nil
+}
+
+// DefaultStateMachineDefinition возвращает стандартный жизненный цикл заказа
+func DefaultStateMachineDefinition() StateMachineDefinition {
+	return StateMachineDefinition{
+		Initial: OrderStatusPending,
+		States: []StateDefinition{
+			{Name: OrderStatusPending, Description: "Ожидает обработки"},
+			{Name: OrderStatusConfirmed, Description: "Подтвержден"},
+			{Name: OrderStatusProcessing, Description: "В обработке"},
+			{Name: OrderStatusPartiallyShipped, Description: "Отправлен частично"},
+			{Name: OrderStatusShipped, Description: "Отправлен"},
+			{Name: OrderStatusDelivered, Description: "Доставлен"},
+			{Name: OrderStatusCancelled, Final: true, Description: "Отменен"},
+			{Name: OrderStatusPartiallyRefunded, Description: "Возвращен частично"},
+			{Name: OrderStatusRefunded, Final: true, Description: "Возврат"},
+			{Name: OrderStatusOnHold, Description: "Задержан до проверки"},
+			{Name: OrderStatusInReview, Description: "На ручной проверке"},
+		},
+		Transitions: []TransitionDefinition{
+			{From: OrderStatusPending, To: OrderStatusConfirmed, Action: "confirm", Event: EventOrderConfirmed},
+			{From: OrderStatusPending, To: OrderStatusCancelled, Action: "cancel", Event: EventOrderCancelled},
+			{From: OrderStatusPending, To: OrderStatusOnHold, Action: "hold", Event: EventOrderHeld},
+			{From: OrderStatusOnHold, To: OrderStatusInReview, Action: "review", Event: EventOrderStatusChanged},
+			{From: OrderStatusOnHold, To: OrderStatusConfirmed, Action: "approve", Event: EventOrderConfirmed},
+			{From: OrderStatusOnHold, To: OrderStatusCancelled, Action: "reject", Event: EventOrderCancelled},
+			{From: OrderStatusInReview, To: OrderStatusConfirmed, Action: "approve", Event: EventOrderConfirmed},
+			{From: OrderStatusInReview, To: OrderStatusCancelled, Action: "reject", Event: EventOrderCancelled},
+			{From: OrderStatusConfirmed, To: OrderStatusProcessing, Action: "process", Event: EventOrderStatusChanged},
+			{From: OrderStatusConfirmed, To: OrderStatusCancelled, Action: "cancel", Event: EventOrderCancelled},
+			{From: OrderStatusProcessing, To: OrderStatusShipped, Action: "ship", Event: EventOrderShipped},
+			{From: OrderStatusProcessing, To: OrderStatusPartiallyShipped, Action: "ship_partial", Event: EventOrderStatusChanged},
+			{From: OrderStatusPartiallyShipped, To: OrderStatusShipped, Action: "ship", Event: EventOrderShipped},
+			{From: OrderStatusProcessing, To: OrderStatusCancelled, Action: "cancel", Event: EventOrderCancelled},
+			{From: OrderStatusShipped, To: OrderStatusDelivered, Action: "deliver", Event: EventOrderDelivered},
+			{From: OrderStatusDelivered, To: OrderStatusRefunded, Action: "refund", Event: EventOrderRefunded},
+			{From: OrderStatusDelivered, To: OrderStatusPartiallyRefunded, Action: "refund_partial", Event: EventOrderStatusChanged},
+			{From: OrderStatusPartiallyRefunded, To: OrderStatusRefunded, Action: "refund", Event: EventOrderRefunded},
+		},
+	}
+}
+
+// defaultStateMachine машина состояний по умолчанию, используется Order.UpdateStatus
+var defaultStateMachine = mustStateMachine(DefaultStateMachineDefinition())
+
+// DefaultStateMachine возвращает стандартную машину состояний заказа
+func DefaultStateMachine() *StateMachine {
+	return defaultStateMachine
+}
+
+// mustStateMachine создает машину состояний или паникует при ошибке описания
+func mustStateMachine(def StateMachineDefinition) *StateMachine {
+	sm, err := NewStateMachine(def)
+	if err != nil {
+		panic(err)
+	}
+	return sm
+}
@@ -0,0 +1,146 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewStateMachine_Validation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(def *StateMachineDefinition)
+	}{
+		{"unknown initial state", func(def *StateMachineDefinition) {
+			def.Initial = "unknown"
+		}},
+		{"initial state other than pending", func(def *StateMachineDefinition) {
+			def.Initial = OrderStatusConfirmed
+		}},
+		{"unknown target state", func(def *StateMachineDefinition) {
+			def.Transitions = append(def.Transitions, TransitionDefinition{
+				From: OrderStatusPending, To: "backordered", Event: "order.backordered",
+			})
+		}},
+		{"transition from final state", func(def *StateMachineDefinition) {
+			def.Transitions = append(def.Transitions, TransitionDefinition{
+				From: OrderStatusCancelled, To: OrderStatusPending, Event: "order.reopened",
+			})
+		}},
+		{"missing event", func(def *StateMachineDefinition) {
+			def.Transitions[0].Event = ""
+		}},
+		{"unknown guard", func(def *StateMachineDefinition) {
+			def.Transitions[0].Guards = []string{"is_paid"}
+		}},
+		{"duplicate transition", func(def *StateMachineDefinition) {
+			def.Transitions = append(def.Transitions, def.Transitions[0])
+		}},
+		{"unreachable state", func(def *StateMachineDefinition) {
+			def.States = append(def.States, StateDefinition{Name: "backordered"})
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			def := DefaultStateMachineDefinition()
+			tc.modify(&def)
+
+			if _, err := NewStateMachine(def); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestStateMachine_CheckTransition(t *testing.T) {
+	def := DefaultStateMachineDefinition()
+	def.Transitions[0].Guards = []string{"has_shipping_address"}
+
+	sm, err := NewStateMachine(def)
+	if err != nil {
+		t.Fatalf("Expected valid state machine, got %v", err)
+	}
+
+	order := NewOrder(uuid.New(), "test@example.com")
+	order.AddItem(uuid.New(), "Test Product", 10.0, 1)
+
+	if _, err := order.TransitionTo(sm, OrderStatusConfirmed); err == nil {
+		t.Error("Expected guard error without shipping address")
+	}
+
+	order.SetShippingAddress(&Address{Street: "Main St", City: "Minsk", Country: "BY", ZipCode: "220030"})
+
+	transition, err := order.TransitionTo(sm, OrderStatusConfirmed)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if transition.Event != EventOrderConfirmed {
+		t.Errorf("Expected event %s, got %s", EventOrderConfirmed, transition.Event)
+	}
+
+	if _, err := order.TransitionTo(sm, "unknown"); err == nil {
+		t.Error("Expected error for unknown status")
+	}
+}
+
+func TestStateMachineRegistry_ForOrder(t *testing.T) {
+	digital, err := NewStateMachine(StateMachineDefinition{
+		Initial: OrderStatusPending,
+		States: []StateDefinition{
+			{Name: OrderStatusPending},
+			{Name: OrderStatusDelivered, Final: true},
+		},
+		Transitions: []TransitionDefinition{
+			{From: OrderStatusPending, To: OrderStatusDelivered, Event: EventOrderDelivered},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid state machine, got %v", err)
+	}
+
+	registry := NewStateMachineRegistry(DefaultStateMachine(), map[string]*StateMachine{"digital": digital})
+
+	order := NewOrder(uuid.New(), "test@example.com")
+	if registry.ForOrder(order) != DefaultStateMachine() {
+		t.Error("Expected default state machine for order without channel")
+	}
+
+	order.Metadata[MetadataSalesChannel] = "digital"
+	if registry.ForOrder(order) != digital {
+		t.Error("Expected digital state machine for digital channel")
+	}
+
+	if _, err := order.TransitionTo(registry.ForOrder(order), OrderStatusDelivered); err != nil {
+		t.Errorf("Expected digital order to be delivered directly, got %v", err)
+	}
+}
+
+func TestStateMachineRegistry_RequireTransition(t *testing.T) {
+	digital, err := NewStateMachine(StateMachineDefinition{
+		Initial: OrderStatusPending,
+		States: []StateDefinition{
+			{Name: OrderStatusPending},
+			{Name: OrderStatusDelivered, Final: true},
+		},
+		Transitions: []TransitionDefinition{
+			{From: OrderStatusPending, To: OrderStatusDelivered, Event: EventOrderDelivered},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid state machine, got %v", err)
+	}
+
+	registry := NewStateMachineRegistry(DefaultStateMachine(), nil)
+	if err := registry.RequireTransition(OrderStatusPending, OrderStatusOnHold); err != nil {
+		t.Fatalf("Expected default state machine to allow on_hold, got %v", err)
+	}
+
+	tenants := map[string]*StateMachineRegistry{
+		"acme": NewStateMachineRegistry(DefaultStateMachine(), map[string]*StateMachine{"digital": digital}),
+	}
+	if err := registry.WithTenants(tenants).RequireTransition(OrderStatusPending, OrderStatusOnHold); err == nil {
+		t.Error("Expected error for tenant channel without on_hold")
+	}
+}
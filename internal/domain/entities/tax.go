@@ -0,0 +1,248 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultTaxCategory категория налогообложения товара по умолчанию
+const DefaultTaxCategory = "standard"
+
+// TaxRate ставка налога юрисдикции. Пустая категория действует для всех
+// категорий, для которых нет отдельной ставки.
+type TaxRate struct {
+	Name     string  `json:"name" yaml:"name"`
+	Category string  `json:"category,omitempty" yaml:"category"`
+	Rate     float64 `json:"rate" yaml:"rate"` // 0.2 = 20%
+}
+
+// TaxJurisdiction таблица ставок страны или штата/региона страны
+type TaxJurisdiction struct {
+	Country string `json:"country" yaml:"country"`
+	State   string `json:"state,omitempty" yaml:"state"`
+	// Inclusive - цены уже включают налог (НДС), иначе налог добавляется сверху
+	Inclusive bool      `json:"inclusive,omitempty" yaml:"inclusive"`
+	Rates     []TaxRate `json:"rates" yaml:"rates"`
+}
+
+// TaxLine строка налога позиции заказа
+type TaxLine struct {
+	Name         string  `json:"name"`
+	Jurisdiction string  `json:"jurisdiction"`
+	Rate         float64 `json:"rate"`
+	Amount       float64 `json:"amount"`
+	Inclusive    bool    `json:"inclusive"`
+}
+
+// TaxTable таблица ставок налогов по юрисдикциям
+type TaxTable struct {
+	jurisdictions map[string]TaxJurisdiction
+}
+
+// NewTaxTable создает и проверяет таблицу ставок
+func NewTaxTable(jurisdictions []TaxJurisdiction) (*TaxTable, error) {
+	table := &TaxTable{jurisdictions: make(map[string]TaxJurisdiction, len(jurisdictions))}
+
+	for _, j := range jurisdictions {
+		j.Country = strings.ToUpper(strings.TrimSpace(j.Country))
+		j.State = strings.ToUpper(strings.TrimSpace(j.State))
+		if j.Country == "" {
+			return nil, NewValidationError("tax jurisdiction country is required")
+		}
+
+		key := jurisdictionKey(j.Country, j.State)
+		if _, exists := table.jurisdictions[key]; exists {
+			return nil, NewValidationError("duplicate tax jurisdiction: %s", key)
+		}
+
+		for i, rate := range j.Rates {
+			if rate.Rate < 0 || rate.Rate >= 1 {
+				return nil, NewValidationError("tax jurisdiction %s rate %d: rate must be in [0, 1)", key, i)
+			}
+			j.Rates[i].Category = strings.ToLower(strings.TrimSpace(rate.Category))
+		}
+
+		table.jurisdictions[key] = j
+	}
+
+	return table, nil
+}
+
+// Jurisdictions возвращает юрисдикции таблицы в стабильном порядке
+func (t *TaxTable) Jurisdictions() []TaxJurisdiction {
+	keys := make([]string, 0, len(t.jurisdictions))
+	for key := range t.jurisdictions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]TaxJurisdiction, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, t.jurisdictions[key])
+	}
+	return result
+}
+
+// taxRateMatch ставка, примененная к позиции, с ее юрисдикцией
+type taxRateMatch struct {
+	rate         TaxRate
+	jurisdiction string
+}
+
+// ratesFor подбирает ставки для адреса и категории товара. Ставки страны и
+// штата суммируются (например, федеральный и региональный налог), признак
+// включения налога в цену берется из наиболее точной юрисдикции.
+func (t *TaxTable) ratesFor(address *Address, category string) ([]taxRateMatch, bool) {
+	if address == nil {
+		return nil, false
+	}
+
+	country := strings.ToUpper(strings.TrimSpace(address.Country))
+	state := strings.ToUpper(strings.TrimSpace(address.State))
+
+	var matches []taxRateMatch
+	inclusive := false
+
+	keys := []string{jurisdictionKey(country, "")}
+	if state != "" {
+		keys = append(keys, jurisdictionKey(country, state))
+	}
+
+	for _, key := range keys {
+		j, exists := t.jurisdictions[key]
+		if !exists {
+			continue
+		}
+		inclusive = j.Inclusive
+
+		for _, rate := range ratesForCategory(j.Rates, category) {
+			matches = append(matches, taxRateMatch{rate: rate, jurisdiction: key})
+		}
+	}
+
+	return matches, inclusive
+}
+
+// ratesForCategory возвращает ставки категории, а при их отсутствии - общие ставки
+func ratesForCategory(rates []TaxRate, category string) []TaxRate {
+	var specific, general []TaxRate
+	for _, rate := range rates {
+		switch rate.Category {
+		case category:
+			specific = append(specific, rate)
+		case "":
+			general = append(general, rate)
+		}
+	}
+	if len(specific) > 0 {
+		return specific
+	}
+	return general
+}
+
+// jurisdictionKey строит ключ юрисдикции: страна или страна/штат
+func jurisdictionKey(country, state string) string {
+	if state == "" {
+		return country
+	}
+	return country + "/" + state
+}
+
+// ApplyTaxes привязывает таблицу ставок к заказу и рассчитывает налоги.
+// Дальше налоги пересчитываются при изменении позиций, скидок и адреса доставки.
+func (o *Order) ApplyTaxes(table *TaxTable) {
+	o.taxTable = table
+	o.recalculate()
+}
+
+// taxAddress возвращает адрес, определяющий налоговую юрисдикцию
+func (o *Order) taxAddress() *Address {
+	if o.ShippingAddress != nil {
+		return o.ShippingAddress
+	}
+	return o.BillingAddress
+}
+
+// calculateTaxes рассчитывает налоги позиций от стоимости после скидок
+func (o *Order) calculateTaxes() {
+	address := o.taxAddress()
+
+	for i := range o.Items {
+		item := &o.Items[i]
+		item.TaxAmount = 0
+		item.TaxInclusive = false
+		item.TaxLines = nil
+
+		category := item.TaxCategory
+		if category == "" {
+			category = DefaultTaxCategory
+		}
+
+		matches, inclusive := o.taxTable.ratesFor(address, category)
+		if len(matches) == 0 {
+			continue
+		}
+
+		base := toCents(item.Total) - toCents(item.DiscountAmount)
+		combined := 0.0
+		for _, m := range matches {
+			combined += m.rate.Rate
+		}
+
+		var taxCents int64
+		for _, m := range matches {
+			var amount int64
+			if inclusive {
+				// Налог выделяется из цены, уже включающей все ставки
+				amount = toCents(fromCents(base) * m.rate.Rate / (1 + combined))
+			} else {
+				amount = toCents(fromCents(base) * m.rate.Rate)
+			}
+			taxCents += amount
+
+			item.TaxLines = append(item.TaxLines, TaxLine{
+				Name:         m.rate.Name,
+				Jurisdiction: m.jurisdiction,
+				Rate:         m.rate.Rate,
+				Amount:       fromCents(amount),
+				Inclusive:    inclusive,
+			})
+		}
+
+		item.TaxAmount = fromCents(taxCents)
+		item.TaxInclusive = inclusive
+	}
+}
+
+// TaxBreakdown суммирует налоги заказа по юрисдикциям и названиям
+func (o *Order) TaxBreakdown() []TaxLine {
+	type key struct {
+		name, jurisdiction string
+		rate               float64
+		inclusive          bool
+	}
+
+	totals := make(map[key]int64)
+	order := make([]key, 0)
+	for _, item := range o.Items {
+		for _, line := range item.TaxLines {
+			k := key{line.Name, line.Jurisdiction, line.Rate, line.Inclusive}
+			if _, exists := totals[k]; !exists {
+				order = append(order, k)
+			}
+			totals[k] += toCents(line.Amount)
+		}
+	}
+
+	breakdown := make([]TaxLine, 0, len(order))
+	for _, k := range order {
+		breakdown = append(breakdown, TaxLine{
+			Name:         k.name,
+			Jurisdiction: k.jurisdiction,
+			Rate:         k.rate,
+			Amount:       fromCents(totals[k]),
+			Inclusive:    k.inclusive,
+		})
+	}
+	return breakdown
+}
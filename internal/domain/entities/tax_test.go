@@ -0,0 +1,92 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTestTaxTable(t *testing.T) *TaxTable {
+	table, err := NewTaxTable([]TaxJurisdiction{
+		{Country: "DE", Inclusive: true, Rates: []TaxRate{
+			{Name: "VAT", Rate: 0.19},
+			{Name: "VAT", Category: "food", Rate: 0.07},
+		}},
+		{Country: "CA", Rates: []TaxRate{{Name: "GST", Rate: 0.05}}},
+		{Country: "CA", State: "BC", Rates: []TaxRate{{Name: "PST", Rate: 0.07}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return table
+}
+
+func TestApplyTaxes_ExclusiveCombinesCountryAndState(t *testing.T) {
+	order := NewOrder(uuid.New(), "test@example.com")
+	order.AddItem(uuid.New(), "Product 1", 50.0, 2)
+	order.SetShippingAddress(&Address{Country: "CA", State: "BC"})
+
+	order.ApplyTaxes(newTestTaxTable(t))
+
+	if order.TaxAmount != 12.0 {
+		t.Errorf("Expected tax 12.00, got %.2f", order.TaxAmount)
+	}
+	if order.TotalAmount != 112.0 {
+		t.Errorf("Expected total 112.00, got %.2f", order.TotalAmount)
+	}
+	if len(order.Items[0].TaxLines) != 2 {
+		t.Errorf("Expected GST and PST lines, got %d", len(order.Items[0].TaxLines))
+	}
+}
+
+func TestApplyTaxes_InclusiveByCategory(t *testing.T) {
+	order := NewOrder(uuid.New(), "test@example.com")
+	order.AddItem(uuid.New(), "Book", 119.0, 1)
+	order.AddTaxableItem(uuid.New(), "Bread", 10.70, 1, "food")
+	order.SetShippingAddress(&Address{Country: "de"})
+
+	order.ApplyTaxes(newTestTaxTable(t))
+
+	if order.Items[0].TaxAmount != 19.0 {
+		t.Errorf("Expected standard VAT 19.00, got %.2f", order.Items[0].TaxAmount)
+	}
+	if order.Items[1].TaxAmount != 0.70 {
+		t.Errorf("Expected food VAT 0.70, got %.2f", order.Items[1].TaxAmount)
+	}
+	// Налог включен в цену и не увеличивает итог
+	if order.TotalAmount != 129.70 {
+		t.Errorf("Expected total 129.70, got %.2f", order.TotalAmount)
+	}
+}
+
+func TestApplyTaxes_RecalculatedOnChanges(t *testing.T) {
+	order := NewOrder(uuid.New(), "test@example.com")
+	order.AddItem(uuid.New(), "Product 1", 100.0, 1)
+	order.ApplyTaxes(newTestTaxTable(t))
+
+	if order.TaxAmount != 0 {
+		t.Errorf("Expected no tax without address, got %.2f", order.TaxAmount)
+	}
+
+	order.SetShippingAddress(&Address{Country: "CA"})
+	if order.TaxAmount != 5.0 {
+		t.Errorf("Expected GST 5.00 after address change, got %.2f", order.TaxAmount)
+	}
+
+	order.AddItem(uuid.New(), "Product 2", 20.0, 1)
+	if order.TaxAmount != 6.0 || order.TotalAmount != 126.0 {
+		t.Errorf("Expected tax 6.00 and total 126.00, got %.2f and %.2f", order.TaxAmount, order.TotalAmount)
+	}
+
+	breakdown := order.TaxBreakdown()
+	if len(breakdown) != 1 || breakdown[0].Amount != 6.0 {
+		t.Errorf("Expected single GST line of 6.00, got %+v", breakdown)
+	}
+}
+
+func TestNewTaxTable_RejectsDuplicates(t *testing.T) {
+	_, err := NewTaxTable([]TaxJurisdiction{{Country: "US", State: "CA"}, {Country: "us", State: "ca"}})
+	if err == nil {
+		t.Error("Expected duplicate jurisdiction error")
+	}
+}
@@ -0,0 +1,236 @@
+package entities
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTenantID арендатор, к которому относятся заказы без явной привязки
+const DefaultTenantID = "default"
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ErrUnauthenticated запрос без действительного API ключа или токена арендатора
+var ErrUnauthenticated = errors.New("tenant credentials are missing or invalid")
+
+// TenantLimits ограничения заказов арендатора; нулевое значение - без ограничения
+type TenantLimits struct {
+	MaxOrderAmount float64 `json:"max_order_amount,omitempty" yaml:"max_order_amount"`
+	MaxItems       int     `json:"max_items,omitempty" yaml:"max_items"`
+}
+
+// Tenant витрина (бренд) со своими настройками
+type Tenant struct {
+	ID              string
+	Name            string
+	DefaultCurrency string
+	Limits          TenantLimits
+	// StateMachines собственный реестр машин состояний; nil - общий
+	StateMachines *StateMachineRegistry
+	// APIKeyHashes SHA-256 (hex) API ключей арендатора
+	APIKeyHashes []string
+}
+
+// ValidateTenantID проверяет формат идентификатора арендатора
+func ValidateTenantID(id string) error {
+	if !tenantIDPattern.MatchString(id) {
+		return NewValidationError("invalid tenant id %q: expected lowercase letters, digits, '_' or '-'", id)
+	}
+	return nil
+}
+
+// CheckOrder проверяет заказ на соответствие лимитам арендатора
+func (t *Tenant) CheckOrder(order *Order) error {
+	if t == nil {
+		return nil
+	}
+	if t.Limits.MaxItems > 0 && order.GetItemCount() > t.Limits.MaxItems {
+		return NewValidationError("order has %d items, tenant %s allows at most %d", order.GetItemCount(), t.ID, t.Limits.MaxItems)
+	}
+	if t.Limits.MaxOrderAmount > 0 && order.TotalAmount > t.Limits.MaxOrderAmount {
+		return NewValidationError("order total %.2f exceeds tenant %s limit %.2f", order.TotalAmount, t.ID, t.Limits.MaxOrderAmount)
+	}
+	return nil
+}
+
+type tenantContextKey struct{}
+
+// ContextWithTenant кладет арендатора в контекст
+func ContextWithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext возвращает арендатора из контекста
+func TenantFromContext(ctx context.Context) (*Tenant, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(*Tenant)
+	return tenant, ok && tenant != nil
+}
+
+// TenantIDFromContext возвращает ID арендатора из контекста; пусто - запрос без привязки
+// к арендатору (фоновые задачи, служебные утилиты)
+func TenantIDFromContext(ctx context.Context) string {
+	if tenant, ok := TenantFromContext(ctx); ok {
+		return tenant.ID
+	}
+	return ""
+}
+
+// TenantIDOrDefault возвращает ID арендатора; пустой ID - данные до появления арендаторов
+func TenantIDOrDefault(id string) string {
+	if id == "" {
+		return DefaultTenantID
+	}
+	return id
+}
+
+// CheckSharedCatalogAccess разрешает изменять общие для всех арендаторов каталог товаров и склад
+// только арендатору по умолчанию (оператору площадки); без арендатора - служебный доступ
+func CheckSharedCatalogAccess(ctx context.Context) error {
+	if tenantID := TenantIDFromContext(ctx); tenantID != "" && tenantID != DefaultTenantID {
+		return NewForbiddenError("shared catalog and stock are managed by the %s tenant, not %s", DefaultTenantID, tenantID)
+	}
+	return nil
+}
+
+// TenantTokenVerifier проверяет токен (JWT) и возвращает ID арендатора из него
+type TenantTokenVerifier interface {
+	TenantID(token string) (string, error)
+}
+
+// CustomerTokenVerifier достает из токена ID клиента, от имени которого он выдан
+type CustomerTokenVerifier interface {
+	CustomerID(token string) (uuid.UUID, bool)
+}
+
+// TenantDirectory справочник арендаторов с поиском по API ключу
+type TenantDirectory struct {
+	tenants  map[string]*Tenant
+	apiKeys  map[string]string
+	verifier TenantTokenVerifier
+}
+
+// NewTenantDirectory создает справочник; verifier может быть nil
+func NewTenantDirectory(tenants []*Tenant, verifier TenantTokenVerifier) (*TenantDirectory, error) {
+	d := &TenantDirectory{
+		tenants:  make(map[string]*Tenant, len(tenants)),
+		apiKeys:  make(map[string]string),
+		verifier: verifier,
+	}
+
+	for _, tenant := range tenants {
+		if err := ValidateTenantID(tenant.ID); err != nil {
+			return nil, err
+		}
+		if _, exists := d.tenants[tenant.ID]; exists {
+			return nil, fmt.Errorf("duplicate tenant %q", tenant.ID)
+		}
+		d.tenants[tenant.ID] = tenant
+
+		for _, hash := range tenant.APIKeyHashes {
+			if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("tenant %s: api key hash must be hex SHA-256", tenant.ID)
+			}
+			if owner, exists := d.apiKeys[hash]; exists {
+				return nil, fmt.Errorf("api key of tenant %s is already used by tenant %s", tenant.ID, owner)
+			}
+			d.apiKeys[hash] = tenant.ID
+		}
+	}
+
+	return d, nil
+}
+
+// RequiresCredentials true, если заданы API ключи или проверка токенов.
+// Без них все запросы относятся к арендатору по умолчанию.
+func (d *TenantDirectory) RequiresCredentials() bool {
+	return d != nil && (len(d.apiKeys) > 0 || d.verifier != nil)
+}
+
+// Get возвращает арендатора по ID; для неизвестного ID - арендатора без собственных настроек
+func (d *TenantDirectory) Get(id string) *Tenant {
+	if d != nil {
+		if tenant, exists := d.tenants[id]; exists {
+			return tenant
+		}
+	}
+	return &Tenant{ID: id}
+}
+
+// IDs возвращает отсортированный список настроенных арендаторов
+func (d *TenantDirectory) IDs() []string {
+	if d == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(d.tenants))
+	for id := range d.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// StateMachines возвращает собственные реестры машин состояний арендаторов
+func (d *TenantDirectory) StateMachines() map[string]*StateMachineRegistry {
+	registries := make(map[string]*StateMachineRegistry)
+	if d == nil {
+		return registries
+	}
+	for id, tenant := range d.tenants {
+		if tenant.StateMachines != nil {
+			registries[id] = tenant.StateMachines
+		}
+	}
+	return registries
+}
+
+// Authenticate определяет арендатора по API ключу или токену. API ключ проверяется первым.
+func (d *TenantDirectory) Authenticate(apiKey, token string) (*Tenant, error) {
+	if !d.RequiresCredentials() {
+		return d.Get(DefaultTenantID), nil
+	}
+
+	if apiKey != "" {
+		sum := sha256.Sum256([]byte(apiKey))
+		hash := hex.EncodeToString(sum[:])
+		for known, id := range d.apiKeys {
+			if subtle.ConstantTimeCompare([]byte(hash), []byte(known)) == 1 {
+				return d.Get(id), nil
+			}
+		}
+		return nil, ErrUnauthenticated
+	}
+
+	if token != "" && d.verifier != nil {
+		id, err := d.verifier.TenantID(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+		}
+		if err := ValidateTenantID(id); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+		}
+		return d.Get(id), nil
+	}
+
+	return nil, ErrUnauthenticated
+}
+
+// AuthenticateCustomer возвращает клиента, от имени которого выдан токен арендатора.
+// Клиент определяется только по токену без API ключа: ключ аутентифицирует витрину, а не клиента.
+func (d *TenantDirectory) AuthenticateCustomer(apiKey, token string) (uuid.UUID, bool) {
+	if d == nil || apiKey != "" || token == "" {
+		return uuid.Nil, false
+	}
+	verifier, ok := d.verifier.(CustomerTokenVerifier)
+	if !ok {
+		return uuid.Nil, false
+	}
+	return verifier.CustomerID(token)
+}
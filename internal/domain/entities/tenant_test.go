@@ -0,0 +1,183 @@
+package entities
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type staticVerifier map[string]string
+
+func (v staticVerifier) TenantID(token string) (string, error) {
+	if id, ok := v[token]; ok {
+		return id, nil
+	}
+	return "", errors.New("bad token")
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestTenantDirectory_Authenticate(t *testing.T) {
+	directory, err := NewTenantDirectory([]*Tenant{
+		{ID: "acme", DefaultCurrency: "EUR", APIKeyHashes: []string{hashKey("acme-key")}},
+		{ID: "globex", APIKeyHashes: []string{hashKey("globex-key")}},
+	}, staticVerifier{"jwt-initech": "initech", "jwt-bad": "Not Valid"})
+	if err != nil {
+		t.Fatalf("NewTenantDirectory: %v", err)
+	}
+
+	testCases := []struct {
+		name   string
+		apiKey string
+		token  string
+		want   string
+	}{
+		{"api key", "acme-key", "", "acme"},
+		{"api key wins over token", "globex-key", "jwt-initech", "globex"},
+		{"token of unconfigured tenant", "", "jwt-initech", "initech"},
+		{"unknown api key", "other-key", "", ""},
+		{"invalid token", "", "jwt-unknown", ""},
+		{"invalid tenant id in token", "", "jwt-bad", ""},
+		{"no credentials", "", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tenant, err := directory.Authenticate(tc.apiKey, tc.token)
+			if tc.want == "" {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Fatalf("expected ErrUnauthenticated, got tenant %v, err %v", tenant, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+			if tenant.ID != tc.want {
+				t.Errorf("expected tenant %s, got %s", tc.want, tenant.ID)
+			}
+		})
+	}
+}
+
+func TestTenantDirectory_WithoutCredentialsUsesDefault(t *testing.T) {
+	directory, err := NewTenantDirectory([]*Tenant{{ID: DefaultTenantID, DefaultCurrency: "GBP"}}, nil)
+	if err != nil {
+		t.Fatalf("NewTenantDirectory: %v", err)
+	}
+
+	tenant, err := directory.Authenticate("", "")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if tenant.ID != DefaultTenantID || tenant.DefaultCurrency != "GBP" {
+		t.Errorf("expected configured default tenant, got %+v", tenant)
+	}
+}
+
+func TestNewTenantDirectory_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tenants []*Tenant
+	}{
+		{"invalid id", []*Tenant{{ID: "Acme Corp"}}},
+		{"duplicate id", []*Tenant{{ID: "acme"}, {ID: "acme"}}},
+		{"invalid hash", []*Tenant{{ID: "acme", APIKeyHashes: []string{"plain-key"}}}},
+		{"shared api key", []*Tenant{
+			{ID: "acme", APIKeyHashes: []string{hashKey("key")}},
+			{ID: "globex", APIKeyHashes: []string{hashKey("key")}},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewTenantDirectory(tc.tenants, nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestTenant_CheckOrder(t *testing.T) {
+	tenant := &Tenant{ID: "acme", Limits: TenantLimits{MaxOrderAmount: 100, MaxItems: 3}}
+
+	order := NewOrder(uuid.New(), "user@example.com")
+	order.AddItem(uuid.New(), "Mug", 20, 3)
+	if err := tenant.CheckOrder(order); err != nil {
+		t.Fatalf("order within limits: %v", err)
+	}
+
+	order.AddItem(uuid.New(), "Plate", 10, 1)
+	if err := tenant.CheckOrder(order); !isValidationError(err) {
+		t.Errorf("expected validation error for item limit, got %v", err)
+	}
+
+	order = NewOrder(uuid.New(), "user@example.com")
+	order.AddItem(uuid.New(), "Lamp", 150, 1)
+	if err := tenant.CheckOrder(order); !isValidationError(err) {
+		t.Errorf("expected validation error for amount limit, got %v", err)
+	}
+}
+
+func TestTenantContext(t *testing.T) {
+	ctx := context.Background()
+	if id := TenantIDFromContext(ctx); id != "" {
+		t.Errorf("expected unscoped context, got %q", id)
+	}
+
+	ctx = ContextWithTenant(ctx, &Tenant{ID: "acme"})
+	if id := TenantIDFromContext(ctx); id != "acme" {
+		t.Errorf("expected acme, got %q", id)
+	}
+}
+
+func TestCheckSharedCatalogAccess(t *testing.T) {
+	if err := CheckSharedCatalogAccess(context.Background()); err != nil {
+		t.Errorf("expected service access without tenant, got %v", err)
+	}
+	if err := CheckSharedCatalogAccess(ContextWithTenant(context.Background(), &Tenant{ID: DefaultTenantID})); err != nil {
+		t.Errorf("expected default tenant to manage the catalog, got %v", err)
+	}
+
+	var forbiddenErr ForbiddenError
+	if err := CheckSharedCatalogAccess(ContextWithTenant(context.Background(), &Tenant{ID: "acme"})); !errors.As(err, &forbiddenErr) {
+		t.Errorf("expected forbidden error for acme, got %v", err)
+	}
+}
+
+func TestStateMachineRegistry_ForTenant(t *testing.T) {
+	shared, err := NewStateMachine(DefaultStateMachineDefinition())
+	if err != nil {
+		t.Fatalf("NewStateMachine: %v", err)
+	}
+	own, err := NewStateMachine(DefaultStateMachineDefinition())
+	if err != nil {
+		t.Fatalf("NewStateMachine: %v", err)
+	}
+
+	registry := NewStateMachineRegistry(shared, nil).WithTenants(map[string]*StateMachineRegistry{
+		"acme": NewStateMachineRegistry(own, nil),
+	})
+
+	order := NewOrder(uuid.New(), "user@example.com")
+	order.TenantID = "acme"
+	if registry.ForOrder(order) != own {
+		t.Error("expected tenant state machine")
+	}
+	order.TenantID = "globex"
+	if registry.ForOrder(order) != shared {
+		t.Error("expected shared state machine")
+	}
+}
+
+func isValidationError(err error) bool {
+	var validationErr ValidationError
+	return errors.As(err, &validationErr)
+}
@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSubscription подписка партнера на события заказов
+type WebhookSubscription struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	TenantID string    `json:"tenant_id" db:"tenant_id"`
+	URL      string    `json:"url" db:"url"`
+	// EventTypes фильтр типов событий; пусто - все события
+	EventTypes []string `json:"event_types" db:"event_types"`
+	// Secret ключ подписи HMAC-SHA256, возвращается только при создании
+	Secret string `json:"-" db:"secret"`
+	Active bool   `json:"active" db:"active"`
+	// IncludePII получать персональные данные в открытом виде; иначе - токены
+	IncludePII bool `json:"include_pii" db:"include_pii"`
+
+	// FailureCount подряд неуспешных доставок после всех повторов
+	FailureCount int        `json:"failure_count" db:"failure_count"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty" db:"disabled_at"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDelivery попытка доставки события подписчику
+type WebhookDelivery struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	SubscriptionID uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	EventID        uuid.UUID `json:"event_id" db:"event_id"`
+	EventType      string    `json:"event_type" db:"event_type"`
+	Attempt        int       `json:"attempt" db:"attempt"`
+	StatusCode     int       `json:"status_code,omitempty" db:"status_code"`
+	Success        bool      `json:"success" db:"success"`
+	Error          string    `json:"error,omitempty" db:"error"`
+	DurationMs     int64     `json:"duration_ms" db:"duration_ms"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewWebhookSubscription создает новую активную подписку
+func NewWebhookSubscription(rawURL string, eventTypes []string, secret string) *WebhookSubscription {
+	now := time.Now()
+	types := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if eventType = strings.ToLower(strings.TrimSpace(eventType)); eventType != "" {
+			types = append(types, eventType)
+		}
+	}
+
+	return &WebhookSubscription{
+		ID:         uuid.New(),
+		URL:        strings.TrimSpace(rawURL),
+		EventTypes: types,
+		Secret:     secret,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Validate выполняет валидацию подписки
+func (s *WebhookSubscription) Validate() error {
+	parsed, err := url.Parse(s.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return NewValidationError("webhook url must be an absolute http(s) URL")
+	}
+	if len(s.Secret) < 16 {
+		return NewValidationError("webhook secret must be at least 16 characters")
+	}
+	return nil
+}
+
+// Matches проверяет, подписана ли подписка на тип события
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	return len(s.EventTypes) == 0 || containsString(s.EventTypes, eventType)
+}
+
+// RecordSuccess сбрасывает счетчик неуспешных доставок
+func (s *WebhookSubscription) RecordSuccess() {
+	s.FailureCount = 0
+	s.UpdatedAt = time.Now()
+}
+
+// RecordFailure учитывает неуспешную доставку и отключает подписку,
+// когда число неудач подряд достигает disableAfter (0 - не отключать).
+// Возвращает true, если подписка отключена этим вызовом.
+func (s *WebhookSubscription) RecordFailure(disableAfter int) bool {
+	now := time.Now()
+	s.FailureCount++
+	s.UpdatedAt = now
+
+	if disableAfter > 0 && s.Active && s.FailureCount >= disableAfter {
+		s.Active = false
+		s.DisabledAt = &now
+		return true
+	}
+	return false
+}
+
+// Enable включает подписку и сбрасывает счетчик неудач
+func (s *WebhookSubscription) Enable() {
+	s.Active = true
+	s.FailureCount = 0
+	s.DisabledAt = nil
+	s.UpdatedAt = time.Now()
+}
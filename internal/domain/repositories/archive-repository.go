@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// OrderArchiveRepository определяет интерфейс выгрузки заказов в архив и восстановления из него
+type OrderArchiveRepository interface {
+	// ListArchivable возвращает до limit заказов в указанных статусах, не менявшихся с before,
+	// со всеми связанными строками; самые старые первыми
+	ListArchivable(ctx context.Context, statuses []entities.OrderStatus, before time.Time, limit int) ([]*entities.ArchivedOrder, error)
+
+	// DeleteArchived удаляет заархивированные заказы. Заказ, изменившийся после выгрузки
+	// (другой updated_at), не удаляется. Вклад удаленных заказов сохраняется в срезе отчетов.
+	DeleteArchived(ctx context.Context, orders []*entities.ArchivedOrder) (int64, error)
+
+	// Restore загружает заказы из архива; уже существующие заказы пропускаются.
+	// Возвращает количество восстановленных заказов.
+	Restore(ctx context.Context, orders []*entities.ArchivedOrder) (int, error)
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// CustomerRepository определяет интерфейс для работы с клиентами
+type CustomerRepository interface {
+	// Create создает нового клиента. Должен отклонять занятый email.
+	Create(ctx context.Context, customer *entities.Customer) error
+
+	// Update обновляет имя и адреса клиента
+	Update(ctx context.Context, customer *entities.Customer) error
+
+	// GetByID получает клиента по ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Customer, error)
+
+	// GetByEmail получает клиента по email; nil, если клиента нет
+	GetByEmail(ctx context.Context, email string) (*entities.Customer, error)
+
+	// Stats возвращает статистику заказов клиента
+	Stats(ctx context.Context, customerID uuid.UUID) (*entities.CustomerStats, error)
+
+	// Erase обезличивает email и адреса клиента в клиенте и всех его заказах, сохраняя суммы,
+	// и записывает удаление в журнал одной транзакцией; заполняет счетчики erasure.
+	// CustomerNotFoundError, если нет ни клиента, ни его заказов.
+	Erase(ctx context.Context, erasure *entities.CustomerErasure) error
+
+	// ListErasures возвращает журнал удалений данных клиента
+	ListErasures(ctx context.Context, customerID uuid.UUID) ([]*entities.CustomerErasure, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// ExchangeRateRepository определяет интерфейс для работы с курсами валют
+type ExchangeRateRepository interface {
+	// Upsert сохраняет курсы; курс на тот же момент перезаписывается
+	Upsert(ctx context.Context, rates []*entities.ExchangeRate) error
+
+	// GetRate получает последний курс валюты к базовой, действующий на момент at
+	GetRate(ctx context.Context, currency, baseCurrency string, at time.Time) (*entities.ExchangeRate, error)
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// InventoryRepository определяет интерфейс для работы с остатками и резервами товаров
+type InventoryRepository interface {
+	// GetStockLevels получает остатки товаров по всем складам
+	GetStockLevels(ctx context.Context, productIDs []uuid.UUID) ([]*entities.StockLevel, error)
+
+	// AdjustStock изменяет остаток товара на складе на delta.
+	// Должен отклонять изменение, после которого остаток станет меньше резерва.
+	AdjustStock(ctx context.Context, productID uuid.UUID, warehouse string, delta int) (*entities.StockLevel, error)
+
+	// ReserveOrder резервирует товары заказа в одной транзакции с блокировкой остатков.
+	// Повторный вызов для заказа с резервами ничего не меняет.
+	ReserveOrder(ctx context.Context, order *entities.Order) ([]*entities.StockReservation, error)
+
+	// CommitOrder списывает оставшиеся резервы заказа с остатков (резервы отправлений
+	// списываются при их создании)
+	CommitOrder(ctx context.Context, orderID uuid.UUID) (int, error)
+
+	// ReleaseOrder снимает несписанные резервы заказа; отправленные товары не возвращаются
+	ReleaseOrder(ctx context.Context, orderID uuid.UUID) (int, error)
+
+	// GetReservations получает резервы заказа
+	GetReservations(ctx context.Context, orderID uuid.UUID) ([]*entities.StockReservation, error)
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// NotificationRepository определяет интерфейс для журнала отправленных уведомлений
+type NotificationRepository interface {
+	// Exists проверяет, отправлялся ли шаблон по заказу
+	Exists(ctx context.Context, orderID uuid.UUID, template string) (bool, error)
+
+	// Record сохраняет отправленное уведомление. Возвращает false, если шаблон по заказу уже записан.
+	// Email получателя - персональные данные: при включенном шифровании он хранится зашифрованным.
+	Record(ctx context.Context, notification *entities.Notification) (bool, error)
+
+	// ListByOrder получает уведомления заказа в порядке отправки
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*entities.Notification, error)
+}
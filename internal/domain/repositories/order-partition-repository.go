@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// OrderPartitionRepository определяет интерфейс обслуживания месячных партиций заказов и позиций.
+// Месяц задается первым числом в UTC.
+type OrderPartitionRepository interface {
+	// ListMonths возвращает месяцы присоединенных партиций по возрастанию
+	ListMonths(ctx context.Context) ([]time.Time, error)
+
+	// CreateMonth создает партиции месяца. false - партиции уже есть или месяц
+	// занят строками партиции по умолчанию (их нужно перенести вручную).
+	CreateMonth(ctx context.Context, month time.Time) (bool, error)
+
+	// DetachMonth отсоединяет партиции месяца, сохраняя вклад заказов в срезе отчетов;
+	// drop - удаляет их вместе со связанными строками заказов. false - партиции нет.
+	DetachMonth(ctx context.Context, month time.Time, drop bool) (bool, error)
+}
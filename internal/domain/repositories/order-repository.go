@@ -13,6 +13,10 @@ type OrderRepository interface {
 	// Create создает новый заказ
 	Create(ctx context.Context, order *entities.Order) error
 
+	// CreateWithCustomer создает заказ и нового клиента одной транзакцией. Если клиент
+	// с тем же email или ID уже появился, заказ сохраняется без новой записи клиента.
+	CreateWithCustomer(ctx context.Context, order *entities.Order, customer *entities.Customer) error
+
 	// GetByID получает заказ по ID
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error)
 
@@ -39,6 +43,23 @@ type OrderRepository interface {
 
 	// Exists проверяет существование заказа
 	Exists(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// Statistics возвращает количество и суммы заказов по статусам в отчетной валюте
+	Statistics(ctx context.Context, filters OrderFilters) ([]*OrderStatusStats, error)
+
+	// Stream читает заказы по фильтрам серверным курсором пачками по batchSize и передает
+	// каждый заказ с позициями и адресами в fn; ошибка fn прерывает чтение
+	Stream(ctx context.Context, filters OrderFilters, batchSize int, fn func(order *entities.Order) error) error
+}
+
+// OrderStatusStats статистика заказов одного статуса в отчетной валюте
+type OrderStatusStats struct {
+	Status          entities.OrderStatus `json:"status"`
+	Count           int64                `json:"count"`
+	TotalAmountBase float64              `json:"total_amount_base"`
+	AvgAmountBase   float64              `json:"avg_amount_base"`
+	// Заказы без итога в отчетной валюте (нет курса на момент создания)
+	Unconverted int64 `json:"unconverted"`
 }
 
 // OrderFilters представляет фильтры для поиска заказов
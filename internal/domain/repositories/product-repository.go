@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// ProductRepository определяет интерфейс для работы с каталогом товаров
+type ProductRepository interface {
+	// Create создает новый товар с ценами
+	Create(ctx context.Context, product *entities.Product) error
+
+	// Update обновляет товар и заменяет его цены
+	Update(ctx context.Context, product *entities.Product) error
+
+	// Delete удаляет товар
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetByID получает товар по ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error)
+
+	// GetByIDs получает товары по ID; неизвестные ID пропускаются
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error)
+
+	// List получает список товаров
+	List(ctx context.Context, activeOnly bool, limit, offset int) ([]*entities.Product, error)
+
+	// UpsertBySKU сохраняет товары в одной транзакции, обновляя существующие по артикулу.
+	// Существующий товар сохраняет свой ID.
+	UpsertBySKU(ctx context.Context, products []*entities.Product) error
+}
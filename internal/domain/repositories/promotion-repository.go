@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// PromotionRepository определяет интерфейс для работы с промоакциями
+type PromotionRepository interface {
+	// Create создает новую промоакцию
+	Create(ctx context.Context, promotion *entities.Promotion) error
+
+	// GetByCodes получает промоакции по кодам купонов; неизвестные коды пропускаются
+	GetByCodes(ctx context.Context, codes []string) ([]*entities.Promotion, error)
+
+	// List получает список промоакций
+	List(ctx context.Context, activeOnly bool, limit, offset int) ([]*entities.Promotion, error)
+
+	// CountCustomerRedemptions считает использования купона клиентом
+	CountCustomerRedemptions(ctx context.Context, promotionID, customerID uuid.UUID) (int, error)
+}
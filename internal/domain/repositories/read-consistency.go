@@ -0,0 +1,18 @@
+package repositories
+
+import "context"
+
+type primaryReadsKey struct{}
+
+// WithPrimaryReads помечает контекст: чтения в нем должны видеть все записи,
+// поэтому реализации с репликами читают из основной БД. Используется для запросов,
+// которые изменяют данные (чтение перед изменением не должно отставать).
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// PrimaryReads true, если чтения в контексте должны идти в основную БД
+func PrimaryReads(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadsKey{}).(bool)
+	return primary
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// ReportPeriod период группировки отчета
+type ReportPeriod string
+
+const (
+	ReportPeriodDay   ReportPeriod = "day"
+	ReportPeriodWeek  ReportPeriod = "week"
+	ReportPeriodMonth ReportPeriod = "month"
+)
+
+// ReportRepository определяет интерфейс отчетов по заказам
+type ReportRepository interface {
+	// OrderReport возвращает агрегаты заказов по дневному срезу; дни, ожидающие
+	// пересчета, агрегируются по заказам напрямую
+	OrderReport(ctx context.Context, query OrderReportQuery) ([]*OrderReportRow, error)
+
+	// RefreshDailyStats пересчитывает устаревшие дни среза и возвращает их количество
+	RefreshDailyStats(ctx context.Context) (int, error)
+}
+
+// OrderReportQuery представляет фильтры и группировку отчета по заказам
+type OrderReportQuery struct {
+	// Дни по UTC включительно
+	DateFrom *time.Time
+	DateTo   *time.Time
+	Status   *string
+	Currency *string
+	Country  *string
+
+	// Пусто - без разбивки по времени
+	Period          ReportPeriod
+	GroupByStatus   bool
+	GroupByCurrency bool
+	GroupByCountry  bool
+}
+
+// OrderReportRow агрегаты одной группы отчета; поля группировки пусты, если по ним не группировали
+type OrderReportRow struct {
+	PeriodStart  *time.Time
+	Status       string
+	Currency     string
+	Country      string
+	OrderCount   int64
+	Revenue      float64 // в валюте заказов
+	RevenueBase  float64 // в отчетной валюте
+	Unconverted  int64   // заказы без итога в отчетной валюте
+	ItemQuantity int64
+}
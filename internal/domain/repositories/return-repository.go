@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// ReturnRepository определяет интерфейс для работы с возвратами товаров и средств
+type ReturnRepository interface {
+	// CreateReturn сохраняет заявку на возврат.
+	// Должен отклонять заявку, превышающую количество, доступное к возврату.
+	CreateReturn(ctx context.Context, ret *entities.ReturnRequest) error
+
+	// GetReturnByID получает заявку на возврат по ID
+	GetReturnByID(ctx context.Context, id uuid.UUID) (*entities.ReturnRequest, error)
+
+	// GetReturnsByOrderID получает заявки на возврат по заказу
+	GetReturnsByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.ReturnRequest, error)
+
+	// ResolveReturn сохраняет решение по заявке, находящейся в статусе requested
+	ResolveReturn(ctx context.Context, ret *entities.ReturnRequest) error
+
+	// CreateRefund сохраняет возврат средств и переводит заказ из fromStatus в orderStatus
+	// в одной транзакции. Должен отклонять возврат, превышающий оплаченную сумму, и заказ,
+	// статус которого под блокировкой уже не fromStatus, и переводить связанную заявку в статус refunded.
+	CreateRefund(ctx context.Context, refund *entities.Refund, fromStatus, orderStatus entities.OrderStatus) error
+
+	// GetRefundsByOrderID получает возвраты средств по заказу
+	GetRefundsByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Refund, error)
+}
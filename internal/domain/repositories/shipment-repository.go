@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// ShipmentRepository определяет интерфейс для работы с отправлениями заказов
+type ShipmentRepository interface {
+	// Create сохраняет отправление и переводит заказ из fromStatus в orderStatus в одной транзакции.
+	// Должен отклонять отправление, превышающее неотправленное количество, и заказ,
+	// статус которого под блокировкой уже не fromStatus. Резервы отправленных позиций списываются
+	// с остатков в той же транзакции.
+	Create(ctx context.Context, shipment *entities.Shipment, fromStatus, orderStatus entities.OrderStatus) error
+
+	// UpdateStatus сохраняет новый статус отправления и переводит заказ из orderFrom в orderTo
+	// в одной транзакции. Должен отклонять отправление, статус которого под блокировкой заказа
+	// уже не fromStatus, и заказ, статус которого уже не orderFrom.
+	UpdateStatus(ctx context.Context, shipment *entities.Shipment, fromStatus entities.ShipmentStatus, orderFrom, orderTo entities.OrderStatus) error
+
+	// GetByOrderID получает отправления заказа
+	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Shipment, error)
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// WebhookRepository определяет интерфейс для работы с подписками и журналом доставки webhooks
+type WebhookRepository interface {
+	// Create создает подписку
+	Create(ctx context.Context, subscription *entities.WebhookSubscription) error
+
+	// GetByID получает подписку по ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscription, error)
+
+	// List получает список подписок
+	List(ctx context.Context, limit, offset int) ([]*entities.WebhookSubscription, error)
+
+	// ListActiveForEvent получает активные подписки на тип события
+	ListActiveForEvent(ctx context.Context, eventType string) ([]*entities.WebhookSubscription, error)
+
+	// Delete удаляет подписку вместе с журналом доставки
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// UpdateHealth сохраняет счетчик неудач и признак активности подписки
+	UpdateHealth(ctx context.Context, subscription *entities.WebhookSubscription) error
+
+	// RecordDelivery сохраняет попытку доставки
+	RecordDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+
+	// ListDeliveries получает журнал доставки подписки, начиная с последних попыток
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit, offset int) ([]*entities.WebhookDelivery, error)
+}
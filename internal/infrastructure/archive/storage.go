@@ -0,0 +1,118 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrNotFound возвращается, если файла архива нет в хранилище
+var ErrNotFound = errors.New("archive not found")
+
+// FileStorage хранит файлы архива в локальном каталоге; имя файла - путь относительно каталога через "/"
+type FileStorage struct {
+	dir string
+}
+
+// NewFileStorage создает хранилище архива в каталоге dir
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("archive directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	return &FileStorage{dir: dir}, nil
+}
+
+// Put записывает файл атомарно: частично записанный файл не виден под итоговым именем
+func (s *FileStorage) Put(_ context.Context, name string, data []byte) error {
+	target, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create archive partition: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync archive file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close archive file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("failed to publish archive file: %w", err)
+	}
+	return nil
+}
+
+// Get читает файл архива
+func (s *FileStorage) Get(_ context.Context, name string) ([]byte, error) {
+	target, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive file: %w", err)
+	}
+	return data, nil
+}
+
+// List возвращает имена файлов архива с префиксом prefix в лексикографическом порядке
+func (s *FileStorage) List(_ context.Context, prefix string) ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive files: %w", err)
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// path переводит имя файла архива в путь внутри каталога, не выпуская за его пределы
+func (s *FileStorage) path(name string) (string, error) {
+	clean := path.Clean("/" + name)
+	if name == "" || clean == "/" || clean != "/"+name {
+		return "", fmt.Errorf("invalid archive name: %q", name)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean[1:])), nil
+}
@@ -0,0 +1,50 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFileStorage_RoundTrip(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, name := range []string{"2024-02/b.ndjson.gz", "2024-01/a.ndjson.gz", "2023-12/c.ndjson.gz"} {
+		if err := storage.Put(ctx, name, []byte(name)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	names, err := storage.List(ctx, "2024-")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(names) != 2 || names[0] != "2024-01/a.ndjson.gz" || names[1] != "2024-02/b.ndjson.gz" {
+		t.Errorf("Unexpected list %v", names)
+	}
+
+	data, err := storage.Get(ctx, "2024-01/a.ndjson.gz")
+	if err != nil || string(data) != "2024-01/a.ndjson.gz" {
+		t.Errorf("Unexpected data %q (%v)", data, err)
+	}
+	if _, err := storage.Get(ctx, "2024-03/none.ndjson.gz"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFileStorage_RejectsEscapingNames(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"../outside.gz", "2024-01/../../x.gz", "", "/abs.gz"} {
+		if err := storage.Put(context.Background(), name, []byte("x")); err == nil {
+			t.Errorf("Expected error for %q", name)
+		}
+	}
+}
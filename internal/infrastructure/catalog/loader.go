@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// LoadCSV загружает и проверяет каталог товаров из CSV файла.
+// Файл содержит заголовок с колонками sku, name, currency, price и
+// необязательными id, active, tax_category, weight. Цены товара в разных
+// валютах задаются отдельными строками с тем же sku.
+func LoadCSV(path string) ([]*entities.Product, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open product catalog file: %w", err)
+	}
+	defer file.Close()
+
+	products, err := ReadCSV(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse product catalog file: %w", err)
+	}
+	return products, nil
+}
+
+// ReadCSV читает каталог товаров в формате CSV
+func ReadCSV(r io.Reader) ([]*entities.Product, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"sku", "name", "currency", "price"} {
+		if _, exists := columns[required]; !exists {
+			return nil, fmt.Errorf("missing column %s", required)
+		}
+	}
+
+	field := func(row []string, name string) string {
+		if i, exists := columns[name]; exists && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var products []*entities.Product
+	bySKU := make(map[string]*entities.Product)
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		sku := entities.NormalizeSKU(field(row, "sku"))
+		product, exists := bySKU[sku]
+		if !exists {
+			product, err = newProduct(sku, row, field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			bySKU[sku] = product
+			products = append(products, product)
+		}
+
+		currency := entities.NormalizeCurrency(field(row, "currency"))
+		if _, duplicate := product.PriceIn(currency); duplicate {
+			return nil, fmt.Errorf("line %d: duplicate price for %s in %s", line, sku, currency)
+		}
+		price, err := strconv.ParseFloat(field(row, "price"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price: %w", line, err)
+		}
+		product.SetPrice(currency, price)
+	}
+
+	for _, product := range products {
+		if err := product.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return products, nil
+}
+
+// newProduct создает товар из первой строки его артикула
+func newProduct(sku string, row []string, field func([]string, string) string) (*entities.Product, error) {
+	product := entities.NewProduct(sku, field(row, "name"))
+	product.TaxCategory = strings.ToLower(field(row, "tax_category"))
+
+	if raw := field(row, "id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id: %w", err)
+		}
+		product.ID = id
+	}
+	if raw := field(row, "active"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid active flag: %w", err)
+		}
+		product.Active = active
+	}
+	if raw := field(row, "weight"); raw != "" {
+		weight, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight: %w", err)
+		}
+		product.Weight = weight
+	}
+
+	return product, nil
+}
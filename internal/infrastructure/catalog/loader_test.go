@@ -0,0 +1,53 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadCSV_ExampleConfig(t *testing.T) {
+	products, err := LoadCSV("../../../configs/products.csv")
+	if err != nil {
+		t.Fatalf("Expected example config to be valid, got %v", err)
+	}
+	if len(products) == 0 {
+		t.Fatal("Expected example config to define products")
+	}
+}
+
+func TestReadCSV_MergesPricesBySKU(t *testing.T) {
+	input := `sku,name,currency,price,weight
+mug-1,Mug,usd,10.00,0.4
+MUG-1,Mug,EUR,9.50,0.4
+`
+	products, err := ReadCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(products) != 1 {
+		t.Fatalf("Expected 1 product, got %d", len(products))
+	}
+
+	p := products[0]
+	if p.SKU != "MUG-1" || !p.Active || p.Weight != 0.4 {
+		t.Errorf("Unexpected product: %+v", p)
+	}
+	if price, ok := p.PriceIn("EUR"); !ok || price != 9.5 {
+		t.Errorf("Expected EUR price 9.50, got %v", p.Prices)
+	}
+}
+
+func TestReadCSV_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"missing column":  "sku,name,price\nA,B,1\n",
+		"duplicate price": "sku,name,currency,price\nA,B,USD,1\nA,B,USD,2\n",
+		"bad currency":    "sku,name,currency,price\nA,B,XXX,1\n",
+		"zero price":      "sku,name,currency,price\nA,B,USD,0\n",
+	}
+
+	for name, input := range cases {
+		if _, err := ReadCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package exchangerates
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// Record описывает курс в файле. Базовая валюта по умолчанию - отчетная,
+// дата - YYYY-MM-DD или RFC3339.
+type Record struct {
+	Currency     string  `json:"currency"`
+	BaseCurrency string  `json:"base_currency,omitempty"`
+	Rate         float64 `json:"rate"`
+	EffectiveAt  string  `json:"effective_at"`
+}
+
+// LoadFile загружает и проверяет курсы из CSV или JSON файла.
+// CSV содержит заголовок с колонками currency, rate, effective_at
+// и необязательной base_currency.
+func LoadFile(path, reportingCurrency string) ([]*entities.ExchangeRate, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open exchange rates file: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		records, err = readCSV(file)
+	case ".json":
+		err = json.NewDecoder(file).Decode(&records)
+	default:
+		return nil, fmt.Errorf("unsupported exchange rates file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates file: %w", err)
+	}
+
+	rates := make([]*entities.ExchangeRate, 0, len(records))
+	for i, record := range records {
+		rate, err := record.toEntity(reportingCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("exchange rate %d: %w", i+1, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+// readCSV читает записи CSV с заголовком
+func readCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"currency", "rate", "effective_at"} {
+		if _, exists := columns[required]; !exists {
+			return nil, fmt.Errorf("missing column %s", required)
+		}
+	}
+
+	field := func(row []string, name string) string {
+		if i, exists := columns[name]; exists && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rate, err := strconv.ParseFloat(field(row, "rate"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate: %w", len(records)+2, err)
+		}
+
+		records = append(records, Record{
+			Currency:     field(row, "currency"),
+			BaseCurrency: field(row, "base_currency"),
+			Rate:         rate,
+			EffectiveAt:  field(row, "effective_at"),
+		})
+	}
+
+	return records, nil
+}
+
+// toEntity преобразует запись файла в курс и проверяет его
+func (r Record) toEntity(reportingCurrency string) (*entities.ExchangeRate, error) {
+	effectiveAt, err := parseDate(r.EffectiveAt)
+	if err != nil {
+		return nil, err
+	}
+
+	base := r.BaseCurrency
+	if base == "" {
+		base = reportingCurrency
+	}
+
+	rate := &entities.ExchangeRate{
+		Currency:     entities.NormalizeCurrency(r.Currency),
+		BaseCurrency: entities.NormalizeCurrency(base),
+		Rate:         r.Rate,
+		EffectiveAt:  effectiveAt,
+	}
+	if err := rate.Validate(); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// parseDate разбирает дату YYYY-MM-DD или RFC3339
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid effective_at %q", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,19 @@
+package exchangerates
+
+import "testing"
+
+func TestLoadFile_ExampleConfig(t *testing.T) {
+	rates, err := LoadFile("../../../configs/exchange-rates.csv", "USD")
+	if err != nil {
+		t.Fatalf("Expected example config to be valid, got %v", err)
+	}
+
+	if len(rates) == 0 {
+		t.Fatal("Expected example config to define rates")
+	}
+	for _, rate := range rates {
+		if rate.BaseCurrency != "USD" {
+			t.Errorf("Expected reporting currency USD as base, got %s", rate.BaseCurrency)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// utf8BOM подсказывает Excel кодировку CSV файла
+const utf8BOM = "\uFEFF"
+
+// CSVWriter пишет выгрузку в CSV. В режиме Excel добавляет BOM, использует CRLF
+// и экранирует значения, которые Excel выполнил бы как формулы.
+type CSVWriter struct {
+	out    io.Writer
+	csv    *csv.Writer
+	excel  bool
+	record []string
+}
+
+// NewCSVWriter создает CSV writer
+func NewCSVWriter(w io.Writer, excel bool) *CSVWriter {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = excel
+	return &CSVWriter{out: w, csv: writer, excel: excel}
+}
+
+// WriteHeader пишет строку заголовков
+func (w *CSVWriter) WriteHeader(columns []string) error {
+	if w.excel {
+		if _, err := io.WriteString(w.out, utf8BOM); err != nil {
+			return err
+		}
+	}
+	w.record = make([]string, len(columns))
+	return w.csv.Write(columns)
+}
+
+// WriteRow пишет строку значений
+func (w *CSVWriter) WriteRow(values []interface{}) error {
+	if len(w.record) != len(values) {
+		w.record = make([]string, len(values))
+	}
+	for i, value := range values {
+		text := formatValue(value)
+		if w.excel {
+			text = escapeFormula(value, text)
+		}
+		w.record[i] = text
+	}
+	return w.csv.Write(w.record)
+}
+
+// Flush сбрасывает буфер в поток
+func (w *CSVWriter) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// NDJSONWriter пишет выгрузку как JSON объект на строку с ключами по колонкам
+type NDJSONWriter struct {
+	out     *bufio.Writer
+	columns []string
+}
+
+// NewNDJSONWriter создает NDJSON writer
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{out: bufio.NewWriter(w)}
+}
+
+// WriteHeader запоминает колонки; отдельной строки заголовков в NDJSON нет
+func (w *NDJSONWriter) WriteHeader(columns []string) error {
+	w.columns = columns
+	return nil
+}
+
+// WriteRow пишет объект строки; порядок ключей совпадает с порядком колонок
+func (w *NDJSONWriter) WriteRow(values []interface{}) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("row has %d values, expected %d", len(values), len(w.columns))
+	}
+
+	w.out.WriteByte('{')
+	for i, column := range w.columns {
+		if i > 0 {
+			w.out.WriteByte(',')
+		}
+		key, _ := json.Marshal(column)
+		w.out.Write(key)
+		w.out.WriteByte(':')
+
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			return fmt.Errorf("failed to encode column %s: %w", column, err)
+		}
+		w.out.Write(value)
+	}
+	w.out.WriteByte('}')
+	return w.out.WriteByte('\n')
+}
+
+// Flush сбрасывает буфер в поток
+func (w *NDJSONWriter) Flush() error {
+	return w.out.Flush()
+}
+
+// formatValue приводит значение колонки к тексту CSV
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// escapeFormula защищает от CSV injection: текст, начинающийся с символа формулы, предваряется апострофом
+func escapeFormula(value interface{}, text string) string {
+	if _, ok := value.(string); !ok || text == "" {
+		return text
+	}
+	if strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,73 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSVWriter_Excel(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, true)
+
+	if err := w.WriteHeader([]string{"email", "total_amount", "created_at"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := w.WriteRow([]interface{}{"=HYPERLINK(\"x\")", -12.5, createdAt}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "\uFEFFemail,total_amount,created_at\r\n\"'=HYPERLINK(\"\"x\"\")\",-12.5,2024-01-02T03:04:05Z\r\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestCSVWriter_Plain(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, false)
+
+	w.WriteHeader([]string{"name", "item_price"})
+	w.WriteRow([]interface{}{"-dash", nil})
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if buf.String() != "name,item_price\n-dash,\n" {
+		t.Errorf("Unexpected CSV output %q", buf.String())
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewNDJSONWriter(&buf)
+
+	w.WriteHeader([]string{"id", "total_amount", "item_name"})
+	w.WriteRow([]interface{}{"a", 10.0, "Widget"})
+	w.WriteRow([]interface{}{"b", 5.5, nil})
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	if lines[0] != `{"id":"a","total_amount":10,"item_name":"Widget"}` {
+		t.Errorf("Unexpected first line %s", lines[0])
+	}
+
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &row); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if row["item_name"] != nil {
+		t.Errorf("Expected null item_name, got %v", row["item_name"])
+	}
+}
@@ -18,16 +18,24 @@ type ConsumerConfig struct {
 	MinBytes      int           `json:"min_bytes"`
 	MaxBytes      int           `json:"max_bytes"`
 	CommitInterval time.Duration `json:"commit_interval"`
+	// Tenants настройки арендаторов для контекста обработки; nil - только ID из заголовка
+	Tenants *entities.TenantDirectory `json:"-"`
 }
 
+// TenantHeader заголовок сообщения с арендатором заказа
+const TenantHeader = "tenant-id"
+
 // MessageHandler интерфейс для обработки сообщений
 type MessageHandler interface {
 	HandleOrderCreated(ctx context.Context, event *entities.OrderEvent) error
 	HandleOrderConfirmed(ctx context.Context, event *entities.OrderEvent) error
 	HandleOrderCancelled(ctx context.Context, event *entities.OrderEvent) error
 	HandleOrderShipped(ctx context.Context, event *entities.OrderEvent) error
+	HandleShipmentCreated(ctx context.Context, event *entities.OrderEvent) error
 	HandleOrderDelivered(ctx context.Context, event *entities.OrderEvent) error
 	HandleOrderRefunded(ctx context.Context, event *entities.OrderEvent) error
+	HandleReturnRequested(ctx context.Context, event *entities.OrderEvent) error
+	HandleRefundIssued(ctx context.Context, event *entities.OrderEvent) error
 	HandleGenericMessage(ctx context.Context, message kafka.Message) error
 }
 
@@ -109,6 +117,8 @@ func (c *Consumer) processMessage(ctx context.Context, message kafka.Message) er
 		return fmt.Errorf("failed to unmarshal order event: %w", err)
 	}
 
+	ctx = withEventTenant(ctx, c.config.Tenants, c.getHeaderValue(message.Headers, TenantHeader), &orderEvent)
+
 	// Добавляем метаданные из Kafka message
 	if orderEvent.Data == nil {
 		orderEvent.Data = make(map[string]interface{})
@@ -127,10 +137,16 @@ func (c *Consumer) processMessage(ctx context.Context, message kafka.Message) er
 		return c.handler.HandleOrderCancelled(ctx, &orderEvent)
 	case entities.EventOrderShipped:
 		return c.handler.HandleOrderShipped(ctx, &orderEvent)
+	case entities.EventOrderShipmentCreated:
+		return c.handler.HandleShipmentCreated(ctx, &orderEvent)
 	case entities.EventOrderDelivered:
 		return c.handler.HandleOrderDelivered(ctx, &orderEvent)
 	case entities.EventOrderRefunded:
 		return c.handler.HandleOrderRefunded(ctx, &orderEvent)
+	case entities.EventOrderReturnRequested:
+		return c.handler.HandleReturnRequested(ctx, &orderEvent)
+	case entities.EventOrderRefundIssued:
+		return c.handler.HandleRefundIssued(ctx, &orderEvent)
 	default:
 		fmt.Printf("Unknown event type: %s, processing as generic\n", eventType)
 		return c.handler.HandleGenericMessage(ctx, message)
@@ -275,6 +291,8 @@ func (c *BatchConsumer) processMessage(ctx context.Context, message kafka.Messag
 		return fmt.Errorf("failed to unmarshal order event: %w", err)
 	}
 
+	ctx = withEventTenant(ctx, c.config.Tenants, c.getHeaderValue(message.Headers, TenantHeader), &orderEvent)
+
 	switch eventType {
 	case entities.EventOrderCreated:
 		return c.handler.HandleOrderCreated(ctx, &orderEvent)
@@ -284,15 +302,35 @@ func (c *BatchConsumer) processMessage(ctx context.Context, message kafka.Messag
 		return c.handler.HandleOrderCancelled(ctx, &orderEvent)
 	case entities.EventOrderShipped:
 		return c.handler.HandleOrderShipped(ctx, &orderEvent)
+	case entities.EventOrderShipmentCreated:
+		return c.handler.HandleShipmentCreated(ctx, &orderEvent)
 	case entities.EventOrderDelivered:
 		return c.handler.HandleOrderDelivered(ctx, &orderEvent)
 	case entities.EventOrderRefunded:
 		return c.handler.HandleOrderRefunded(ctx, &orderEvent)
+	case entities.EventOrderReturnRequested:
+		return c.handler.HandleReturnRequested(ctx, &orderEvent)
+	case entities.EventOrderRefundIssued:
+		return c.handler.HandleRefundIssued(ctx, &orderEvent)
 	default:
 		return c.handler.HandleGenericMessage(ctx, message)
 	}
 }
 
+// withEventTenant кладет в контекст арендатора события из заголовка (или тела события).
+// События без арендатора обрабатываются без привязки к нему.
+func withEventTenant(ctx context.Context, tenants *entities.TenantDirectory, header string, event *entities.OrderEvent) context.Context {
+	tenantID := header
+	if tenantID == "" {
+		tenantID = event.TenantID
+	}
+	if tenantID == "" {
+		return ctx
+	}
+	event.TenantID = tenantID
+	return entities.ContextWithTenant(ctx, tenants.Get(tenantID))
+}
+
 // getHeaderValue для batch consumer
 func (c *BatchConsumer) getHeaderValue(headers []kafka.Header, key string) string {
 	for _, header := range headers {
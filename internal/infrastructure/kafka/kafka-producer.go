@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 	"kafka-order-service/internal/domain/entities"
 )
@@ -53,15 +54,9 @@ func (p *Producer) PublishOrderEvent(ctx context.Context, event *entities.OrderE
 
 	// Создание сообщения Kafka
 	message := kafka.Message{
-		Key:   []byte(event.OrderID.String()), // Ключ - ID заказа для партиционирования
+		Key:   eventKey(event), // Ключ - ID заказа для партиционирования
 		Value: eventData,
-		Headers: []kafka.Header{
-			{Key: "event-type", Value: []byte(event.EventType)},
-			{Key: "event-id", Value: []byte(event.EventID.String())},
-			{Key: "customer-id", Value: []byte(event.CustomerID.String())},
-			{Key: "content-type", Value: []byte("application/json")},
-			{Key: "timestamp", Value: []byte(event.Timestamp.Format(time.RFC3339))},
-		},
+		Headers: eventHeaders(event),
 		Time: event.Timestamp,
 	}
 
@@ -88,15 +83,9 @@ func (p *Producer) PublishOrderEvents(ctx context.Context, events []*entities.Or
 		}
 
 		message := kafka.Message{
-			Key:   []byte(event.OrderID.String()),
+			Key:   eventKey(event),
 			Value: eventData,
-			Headers: []kafka.Header{
-				{Key: "event-type", Value: []byte(event.EventType)},
-				{Key: "event-id", Value: []byte(event.EventID.String())},
-				{Key: "customer-id", Value: []byte(event.CustomerID.String())},
-				{Key: "content-type", Value: []byte("application/json")},
-				{Key: "timestamp", Value: []byte(event.Timestamp.Format(time.RFC3339))},
-			},
+			Headers: eventHeaders(event),
 			Time: event.Timestamp,
 		}
 
@@ -111,6 +100,31 @@ func (p *Producer) PublishOrderEvents(ctx context.Context, events []*entities.Or
 	return nil
 }
 
+// eventKey возвращает ключ партиционирования события: ID заказа, а для событий
+// клиента без заказа (customer.erased) - ID клиента
+func eventKey(event *entities.OrderEvent) []byte {
+	if event.OrderID == uuid.Nil {
+		return []byte(event.CustomerID.String())
+	}
+	return []byte(event.OrderID.String())
+}
+
+// eventHeaders возвращает заголовки сообщения события. tenant-id есть только у событий заказов:
+// события клиента (customer.erased) общие для всех арендаторов
+func eventHeaders(event *entities.OrderEvent) []kafka.Header {
+	headers := []kafka.Header{
+		{Key: "event-type", Value: []byte(event.EventType)},
+		{Key: "event-id", Value: []byte(event.EventID.String())},
+		{Key: "customer-id", Value: []byte(event.CustomerID.String())},
+		{Key: "content-type", Value: []byte("application/json")},
+		{Key: "timestamp", Value: []byte(event.Timestamp.Format(time.RFC3339))},
+	}
+	if event.TenantID != "" {
+		headers = append(headers, kafka.Header{Key: TenantHeader, Value: []byte(event.TenantID)})
+	}
+	return headers
+}
+
 // Close закрывает producer
 func (p *Producer) Close() error {
 	return p.writer.Close()
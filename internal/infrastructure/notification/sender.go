@@ -0,0 +1,143 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// Транспорты email
+const (
+	TransportNoop = "noop"
+	TransportFile = "file"
+	TransportSMTP = "smtp"
+)
+
+// NopSender не отправляет письма; используется, когда транспорт не настроен
+type NopSender struct{}
+
+// NewNopSender создает транспорт, который ничего не отправляет
+func NewNopSender() *NopSender {
+	return &NopSender{}
+}
+
+// Send ничего не делает
+func (s *NopSender) Send(ctx context.Context, message *entities.EmailMessage) error {
+	return nil
+}
+
+// FileSender сохраняет письма в каталог файлами .eml (для локальной разработки и тестов)
+type FileSender struct {
+	dir  string
+	from string
+	seq  atomic.Uint64
+}
+
+// NewFileSender создает транспорт, пишущий письма в каталог
+func NewFileSender(dir, from string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileSender{dir: dir, from: from}, nil
+}
+
+// Send записывает письмо в отдельный файл
+func (s *FileSender) Send(ctx context.Context, message *entities.EmailMessage) error {
+	name := fmt.Sprintf("%s-%04d-%s.eml",
+		time.Now().UTC().Format("20060102T150405.000000000"),
+		s.seq.Add(1)%10000,
+		sanitizeFileName(message.To))
+
+	if err := os.WriteFile(filepath.Join(s.dir, name), buildMessage(s.from, message), 0o644); err != nil {
+		return fmt.Errorf("failed to write email file: %w", err)
+	}
+	return nil
+}
+
+// SMTPConfig параметры SMTP сервера
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPSender отправляет письма через SMTP сервер (STARTTLS, если сервер поддерживает)
+type SMTPSender struct {
+	config SMTPConfig
+}
+
+// NewSMTPSender создает SMTP транспорт
+func NewSMTPSender(config SMTPConfig) *SMTPSender {
+	return &SMTPSender{config: config}
+}
+
+// Send отправляет письмо
+func (s *SMTPSender) Send(ctx context.Context, message *entities.EmailMessage) error {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	// net/smtp не принимает контекст, поэтому отправка выполняется в горутине
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, s.config.From, []string{message.To}, buildMessage(s.config.From, message))
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email via smtp: %w", err)
+		}
+		return nil
+	}
+}
+
+// buildMessage собирает письмо в формате RFC 5322 с HTML телом
+func buildMessage(from string, message *entities.EmailMessage) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + headerValue(from) + "\r\n")
+	buf.WriteString("To: " + headerValue(message.To) + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(message.HTMLBody)
+	return buf.Bytes()
+}
+
+// headerValue убирает переводы строк, чтобы значение не могло добавить заголовки
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
+
+// sanitizeFileName заменяет символы, недопустимые в имени файла
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,43 @@
+package notification
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+func TestFileSender_Send(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := NewFileSender(dir, "orders@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	message := &entities.EmailMessage{To: "test@example.com", Subject: "Заказ принят", HTMLBody: "<p>hi</p>"}
+	for i := 0; i < 2; i++ {
+		if err := sender.Send(context.Background(), message); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 email files, got %d", len(files))
+	}
+
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := string(content)
+	if !strings.Contains(text, "To: test@example.com\r\n") || !strings.Contains(text, "Subject: =?utf-8?q?") {
+		t.Errorf("Unexpected headers: %s", text)
+	}
+	if !strings.HasSuffix(text, "\r\n\r\n<p>hi</p>") {
+		t.Errorf("Unexpected body: %s", text)
+	}
+}
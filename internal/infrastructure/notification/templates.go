@@ -0,0 +1,128 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// defaultTemplates встроенные шаблоны уведомлений
+//
+//go:embed templates
+var defaultTemplates embed.FS
+
+// layoutFile общий макет письма, шаблоны лежат в <locale>/<template>.html
+// и определяют блоки "subject" и "body"
+const layoutFile = "layout.html"
+
+// TemplateRenderer рендерит локализованные email уведомления на html/template
+type TemplateRenderer struct {
+	templates map[string]map[string]*template.Template // locale -> template -> набор
+}
+
+// NewTemplateRenderer загружает шаблоны из каталога, а если он не указан - встроенные.
+// Язык по умолчанию должен содержать все шаблоны уведомлений.
+func NewTemplateRenderer(dir string) (*TemplateRenderer, error) {
+	var fsys fs.FS
+	if dir == "" {
+		sub, err := fs.Sub(defaultTemplates, "templates")
+		if err != nil {
+			return nil, fmt.Errorf("failed to open embedded templates: %w", err)
+		}
+		fsys = sub
+	} else {
+		fsys = os.DirFS(dir)
+	}
+
+	return LoadTemplates(fsys)
+}
+
+// LoadTemplates загружает и проверяет шаблоны уведомлений из файловой системы
+func LoadTemplates(fsys fs.FS) (*TemplateRenderer, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification templates: %w", err)
+	}
+
+	renderer := &TemplateRenderer{templates: make(map[string]map[string]*template.Template)}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		locale := entities.NormalizeLocale(entry.Name())
+
+		files, err := fs.Glob(fsys, path.Join(entry.Name(), "*.html"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s templates: %w", locale, err)
+		}
+		for _, file := range files {
+			name := strings.TrimSuffix(path.Base(file), ".html")
+			tmpl, err := template.New(name).Funcs(templateFuncs).ParseFS(fsys, layoutFile, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse template %s: %w", file, err)
+			}
+			for _, block := range []string{"subject", "body"} {
+				if tmpl.Lookup(block) == nil {
+					return nil, fmt.Errorf("template %s does not define %q", file, block)
+				}
+			}
+
+			if renderer.templates[locale] == nil {
+				renderer.templates[locale] = make(map[string]*template.Template)
+			}
+			renderer.templates[locale][name] = tmpl
+		}
+	}
+
+	for _, name := range entities.NotificationTemplates() {
+		if renderer.templates[entities.DefaultLocale][name] == nil {
+			return nil, fmt.Errorf("template %s/%s.html is missing", entities.DefaultLocale, name)
+		}
+	}
+
+	return renderer, nil
+}
+
+// Render рендерит тему и тело письма, при отсутствии перевода - на языке по умолчанию
+func (r *TemplateRenderer) Render(locale, name string, data interface{}) (string, string, error) {
+	tmpl := r.templates[entities.NormalizeLocale(locale)][name]
+	if tmpl == nil {
+		tmpl = r.templates[entities.DefaultLocale][name]
+	}
+	if tmpl == nil {
+		return "", "", fmt.Errorf("unknown notification template: %s", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := tmpl.ExecuteTemplate(&body, "layout", data); err != nil {
+		return "", "", fmt.Errorf("failed to render body: %w", err)
+	}
+
+	// Тема - обычный текст, экранирование HTML в ней не нужно
+	return strings.TrimSpace(html.UnescapeString(subject.String())), body.String(), nil
+}
+
+// templateFuncs функции, доступные в шаблонах
+var templateFuncs = template.FuncMap{
+	// money форматирует сумму с валютой: 12.50 USD
+	"money": func(amount float64, currency string) string {
+		return fmt.Sprintf("%.2f %s", amount, currency)
+	},
+	// short возвращает короткий номер заказа для клиента
+	"short": func(id uuid.UUID) string {
+		return strings.ToUpper(id.String()[:8])
+	},
+}
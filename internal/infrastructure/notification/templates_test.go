@@ -0,0 +1,83 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type templateData struct {
+	Order  *entities.Order
+	Event  *entities.OrderEvent
+	Locale string
+}
+
+func newTemplateData(locale string) *templateData {
+	order := entities.NewOrder(uuid.New(), "test@example.com")
+	order.AddItem(uuid.New(), "Coffee <Mug>", 12.5, 2)
+	event := order.ToEvent(entities.EventOrderShipped)
+	event.Data["tracking_number"] = "TRK-123"
+	return &templateData{Order: order, Event: event, Locale: locale}
+}
+
+func TestNewTemplateRenderer_EmbeddedTemplates(t *testing.T) {
+	renderer, err := NewTemplateRenderer("")
+	if err != nil {
+		t.Fatalf("Expected embedded templates to be valid, got %v", err)
+	}
+
+	for _, locale := range []string{"en", "ru"} {
+		for _, name := range entities.NotificationTemplates() {
+			subject, body, err := renderer.Render(locale, name, newTemplateData(locale))
+			if err != nil {
+				t.Errorf("%s/%s: unexpected error: %v", locale, name, err)
+				continue
+			}
+			if subject == "" || !strings.Contains(body, "<html") {
+				t.Errorf("%s/%s: unexpected output: %q", locale, name, subject)
+			}
+		}
+	}
+}
+
+func TestTemplateRenderer_Render(t *testing.T) {
+	renderer, err := NewTemplateRenderer("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := newTemplateData("ru")
+	subject, body, err := renderer.Render("ru-RU", entities.NotificationOrderCreated, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(subject, "Заказ ") {
+		t.Errorf("Expected russian subject, got %q", subject)
+	}
+	if !strings.Contains(body, "Coffee &lt;Mug&gt;") || !strings.Contains(body, "25.00 USD") {
+		t.Errorf("Expected escaped item and total in body, got %s", body)
+	}
+
+	// Неизвестный язык - шаблоны по умолчанию
+	subject, _, err = renderer.Render("de", entities.NotificationOrderShipped, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(subject, "Order ") {
+		t.Errorf("Expected fallback to english, got %q", subject)
+	}
+}
+
+func TestLoadTemplates_MissingDefault(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html":           {Data: []byte(`{{define "layout"}}{{template "body" .}}{{end}}`)},
+		"en/order_created.html": {Data: []byte(`{{define "subject"}}s{{end}}{{define "body"}}b{{end}}`)},
+	}
+	if _, err := LoadTemplates(fsys); err == nil {
+		t.Error("Expected error for missing default templates")
+	}
+}
@@ -0,0 +1,293 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// prefix отличает шифротекст от открытых значений, записанных до включения шифрования
+const prefix = "pii:v1:"
+
+// keySize размер ключей AES-256 и ключа blind index
+const keySize = 32
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// File описывает формат файла ключей. Ключи - base64 от 32 случайных байт.
+type File struct {
+	// Primary ключ, которым шифруются новые значения
+	Primary string `json:"primary" yaml:"primary"`
+	// Keys ключи шифрования ключей данных по ID; старые ключи нужны для чтения до перешифрования
+	Keys map[string]string `json:"keys" yaml:"keys"`
+	// IndexKey ключ HMAC для blind index
+	IndexKey string `json:"index_key" yaml:"index_key"`
+}
+
+// Keyring шифрует персональные данные по схеме envelope: каждое значение шифруется
+// AES-GCM своим случайным ключом данных, а ключ данных - ключом из связки (KEK).
+// Ротация KEK требует перешифровать только ключи данных (Rewrap).
+// Методы nil Keyring не шифруют: значения возвращаются как есть.
+type Keyring struct {
+	primary  string
+	keys     map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// LoadKeyring загружает связку ключей из YAML/JSON файла.
+// Если путь не указан, шифрование выключено и возвращается nil.
+func LoadKeyring(path string) (*Keyring, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PII keyring file: %w", err)
+	}
+
+	var file File
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported PII keyring file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PII keyring file: %w", err)
+	}
+
+	keyring, err := NewKeyring(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PII keyring: %w", err)
+	}
+	return keyring, nil
+}
+
+// NewKeyring проверяет ключи и создает связку
+func NewKeyring(file File) (*Keyring, error) {
+	if _, ok := file.Keys[file.Primary]; !ok {
+		return nil, fmt.Errorf("primary key %q is not in keys", file.Primary)
+	}
+
+	keyring := &Keyring{primary: file.Primary, keys: make(map[string]cipher.AEAD, len(file.Keys))}
+	for id, encoded := range file.Keys {
+		if !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("key id %q must contain only letters, digits, '.', '_' and '-'", id)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		keyring.keys[id] = aead
+	}
+
+	indexKey, err := decodeKey(file.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("index_key: %w", err)
+	}
+	keyring.indexKey = indexKey
+
+	return keyring, nil
+}
+
+// Primary возвращает ID ключа, которым шифруются новые значения
+func (k *Keyring) Primary() string {
+	if k == nil {
+		return ""
+	}
+	return k.primary
+}
+
+// IsEncrypted проверяет, что значение - шифротекст связки
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt шифрует значение основным ключом. Пустые и уже зашифрованные значения не меняются.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if k == nil || plaintext == "" || IsEncrypted(plaintext) {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	data, err := seal(dataAEAD, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(k.keys[k.primary], dataKey, []byte(k.primary))
+	if err != nil {
+		return "", err
+	}
+	return format(k.primary, wrapped, data), nil
+}
+
+// Decrypt расшифровывает значение. Открытые значения (записанные до включения
+// шифрования или обезличенные) возвращаются как есть.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if k == nil {
+		return "", errors.New("PII keyring is not configured, cannot decrypt value")
+	}
+
+	keyID, wrapped, data, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := k.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataAEAD, data, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap переводит значение на основной ключ: открытое значение шифруется, у шифротекста
+// другим ключом перешифровывается только ключ данных. Возвращает false, если менять нечего.
+func (k *Keyring) Rewrap(value string) (string, bool, error) {
+	if k == nil || value == "" {
+		return value, false, nil
+	}
+	if !IsEncrypted(value) {
+		encrypted, err := k.Encrypt(value)
+		return encrypted, err == nil, err
+	}
+
+	keyID, wrapped, data, err := parse(value)
+	if err != nil {
+		return "", false, err
+	}
+	if keyID == k.primary {
+		return value, false, nil
+	}
+	dataKey, err := k.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", false, err
+	}
+	rewrapped, err := seal(k.keys[k.primary], dataKey, []byte(k.primary))
+	if err != nil {
+		return "", false, err
+	}
+	return format(k.primary, rewrapped, data), true, nil
+}
+
+// BlindIndex возвращает HMAC-SHA256 значения для поиска по точному совпадению без
+// расшифровки. Значение нормализуется вызывающим кодом. Пустая строка без связки ключей.
+func (k *Keyring) BlindIndex(value string) string {
+	if k == nil || value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// unwrap расшифровывает ключ данных ключом связки keyID
+func (k *Keyring) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("PII key %q is not in the keyring", keyID)
+	}
+	dataKey, err := open(aead, wrapped, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with %q: %w", keyID, err)
+	}
+	return dataKey, nil
+}
+
+// format собирает шифротекст: pii:v1:<key id>:<ключ данных>:<данные>
+func format(keyID string, wrapped, data []byte) string {
+	return prefix + keyID + ":" + base64.RawURLEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawURLEncoding.EncodeToString(data)
+}
+
+// parse разбирает шифротекст
+func parse(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, errors.New("malformed PII ciphertext")
+	}
+	wrapped, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("malformed PII ciphertext: %w", err)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("malformed PII ciphertext: %w", err)
+	}
+	return parts[0], wrapped, data, nil
+}
+
+// seal шифрует с новым случайным nonce в начале результата
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+// open расшифровывает результат seal
+func open(aead cipher.AEAD, sealed, additional []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additional)
+}
+
+// newAEAD создает AES-256-GCM
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// decodeKey декодирует base64 ключ длиной 32 байта
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("key must be base64: %w", err)
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+	return key, nil
+}
@@ -0,0 +1,128 @@
+package pii
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestLoadKeyring_ExampleConfig(t *testing.T) {
+	keyring, err := LoadKeyring("../../../configs/pii-keyring.example.yaml")
+	if err != nil {
+		t.Fatalf("Expected example config to be valid, got %v", err)
+	}
+	if keyring.Primary() == "" {
+		t.Fatal("Expected example config to define primary key")
+	}
+}
+
+func TestLoadKeyring_EmptyPathDisablesEncryption(t *testing.T) {
+	keyring, err := LoadKeyring("")
+	if err != nil || keyring != nil {
+		t.Fatalf("Expected nil keyring, got %v, %v", keyring, err)
+	}
+
+	value, err := keyring.Encrypt("user@example.com")
+	if err != nil || value != "user@example.com" {
+		t.Errorf("Expected passthrough, got %q, %v", value, err)
+	}
+	if index := keyring.BlindIndex("user@example.com"); index != "" {
+		t.Errorf("Expected no blind index, got %q", index)
+	}
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	keyring, err := NewKeyring(File{Primary: "k1", Keys: map[string]string{"k1": testKey(t)}, IndexKey: testKey(t)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, err := keyring.Encrypt("user@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := keyring.Encrypt("user@example.com")
+	if !IsEncrypted(first) || strings.Contains(first, "user@example.com") {
+		t.Fatalf("Expected ciphertext, got %q", first)
+	}
+	if first == second {
+		t.Error("Expected random data keys to produce different ciphertexts")
+	}
+	if again, _ := keyring.Encrypt(first); again != first {
+		t.Error("Expected encrypted value to stay unchanged")
+	}
+
+	plain, err := keyring.Decrypt(first)
+	if err != nil || plain != "user@example.com" {
+		t.Errorf("Expected round trip, got %q, %v", plain, err)
+	}
+	if legacy, err := keyring.Decrypt("legacy@example.com"); err != nil || legacy != "legacy@example.com" {
+		t.Errorf("Expected plaintext to pass through, got %q, %v", legacy, err)
+	}
+
+	tampered := first[:len(first)-2] + "AA"
+	if _, err := keyring.Decrypt(tampered); err == nil {
+		t.Error("Expected tampered ciphertext to fail")
+	}
+	if keyring.BlindIndex("user@example.com") != keyring.BlindIndex("user@example.com") {
+		t.Error("Expected blind index to be deterministic")
+	}
+}
+
+func TestKeyring_Rewrap(t *testing.T) {
+	oldKey, newKey, indexKey := testKey(t), testKey(t), testKey(t)
+	oldRing, _ := NewKeyring(File{Primary: "old", Keys: map[string]string{"old": oldKey}, IndexKey: indexKey})
+	rotated, err := NewKeyring(File{Primary: "new", Keys: map[string]string{"old": oldKey, "new": newKey}, IndexKey: indexKey})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	value, _ := oldRing.Encrypt("Main St 1")
+	rewrapped, changed, err := rotated.Rewrap(value)
+	if err != nil || !changed {
+		t.Fatalf("Expected value to be rewrapped, got %v, %v", changed, err)
+	}
+	if !strings.HasPrefix(rewrapped, prefix+"new:") {
+		t.Errorf("Expected primary key id in %q", rewrapped)
+	}
+	if value[strings.LastIndex(value, ":"):] != rewrapped[strings.LastIndex(rewrapped, ":"):] {
+		t.Error("Expected data ciphertext to be kept")
+	}
+
+	newOnly, _ := NewKeyring(File{Primary: "new", Keys: map[string]string{"new": newKey}, IndexKey: indexKey})
+	if plain, err := newOnly.Decrypt(rewrapped); err != nil || plain != "Main St 1" {
+		t.Errorf("Expected rewrapped value to decrypt without old key, got %q, %v", plain, err)
+	}
+	if _, changed, _ := rotated.Rewrap(rewrapped); changed {
+		t.Error("Expected value on primary key to stay unchanged")
+	}
+	if encrypted, changed, _ := rotated.Rewrap("plain"); !changed || !IsEncrypted(encrypted) {
+		t.Error("Expected plaintext to be encrypted")
+	}
+}
+
+func TestNewKeyring_Invalid(t *testing.T) {
+	key := testKey(t)
+	cases := map[string]File{
+		"missing primary":   {Primary: "k2", Keys: map[string]string{"k1": key}, IndexKey: key},
+		"bad key id":        {Primary: "k:1", Keys: map[string]string{"k:1": key}, IndexKey: key},
+		"short key":         {Primary: "k1", Keys: map[string]string{"k1": "c2hvcnQ="}, IndexKey: key},
+		"missing index key": {Primary: "k1", Keys: map[string]string{"k1": key}},
+	}
+
+	for name, file := range cases {
+		if _, err := NewKeyring(file); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// archiveTable таблица, строки которой архивируются вместе с заказом.
+// orderID - выражение с ID заказа, join - путь к заказу для таблиц без order_id,
+// restore - запрос восстановления строк из $1, если простой вставки недостаточно.
+type archiveTable struct {
+	name    string
+	join    string
+	orderID string
+	restore string
+}
+
+// archiveTables связанные таблицы заказа в порядке восстановления (родительские раньше дочерних)
+var archiveTables = []archiveTable{
+	// Ключ партиции позиции берется из реестра: в архивах до секционирования его нет
+	{name: "order_items", orderID: "t.order_id", restore: `
+		INSERT INTO order_items
+		SELECT (jsonb_populate_record(r, jsonb_build_object('order_created_at', k.created_at))).*
+		FROM json_populate_recordset(NULL::order_items, $1) r
+		JOIN order_keys k ON k.id = r.order_id`},
+	{name: "order_addresses", orderID: "t.order_id"},
+	{name: "order_item_taxes", orderID: "t.order_id"},
+	{name: "order_discounts", orderID: "t.order_id"},
+	{name: "promotion_redemptions", orderID: "t.order_id"},
+	{name: "stock_reservations", orderID: "t.order_id"},
+	{name: "order_risk_assessments", orderID: "t.order_id"},
+	{name: "order_notifications", orderID: "t.order_id"},
+	{name: "shipments", orderID: "t.order_id"},
+	{name: "shipment_items", join: "JOIN shipments p ON p.id = t.shipment_id", orderID: "p.order_id"},
+	{name: "return_requests", orderID: "t.order_id"},
+	{name: "return_items", join: "JOIN return_requests p ON p.id = t.return_id", orderID: "p.order_id"},
+	{name: "refunds", orderID: "t.order_id"},
+}
+
+// archivedStatsDelta обновляет вклад заказов в срез отчетов: sign 1 - заказы уходят в архив, -1 - возвращаются
+const archivedStatsDelta = `
+	INSERT INTO order_daily_stats_archived AS a (tenant_id, day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity)
+	SELECT tenant_id, order_day, status, currency, shipping_country, $2 * COUNT(*), $2 * COALESCE(SUM(total_amount), 0),
+		$2 * COALESCE(SUM(total_amount_base), 0), $2 * COUNT(*) FILTER (WHERE total_amount_base IS NULL), $2 * SUM(total_quantity)
+	FROM orders_with_stats
+	WHERE id = ANY($1)
+	GROUP BY tenant_id, order_day, status, currency, shipping_country
+	ON CONFLICT (tenant_id, day, status, currency, country) DO UPDATE SET
+		order_count = a.order_count + EXCLUDED.order_count,
+		revenue = a.revenue + EXCLUDED.revenue,
+		revenue_base = a.revenue_base + EXCLUDED.revenue_base,
+		unconverted = a.unconverted + EXCLUDED.unconverted,
+		item_quantity = a.item_quantity + EXCLUDED.item_quantity`
+
+// ArchiveRepository реализация репозитория архива заказов для PostgreSQL
+type ArchiveRepository struct {
+	db *sql.DB
+}
+
+// NewArchiveRepository создает новый репозиторий архива заказов
+func NewArchiveRepository(db *sql.DB) *ArchiveRepository {
+	return &ArchiveRepository{
+		db: db,
+	}
+}
+
+// ListArchivable возвращает заказы для архивации со всеми связанными строками
+func (r *ArchiveRepository) ListArchivable(ctx context.Context, statuses []entities.OrderStatus, before time.Time, limit int) ([]*entities.ArchivedOrder, error) {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+
+	// Строки заказа и связанных таблиц читаются из одного снимка
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin archive read transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT o.id, o.status, o.created_at, o.updated_at, row_to_json(o)
+		FROM orders o
+		WHERE o.status = ANY($1) AND o.updated_at < $2
+		ORDER BY o.created_at
+		LIMIT $3`, pq.Array(names), before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archivable orders: %w", err)
+	}
+
+	var orders []*entities.ArchivedOrder
+	byID := make(map[uuid.UUID]*entities.ArchivedOrder)
+	ids := make([]uuid.UUID, 0, limit)
+	for rows.Next() {
+		order := &entities.ArchivedOrder{Related: make(map[string][]json.RawMessage)}
+		var row []byte
+		if err := rows.Scan(&order.ID, &order.Status, &order.CreatedAt, &order.UpdatedAt, &row); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan archivable order: %w", err)
+		}
+		order.Order = row
+		orders = append(orders, order)
+		byID[order.ID] = order
+		ids = append(ids, order.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list archivable orders: %w", err)
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	for _, table := range archiveTables {
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf(
+			`SELECT %s, row_to_json(t) FROM %s t %s WHERE %s = ANY($1)`,
+			table.orderID, table.name, table.join, table.orderID), pq.Array(uuidStrings(ids)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s for archive: %w", table.name, err)
+		}
+		for rows.Next() {
+			var orderID uuid.UUID
+			var row []byte
+			if err := rows.Scan(&orderID, &row); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s for archive: %w", table.name, err)
+			}
+			if order, ok := byID[orderID]; ok {
+				order.Related[table.name] = append(order.Related[table.name], row)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s for archive: %w", table.name, err)
+		}
+	}
+
+	return orders, tx.Commit()
+}
+
+// DeleteArchived удаляет заархивированные заказы, не изменившиеся после выгрузки
+func (r *ArchiveRepository) DeleteArchived(ctx context.Context, orders []*entities.ArchivedOrder) (int64, error) {
+	if len(orders) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, len(orders))
+	updatedAt := make([]string, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID.String()
+		updatedAt[i] = order.UpdatedAt.Format(time.RFC3339Nano)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin archive delete transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT o.id
+		FROM orders o
+		JOIN unnest($1::uuid[], $2::timestamptz[]) AS a(id, updated_at) ON a.id = o.id AND a.updated_at = o.updated_at
+		FOR UPDATE OF o`, pq.Array(ids), pq.Array(updatedAt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock archived orders: %w", err)
+	}
+	var unchanged []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan archived order: %w", err)
+		}
+		unchanged = append(unchanged, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to lock archived orders: %w", err)
+	}
+	if len(unchanged) == 0 {
+		return 0, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, archivedStatsDelta, pq.Array(unchanged), 1); err != nil {
+		return 0, fmt.Errorf("failed to keep archived orders in reports: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM orders WHERE id = ANY($1)`, pq.Array(unchanged))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete archived orders: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete archived orders: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit archive delete: %w", err)
+	}
+	return deleted, nil
+}
+
+// Restore загружает заказы из архива одной транзакцией
+func (r *ArchiveRepository) Restore(ctx context.Context, orders []*entities.ArchivedOrder) (int, error) {
+	if len(orders) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin restore transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	orderRows := make([]json.RawMessage, len(orders))
+	for i, order := range orders {
+		orderRows[i] = order.Order
+	}
+	rowsJSON, err := json.Marshal(orderRows)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode archived orders: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		INSERT INTO orders
+		SELECT a.* FROM json_populate_recordset(NULL::orders, $1) a
+		WHERE NOT EXISTS (SELECT 1 FROM order_keys k WHERE k.id = a.id)
+		RETURNING id`, rowsJSON)
+	if err != nil {
+		return 0, fmt.Errorf("failed to restore orders: %w", err)
+	}
+	restored := make(map[uuid.UUID]bool, len(orders))
+	var restoredIDs []string
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan restored order: %w", err)
+		}
+		restored[id] = true
+		restoredIDs = append(restoredIDs, id.String())
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to restore orders: %w", err)
+	}
+	if len(restored) == 0 {
+		return 0, nil
+	}
+
+	// Связанные строки восстанавливаются только для вставленных заказов
+	for _, table := range archiveTables {
+		var tableRows []json.RawMessage
+		for _, order := range orders {
+			if restored[order.ID] {
+				tableRows = append(tableRows, order.Related[table.name]...)
+			}
+		}
+		if len(tableRows) == 0 {
+			continue
+		}
+
+		data, err := json.Marshal(tableRows)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode archived %s: %w", table.name, err)
+		}
+		query := table.restore
+		if query == "" {
+			query = fmt.Sprintf(`INSERT INTO %[1]s SELECT * FROM json_populate_recordset(NULL::%[1]s, $1)`, table.name)
+		}
+		if _, err := tx.ExecContext(ctx, query, data); err != nil {
+			return 0, fmt.Errorf("failed to restore %s: %w", table.name, err)
+		}
+	}
+
+	// Заказы клиентов, удаливших персональные данные после архивации, возвращаются обезличенными
+	erased, err := erasedCustomersOf(ctx, tx, restoredIDs)
+	if err != nil {
+		return 0, err
+	}
+	for tenantID, customerIDs := range erased {
+		tenantCtx := entities.ContextWithTenant(ctx, &entities.Tenant{ID: tenantID})
+		if _, _, err := anonymizeCustomers(tenantCtx, tx, customerIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	// Триггеры позиций пересчитывают итоги и updated_at; возвращаем итоги и время из архива
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE orders o
+		SET subtotal = a.subtotal, discount_amount = a.discount_amount, tax_amount = a.tax_amount,
+			total_amount = a.total_amount, updated_at = a.updated_at
+		FROM json_populate_recordset(NULL::orders, $1) a
+		WHERE o.id = a.id AND o.id = ANY($2)`, rowsJSON, pq.Array(restoredIDs)); err != nil {
+		return 0, fmt.Errorf("failed to restore order totals: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, archivedStatsDelta, pq.Array(restoredIDs), -1); err != nil {
+		return 0, fmt.Errorf("failed to move restored orders out of archived reports: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM order_daily_stats_archived WHERE order_count <= 0`); err != nil {
+		return 0, fmt.Errorf("failed to clean archived reports: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit restore: %w", err)
+	}
+	return len(restored), nil
+}
+
+// erasedCustomersOf возвращает по арендаторам клиентов заказов, по которым есть записи
+// в журнале удаления данных этого арендатора
+func erasedCustomersOf(ctx context.Context, tx *sql.Tx, orderIDs []string) (map[string][]uuid.UUID, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT DISTINCT o.tenant_id, o.customer_id
+		FROM orders o
+		JOIN customer_erasures e ON e.customer_id = o.customer_id AND e.tenant_id = o.tenant_id
+		WHERE o.id = ANY($1)`, pq.Array(orderIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check erased customers: %w", err)
+	}
+	defer rows.Close()
+
+	customerIDs := make(map[string][]uuid.UUID)
+	for rows.Next() {
+		var tenantID string
+		var id uuid.UUID
+		if err := rows.Scan(&tenantID, &id); err != nil {
+			return nil, fmt.Errorf("failed to scan erased customer: %w", err)
+		}
+		customerIDs[tenantID] = append(customerIDs[tenantID], id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check erased customers: %w", err)
+	}
+	return customerIDs, nil
+}
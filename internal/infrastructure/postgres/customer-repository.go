@@ -0,0 +1,422 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// CustomerRepository реализация репозитория клиентов для PostgreSQL
+type CustomerRepository struct {
+	db     *sql.DB
+	fields FieldCipher
+	router *DBRouter
+}
+
+// NewCustomerRepository создает новый репозиторий клиентов.
+// fields шифрует email и адреса по умолчанию; nil - данные хранятся открытым текстом.
+func NewCustomerRepository(db *sql.DB, fields FieldCipher) *CustomerRepository {
+	if fields == nil {
+		fields = plainFields{}
+	}
+	return &CustomerRepository{
+		db:     db,
+		fields: fields,
+	}
+}
+
+// WithReadYourWrites возвращает репозиторий, отмечающий в маршрутизаторе реплик клиента и заказы, обезличенные удалением данных,
+// чтобы их чтения шли в основную БД
+func (r *CustomerRepository) WithReadYourWrites(router *DBRouter) *CustomerRepository {
+	withRouter := *r
+	withRouter.router = router
+	return &withRouter
+}
+
+const customerColumns = `id, email, name, default_shipping_address, default_billing_address, created_at, updated_at`
+
+// Create создает нового клиента в арендаторе из контекста
+func (r *CustomerRepository) Create(ctx context.Context, customer *entities.Customer) error {
+	args, err := customerInsertArgs(ctx, r.fields, customer)
+	if err != nil {
+		return err
+	}
+
+	err = withTenant(ctx, r.db, func(q querier) error {
+		_, err := q.ExecContext(ctx, customerInsertQuery, args...)
+		return err
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return entities.NewValidationError("customer with email %s or ID %s already exists", customer.Email, customer.ID)
+		}
+		return fmt.Errorf("failed to insert customer: %w", err)
+	}
+
+	return nil
+}
+
+const customerInsertQuery = `
+	INSERT INTO customers (` + customerColumns + `, email_index, tenant_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+// customerInsertArgs возвращает параметры customerInsertQuery для клиента в арендаторе из контекста
+func customerInsertArgs(ctx context.Context, fields FieldCipher, customer *entities.Customer) ([]interface{}, error) {
+	email, err := fields.Encrypt(customer.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt customer email: %w", err)
+	}
+	shipping, billing, err := marshalCustomerAddresses(fields, customer)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		customer.ID, email, customer.Name, shipping, billing, customer.CreatedAt, customer.UpdatedAt,
+		emailIndex(fields, customer.Email), entities.TenantIDOrDefault(entities.TenantIDFromContext(ctx)),
+	}, nil
+}
+
+// Update обновляет имя и адреса клиента
+func (r *CustomerRepository) Update(ctx context.Context, customer *entities.Customer) error {
+	shipping, billing, err := marshalCustomerAddresses(r.fields, customer)
+	if err != nil {
+		return err
+	}
+
+	query, args := andTenant(ctx, `
+		UPDATE customers
+		SET name = $2, default_shipping_address = $3, default_billing_address = $4, updated_at = $5
+		WHERE id = $1`, customer.ID, customer.Name, shipping, billing, customer.UpdatedAt)
+
+	var rowsAffected int64
+	err = withTenant(ctx, r.db, func(q querier) error {
+		result, err := q.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to update customer: %w", err)
+		}
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return entities.NewCustomerNotFoundError(customer.ID.String())
+	}
+
+	return nil
+}
+
+// GetByID получает клиента по ID
+func (r *CustomerRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Customer, error) {
+	query, args := andTenant(ctx, `SELECT `+customerColumns+` FROM customers WHERE id = $1`, id)
+
+	var customer *entities.Customer
+	err := withTenant(ctx, r.db, func(q querier) error {
+		var err error
+		customer, err = scanCustomer(r.fields, q.QueryRowContext(ctx, query, args...))
+		return err
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.NewCustomerNotFoundError(id.String())
+		}
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	return customer, nil
+}
+
+// GetByEmail получает клиента по email; nil, если клиента нет.
+// С шифрованием клиент ищется по blind index, а записанные без него - по email.
+func (r *CustomerRepository) GetByEmail(ctx context.Context, email string) (*entities.Customer, error) {
+	condition, conditionArgs := `LOWER(email) = $1`, []interface{}{entities.NormalizeEmail(email)}
+	if index := emailIndex(r.fields, email); index != nil {
+		condition = `(email_index = $2 OR (email_index IS NULL AND LOWER(email) = $1))`
+		conditionArgs = append(conditionArgs, index)
+	}
+	query, args := andTenant(ctx, `SELECT `+customerColumns+` FROM customers WHERE `+condition, conditionArgs...)
+
+	var customer *entities.Customer
+	err := withTenant(ctx, r.db, func(q querier) error {
+		var err error
+		customer, err = scanCustomer(r.fields, q.QueryRowContext(ctx, query, args...))
+		return err
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get customer by email: %w", err)
+	}
+
+	return customer, nil
+}
+
+// Stats возвращает статистику заказов клиента в отчетной валюте в рамках арендатора
+func (r *CustomerRepository) Stats(ctx context.Context, customerID uuid.UUID) (*entities.CustomerStats, error) {
+	query, args := andTenantOf(ctx, "o", `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(o.total_amount_base - COALESCE(rf.refunded, 0) * o.exchange_rate), 0),
+			MIN(o.created_at),
+			MAX(o.created_at)
+		FROM orders o
+		LEFT JOIN (
+			SELECT order_id, SUM(amount) AS refunded FROM refunds GROUP BY order_id
+		) rf ON rf.order_id = o.id
+		WHERE o.customer_id = $1 AND o.status <> 'cancelled'`, customerID)
+
+	var stats entities.CustomerStats
+	var firstOrderAt, lastOrderAt sql.NullTime
+	err := withTenant(ctx, r.db, func(q querier) error {
+		return q.QueryRowContext(ctx, query, args...).Scan(
+			&stats.OrderCount, &stats.TotalSpent, &firstOrderAt, &lastOrderAt)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer stats: %w", err)
+	}
+
+	if firstOrderAt.Valid {
+		stats.FirstOrderAt = &firstOrderAt.Time
+	}
+	if lastOrderAt.Valid {
+		stats.LastOrderAt = &lastOrderAt.Time
+	}
+
+	return &stats, nil
+}
+
+// Erase обезличивает персональные данные клиента в рамках арендатора и записывает удаление в журнал
+func (r *CustomerRepository) Erase(ctx context.Context, erasure *entities.CustomerErasure) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin erasure transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setTenant(ctx, tx); err != nil {
+		return err
+	}
+
+	customerQuery, args := andTenant(ctx, `SELECT 1 FROM customers WHERE id = $1`, erasure.CustomerID)
+	orderQuery, _ := andTenant(ctx, `SELECT 1 FROM orders WHERE customer_id = $1`, erasure.CustomerID)
+
+	var known bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (`+customerQuery+`) OR EXISTS (`+orderQuery+`)`, args...).Scan(&known)
+	if err != nil {
+		return fmt.Errorf("failed to check customer: %w", err)
+	}
+	if !known {
+		return entities.NewCustomerNotFoundError(erasure.CustomerID.String())
+	}
+
+	orderIDs, addresses, err := anonymizeCustomers(ctx, tx, []uuid.UUID{erasure.CustomerID})
+	if err != nil {
+		return err
+	}
+	erasure.OrdersAnonymized = len(orderIDs)
+	erasure.AddressesAnonymized = int(addresses)
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO customer_erasures (id, customer_id, reason, orders_anonymized, addresses_anonymized, event_id, erased_at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		erasure.ID, erasure.CustomerID, erasure.Reason, erasure.OrdersAnonymized, erasure.AddressesAnonymized,
+		erasure.EventID, erasure.ErasedAt, entities.TenantIDOrDefault(entities.TenantIDFromContext(ctx)))
+	if err != nil {
+		return fmt.Errorf("failed to record customer erasure: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit customer erasure: %w", err)
+	}
+
+	keys := []string{customerKey(erasure.CustomerID)}
+	for _, id := range orderIDs {
+		keys = append(keys, orderKey(id))
+	}
+	r.router.MarkWritten(keys...)
+	return nil
+}
+
+// ListErasures возвращает журнал удалений данных клиента, начиная с первых
+func (r *CustomerRepository) ListErasures(ctx context.Context, customerID uuid.UUID) ([]*entities.CustomerErasure, error) {
+	query, args := andTenant(ctx, `
+		SELECT id, customer_id, reason, orders_anonymized, addresses_anonymized, event_id, erased_at
+		FROM customer_erasures
+		WHERE customer_id = $1`, customerID)
+
+	erasures := make([]*entities.CustomerErasure, 0)
+	err := withTenant(ctx, r.db, func(q querier) error {
+		rows, err := q.QueryContext(ctx, query+` ORDER BY erased_at`, args...)
+		if err != nil {
+			return fmt.Errorf("failed to list customer erasures: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var e entities.CustomerErasure
+			if err := rows.Scan(&e.ID, &e.CustomerID, &e.Reason, &e.OrdersAnonymized, &e.AddressesAnonymized,
+				&e.EventID, &e.ErasedAt); err != nil {
+				return fmt.Errorf("failed to scan customer erasure: %w", err)
+			}
+			erasures = append(erasures, &e)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to list customer erasures: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return erasures, nil
+}
+
+// anonymizeCustomers заменяет email, имя и адреса клиентов обезличенными значениями.
+// Суммы, статусы и страна адреса остаются: от них зависят отчеты и налоги.
+// Из metadata заказов остаются только служебные ключи. Повторный вызов ничего не меняет.
+// Затрагивает только клиентов и заказы арендатора из контекста.
+// Возвращает ID измененных заказов и количество измененных адресов.
+func anonymizeCustomers(ctx context.Context, tx *sql.Tx, customerIDs []uuid.UUID) ([]uuid.UUID, int64, error) {
+	ids := make([]string, len(customerIDs))
+	for i, id := range customerIDs {
+		ids[i] = id.String()
+	}
+
+	// Обезличенный email как в entities.ErasedEmail
+	query, args := andTenant(ctx, `
+		UPDATE customers
+		SET email = 'erased-' || id::text || $2, email_index = NULL, name = '',
+			default_shipping_address = NULL, default_billing_address = NULL
+		WHERE id = ANY($1) AND email <> 'erased-' || id::text || $2`, pq.Array(ids), entities.ErasedEmailDomain)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to anonymize customers: %w", err)
+	}
+
+	query, args = andTenant(ctx, `
+		UPDATE orders
+		SET email = 'erased-' || customer_id::text || $2, email_index = NULL,
+			metadata = (
+				SELECT COALESCE(jsonb_object_agg(key, value), '{}'::jsonb)
+				FROM jsonb_each(metadata)
+				WHERE key = ANY($3)
+			)
+		WHERE customer_id = ANY($1) AND email <> 'erased-' || customer_id::text || $2`,
+		pq.Array(ids), entities.ErasedEmailDomain, pq.Array([]string{entities.MetadataLocale, entities.MetadataSalesChannel}))
+	rows, err := tx.QueryContext(ctx, query+" RETURNING id", args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to anonymize orders: %w", err)
+	}
+	var orderIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, 0, fmt.Errorf("failed to scan anonymized order: %w", err)
+		}
+		orderIDs = append(orderIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to anonymize orders: %w", err)
+	}
+
+	query, args = andTenantOf(ctx, "o", `
+		UPDATE order_addresses a
+		SET street = $2, city = $2, state = '', zip_code = ''
+		FROM orders o
+		WHERE a.order_id = o.id AND o.customer_id = ANY($1) AND a.street <> $2`, pq.Array(ids), entities.ErasedValue)
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to anonymize order addresses: %w", err)
+	}
+	addresses, err := result.RowsAffected()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	query, args = andTenantOf(ctx, "o", `
+		UPDATE order_notifications n
+		SET recipient = o.email
+		FROM orders o
+		WHERE n.order_id = o.id AND o.customer_id = ANY($1) AND n.recipient <> o.email`, pq.Array(ids))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to anonymize notifications: %w", err)
+	}
+
+	return orderIDs, addresses, nil
+}
+
+// scanCustomer считывает клиента из строки результата и расшифровывает его данные
+func scanCustomer(fields FieldCipher, row *sql.Row) (*entities.Customer, error) {
+	var c entities.Customer
+	var shipping, billing []byte
+
+	if err := row.Scan(&c.ID, &c.Email, &c.Name, &shipping, &billing, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	email, err := fields.Decrypt(c.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt customer email: %w", err)
+	}
+	c.Email = email
+
+	if len(shipping) > 0 {
+		if err := json.Unmarshal(shipping, &c.DefaultShippingAddress); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal shipping address: %w", err)
+		}
+		if err := openAddress(fields, c.DefaultShippingAddress); err != nil {
+			return nil, err
+		}
+	}
+	if len(billing) > 0 {
+		if err := json.Unmarshal(billing, &c.DefaultBillingAddress); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal billing address: %w", err)
+		}
+		if err := openAddress(fields, c.DefaultBillingAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	return &c, nil
+}
+
+// marshalCustomerAddresses шифрует и сериализует адреса клиента в JSONB; nil - NULL
+func marshalCustomerAddresses(fields FieldCipher, customer *entities.Customer) (interface{}, interface{}, error) {
+	marshal := func(address *entities.Address) (interface{}, error) {
+		if address == nil {
+			return nil, nil
+		}
+		sealed, err := sealAddress(fields, address)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(sealed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal customer address: %w", err)
+		}
+		return data, nil
+	}
+
+	shipping, err := marshal(customer.DefaultShippingAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	billing, err := marshal(customer.DefaultBillingAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shipping, billing, nil
+}
@@ -0,0 +1,18 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// BypassesRowSecurity сообщает, обходит ли роль подключения row-level security
+// (суперпользователь или роль с BYPASSRLS)
+func BypassesRowSecurity(ctx context.Context, db *sql.DB) (bool, error) {
+	var bypass bool
+	err := db.QueryRowContext(ctx, `SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user`).Scan(&bypass)
+	if err != nil {
+		return false, fmt.Errorf("failed to check database role: %w", err)
+	}
+	return bypass, nil
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// replicaCheckTimeout ограничение времени проверки одной реплики
+const replicaCheckTimeout = 3 * time.Second
+
+// replicaLagQuery отставание реплики в секундах; без входящих изменений (равные LSN) - 0.
+// На основной БД (реплика повышена) отставание тоже 0.
+const replicaLagQuery = `
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
+// ReplicaHealth результат проверки реплики
+type ReplicaHealth struct {
+	Name    string
+	Healthy bool
+	Lag     time.Duration
+	Err     error
+	// Changed true при первой проверке и при изменении состояния после прошлой
+	Changed bool
+}
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+	checked atomic.Bool
+}
+
+// DBRouter направляет чтения на исправные реплики по кругу, записи - в основную БД.
+// После записи чтения тех же ключей (заказ, клиент) в течение stickyWindow идут
+// в основную БД, чтобы клиент видел свои изменения. Реплика считается исправной
+// после успешной проверки CheckReplicas с отставанием не больше maxLag.
+type DBRouter struct {
+	primary      *sql.DB
+	replicas     []*replica
+	maxLag       time.Duration
+	stickyWindow time.Duration
+	next         atomic.Uint64
+	now          func() time.Time
+
+	mu        sync.Mutex
+	writes    map[string]time.Time
+	lastPrune time.Time
+}
+
+// NewDBRouter создает маршрутизатор. Реплики не используются до первой успешной проверки;
+// maxLag 0 - отставание не проверяется.
+func NewDBRouter(primary *sql.DB, replicas []*sql.DB, maxLag, stickyWindow time.Duration) *DBRouter {
+	r := &DBRouter{
+		primary:      primary,
+		maxLag:       maxLag,
+		stickyWindow: stickyWindow,
+		now:          time.Now,
+		writes:       make(map[string]time.Time),
+	}
+	for i, db := range replicas {
+		r.replicas = append(r.replicas, &replica{name: fmt.Sprintf("replica-%d", i+1), db: db})
+	}
+	return r
+}
+
+// Reader выбирает БД для чтения: основную, если контекст требует свежих данных,
+// по одному из ключей недавно была запись или нет исправных реплик
+func (r *DBRouter) Reader(ctx context.Context, keys ...string) *sql.DB {
+	if len(r.replicas) == 0 || repositories.PrimaryReads(ctx) || r.recentlyWritten(keys) {
+		return r.primary
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		rep := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if rep.healthy.Load() {
+			return rep.db
+		}
+	}
+	return r.primary
+}
+
+// MarkWritten запоминает запись по ключам для чтения своих изменений; nil маршрутизатор ничего не запоминает.
+// Записи хранятся в памяти процесса: изменения, сделанные другими процессами (consumer),
+// не отмечаются, и их чтение может прийти с реплики с отставанием до maxLag.
+func (r *DBRouter) MarkWritten(keys ...string) {
+	if r == nil || r.stickyWindow <= 0 || len(r.replicas) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, key := range keys {
+		r.writes[key] = now
+	}
+
+	// Устаревшие записи удаляются не чаще раза за окно
+	if now.Sub(r.lastPrune) < r.stickyWindow {
+		return
+	}
+	for key, at := range r.writes {
+		if now.Sub(at) >= r.stickyWindow {
+			delete(r.writes, key)
+		}
+	}
+	r.lastPrune = now
+}
+
+// ReportFailure исключает реплику после ошибки запроса до следующей проверки
+func (r *DBRouter) ReportFailure(db *sql.DB) {
+	for _, rep := range r.replicas {
+		if rep.db == db {
+			rep.healthy.Store(false)
+		}
+	}
+}
+
+// CheckReplicas проверяет доступность и отставание всех реплик
+func (r *DBRouter) CheckReplicas(ctx context.Context) []ReplicaHealth {
+	results := make([]ReplicaHealth, 0, len(r.replicas))
+	for _, rep := range r.replicas {
+		health := r.checkReplica(ctx, rep)
+		changed := rep.healthy.Swap(health.Healthy) != health.Healthy
+		health.Changed = !rep.checked.Swap(true) || changed
+		results = append(results, health)
+	}
+	return results
+}
+
+func (r *DBRouter) checkReplica(ctx context.Context, rep *replica) ReplicaHealth {
+	health := ReplicaHealth{Name: rep.name}
+
+	ctx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+	defer cancel()
+
+	var lagSeconds float64
+	if err := rep.db.QueryRowContext(ctx, replicaLagQuery).Scan(&lagSeconds); err != nil {
+		health.Err = fmt.Errorf("failed to check replica: %w", err)
+		return health
+	}
+
+	health.Lag = time.Duration(lagSeconds * float64(time.Second))
+	if r.maxLag > 0 && health.Lag > r.maxLag {
+		health.Err = fmt.Errorf("replica lag %s exceeds %s", health.Lag.Round(time.Millisecond), r.maxLag)
+		return health
+	}
+
+	health.Healthy = true
+	return health
+}
+
+// Close закрывает соединения с репликами; основная БД закрывается владельцем
+func (r *DBRouter) Close() error {
+	var firstErr error
+	for _, rep := range r.replicas {
+		if err := rep.db.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (r *DBRouter) recentlyWritten(keys []string) bool {
+	if r.stickyWindow <= 0 || len(keys) == 0 {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, key := range keys {
+		if at, ok := r.writes[key]; ok && now.Sub(at) < r.stickyWindow {
+			return true
+		}
+	}
+	return false
+}
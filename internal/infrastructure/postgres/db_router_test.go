@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"kafka-order-service/internal/domain/repositories"
+)
+
+func openTestDB(t *testing.T, host string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("postgres", "host="+host)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func newTestRouter(t *testing.T) (*DBRouter, *sql.DB, []*sql.DB) {
+	primary := openTestDB(t, "primary")
+	replicas := []*sql.DB{openTestDB(t, "replica1"), openTestDB(t, "replica2")}
+	router := NewDBRouter(primary, replicas, time.Second, 10*time.Second)
+	for _, rep := range router.replicas {
+		rep.healthy.Store(true)
+	}
+	return router, primary, replicas
+}
+
+func TestDBRouter_ReadsFromHealthyReplicas(t *testing.T) {
+	router, primary, replicas := newTestRouter(t)
+	ctx := context.Background()
+
+	seen := make(map[*sql.DB]int)
+	for i := 0; i < 4; i++ {
+		seen[router.Reader(ctx)]++
+	}
+	if seen[replicas[0]] != 2 || seen[replicas[1]] != 2 {
+		t.Errorf("expected round robin over replicas, got %v", seen)
+	}
+
+	router.ReportFailure(replicas[0])
+	for i := 0; i < 3; i++ {
+		if db := router.Reader(ctx); db != replicas[1] {
+			t.Fatalf("expected failed replica to be skipped")
+		}
+	}
+
+	router.ReportFailure(replicas[1])
+	if router.Reader(ctx) != primary {
+		t.Error("expected primary without healthy replicas")
+	}
+}
+
+func TestDBRouter_ReadYourWrites(t *testing.T) {
+	router, primary, _ := newTestRouter(t)
+	now := time.Now()
+	router.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	router.MarkWritten("order:1", "customer:1")
+	if router.Reader(ctx, "order:1") != primary {
+		t.Error("expected primary right after write")
+	}
+	if router.Reader(ctx, "customer:2", "customer:1") != primary {
+		t.Error("expected primary when any key was written")
+	}
+	if router.Reader(ctx, "order:2") == primary {
+		t.Error("expected replica for other keys")
+	}
+
+	now = now.Add(10 * time.Second)
+	if router.Reader(ctx, "order:1") == primary {
+		t.Error("expected replica after sticky window")
+	}
+
+	// Репозитории без реплик передают nil маршрутизатор
+	var withoutReplicas *DBRouter
+	withoutReplicas.MarkWritten("order:1")
+}
+
+func TestDBRouter_PrimaryReadsContext(t *testing.T) {
+	router, primary, _ := newTestRouter(t)
+
+	if router.Reader(repositories.WithPrimaryReads(context.Background())) != primary {
+		t.Error("expected primary for context requiring primary reads")
+	}
+}
+
+func TestDBRouter_ReplicasUnusedUntilChecked(t *testing.T) {
+	primary := openTestDB(t, "primary")
+	router := NewDBRouter(primary, []*sql.DB{openTestDB(t, "replica1")}, 0, time.Second)
+
+	if router.Reader(context.Background()) != primary {
+		t.Error("expected primary before the first health check")
+	}
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// ExchangeRateRepository реализация репозитория курсов валют для PostgreSQL
+type ExchangeRateRepository struct {
+	db *sql.DB
+}
+
+// NewExchangeRateRepository создает новый репозиторий курсов валют
+func NewExchangeRateRepository(db *sql.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		db: db,
+	}
+}
+
+// Upsert сохраняет курсы в одной транзакции
+func (r *ExchangeRateRepository) Upsert(ctx context.Context, rates []*entities.ExchangeRate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO exchange_rates (currency, base_currency, rate, effective_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (currency, base_currency, effective_at) DO UPDATE SET rate = EXCLUDED.rate`
+
+	for _, rate := range rates {
+		if _, err := tx.ExecContext(ctx, query,
+			rate.Currency, rate.BaseCurrency, rate.Rate, rate.EffectiveAt); err != nil {
+			return fmt.Errorf("failed to upsert exchange rate %s/%s: %w", rate.Currency, rate.BaseCurrency, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetRate получает последний курс, действующий на момент at; nil, если курса нет
+func (r *ExchangeRateRepository) GetRate(ctx context.Context, currency, baseCurrency string, at time.Time) (*entities.ExchangeRate, error) {
+	query := `
+		SELECT currency, base_currency, rate, effective_at
+		FROM exchange_rates
+		WHERE currency = $1 AND base_currency = $2 AND effective_at <= $3
+		ORDER BY effective_at DESC
+		LIMIT 1`
+
+	var rate entities.ExchangeRate
+	err := r.db.QueryRowContext(ctx, query, currency, baseCurrency, at).Scan(
+		&rate.Currency, &rate.BaseCurrency, &rate.Rate, &rate.EffectiveAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	return &rate, nil
+}
@@ -0,0 +1,409 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// InventoryRepository реализация репозитория остатков для PostgreSQL
+type InventoryRepository struct {
+	db *sql.DB
+}
+
+// NewInventoryRepository создает новый репозиторий остатков
+func NewInventoryRepository(db *sql.DB) *InventoryRepository {
+	return &InventoryRepository{
+		db: db,
+	}
+}
+
+const stockLevelColumns = `product_id, warehouse, on_hand, reserved, updated_at`
+
+// queryer общий интерфейс *sql.DB и *sql.Tx для чтения
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// GetStockLevels получает остатки товаров по всем складам
+func (r *InventoryRepository) GetStockLevels(ctx context.Context, productIDs []uuid.UUID) ([]*entities.StockLevel, error) {
+	query := `SELECT ` + stockLevelColumns + ` FROM stock_levels
+		WHERE product_id = ANY($1::uuid[]) ORDER BY product_id, warehouse`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(uuidStrings(productIDs)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock levels: %w", err)
+	}
+	defer rows.Close()
+
+	return scanStockLevels(rows)
+}
+
+// AdjustStock изменяет остаток товара на складе, создавая запись при необходимости
+func (r *InventoryRepository) AdjustStock(ctx context.Context, productID uuid.UUID, warehouse string, delta int) (*entities.StockLevel, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO stock_levels (product_id, warehouse) VALUES ($1, $2)
+		ON CONFLICT (product_id, warehouse) DO NOTHING`, productID, warehouse); err != nil {
+		return nil, fmt.Errorf("failed to create stock level: %w", err)
+	}
+
+	query := `SELECT ` + stockLevelColumns + ` FROM stock_levels
+		WHERE product_id = $1 AND warehouse = $2 FOR UPDATE`
+
+	var level entities.StockLevel
+	if err := tx.QueryRowContext(ctx, query, productID, warehouse).Scan(
+		&level.ProductID, &level.Warehouse, &level.OnHand, &level.Reserved, &level.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to lock stock level: %w", err)
+	}
+
+	if level.OnHand+delta < level.Reserved {
+		return nil, entities.NewValidationError("product %s in %s: on hand %d cannot go below reserved %d",
+			productID, warehouse, level.OnHand+delta, level.Reserved)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE stock_levels SET on_hand = on_hand + $3
+		WHERE product_id = $1 AND warehouse = $2
+		RETURNING on_hand, updated_at`, productID, warehouse, delta).Scan(&level.OnHand, &level.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update stock level: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &level, nil
+}
+
+// ReserveOrder резервирует товары заказа. Строки остатков блокируются в порядке
+// product_id, чтобы параллельные заказы не продали больше, чем есть на складе.
+// Товары без записей об остатках не отслеживаются и не резервируются.
+func (r *InventoryRepository) ReserveOrder(ctx context.Context, order *entities.Order) ([]*entities.StockReservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setTenant(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	// Блокируем заказ, чтобы повторная доставка события не создала второй резерв
+	if _, err := lockOrder(ctx, tx, order.ID); err != nil {
+		return nil, err
+	}
+
+	existing, err := r.queryReservations(ctx, tx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return existing, nil
+	}
+
+	reservations, err := reserveStock(ctx, tx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// reserveStock резервирует товары заказа в транзакции tx, блокируя их остатки.
+// Товары без остатков на складах не резервируются.
+func reserveStock(ctx context.Context, tx *sql.Tx, order *entities.Order) ([]*entities.StockReservation, error) {
+	productIDs := make([]uuid.UUID, 0, len(order.Items))
+	for _, item := range order.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	query := `SELECT ` + stockLevelColumns + ` FROM stock_levels
+		WHERE product_id = ANY($1::uuid[]) ORDER BY product_id, warehouse FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, pq.Array(uuidStrings(productIDs)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock stock levels: %w", err)
+	}
+	levels, err := scanStockLevels(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[uuid.UUID][]*entities.StockLevel)
+	for _, level := range levels {
+		byProduct[level.ProductID] = append(byProduct[level.ProductID], level)
+	}
+
+	var reservations []*entities.StockReservation
+	for _, item := range order.Items {
+		productLevels, tracked := byProduct[item.ProductID]
+		if !tracked {
+			continue
+		}
+		allocated, err := entities.AllocateStock(item, productLevels)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, allocated...)
+	}
+
+	insertQuery := `
+		INSERT INTO stock_reservations (id, order_id, order_item_id, product_id, warehouse, quantity, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	for _, res := range reservations {
+		if _, err := tx.ExecContext(ctx, insertQuery,
+			res.ID, res.OrderID, res.OrderItemID, res.ProductID, res.Warehouse,
+			res.Quantity, res.Status, res.CreatedAt, res.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to insert stock reservation: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE stock_levels SET reserved = reserved + $3
+			WHERE product_id = $1 AND warehouse = $2`,
+			res.ProductID, res.Warehouse, res.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to reserve stock: %w", err)
+		}
+	}
+
+	return reservations, nil
+}
+
+// CommitOrder списывает зарезервированные товары заказа с остатков
+func (r *InventoryRepository) CommitOrder(ctx context.Context, orderID uuid.UUID) (int, error) {
+	return r.settleReservations(ctx, orderID, entities.ReservationStatusCommitted,
+		`UPDATE stock_levels SET on_hand = on_hand - $3, reserved = reserved - $3
+		WHERE product_id = $1 AND warehouse = $2`)
+}
+
+// ReleaseOrder возвращает зарезервированные товары заказа в доступный остаток
+func (r *InventoryRepository) ReleaseOrder(ctx context.Context, orderID uuid.UUID) (int, error) {
+	return r.settleReservations(ctx, orderID, entities.ReservationStatusReleased,
+		`UPDATE stock_levels SET reserved = reserved - $3
+		WHERE product_id = $1 AND warehouse = $2`)
+}
+
+// GetReservations получает резервы заказа
+func (r *InventoryRepository) GetReservations(ctx context.Context, orderID uuid.UUID) ([]*entities.StockReservation, error) {
+	var reservations []*entities.StockReservation
+	err := withTenant(ctx, r.db, func(q querier) error {
+		var err error
+		reservations, err = r.queryReservations(ctx, q, orderID)
+		return err
+	})
+	return reservations, err
+}
+
+// settleReservations переводит активные резервы заказа в статус status и
+// применяет stockQuery к остаткам. Возвращает количество обработанных резервов.
+func (r *InventoryRepository) settleReservations(ctx context.Context, orderID uuid.UUID, status entities.ReservationStatus, stockQuery string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setTenant(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE stock_reservations SET status = $2
+		WHERE order_id = $1 AND status = 'reserved'
+		RETURNING product_id, warehouse, quantity`, orderID, status)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update stock reservations: %w", err)
+	}
+
+	type settled struct {
+		productID uuid.UUID
+		warehouse string
+		quantity  int
+	}
+	var items []settled
+	for rows.Next() {
+		var s settled
+		if err := rows.Scan(&s.productID, &s.warehouse, &s.quantity); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan stock reservation: %w", err)
+		}
+		items = append(items, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate stock reservations: %w", err)
+	}
+
+	for _, s := range items {
+		if _, err := tx.ExecContext(ctx, stockQuery, s.productID, s.warehouse, s.quantity); err != nil {
+			return 0, fmt.Errorf("failed to update stock level: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(items), nil
+}
+
+// queryReservations получает резервы заказа через db или транзакцию
+func (r *InventoryRepository) queryReservations(ctx context.Context, q queryer, orderID uuid.UUID) ([]*entities.StockReservation, error) {
+	query := `
+		SELECT id, order_id, order_item_id, product_id, warehouse, quantity, status, created_at, updated_at
+		FROM stock_reservations
+		WHERE order_id = $1
+		ORDER BY created_at, product_id, warehouse`
+
+	rows, err := q.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock reservations: %w", err)
+	}
+	defer rows.Close()
+
+	var reservations []*entities.StockReservation
+	for rows.Next() {
+		var res entities.StockReservation
+		if err := rows.Scan(&res.ID, &res.OrderID, &res.OrderItemID, &res.ProductID, &res.Warehouse,
+			&res.Quantity, &res.Status, &res.CreatedAt, &res.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stock reservation: %w", err)
+		}
+		reservations = append(reservations, &res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate stock reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// commitShipmentReservations списывает с остатков резервы позиций отправления в транзакции,
+// где заказ уже заблокирован. Резерв больше отправленного количества делится: отправленная
+// часть списывается, остаток остается в резерве и при отмене заказа возвращается на склад.
+// Позиции без резервов (товар не отслеживается) пропускаются.
+func commitShipmentReservations(ctx context.Context, tx *sql.Tx, shipment *entities.Shipment) error {
+	type committed struct {
+		productID uuid.UUID
+		warehouse string
+		quantity  int
+	}
+	var stock []committed
+
+	for _, item := range shipment.Items {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id, product_id, warehouse, quantity
+			FROM stock_reservations
+			WHERE order_id = $1 AND order_item_id = $2 AND status = 'reserved'
+			ORDER BY warehouse
+			FOR UPDATE`, shipment.OrderID, item.OrderItemID)
+		if err != nil {
+			return fmt.Errorf("failed to lock stock reservations: %w", err)
+		}
+		var reservations []*entities.StockReservation
+		for rows.Next() {
+			var res entities.StockReservation
+			if err := rows.Scan(&res.ID, &res.ProductID, &res.Warehouse, &res.Quantity); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan stock reservation: %w", err)
+			}
+			reservations = append(reservations, &res)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to iterate stock reservations: %w", err)
+		}
+
+		need := item.Quantity
+		for _, res := range reservations {
+			if need == 0 {
+				break
+			}
+			quantity := res.Quantity
+			if quantity > need {
+				quantity = need
+			}
+
+			if quantity == res.Quantity {
+				_, err = tx.ExecContext(ctx, `UPDATE stock_reservations SET status = 'committed' WHERE id = $1`, res.ID)
+			} else {
+				_, err = tx.ExecContext(ctx, `UPDATE stock_reservations SET quantity = quantity - $2 WHERE id = $1`, res.ID, quantity)
+				if err == nil {
+					_, err = tx.ExecContext(ctx, `
+						INSERT INTO stock_reservations (id, order_id, order_item_id, product_id, warehouse, quantity, status, created_at, updated_at)
+						VALUES ($1, $2, $3, $4, $5, $6, 'committed', NOW(), NOW())`,
+						uuid.New(), shipment.OrderID, item.OrderItemID, res.ProductID, res.Warehouse, quantity)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to commit stock reservation: %w", err)
+			}
+
+			stock = append(stock, committed{productID: res.ProductID, warehouse: res.Warehouse, quantity: quantity})
+			need -= quantity
+		}
+	}
+
+	// Остатки обновляются в порядке product_id, как при резервировании, чтобы не было взаимных блокировок
+	sort.Slice(stock, func(i, j int) bool {
+		if stock[i].productID != stock[j].productID {
+			return stock[i].productID.String() < stock[j].productID.String()
+		}
+		return stock[i].warehouse < stock[j].warehouse
+	})
+	for _, c := range stock {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE stock_levels SET on_hand = on_hand - $3, reserved = reserved - $3
+			WHERE product_id = $1 AND warehouse = $2`,
+			c.productID, c.warehouse, c.quantity); err != nil {
+			return fmt.Errorf("failed to update stock level: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanStockLevels читает строки остатков
+func scanStockLevels(rows *sql.Rows) ([]*entities.StockLevel, error) {
+	var levels []*entities.StockLevel
+	for rows.Next() {
+		var level entities.StockLevel
+		if err := rows.Scan(&level.ProductID, &level.Warehouse, &level.OnHand, &level.Reserved, &level.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stock level: %w", err)
+		}
+		levels = append(levels, &level)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate stock levels: %w", err)
+	}
+
+	return levels, nil
+}
+
+// uuidStrings переводит идентификаторы в строки для передачи массивом
+func uuidStrings(ids []uuid.UUID) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, id.String())
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// NotificationRepository реализация журнала уведомлений для PostgreSQL
+type NotificationRepository struct {
+	db     *sql.DB
+	fields FieldCipher
+}
+
+// NewNotificationRepository создает новый репозиторий уведомлений.
+// fields шифрует email получателя; nil - он хранится открытым текстом.
+func NewNotificationRepository(db *sql.DB, fields FieldCipher) *NotificationRepository {
+	if fields == nil {
+		fields = plainFields{}
+	}
+	return &NotificationRepository{
+		db:     db,
+		fields: fields,
+	}
+}
+
+// Exists проверяет, отправлялся ли шаблон по заказу
+func (r *NotificationRepository) Exists(ctx context.Context, orderID uuid.UUID, template string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM order_notifications WHERE order_id = $1 AND template = $2)`
+	if err := r.db.QueryRowContext(ctx, query, orderID, template).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check notification: %w", err)
+	}
+	return exists, nil
+}
+
+// Record сохраняет отправленное уведомление, повторная запись шаблона по заказу игнорируется
+func (r *NotificationRepository) Record(ctx context.Context, n *entities.Notification) (bool, error) {
+	query := `
+		INSERT INTO order_notifications (id, order_id, event_id, template, recipient, locale, subject, sent_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (order_id, template) DO NOTHING`
+
+	recipient, err := r.fields.Encrypt(n.Recipient)
+	if err != nil {
+		return false, fmt.Errorf("failed to encrypt notification recipient: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		n.ID, n.OrderID, n.EventID, n.Template, recipient, n.Locale, n.Subject, n.SentAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert notification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ListByOrder получает уведомления заказа
+func (r *NotificationRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*entities.Notification, error) {
+	query := `
+		SELECT id, order_id, event_id, template, recipient, locale, subject, sent_at
+		FROM order_notifications
+		WHERE order_id = $1
+		ORDER BY sent_at`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := make([]*entities.Notification, 0)
+	for rows.Next() {
+		var n entities.Notification
+		if err := rows.Scan(&n.ID, &n.OrderID, &n.EventID, &n.Template, &n.Recipient,
+			&n.Locale, &n.Subject, &n.SentAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		recipient, err := r.fields.Decrypt(n.Recipient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt notification recipient: %w", err)
+		}
+		n.Recipient = recipient
+		notifications = append(notifications, &n)
+	}
+
+	return notifications, rows.Err()
+}
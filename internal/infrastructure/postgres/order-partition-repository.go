@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// orderPartitionPrefix префикс имен месячных партиций заказов (orders_pYYYYMM)
+const orderPartitionPrefix = "orders_p"
+
+// OrderPartitionRepository реализация обслуживания партиций заказов для PostgreSQL
+// через функции create_order_partition и detach_order_partition
+type OrderPartitionRepository struct {
+	db *sql.DB
+}
+
+// NewOrderPartitionRepository создает новый репозиторий партиций заказов
+func NewOrderPartitionRepository(db *sql.DB) *OrderPartitionRepository {
+	return &OrderPartitionRepository{
+		db: db,
+	}
+}
+
+// ListMonths возвращает месяцы присоединенных партиций заказов по возрастанию
+func (r *OrderPartitionRepository) ListMonths(ctx context.Context) ([]time.Time, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'orders'::regclass AND c.relname ~ '^orders_p[0-9]{6}$'
+		ORDER BY c.relname`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var months []time.Time
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan order partition: %w", err)
+		}
+		month, err := time.Parse("200601", strings.TrimPrefix(name, orderPartitionPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid order partition name %s: %w", name, err)
+		}
+		months = append(months, month)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list order partitions: %w", err)
+	}
+
+	return months, nil
+}
+
+// CreateMonth создает партиции заказов и позиций месяца
+func (r *OrderPartitionRepository) CreateMonth(ctx context.Context, month time.Time) (bool, error) {
+	var created bool
+	err := r.db.QueryRowContext(ctx, `SELECT create_order_partition($1::date)`, month.Format("2006-01-02")).Scan(&created)
+	if err != nil {
+		return false, fmt.Errorf("failed to create order partition %s: %w", month.Format("2006-01"), err)
+	}
+	return created, nil
+}
+
+// DetachMonth отсоединяет или удаляет партиции заказов и позиций месяца
+func (r *OrderPartitionRepository) DetachMonth(ctx context.Context, month time.Time, drop bool) (bool, error) {
+	var detached bool
+	err := r.db.QueryRowContext(ctx, `SELECT detach_order_partition($1::date, $2)`, month.Format("2006-01-02"), drop).Scan(&detached)
+	if err != nil {
+		return false, fmt.Errorf("failed to detach order partition %s: %w", month.Format("2006-01"), err)
+	}
+	return detached, nil
+}
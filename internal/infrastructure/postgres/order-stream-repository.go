@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// defaultStreamBatchSize размер пачки курсора, если batchSize не задан
+const defaultStreamBatchSize = 1000
+
+// Stream читает заказы серверным курсором: в памяти держится только текущая пачка.
+// Позиции и адреса пачки загружаются одним запросом на пачку.
+func (r *OrderRepository) Stream(ctx context.Context, filters repositories.OrderFilters, batchSize int, fn func(order *entities.Order) error) error {
+	if batchSize <= 0 {
+		batchSize = defaultStreamBatchSize
+	}
+
+	// Курсор живет только внутри транзакции
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin stream transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setTenant(ctx, tx); err != nil {
+		return err
+	}
+
+	query, args := r.buildListQuery(ctx, filters)
+	if _, err := tx.ExecContext(ctx, "DECLARE orders_stream NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return fmt.Errorf("failed to declare orders cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM orders_stream", batchSize)
+	for {
+		orders, err := fetchOrders(ctx, tx, fetch, r.fields)
+		if err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			break
+		}
+
+		if err := r.loadBatchDetails(ctx, tx, orders); err != nil {
+			return err
+		}
+		for _, order := range orders {
+			if err := fn(order); err != nil {
+				return err
+			}
+		}
+
+		if len(orders) < batchSize {
+			break
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "CLOSE orders_stream"); err != nil {
+		return fmt.Errorf("failed to close orders cursor: %w", err)
+	}
+	return tx.Commit()
+}
+
+// fetchOrders читает очередную пачку заказов из курсора
+func fetchOrders(ctx context.Context, tx *sql.Tx, fetch string, fields FieldCipher) ([]*entities.Order, error) {
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*entities.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		if err := openOrder(fields, order); err != nil {
+			return nil, err
+		}
+		order.Items = make([]entities.OrderItem, 0)
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// loadBatchDetails загружает позиции и адреса пачки заказов
+func (r *OrderRepository) loadBatchDetails(ctx context.Context, tx *sql.Tx, orders []*entities.Order) error {
+	byID := make(map[uuid.UUID]*entities.Order, len(orders))
+	ids := make([]uuid.UUID, 0, len(orders))
+	// Границы created_at пачки ограничивают поиск позиций партициями ее месяцев
+	from, to := orders[0].CreatedAt, orders[0].CreatedAt
+	for _, order := range orders {
+		byID[order.ID] = order
+		ids = append(ids, order.ID)
+		if order.CreatedAt.Before(from) {
+			from = order.CreatedAt
+		}
+		if order.CreatedAt.After(to) {
+			to = order.CreatedAt
+		}
+	}
+
+	itemRows, err := tx.QueryContext(ctx, `
+		SELECT id, order_id, product_id, name, price, quantity, total, discount_amount,
+			tax_category, tax_amount, tax_inclusive
+		FROM order_items
+		WHERE order_id = ANY($1) AND order_created_at BETWEEN $2 AND $3
+		ORDER BY order_id, name`, pq.Array(uuidStrings(ids)), from, to)
+	if err != nil {
+		return fmt.Errorf("failed to load order items: %w", err)
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item entities.OrderItem
+		if err := itemRows.Scan(
+			&item.ID, &item.OrderID, &item.ProductID, &item.Name,
+			&item.Price, &item.Quantity, &item.Total, &item.DiscountAmount,
+			&item.TaxCategory, &item.TaxAmount, &item.TaxInclusive); err != nil {
+			return fmt.Errorf("failed to scan order item: %w", err)
+		}
+		if order, ok := byID[item.OrderID]; ok {
+			order.Items = append(order.Items, item)
+		}
+	}
+	if err := itemRows.Err(); err != nil {
+		return fmt.Errorf("failed to load order items: %w", err)
+	}
+
+	addressRows, err := tx.QueryContext(ctx, `
+		SELECT id, order_id, type, street, city, state, country, zip_code
+		FROM order_addresses
+		WHERE order_id = ANY($1)`, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		return fmt.Errorf("failed to load order addresses: %w", err)
+	}
+	defer addressRows.Close()
+
+	for addressRows.Next() {
+		var addr entities.Address
+		if err := addressRows.Scan(
+			&addr.ID, &addr.OrderID, &addr.Type, &addr.Street,
+			&addr.City, &addr.State, &addr.Country, &addr.ZipCode); err != nil {
+			return fmt.Errorf("failed to scan order address: %w", err)
+		}
+		if err := openAddress(r.fields, &addr); err != nil {
+			return err
+		}
+		order, ok := byID[addr.OrderID]
+		if !ok {
+			continue
+		}
+		switch addr.Type {
+		case "shipping":
+			order.ShippingAddress = &addr
+		case "billing":
+			order.BillingAddress = &addr
+		}
+	}
+	if err := addressRows.Err(); err != nil {
+		return fmt.Errorf("failed to load order addresses: %w", err)
+	}
+
+	return nil
+}
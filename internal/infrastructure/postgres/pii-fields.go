@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"fmt"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// FieldCipher шифрует персональные данные в колонках заказов (email, адреса)
+type FieldCipher interface {
+	// Encrypt шифрует значение; пустое значение остается пустым
+	Encrypt(plaintext string) (string, error)
+	// Decrypt расшифровывает значение; открытый текст возвращается как есть
+	Decrypt(value string) (string, error)
+	// BlindIndex возвращает ключ поиска по точному совпадению; пустая строка, если шифрование выключено
+	BlindIndex(value string) string
+}
+
+// plainFields хранит персональные данные открытым текстом
+type plainFields struct{}
+
+func (plainFields) Encrypt(plaintext string) (string, error) { return plaintext, nil }
+func (plainFields) Decrypt(value string) (string, error)     { return value, nil }
+func (plainFields) BlindIndex(string) string                 { return "" }
+
+// emailIndex возвращает blind index email или NULL, если шифрование выключено
+func emailIndex(fields FieldCipher, email string) interface{} {
+	index := fields.BlindIndex(entities.NormalizeEmail(email))
+	if index == "" {
+		return nil
+	}
+	return index
+}
+
+// sealAddress возвращает копию адреса с зашифрованными полями
+func sealAddress(fields FieldCipher, address *entities.Address) (*entities.Address, error) {
+	sealed := *address
+	for _, field := range []*string{&sealed.Street, &sealed.City, &sealed.State, &sealed.ZipCode} {
+		value, err := fields.Encrypt(*field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt address: %w", err)
+		}
+		*field = value
+	}
+	return &sealed, nil
+}
+
+// openAddress расшифровывает поля адреса на месте
+func openAddress(fields FieldCipher, address *entities.Address) error {
+	for _, field := range []*string{&address.Street, &address.City, &address.State, &address.ZipCode} {
+		value, err := fields.Decrypt(*field)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt address: %w", err)
+		}
+		*field = value
+	}
+	return nil
+}
+
+// openOrder расшифровывает email заказа на месте
+func openOrder(fields FieldCipher, order *entities.Order) error {
+	email, err := fields.Decrypt(order.Email)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt order email: %w", err)
+	}
+	order.Email = email
+	return nil
+}
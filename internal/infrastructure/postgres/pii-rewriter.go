@@ -0,0 +1,433 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// defaultRewriteBatchSize размер пачки перезаписи, если batchSize не задан
+const defaultRewriteBatchSize = 500
+
+// FieldRewrite описывает перезапись сохраненных персональных данных
+type FieldRewrite interface {
+	// Rewrite возвращает новое значение колонки и признак изменения
+	Rewrite(stored string) (string, bool, error)
+	// EmailIndex возвращает blind index сохраненного email; пустая строка - NULL
+	EmailIndex(stored string) (string, error)
+}
+
+// PIIRewriteResult итог перезаписи
+type PIIRewriteResult struct {
+	Orders        int64 `json:"orders"`
+	Addresses     int64 `json:"addresses"`
+	Customers     int64 `json:"customers"`
+	Notifications int64 `json:"notifications"`
+}
+
+// PIIRewriter перезаписывает email и адреса заказов и клиентов и получателей уведомлений
+// пачками: шифрование данных,
+// записанных до включения шифрования, переход на новый ключ, пересчет blind index
+// и расшифровка перед откатом миграции. updated_at и дневной срез отчетов не меняются.
+type PIIRewriter struct {
+	db *sql.DB
+}
+
+// NewPIIRewriter создает новый PIIRewriter
+func NewPIIRewriter(db *sql.DB) *PIIRewriter {
+	return &PIIRewriter{
+		db: db,
+	}
+}
+
+// Rewrite перезаписывает все заказы, адреса, клиентов и уведомления. Строки пачки блокируются до конца ее
+// транзакции, поэтому параллельные изменения заказов не теряются. Повторный запуск
+// изменяет только то, что еще не перезаписано.
+func (w *PIIRewriter) Rewrite(ctx context.Context, rewrite FieldRewrite, batchSize int) (*PIIRewriteResult, error) {
+	if batchSize <= 0 {
+		batchSize = defaultRewriteBatchSize
+	}
+
+	result := &PIIRewriteResult{}
+	for after := uuid.Nil; ; {
+		last, updated, err := w.rewriteOrders(ctx, rewrite, after, batchSize)
+		if err != nil {
+			return result, err
+		}
+		result.Orders += updated
+		if last == uuid.Nil {
+			break
+		}
+		after = last
+	}
+
+	for after := uuid.Nil; ; {
+		last, updated, err := w.rewriteAddresses(ctx, rewrite, after, batchSize)
+		if err != nil {
+			return result, err
+		}
+		result.Addresses += updated
+		if last == uuid.Nil {
+			break
+		}
+		after = last
+	}
+
+	for after := uuid.Nil; ; {
+		last, updated, err := w.rewriteCustomers(ctx, rewrite, after, batchSize)
+		if err != nil {
+			return result, err
+		}
+		result.Customers += updated
+		if last == uuid.Nil {
+			break
+		}
+		after = last
+	}
+
+	for after := uuid.Nil; ; {
+		last, updated, err := w.rewriteNotifications(ctx, rewrite, after, batchSize)
+		if err != nil {
+			return result, err
+		}
+		result.Notifications += updated
+		if last == uuid.Nil {
+			break
+		}
+		after = last
+	}
+
+	return result, nil
+}
+
+// rewriteOrders перезаписывает пачку заказов после after.
+// Возвращает ID последнего заказа пачки (uuid.Nil, если пачка последняя).
+func (w *PIIRewriter) rewriteOrders(ctx context.Context, rewrite FieldRewrite, after uuid.UUID, limit int) (uuid.UUID, int64, error) {
+	tx, err := beginRewrite(ctx, w.db)
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	defer tx.Rollback()
+
+	type orderRow struct {
+		id    uuid.UUID
+		email string
+		index string
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, email, COALESCE(email_index, '')
+		FROM orders
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE`, after, limit)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to select orders: %w", err)
+	}
+	var batch []orderRow
+	for rows.Next() {
+		var row orderRow
+		if err := rows.Scan(&row.id, &row.email, &row.index); err != nil {
+			rows.Close()
+			return uuid.Nil, 0, fmt.Errorf("failed to scan order: %w", err)
+		}
+		batch = append(batch, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to select orders: %w", err)
+	}
+
+	var updated int64
+	for _, row := range batch {
+		email, changed, err := rewrite.Rewrite(row.email)
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("order %s: %w", row.id, err)
+		}
+		index, err := rewrite.EmailIndex(email)
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("order %s: %w", row.id, err)
+		}
+		if !changed && index == row.index {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE orders SET email = $2, email_index = NULLIF($3, '') WHERE id = $1 AND `+orderPartition,
+			row.id, email, index)
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("failed to update order %s: %w", row.id, err)
+		}
+		updated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if len(batch) < limit {
+		return uuid.Nil, updated, nil
+	}
+	return batch[len(batch)-1].id, updated, nil
+}
+
+// rewriteAddresses перезаписывает пачку адресов после after
+func (w *PIIRewriter) rewriteAddresses(ctx context.Context, rewrite FieldRewrite, after uuid.UUID, limit int) (uuid.UUID, int64, error) {
+	tx, err := beginRewrite(ctx, w.db)
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	defer tx.Rollback()
+
+	type addressRow struct {
+		id     uuid.UUID
+		fields [4]string
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, street, city, COALESCE(state, ''), zip_code
+		FROM order_addresses
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE`, after, limit)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to select order addresses: %w", err)
+	}
+	var batch []addressRow
+	for rows.Next() {
+		var row addressRow
+		if err := rows.Scan(&row.id, &row.fields[0], &row.fields[1], &row.fields[2], &row.fields[3]); err != nil {
+			rows.Close()
+			return uuid.Nil, 0, fmt.Errorf("failed to scan order address: %w", err)
+		}
+		batch = append(batch, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to select order addresses: %w", err)
+	}
+
+	var updated int64
+	for _, row := range batch {
+		var values [4]string
+		anyChanged := false
+		for i, stored := range row.fields {
+			value, changed, err := rewrite.Rewrite(stored)
+			if err != nil {
+				return uuid.Nil, 0, fmt.Errorf("address %s: %w", row.id, err)
+			}
+			values[i] = value
+			anyChanged = anyChanged || changed
+		}
+		if !anyChanged {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE order_addresses SET street = $2, city = $3, state = $4, zip_code = $5
+			WHERE id = $1`, row.id, values[0], values[1], values[2], values[3])
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("failed to update order address %s: %w", row.id, err)
+		}
+		updated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if len(batch) < limit {
+		return uuid.Nil, updated, nil
+	}
+	return batch[len(batch)-1].id, updated, nil
+}
+
+// rewriteCustomers перезаписывает пачку клиентов после after: email, blind index и адреса по умолчанию
+func (w *PIIRewriter) rewriteCustomers(ctx context.Context, rewrite FieldRewrite, after uuid.UUID, limit int) (uuid.UUID, int64, error) {
+	tx, err := beginRewrite(ctx, w.db)
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	defer tx.Rollback()
+
+	type customerRow struct {
+		id                uuid.UUID
+		email             string
+		index             string
+		shipping, billing []byte
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, email, COALESCE(email_index, ''), default_shipping_address, default_billing_address
+		FROM customers
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE`, after, limit)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to select customers: %w", err)
+	}
+	var batch []customerRow
+	for rows.Next() {
+		var row customerRow
+		if err := rows.Scan(&row.id, &row.email, &row.index, &row.shipping, &row.billing); err != nil {
+			rows.Close()
+			return uuid.Nil, 0, fmt.Errorf("failed to scan customer: %w", err)
+		}
+		batch = append(batch, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to select customers: %w", err)
+	}
+
+	var updated int64
+	for _, row := range batch {
+		email, emailChanged, err := rewrite.Rewrite(row.email)
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("customer %s: %w", row.id, err)
+		}
+		index, err := rewrite.EmailIndex(email)
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("customer %s: %w", row.id, err)
+		}
+		shipping, shippingChanged, err := rewriteAddressJSON(rewrite, row.shipping)
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("customer %s: %w", row.id, err)
+		}
+		billing, billingChanged, err := rewriteAddressJSON(rewrite, row.billing)
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("customer %s: %w", row.id, err)
+		}
+		if !emailChanged && !shippingChanged && !billingChanged && index == row.index {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE customers
+			SET email = $2, email_index = NULLIF($3, ''), default_shipping_address = $4, default_billing_address = $5
+			WHERE id = $1`, row.id, email, index, shipping, billing)
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("failed to update customer %s: %w", row.id, err)
+		}
+		updated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if len(batch) < limit {
+		return uuid.Nil, updated, nil
+	}
+	return batch[len(batch)-1].id, updated, nil
+}
+
+// rewriteNotifications перезаписывает пачку получателей уведомлений после after
+func (w *PIIRewriter) rewriteNotifications(ctx context.Context, rewrite FieldRewrite, after uuid.UUID, limit int) (uuid.UUID, int64, error) {
+	tx, err := beginRewrite(ctx, w.db)
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	defer tx.Rollback()
+
+	type notificationRow struct {
+		id        uuid.UUID
+		recipient string
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, recipient
+		FROM order_notifications
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE`, after, limit)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to select notifications: %w", err)
+	}
+	var batch []notificationRow
+	for rows.Next() {
+		var row notificationRow
+		if err := rows.Scan(&row.id, &row.recipient); err != nil {
+			rows.Close()
+			return uuid.Nil, 0, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		batch = append(batch, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to select notifications: %w", err)
+	}
+
+	var updated int64
+	for _, row := range batch {
+		recipient, changed, err := rewrite.Rewrite(row.recipient)
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("notification %s: %w", row.id, err)
+		}
+		if !changed {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE order_notifications SET recipient = $2 WHERE id = $1`, row.id, recipient)
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("failed to update notification %s: %w", row.id, err)
+		}
+		updated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if len(batch) < limit {
+		return uuid.Nil, updated, nil
+	}
+	return batch[len(batch)-1].id, updated, nil
+}
+
+// rewriteAddressJSON перезаписывает поля адреса клиента в JSONB; NULL остается NULL
+func rewriteAddressJSON(rewrite FieldRewrite, data []byte) (interface{}, bool, error) {
+	if len(data) == 0 {
+		return nil, false, nil
+	}
+
+	var address entities.Address
+	if err := json.Unmarshal(data, &address); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal address: %w", err)
+	}
+	anyChanged := false
+	for _, field := range []*string{&address.Street, &address.City, &address.State, &address.ZipCode} {
+		value, changed, err := rewrite.Rewrite(*field)
+		if err != nil {
+			return nil, false, err
+		}
+		*field = value
+		anyChanged = anyChanged || changed
+	}
+	if !anyChanged {
+		return data, false, nil
+	}
+
+	rewritten, err := json.Marshal(&address)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal address: %w", err)
+	}
+	return rewritten, true, nil
+}
+
+// beginRewrite открывает транзакцию, в которой триггер не меняет updated_at заказов
+func beginRewrite(ctx context.Context, db *sql.DB) (*sql.Tx, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `SET LOCAL app.keep_updated_at = 'on'`); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to disable updated_at trigger: %w", err)
+	}
+	return tx, nil
+}
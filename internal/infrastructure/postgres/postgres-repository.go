@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -16,43 +18,122 @@ import (
 
 // OrderRepository реализация репозитория заказов для PostgreSQL
 type OrderRepository struct {
-	db *sql.DB
+	db           *sql.DB
+	fields       FieldCipher
+	router       *DBRouter
+	reserveStock bool
 }
 
-// NewOrderRepository создает новый репозиторий заказов
-func NewOrderRepository(db *sql.DB) *OrderRepository {
+// NewOrderRepository создает новый репозиторий заказов.
+// fields шифрует email и адреса; nil - данные хранятся открытым текстом.
+func NewOrderRepository(db *sql.DB, fields FieldCipher) *OrderRepository {
+	if fields == nil {
+		fields = plainFields{}
+	}
 	return &OrderRepository{
-		db: db,
+		db:     db,
+		fields: fields,
 	}
 }
 
+// WithReplicas возвращает репозиторий, читающий GetByID, List, Count, Exists и Statistics
+// через маршрутизатор реплик; записи по-прежнему идут в основную БД
+func (r *OrderRepository) WithReplicas(router *DBRouter) *OrderRepository {
+	withReplicas := *r
+	withReplicas.router = router
+	return &withReplicas
+}
+
+// WithStockReservation возвращает репозиторий, резервирующий товары заказа в транзакции
+// его создания: при нехватке товара заказ не сохраняется
+func (r *OrderRepository) WithStockReservation() *OrderRepository {
+	withReservation := *r
+	withReservation.reserveStock = true
+	return &withReservation
+}
+
 // Create создает новый заказ
 func (r *OrderRepository) Create(ctx context.Context, order *entities.Order) error {
+	return r.create(ctx, order, nil)
+}
+
+// CreateWithCustomer создает заказ и нового клиента одной транзакцией
+func (r *OrderRepository) CreateWithCustomer(ctx context.Context, order *entities.Order, customer *entities.Customer) error {
+	return r.create(ctx, order, customer)
+}
+
+// create сохраняет заказ и, если передан, нового клиента
+func (r *OrderRepository) create(ctx context.Context, order *entities.Order, customer *entities.Customer) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	if err := assignTenant(ctx, order); err != nil {
+		return err
+	}
+	if err := setTenant(ctx, tx); err != nil {
+		return err
+	}
+
+	// Клиент, появившийся параллельно, не перезаписывается
+	if customer != nil {
+		args, err := customerInsertArgs(ctx, r.fields, customer)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, customerInsertQuery+` ON CONFLICT DO NOTHING`, args...); err != nil {
+			return fmt.Errorf("failed to insert customer: %w", err)
+		}
+	}
+
+	metadata, err := marshalMetadata(order.Metadata)
+	if err != nil {
+		return err
+	}
+
+	email, err := r.fields.Encrypt(order.Email)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt order email: %w", err)
+	}
+
 	// Вставка основной информации о заказе
 	query := `
 		INSERT INTO orders (
-			id, customer_id, email, status, total_amount, currency, 
-			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+			id, customer_id, email, status, subtotal, discount_amount, tax_amount, shipping_method,
+			shipping_amount, total_amount, currency, base_currency, exchange_rate, total_amount_base,
+			metadata, created_at, updated_at, email_index, tenant_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`
 
+	baseCurrency, exchangeRate, totalAmountBase := baseAmountArgs(order)
 	_, err = tx.ExecContext(ctx, query,
-		order.ID, order.CustomerID, order.Email, order.Status,
-		order.TotalAmount, order.Currency, order.CreatedAt, order.UpdatedAt)
+		order.ID, order.CustomerID, email, order.Status, order.Subtotal, order.Discount,
+		order.TaxAmount, order.ShippingMethod, order.ShippingAmount, order.TotalAmount,
+		order.Currency, baseCurrency, exchangeRate, totalAmountBase, metadata, order.CreatedAt, order.UpdatedAt,
+		emailIndex(r.fields, order.Email), order.TenantID)
 	if err != nil {
 		return fmt.Errorf("failed to insert order: %w", err)
 	}
 
 	// Вставка элементов заказа
 	if len(order.Items) > 0 {
-		if err := r.insertOrderItems(ctx, tx, order.Items); err != nil {
+		if err := r.insertOrderItems(ctx, tx, order.CreatedAt, order.Items); err != nil {
 			return fmt.Errorf("failed to insert order items: %w", err)
 		}
+		if err := r.insertItemTaxes(ctx, tx, order.Items); err != nil {
+			return fmt.Errorf("failed to insert order item taxes: %w", err)
+		}
+	}
+
+	// Вставка скидок и учет использования купонов
+	if len(order.Discounts) > 0 {
+		if err := r.insertDiscounts(ctx, tx, order.Discounts); err != nil {
+			return fmt.Errorf("failed to insert order discounts: %w", err)
+		}
+		if err := r.redeemPromotions(ctx, tx, order); err != nil {
+			return err
+		}
 	}
 
 	// Вставка адресов
@@ -68,43 +149,72 @@ func (r *OrderRepository) Create(ctx context.Context, order *entities.Order) err
 		}
 	}
 
+	// Вставка оценки риска
+	if order.RiskAssessment != nil {
+		if err := r.insertRiskAssessment(ctx, tx, order.ID, order.RiskAssessment); err != nil {
+			return fmt.Errorf("failed to insert risk assessment: %w", err)
+		}
+	}
+
+	// Резервирование товаров: остатки блокируются до фиксации заказа
+	if r.reserveStock {
+		if _, err := reserveStock(ctx, tx, order); err != nil {
+			return err
+		}
+	}
+
 	// Фиксация транзакции
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	r.markWritten(orderKey(order.ID), customerKey(order.CustomerID))
 
 	return nil
 }
 
 // GetByID получает заказ по ID
 func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
+	var order *entities.Order
+	err := r.read(ctx, []string{orderKey(id)}, func(db *sql.DB) error {
+		return withTenant(ctx, db, func(q querier) error {
+			var err error
+			order, err = r.getByID(ctx, q, id)
+			return err
+		})
+	})
+	return order, err
+}
+
+// getByID загружает заказ со всеми связанными данными
+func (r *OrderRepository) getByID(ctx context.Context, q querier, id uuid.UUID) (*entities.Order, error) {
 	// Получение основной информации о заказе
-	query := `
-		SELECT id, customer_id, email, status, total_amount, currency, 
-			   created_at, updated_at
-		FROM orders 
-		WHERE id = $1`
+	query, args := andTenant(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = $1 AND `+orderPartition, id)
 
-	var order entities.Order
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&order.ID, &order.CustomerID, &order.Email, &order.Status,
-		&order.TotalAmount, &order.Currency, &order.CreatedAt, &order.UpdatedAt)
+	order, err := scanOrder(q.QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, entities.NewOrderNotFoundError(id.String())
 		}
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
+	if err := openOrder(r.fields, order); err != nil {
+		return nil, err
+	}
 
 	// Получение элементов заказа
-	items, err := r.getOrderItems(ctx, id)
+	items, err := r.getOrderItems(ctx, q, id, order.CreatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get order items: %w", err)
 	}
 	order.Items = items
 
+	// Получение налогов позиций
+	if err := r.loadItemTaxes(ctx, q, id, order.Items); err != nil {
+		return nil, fmt.Errorf("failed to get order item taxes: %w", err)
+	}
+
 	// Получение адресов
-	addresses, err := r.getOrderAddresses(ctx, id)
+	addresses, err := r.getOrderAddresses(ctx, q, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get order addresses: %w", err)
 	}
@@ -117,115 +227,122 @@ func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.
 		}
 	}
 
-	// Инициализация метаданных
-	order.Metadata = make(map[string]interface{})
+	// Получение оценки риска
+	assessment, err := r.getRiskAssessment(ctx, q, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order risk assessment: %w", err)
+	}
+	order.RiskAssessment = assessment
 
-	return &order, nil
+	// Получение скидок
+	discounts, err := r.getOrderDiscounts(ctx, q, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order discounts: %w", err)
+	}
+	order.Discounts = discounts
+
+	return order, nil
 }
 
 // Update обновляет заказ
 func (r *OrderRepository) Update(ctx context.Context, order *entities.Order) error {
-	query := `
-		UPDATE orders 
-		SET customer_id = $2, email = $3, status = $4, total_amount = $5, 
-			currency = $6, updated_at = $7
-		WHERE id = $1`
-
-	result, err := r.db.ExecContext(ctx, query,
-		order.ID, order.CustomerID, order.Email, order.Status,
-		order.TotalAmount, order.Currency, order.UpdatedAt)
+	metadata, err := marshalMetadata(order.Metadata)
 	if err != nil {
-		return fmt.Errorf("failed to update order: %w", err)
+		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	email, err := r.fields.Encrypt(order.Email)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return entities.NewOrderNotFoundError(order.ID.String())
+		return fmt.Errorf("failed to encrypt order email: %w", err)
 	}
 
-	return nil
+	query, args := andTenant(ctx, `
+		UPDATE orders 
+		SET customer_id = $2, email = $3, status = $4, subtotal = $5, discount_amount = $6,
+			tax_amount = $7, shipping_method = $8, shipping_amount = $9, total_amount = $10,
+			currency = $11, metadata = $12, updated_at = $13, email_index = $14
+		WHERE id = $1 AND `+orderPartition,
+		order.ID, order.CustomerID, email, order.Status, order.Subtotal, order.Discount,
+		order.TaxAmount, order.ShippingMethod, order.ShippingAmount, order.TotalAmount,
+		order.Currency, metadata, order.UpdatedAt, emailIndex(r.fields, order.Email))
+
+	return r.execAffectingOrder(ctx, order.ID, "failed to update order", query+" RETURNING customer_id", args...)
 }
 
 // UpdateStatus обновляет только статус заказа
 func (r *OrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entities.OrderStatus) error {
-	query := `
+	query, args := andTenant(ctx, `
 		UPDATE orders 
 		SET status = $2, updated_at = $3
-		WHERE id = $1`
-
-	result, err := r.db.ExecContext(ctx, query, id, status, time.Now())
-	if err != nil {
-		return fmt.Errorf("failed to update order status: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+		WHERE id = $1 AND `+orderPartition, id, status, time.Now())
 
-	if rowsAffected == 0 {
-		return entities.NewOrderNotFoundError(id.String())
-	}
-
-	return nil
+	return r.execAffectingOrder(ctx, id, "failed to update order status", query+" RETURNING customer_id", args...)
 }
 
 // Delete удаляет заказ (мягкое удаление)
 func (r *OrderRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `
+	query, args := andTenant(ctx, `
 		UPDATE orders 
 		SET status = 'cancelled', updated_at = $2
-		WHERE id = $1 AND status NOT IN ('delivered', 'refunded', 'cancelled')`
+		WHERE id = $1 AND `+orderPartition+` AND status NOT IN ('delivered', 'partially_refunded', 'refunded', 'cancelled')`, id, time.Now())
 
-	result, err := r.db.ExecContext(ctx, query, id, time.Now())
-	if err != nil {
-		return fmt.Errorf("failed to delete order: %w", err)
-	}
+	return r.execAffectingOrder(ctx, id, "failed to delete order", query+" RETURNING customer_id", args...)
+}
 
-	rowsAffected, err := result.RowsAffected()
+// execAffectingOrder выполняет изменение одного заказа в рамках арендатора; запрос
+// возвращает customer_id измененного заказа, ни одной строки - заказ не найден
+func (r *OrderRepository) execAffectingOrder(ctx context.Context, id uuid.UUID, failure, query string, args ...interface{}) error {
+	var customerID uuid.UUID
+	err := withTenant(ctx, r.db, func(q querier) error {
+		if err := q.QueryRowContext(ctx, query, args...).Scan(&customerID); err != nil {
+			if err == sql.ErrNoRows {
+				return entities.NewOrderNotFoundError(id.String())
+			}
+			return fmt.Errorf("%s: %w", failure, err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return entities.NewOrderNotFoundError(id.String())
+		return err
 	}
 
+	r.markWritten(orderKey(id), customerKey(customerID))
 	return nil
 }
 
 // List получает список заказов с пагинацией и фильтрацией
 func (r *OrderRepository) List(ctx context.Context, filters repositories.OrderFilters) ([]*entities.Order, error) {
-	query, args := r.buildListQuery(filters)
-
-	rows, err := r.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute list query: %w", err)
-	}
-	defer rows.Close()
+	query, args := r.buildListQuery(ctx, filters)
 
 	var orders []*entities.Order
-	for rows.Next() {
-		var order entities.Order
-		err := rows.Scan(
-			&order.ID, &order.CustomerID, &order.Email, &order.Status,
-			&order.TotalAmount, &order.Currency, &order.CreatedAt, &order.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan order: %w", err)
-		}
-
-		// Инициализация метаданных и элементов
-		order.Metadata = make(map[string]interface{})
-		order.Items = make([]entities.OrderItem, 0)
-
-		orders = append(orders, &order)
-	}
-
-	return orders, nil
+	err := r.read(ctx, filterKeys(filters), func(db *sql.DB) error {
+		orders = nil
+		return withTenant(ctx, db, func(q querier) error {
+			rows, err := q.QueryContext(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("failed to execute list query: %w", err)
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				order, err := scanOrder(rows)
+				if err != nil {
+					return fmt.Errorf("failed to scan order: %w", err)
+				}
+				if err := openOrder(r.fields, order); err != nil {
+					return err
+				}
+
+				// Элементы заказа в списке не загружаются
+				order.Items = make([]entities.OrderItem, 0)
+
+				orders = append(orders, order)
+			}
+			return rows.Err()
+		})
+	})
+
+	return orders, err
 }
 
 // GetByCustomerID получает заказы конкретного клиента
@@ -256,10 +373,14 @@ func (r *OrderRepository) GetByStatus(ctx context.Context, status entities.Order
 
 // Count возвращает общее количество заказов
 func (r *OrderRepository) Count(ctx context.Context, filters repositories.OrderFilters) (int64, error) {
-	query, args := r.buildCountQuery(filters)
+	query, args := r.buildCountQuery(ctx, filters)
 
 	var count int64
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	err := r.read(ctx, filterKeys(filters), func(db *sql.DB) error {
+		return withTenant(ctx, db, func(q querier) error {
+			return q.QueryRowContext(ctx, query, args...).Scan(&count)
+		})
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to count orders: %w", err)
 	}
@@ -269,10 +390,19 @@ func (r *OrderRepository) Count(ctx context.Context, filters repositories.OrderF
 
 // Exists проверяет существование заказа
 func (r *OrderRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM orders WHERE id = $1)`
+	query, args := andTenant(ctx, `SELECT 1 FROM orders WHERE id = $1 AND `+orderPartition, id)
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&exists)
+	err := r.read(ctx, []string{orderKey(id)}, func(db *sql.DB) error {
+		err := withTenant(ctx, db, func(q querier) error {
+			return q.QueryRowContext(ctx, `SELECT EXISTS(`+query+`)`, args...).Scan(&exists)
+		})
+		if err == nil && !exists && db != r.db {
+			// Заказ мог еще не дойти до реплики
+			return errNotOnReplica
+		}
+		return err
+	})
 	if err != nil {
 		return false, fmt.Errorf("failed to check order existence: %w", err)
 	}
@@ -280,18 +410,246 @@ func (r *OrderRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error
 	return exists, nil
 }
 
+// Statistics возвращает количество и суммы заказов по статусам в отчетной валюте
+func (r *OrderRepository) Statistics(ctx context.Context, filters repositories.OrderFilters) ([]*repositories.OrderStatusStats, error) {
+	where, args := r.buildFilterConditions(ctx, filters)
+	query := `
+		SELECT status, COUNT(*), COALESCE(SUM(total_amount_base), 0), COALESCE(ROUND(AVG(total_amount_base), 2), 0),
+			COUNT(*) FILTER (WHERE total_amount_base IS NULL)
+		FROM orders` + where + `
+		GROUP BY status
+		ORDER BY COUNT(*) DESC`
+
+	var stats []*repositories.OrderStatusStats
+	err := r.read(ctx, filterKeys(filters), func(db *sql.DB) error {
+		stats = nil
+		return withTenant(ctx, db, func(q querier) error {
+			rows, err := q.QueryContext(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("failed to get order statistics: %w", err)
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				var s repositories.OrderStatusStats
+				if err := rows.Scan(&s.Status, &s.Count, &s.TotalAmountBase, &s.AvgAmountBase, &s.Unconverted); err != nil {
+					return fmt.Errorf("failed to scan order statistics: %w", err)
+				}
+				stats = append(stats, &s)
+			}
+			return rows.Err()
+		})
+	})
+
+	return stats, err
+}
+
 // Helper methods
 
-// insertOrderItems вставляет элементы заказа
-func (r *OrderRepository) insertOrderItems(ctx context.Context, tx *sql.Tx, items []entities.OrderItem) error {
+// errNotOnReplica запись не найдена на реплике и должна быть перечитана из основной БД
+var errNotOnReplica = errors.New("not found on replica")
+
+// read выполняет чтение на БД, выбранной маршрутизатором. Ошибка на реплике или
+// отсутствующий на ней заказ (отставание) - повтор на основной БД.
+func (r *OrderRepository) read(ctx context.Context, keys []string, fn func(db *sql.DB) error) error {
+	if r.router == nil {
+		return fn(r.db)
+	}
+
+	db := r.router.Reader(ctx, keys...)
+	err := fn(db)
+	if err == nil || db == r.db || ctx.Err() != nil {
+		return err
+	}
+
+	var notFound entities.OrderNotFoundError
+	if !errors.As(err, &notFound) && !errors.Is(err, errNotOnReplica) {
+		r.router.ReportFailure(db)
+	}
+	return fn(r.db)
+}
+
+// markWritten запоминает запись для чтения своих изменений
+func (r *OrderRepository) markWritten(keys ...string) {
+	if r.router != nil {
+		r.router.MarkWritten(keys...)
+	}
+}
+
+// orderKey ключ заказа для чтения своих изменений
+func orderKey(id uuid.UUID) string {
+	return "order:" + id.String()
+}
+
+// customerKey ключ клиента для чтения своих изменений
+func customerKey(id uuid.UUID) string {
+	return "customer:" + id.String()
+}
+
+// filterKeys ключи чтения по фильтру: список заказов клиента читается из основной БД
+// после его записи
+func filterKeys(filters repositories.OrderFilters) []string {
+	if filters.CustomerID == nil {
+		return nil
+	}
+	return []string{customerKey(*filters.CustomerID)}
+}
+
+// orderColumns список колонок заказа в порядке, ожидаемом scanOrder
+const orderColumns = `id, customer_id, email, status, subtotal, discount_amount, tax_amount, shipping_method,
+	shipping_amount, total_amount, currency, base_currency, exchange_rate, total_amount_base,
+	metadata, created_at, updated_at, tenant_id`
+
+// orderPartition условие на партицию заказа с ID в $1: месяц берется из реестра order_keys,
+// и запрос по ID читает одну партицию вместо всех
+const orderPartition = `created_at = (SELECT created_at FROM order_keys WHERE id = $1)`
+
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanOrder считывает заказ из строки результата
+func scanOrder(row rowScanner) (*entities.Order, error) {
+	var order entities.Order
+	var metadata []byte
+	var baseCurrency sql.NullString
+	var exchangeRate, totalAmountBase sql.NullFloat64
+
+	err := row.Scan(
+		&order.ID, &order.CustomerID, &order.Email, &order.Status, &order.Subtotal, &order.Discount,
+		&order.TaxAmount, &order.ShippingMethod, &order.ShippingAmount, &order.TotalAmount,
+		&order.Currency, &baseCurrency, &exchangeRate, &totalAmountBase,
+		&metadata, &order.CreatedAt, &order.UpdatedAt, &order.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	order.BaseCurrency = baseCurrency.String
+	order.ExchangeRate = exchangeRate.Float64
+	order.TotalAmountBase = totalAmountBase.Float64
+
+	order.Metadata = make(map[string]interface{})
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &order.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal order metadata: %w", err)
+		}
+	}
+
+	return &order, nil
+}
+
+// baseAmountArgs возвращает колонки отчетной валюты; NULL, если итог не пересчитан
+func baseAmountArgs(order *entities.Order) (interface{}, interface{}, interface{}) {
+	if order.BaseCurrency == "" {
+		return nil, nil, nil
+	}
+	return order.BaseCurrency, order.ExchangeRate, order.TotalAmountBase
+}
+
+// marshalMetadata сериализует метаданные заказа в JSON
+func marshalMetadata(metadata map[string]interface{}) ([]byte, error) {
+	if metadata == nil {
+		return []byte("{}"), nil
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal order metadata: %w", err)
+	}
+
+	return data, nil
+}
+
+// insertOrderItems вставляет элементы заказа в партицию месяца заказа
+func (r *OrderRepository) insertOrderItems(ctx context.Context, tx *sql.Tx, orderCreatedAt time.Time, items []entities.OrderItem) error {
 	query := `
-		INSERT INTO order_items (id, order_id, product_id, name, price, quantity, total)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		INSERT INTO order_items (
+			id, order_id, product_id, name, price, quantity, total, discount_amount,
+			tax_category, tax_amount, tax_inclusive, order_created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 
 	for _, item := range items {
+		taxCategory := item.TaxCategory
+		if taxCategory == "" {
+			taxCategory = entities.DefaultTaxCategory
+		}
+
 		_, err := tx.ExecContext(ctx, query,
 			item.ID, item.OrderID, item.ProductID, item.Name,
-			item.Price, item.Quantity, item.Total)
+			item.Price, item.Quantity, item.Total, item.DiscountAmount,
+			taxCategory, item.TaxAmount, item.TaxInclusive, orderCreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertItemTaxes вставляет строки налогов позиций заказа
+func (r *OrderRepository) insertItemTaxes(ctx context.Context, tx *sql.Tx, items []entities.OrderItem) error {
+	query := `
+		INSERT INTO order_item_taxes (id, order_id, order_item_id, name, jurisdiction, rate, amount, inclusive)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	for _, item := range items {
+		for _, line := range item.TaxLines {
+			_, err := tx.ExecContext(ctx, query,
+				uuid.New(), item.OrderID, item.ID, line.Name,
+				line.Jurisdiction, line.Rate, line.Amount, line.Inclusive)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadItemTaxes загружает строки налогов в позиции заказа
+func (r *OrderRepository) loadItemTaxes(ctx context.Context, q querier, orderID uuid.UUID, items []entities.OrderItem) error {
+	query := `
+		SELECT order_item_id, name, jurisdiction, rate, amount, inclusive
+		FROM order_item_taxes
+		WHERE order_id = $1
+		ORDER BY jurisdiction, name`
+
+	rows, err := q.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	index := make(map[uuid.UUID]int, len(items))
+	for i, item := range items {
+		index[item.ID] = i
+	}
+
+	for rows.Next() {
+		var itemID uuid.UUID
+		var line entities.TaxLine
+		if err := rows.Scan(&itemID, &line.Name, &line.Jurisdiction, &line.Rate, &line.Amount, &line.Inclusive); err != nil {
+			return err
+		}
+		if i, exists := index[itemID]; exists {
+			items[i].TaxLines = append(items[i].TaxLines, line)
+		}
+	}
+
+	return rows.Err()
+}
+
+// insertDiscounts вставляет строки скидок заказа
+func (r *OrderRepository) insertDiscounts(ctx context.Context, tx *sql.Tx, discounts []entities.DiscountLine) error {
+	query := `
+		INSERT INTO order_discounts (id, order_id, order_item_id, promotion_id, code, type, amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	for _, line := range discounts {
+		_, err := tx.ExecContext(ctx, query,
+			line.ID, line.OrderID, line.OrderItemID, line.PromotionID,
+			line.Code, line.Type, line.Amount)
 		if err != nil {
 			return err
 		}
@@ -300,28 +658,158 @@ func (r *OrderRepository) insertOrderItems(ctx context.Context, tx *sql.Tx, item
 	return nil
 }
 
+// redeemPromotions атомарно учитывает использование купонов заказом.
+// Обновление строки промоакции блокирует ее до конца транзакции, поэтому
+// параллельные заказы не превысят ни общий лимит, ни лимит на клиента.
+func (r *OrderRepository) redeemPromotions(ctx context.Context, tx *sql.Tx, order *entities.Order) error {
+	codes := make(map[uuid.UUID]string)
+	for _, line := range order.Discounts {
+		codes[line.PromotionID] = line.Code
+	}
+
+	for _, promotionID := range order.AppliedPromotionIDs() {
+		code := codes[promotionID]
+		var perCustomerLimit int
+		err := tx.QueryRowContext(ctx, `
+			UPDATE promotions
+			SET usage_count = usage_count + 1
+			WHERE id = $1 AND tenant_id = $2 AND active AND (usage_limit = 0 OR usage_count < usage_limit)
+			RETURNING per_customer_limit`, promotionID, order.TenantID).Scan(&perCustomerLimit)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return entities.NewValidationError("coupon %s is no longer available", code)
+			}
+			return fmt.Errorf("failed to redeem promotion: %w", err)
+		}
+
+		if perCustomerLimit > 0 {
+			var used int
+			err := tx.QueryRowContext(ctx, `
+				SELECT COUNT(*) FROM promotion_redemptions
+				WHERE promotion_id = $1 AND customer_id = $2`,
+				promotionID, order.CustomerID).Scan(&used)
+			if err != nil {
+				return fmt.Errorf("failed to count promotion redemptions: %w", err)
+			}
+			if used >= perCustomerLimit {
+				return entities.NewValidationError("coupon %s usage limit per customer reached", code)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO promotion_redemptions (id, promotion_id, order_id, customer_id, created_at)
+			VALUES ($1, $2, $3, $4, $5)`,
+			uuid.New(), promotionID, order.ID, order.CustomerID, order.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert promotion redemption: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// getOrderDiscounts получает строки скидок заказа
+func (r *OrderRepository) getOrderDiscounts(ctx context.Context, q querier, orderID uuid.UUID) ([]entities.DiscountLine, error) {
+	query := `
+		SELECT id, order_id, order_item_id, promotion_id, code, type, amount
+		FROM order_discounts
+		WHERE order_id = $1`
+
+	rows, err := q.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var discounts []entities.DiscountLine
+	for rows.Next() {
+		var line entities.DiscountLine
+		var orderItemID uuid.NullUUID
+		err := rows.Scan(
+			&line.ID, &line.OrderID, &orderItemID, &line.PromotionID,
+			&line.Code, &line.Type, &line.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if orderItemID.Valid {
+			line.OrderItemID = &orderItemID.UUID
+		}
+		discounts = append(discounts, line)
+	}
+
+	return discounts, rows.Err()
+}
+
 // insertAddress вставляет адрес
 func (r *OrderRepository) insertAddress(ctx context.Context, tx *sql.Tx, address *entities.Address) error {
+	address, err := sealAddress(r.fields, address)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO order_addresses (id, order_id, type, street, city, state, country, zip_code)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 
-	_, err := tx.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		address.ID, address.OrderID, address.Type, address.Street,
 		address.City, address.State, address.Country, address.ZipCode)
 
 	return err
 }
 
-// getOrderItems получает элементы заказа
-func (r *OrderRepository) getOrderItems(ctx context.Context, orderID uuid.UUID) ([]entities.OrderItem, error) {
+// insertRiskAssessment вставляет оценку риска заказа
+func (r *OrderRepository) insertRiskAssessment(ctx context.Context, tx *sql.Tx, orderID uuid.UUID, assessment *entities.RiskAssessment) error {
+	triggered, err := json.Marshal(assessment.Triggered)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO order_risk_assessments (order_id, score, cutoff, triggered_rules, assessed_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err = tx.ExecContext(ctx, query,
+		orderID, assessment.Score, assessment.Cutoff, triggered, assessment.AssessedAt)
+
+	return err
+}
+
+// getRiskAssessment получает оценку риска заказа (nil, если оценки нет)
+func (r *OrderRepository) getRiskAssessment(ctx context.Context, q querier, orderID uuid.UUID) (*entities.RiskAssessment, error) {
+	query := `
+		SELECT score, cutoff, triggered_rules, assessed_at
+		FROM order_risk_assessments
+		WHERE order_id = $1`
+
+	var assessment entities.RiskAssessment
+	var triggered []byte
+	err := q.QueryRowContext(ctx, query, orderID).Scan(
+		&assessment.Score, &assessment.Cutoff, &triggered, &assessment.AssessedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(triggered, &assessment.Triggered); err != nil {
+		return nil, err
+	}
+
+	return &assessment, nil
+}
+
+// getOrderItems получает элементы заказа из партиции месяца заказа
+func (r *OrderRepository) getOrderItems(ctx context.Context, q querier, orderID uuid.UUID, orderCreatedAt time.Time) ([]entities.OrderItem, error) {
 	query := `
-		SELECT id, order_id, product_id, name, price, quantity, total
+		SELECT id, order_id, product_id, name, price, quantity, total, discount_amount,
+			tax_category, tax_amount, tax_inclusive
 		FROM order_items 
-		WHERE order_id = $1
+		WHERE order_id = $1 AND order_created_at = $2
 		ORDER BY name`
 
-	rows, err := r.db.QueryContext(ctx, query, orderID)
+	rows, err := q.QueryContext(ctx, query, orderID, orderCreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -332,7 +820,8 @@ func (r *OrderRepository) getOrderItems(ctx context.Context, orderID uuid.UUID)
 		var item entities.OrderItem
 		err := rows.Scan(
 			&item.ID, &item.OrderID, &item.ProductID, &item.Name,
-			&item.Price, &item.Quantity, &item.Total)
+			&item.Price, &item.Quantity, &item.Total, &item.DiscountAmount,
+			&item.TaxCategory, &item.TaxAmount, &item.TaxInclusive)
 		if err != nil {
 			return nil, err
 		}
@@ -343,13 +832,13 @@ func (r *OrderRepository) getOrderItems(ctx context.Context, orderID uuid.UUID)
 }
 
 // getOrderAddresses получает адреса заказа
-func (r *OrderRepository) getOrderAddresses(ctx context.Context, orderID uuid.UUID) ([]*entities.Address, error) {
+func (r *OrderRepository) getOrderAddresses(ctx context.Context, q querier, orderID uuid.UUID) ([]*entities.Address, error) {
 	query := `
 		SELECT id, order_id, type, street, city, state, country, zip_code
 		FROM order_addresses 
 		WHERE order_id = $1`
 
-	rows, err := r.db.QueryContext(ctx, query, orderID)
+	rows, err := q.QueryContext(ctx, query, orderID)
 	if err != nil {
 		return nil, err
 	}
@@ -364,6 +853,9 @@ func (r *OrderRepository) getOrderAddresses(ctx context.Context, orderID uuid.UU
 		if err != nil {
 			return nil, err
 		}
+		if err := openAddress(r.fields, &addr); err != nil {
+			return nil, err
+		}
 		addresses = append(addresses, &addr)
 	}
 
@@ -371,68 +863,12 @@ func (r *OrderRepository) getOrderAddresses(ctx context.Context, orderID uuid.UU
 }
 
 // buildListQuery строит запрос для получения списка заказов
-func (r *OrderRepository) buildListQuery(filters repositories.OrderFilters) (string, []interface{}) {
-	query := `
-		SELECT id, customer_id, email, status, total_amount, currency, created_at, updated_at
-		FROM orders`
-
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
-
-	// Фильтры
-	if filters.CustomerID != nil {
-		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", argIndex))
-		args = append(args, *filters.CustomerID)
-		argIndex++
-	}
-
-	if filters.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
-		args = append(args, *filters.Status)
-		argIndex++
-	}
-
-	if filters.Email != nil {
-		conditions = append(conditions, fmt.Sprintf("email ILIKE $%d", argIndex))
-		args = append(args, "%"+*filters.Email+"%")
-		argIndex++
-	}
-
-	if filters.MinAmount != nil {
-		conditions = append(conditions, fmt.Sprintf("total_amount >= $%d", argIndex))
-		args = append(args, *filters.MinAmount)
-		argIndex++
-	}
-
-	if filters.MaxAmount != nil {
-		conditions = append(conditions, fmt.Sprintf("total_amount <= $%d", argIndex))
-		args = append(args, *filters.MaxAmount)
-		argIndex++
-	}
-
-	if filters.Currency != nil {
-		conditions = append(conditions, fmt.Sprintf("currency = $%d", argIndex))
-		args = append(args, *filters.Currency)
-		argIndex++
-	}
-
-	if filters.DateFrom != nil {
-		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argIndex))
-		args = append(args, *filters.DateFrom)
-		argIndex++
-	}
-
-	if filters.DateTo != nil {
-		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", argIndex))
-		args = append(args, *filters.DateTo)
-		argIndex++
-	}
+func (r *OrderRepository) buildListQuery(ctx context.Context, filters repositories.OrderFilters) (string, []interface{}) {
+	query := `SELECT ` + orderColumns + ` FROM orders`
 
-	// WHERE clause
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	where, args := r.buildFilterConditions(ctx, filters)
+	query += where
+	argIndex := len(args) + 1
 
 	// ORDER BY
 	sortBy := filters.SortBy
@@ -463,14 +899,28 @@ func (r *OrderRepository) buildListQuery(filters repositories.OrderFilters) (str
 }
 
 // buildCountQuery строит запрос для подсчета заказов
-func (r *OrderRepository) buildCountQuery(filters repositories.OrderFilters) (string, []interface{}) {
-	query := "SELECT COUNT(*) FROM orders"
+func (r *OrderRepository) buildCountQuery(ctx context.Context, filters repositories.OrderFilters) (string, []interface{}) {
+	// Те же фильтры что и в buildListQuery, но без LIMIT/OFFSET/ORDER BY
+	where, args := r.buildFilterConditions(ctx, filters)
+	return "SELECT COUNT(*) FROM orders" + where, args
+}
 
+// buildFilterConditions строит WHERE по фильтрам заказов.
+// Без фильтра по валюте суммы сравниваются в отчетной валюте (total_amount_base),
+// с фильтром - в валюте заказа. При шифровании email ищется по точному совпадению
+// через blind index; заказы, еще не перешифрованные pii rewrap, - по открытому тексту.
+// Заказы всегда ограничены арендатором из контекста.
+func (r *OrderRepository) buildFilterConditions(ctx context.Context, filters repositories.OrderFilters) (string, []interface{}) {
 	var conditions []string
 	var args []interface{}
 	argIndex := 1
 
-	// Те же фильтры что и в buildListQuery, но без LIMIT/OFFSET/ORDER BY
+	if condition, tenantArgs := tenantCondition(ctx, argIndex); condition != "" {
+		conditions = append(conditions, condition)
+		args = append(args, tenantArgs...)
+		argIndex++
+	}
+
 	if filters.CustomerID != nil {
 		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", argIndex))
 		args = append(args, *filters.CustomerID)
@@ -484,19 +934,31 @@ func (r *OrderRepository) buildCountQuery(filters repositories.OrderFilters) (st
 	}
 
 	if filters.Email != nil {
-		conditions = append(conditions, fmt.Sprintf("email ILIKE $%d", argIndex))
-		args = append(args, "%"+*filters.Email+"%")
-		argIndex++
+		if index := emailIndex(r.fields, *filters.Email); index != nil {
+			conditions = append(conditions, fmt.Sprintf(
+				"(email_index = $%d OR (email_index IS NULL AND LOWER(email) = $%d))", argIndex, argIndex+1))
+			args = append(args, index, entities.NormalizeEmail(*filters.Email))
+			argIndex += 2
+		} else {
+			conditions = append(conditions, fmt.Sprintf("email ILIKE $%d", argIndex))
+			args = append(args, "%"+*filters.Email+"%")
+			argIndex++
+		}
+	}
+
+	amountColumn := "total_amount_base"
+	if filters.Currency != nil {
+		amountColumn = "total_amount"
 	}
 
 	if filters.MinAmount != nil {
-		conditions = append(conditions, fmt.Sprintf("total_amount >= $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", amountColumn, argIndex))
 		args = append(args, *filters.MinAmount)
 		argIndex++
 	}
 
 	if filters.MaxAmount != nil {
-		conditions = append(conditions, fmt.Sprintf("total_amount <= $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("%s <= $%d", amountColumn, argIndex))
 		args = append(args, *filters.MaxAmount)
 		argIndex++
 	}
@@ -518,9 +980,8 @@ func (r *OrderRepository) buildCountQuery(filters repositories.OrderFilters) (st
 		args = append(args, *filters.DateTo)
 	}
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	if len(conditions) == 0 {
+		return "", args
 	}
-
-	return query, args
+	return " WHERE " + strings.Join(conditions, " AND "), args
 }
@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// ProductRepository реализация репозитория каталога товаров для PostgreSQL
+type ProductRepository struct {
+	db *sql.DB
+}
+
+// NewProductRepository создает новый репозиторий каталога товаров
+func NewProductRepository(db *sql.DB) *ProductRepository {
+	return &ProductRepository{
+		db: db,
+	}
+}
+
+const productColumns = `id, sku, name, active, tax_category, weight, created_at, updated_at`
+
+// Create создает новый товар с ценами
+func (r *ProductRepository) Create(ctx context.Context, product *entities.Product) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO products (` + productColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err = tx.ExecContext(ctx, query,
+		product.ID, product.SKU, product.Name, product.Active, product.TaxCategory,
+		product.Weight, product.CreatedAt, product.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return entities.NewValidationError("product sku %s already exists", product.SKU)
+		}
+		return fmt.Errorf("failed to insert product: %w", err)
+	}
+
+	if err := replaceProductPrices(ctx, tx, product); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Update обновляет товар и заменяет его цены
+func (r *ProductRepository) Update(ctx context.Context, product *entities.Product) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE products
+		SET sku = $2, name = $3, active = $4, tax_category = $5, weight = $6, updated_at = $7
+		WHERE id = $1`
+
+	result, err := tx.ExecContext(ctx, query,
+		product.ID, product.SKU, product.Name, product.Active, product.TaxCategory,
+		product.Weight, product.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return entities.NewValidationError("product sku %s already exists", product.SKU)
+		}
+		return fmt.Errorf("failed to update product: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.NewProductNotFoundError(product.ID.String())
+	}
+
+	if err := replaceProductPrices(ctx, tx, product); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет товар
+func (r *ProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM products WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.NewProductNotFoundError(id.String())
+	}
+
+	return nil
+}
+
+// GetByID получает товар по ID
+func (r *ProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
+	products, err := r.GetByIDs(ctx, []uuid.UUID{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, entities.NewProductNotFoundError(id.String())
+	}
+	return products[0], nil
+}
+
+// GetByIDs получает товары по ID
+func (r *ProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = ANY($1::uuid[])`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanProducts(ctx, rows)
+}
+
+// List получает список товаров
+func (r *ProductRepository) List(ctx context.Context, activeOnly bool, limit, offset int) ([]*entities.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products`
+	if activeOnly {
+		query += " WHERE active"
+	}
+	query += " ORDER BY sku LIMIT $1 OFFSET $2"
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list products: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanProducts(ctx, rows)
+}
+
+// UpsertBySKU сохраняет товары, обновляя существующие по артикулу
+func (r *ProductRepository) UpsertBySKU(ctx context.Context, products []*entities.Product) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO products (` + productColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (sku) DO UPDATE SET
+			name = EXCLUDED.name,
+			active = EXCLUDED.active,
+			tax_category = EXCLUDED.tax_category,
+			weight = EXCLUDED.weight,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at`
+
+	for _, product := range products {
+		err := tx.QueryRowContext(ctx, query,
+			product.ID, product.SKU, product.Name, product.Active, product.TaxCategory,
+			product.Weight, product.CreatedAt, product.UpdatedAt).Scan(&product.ID, &product.CreatedAt)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+				return entities.NewValidationError("product %s: id already used by another sku", product.SKU)
+			}
+			return fmt.Errorf("failed to upsert product %s: %w", product.SKU, err)
+		}
+
+		if err := replaceProductPrices(ctx, tx, product); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// replaceProductPrices заменяет цены товара
+func replaceProductPrices(ctx context.Context, tx *sql.Tx, product *entities.Product) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_prices WHERE product_id = $1`, product.ID); err != nil {
+		return fmt.Errorf("failed to delete product prices: %w", err)
+	}
+
+	query := `INSERT INTO product_prices (product_id, currency, price) VALUES ($1, $2, $3)`
+	for currency, price := range product.Prices {
+		if _, err := tx.ExecContext(ctx, query, product.ID, currency, price); err != nil {
+			return fmt.Errorf("failed to insert product price: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanProducts считывает товары и подгружает их цены
+func (r *ProductRepository) scanProducts(ctx context.Context, rows *sql.Rows) ([]*entities.Product, error) {
+	products := make([]*entities.Product, 0)
+	byID := make(map[uuid.UUID]*entities.Product)
+	ids := make([]string, 0)
+
+	for rows.Next() {
+		p := &entities.Product{Prices: make(map[string]float64)}
+		if err := rows.Scan(&p.ID, &p.SKU, &p.Name, &p.Active, &p.TaxCategory,
+			&p.Weight, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, p)
+		byID[p.ID] = p
+		ids = append(ids, p.ID.String())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate products: %w", err)
+	}
+
+	if len(products) == 0 {
+		return products, nil
+	}
+
+	priceRows, err := r.db.QueryContext(ctx,
+		`SELECT product_id, currency, price FROM product_prices WHERE product_id = ANY($1::uuid[])`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product prices: %w", err)
+	}
+	defer priceRows.Close()
+
+	for priceRows.Next() {
+		var productID uuid.UUID
+		var currency string
+		var price float64
+		if err := priceRows.Scan(&productID, &currency, &price); err != nil {
+			return nil, fmt.Errorf("failed to scan product price: %w", err)
+		}
+		if p, exists := byID[productID]; exists {
+			p.Prices[currency] = price
+		}
+	}
+
+	return products, priceRows.Err()
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// promotionColumns список колонок промоакции в порядке, ожидаемом scanPromotion
+const promotionColumns = `id, code, name, type, value, buy_quantity, get_quantity, product_ids, min_subtotal,
+	starts_at, ends_at, usage_limit, per_customer_limit, usage_count, stackable, active, created_at, updated_at`
+
+// PromotionRepository реализация репозитория промоакций для PostgreSQL
+type PromotionRepository struct {
+	db *sql.DB
+}
+
+// NewPromotionRepository создает новый репозиторий промоакций
+func NewPromotionRepository(db *sql.DB) *PromotionRepository {
+	return &PromotionRepository{
+		db: db,
+	}
+}
+
+// Create создает новую промоакцию в арендаторе из контекста
+func (r *PromotionRepository) Create(ctx context.Context, promotion *entities.Promotion) error {
+	query := `
+		INSERT INTO promotions (` + promotionColumns + `, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`
+
+	productIDs := make([]string, 0, len(promotion.ProductIDs))
+	for _, id := range promotion.ProductIDs {
+		productIDs = append(productIDs, id.String())
+	}
+
+	err := withTenant(ctx, r.db, func(q querier) error {
+		_, err := q.ExecContext(ctx, query,
+			promotion.ID, promotion.Code, promotion.Name, promotion.Type, promotion.Value,
+			promotion.BuyQuantity, promotion.GetQuantity, pq.Array(productIDs), promotion.MinSubtotal,
+			promotion.StartsAt, promotion.EndsAt, promotion.UsageLimit, promotion.PerCustomerLimit,
+			promotion.UsageCount, promotion.Stackable, promotion.Active, promotion.CreatedAt, promotion.UpdatedAt,
+			entities.TenantIDOrDefault(entities.TenantIDFromContext(ctx)))
+		return err
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return entities.NewValidationError("promotion code %s already exists", promotion.Code)
+		}
+		return fmt.Errorf("failed to insert promotion: %w", err)
+	}
+
+	return nil
+}
+
+// GetByCodes получает промоакции арендатора по кодам купонов
+func (r *PromotionRepository) GetByCodes(ctx context.Context, codes []string) ([]*entities.Promotion, error) {
+	query, args := andTenant(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE code = ANY($1)`, pq.Array(codes))
+
+	var promotions []*entities.Promotion
+	err := withTenant(ctx, r.db, func(q querier) error {
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to get promotions: %w", err)
+		}
+		defer rows.Close()
+
+		promotions, err = scanPromotions(rows)
+		return err
+	})
+	return promotions, err
+}
+
+// List получает список промоакций арендатора
+func (r *PromotionRepository) List(ctx context.Context, activeOnly bool, limit, offset int) ([]*entities.Promotion, error) {
+	conditions := []string{"TRUE"}
+	if activeOnly {
+		conditions = append(conditions, "active")
+	}
+
+	query, args := andTenant(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE `+strings.Join(conditions, " AND "), limit, offset)
+	query += " ORDER BY created_at DESC LIMIT $1 OFFSET $2"
+
+	var promotions []*entities.Promotion
+	err := withTenant(ctx, r.db, func(q querier) error {
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to list promotions: %w", err)
+		}
+		defer rows.Close()
+
+		promotions, err = scanPromotions(rows)
+		return err
+	})
+	return promotions, err
+}
+
+// CountCustomerRedemptions считает использования купона клиентом
+func (r *PromotionRepository) CountCustomerRedemptions(ctx context.Context, promotionID, customerID uuid.UUID) (int, error) {
+	query, args := andTenant(ctx, `SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = $1 AND customer_id = $2`,
+		promotionID, customerID)
+
+	var count int
+	err := withTenant(ctx, r.db, func(q querier) error {
+		return q.QueryRowContext(ctx, query, args...).Scan(&count)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count promotion redemptions: %w", err)
+	}
+
+	return count, nil
+}
+
+// scanPromotions считывает промоакции из результата запроса
+func scanPromotions(rows *sql.Rows) ([]*entities.Promotion, error) {
+	promotions := make([]*entities.Promotion, 0)
+	for rows.Next() {
+		var p entities.Promotion
+		var productIDs []string
+		var startsAt, endsAt sql.NullTime
+
+		err := rows.Scan(
+			&p.ID, &p.Code, &p.Name, &p.Type, &p.Value, &p.BuyQuantity, &p.GetQuantity,
+			pq.Array(&productIDs), &p.MinSubtotal, &startsAt, &endsAt, &p.UsageLimit,
+			&p.PerCustomerLimit, &p.UsageCount, &p.Stackable, &p.Active, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan promotion: %w", err)
+		}
+
+		for _, raw := range productIDs {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse promotion product id: %w", err)
+			}
+			p.ProductIDs = append(p.ProductIDs, id)
+		}
+		if startsAt.Valid {
+			p.StartsAt = &startsAt.Time
+		}
+		if endsAt.Valid {
+			p.EndsAt = &endsAt.Time
+		}
+
+		promotions = append(promotions, &p)
+	}
+
+	return promotions, rows.Err()
+}
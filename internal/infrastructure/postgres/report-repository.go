@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// ReportRepository реализация репозитория отчетов для PostgreSQL
+type ReportRepository struct {
+	db *sql.DB
+}
+
+// NewReportRepository создает новый репозиторий отчетов
+func NewReportRepository(db *sql.DB) *ReportRepository {
+	return &ReportRepository{
+		db: db,
+	}
+}
+
+// reportFacts строки дневного среза; дни, ожидающие пересчета, берутся из заказов напрямую
+// (плюс вклад заархивированных заказов), поэтому отчет не отстает от данных
+const reportFacts = `
+	WITH dirty AS (SELECT day FROM order_daily_stats_dirty),
+	facts AS (
+		SELECT tenant_id, day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity
+		FROM order_daily_stats
+		WHERE day NOT IN (SELECT day FROM dirty)
+		UNION ALL
+		SELECT tenant_id, order_day, status, currency, shipping_country, 1, total_amount, COALESCE(total_amount_base, 0),
+			CASE WHEN total_amount_base IS NULL THEN 1 ELSE 0 END, total_quantity
+		FROM orders_with_stats
+		WHERE order_day IN (SELECT day FROM dirty)
+		UNION ALL
+		SELECT tenant_id, day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity
+		FROM order_daily_stats_archived
+		WHERE day IN (SELECT day FROM dirty)
+	)`
+
+// OrderReport возвращает агрегаты заказов по группам
+func (r *ReportRepository) OrderReport(ctx context.Context, query repositories.OrderReportQuery) ([]*repositories.OrderReportRow, error) {
+	var dimensions []string
+	switch query.Period {
+	case repositories.ReportPeriodDay:
+		dimensions = append(dimensions, "day")
+	case repositories.ReportPeriodWeek:
+		dimensions = append(dimensions, "date_trunc('week', day)::date")
+	case repositories.ReportPeriodMonth:
+		dimensions = append(dimensions, "date_trunc('month', day)::date")
+	case "":
+	default:
+		return nil, fmt.Errorf("unsupported report period: %s", query.Period)
+	}
+	if query.GroupByStatus {
+		dimensions = append(dimensions, "status")
+	}
+	if query.GroupByCurrency {
+		dimensions = append(dimensions, "currency")
+	}
+	if query.GroupByCountry {
+		dimensions = append(dimensions, "country")
+	}
+
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if tenantID := entities.TenantIDFromContext(ctx); tenantID != "" {
+		addCondition("tenant_id = $%d", tenantID)
+	}
+	if query.DateFrom != nil {
+		addCondition("day >= $%d", query.DateFrom.Format("2006-01-02"))
+	}
+	if query.DateTo != nil {
+		addCondition("day <= $%d", query.DateTo.Format("2006-01-02"))
+	}
+	if query.Status != nil {
+		addCondition("status = $%d", *query.Status)
+	}
+	if query.Currency != nil {
+		addCondition("currency = $%d", *query.Currency)
+	}
+	if query.Country != nil {
+		addCondition("country = $%d", *query.Country)
+	}
+
+	selectList := append(append([]string(nil), dimensions...),
+		"COALESCE(SUM(order_count), 0)", "COALESCE(SUM(revenue), 0)", "COALESCE(SUM(revenue_base), 0)",
+		"COALESCE(SUM(unconverted), 0)", "COALESCE(SUM(item_quantity), 0)")
+	sqlQuery := reportFacts + `
+		SELECT ` + strings.Join(selectList, ", ") + `
+		FROM facts`
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if len(dimensions) > 0 {
+		sqlQuery += " GROUP BY " + strings.Join(dimensions, ", ") + " ORDER BY " + strings.Join(dimensions, ", ")
+	}
+
+	// Дни, ожидающие пересчета, читаются из заказов под row-level security арендатора
+	var report []*repositories.OrderReportRow
+	err := withTenant(ctx, r.db, func(q querier) error {
+		rows, err := q.QueryContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return fmt.Errorf("failed to get order report: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var row repositories.OrderReportRow
+			var periodStart time.Time
+
+			dest := make([]interface{}, 0, len(selectList))
+			if query.Period != "" {
+				dest = append(dest, &periodStart)
+			}
+			if query.GroupByStatus {
+				dest = append(dest, &row.Status)
+			}
+			if query.GroupByCurrency {
+				dest = append(dest, &row.Currency)
+			}
+			if query.GroupByCountry {
+				dest = append(dest, &row.Country)
+			}
+			dest = append(dest, &row.OrderCount, &row.Revenue, &row.RevenueBase, &row.Unconverted, &row.ItemQuantity)
+
+			if err := rows.Scan(dest...); err != nil {
+				return fmt.Errorf("failed to scan order report: %w", err)
+			}
+			if query.Period != "" {
+				row.PeriodStart = &periodStart
+			}
+			report = append(report, &row)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// RefreshDailyStats пересчитывает устаревшие дни среза
+func (r *ReportRepository) RefreshDailyStats(ctx context.Context) (int, error) {
+	var days int
+	if err := r.db.QueryRowContext(ctx, `SELECT refresh_order_daily_stats()`).Scan(&days); err != nil {
+		return 0, fmt.Errorf("failed to refresh order daily stats: %w", err)
+	}
+	return days, nil
+}
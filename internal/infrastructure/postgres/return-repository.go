@@ -0,0 +1,374 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// ReturnRepository реализация репозитория возвратов для PostgreSQL
+type ReturnRepository struct {
+	db     *sql.DB
+	router *DBRouter
+}
+
+// NewReturnRepository создает новый репозиторий возвратов
+func NewReturnRepository(db *sql.DB) *ReturnRepository {
+	return &ReturnRepository{
+		db: db,
+	}
+}
+
+// WithReadYourWrites возвращает репозиторий, отмечающий в маршрутизаторе реплик заказы, статус которых изменил возврат средств,
+// чтобы их чтения шли в основную БД
+func (r *ReturnRepository) WithReadYourWrites(router *DBRouter) *ReturnRepository {
+	withRouter := *r
+	withRouter.router = router
+	return &withRouter
+}
+
+// CreateReturn сохраняет заявку на возврат с позициями
+func (r *ReturnRepository) CreateReturn(ctx context.Context, ret *entities.ReturnRequest) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setTenant(ctx, tx); err != nil {
+		return err
+	}
+
+	// Блокируем заказ, чтобы параллельные заявки не превысили количество
+	if _, err := lockOrder(ctx, tx, ret.OrderID); err != nil {
+		return err
+	}
+
+	returnable, err := r.returnableQuantities(ctx, tx, ret.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get returnable quantities: %w", err)
+	}
+
+	for _, item := range ret.Items {
+		if item.Quantity > returnable[item.OrderItemID] {
+			return entities.NewValidationError("order item %s: quantity %d exceeds returnable %d",
+				item.OrderItemID, item.Quantity, returnable[item.OrderItemID])
+		}
+		returnable[item.OrderItemID] -= item.Quantity
+	}
+
+	query := `
+		INSERT INTO return_requests (id, order_id, status, reason, resolution_note, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = tx.ExecContext(ctx, query,
+		ret.ID, ret.OrderID, ret.Status, ret.Reason, ret.ResolutionNote, ret.CreatedAt, ret.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert return: %w", err)
+	}
+
+	itemQuery := `
+		INSERT INTO return_items (id, return_id, order_item_id, quantity, reason)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	for _, item := range ret.Items {
+		if _, err := tx.ExecContext(ctx, itemQuery, item.ID, item.ReturnID, item.OrderItemID, item.Quantity, item.Reason); err != nil {
+			return fmt.Errorf("failed to insert return item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetReturnByID получает заявку на возврат по ID
+func (r *ReturnRepository) GetReturnByID(ctx context.Context, id uuid.UUID) (*entities.ReturnRequest, error) {
+	returns, err := r.queryReturns(ctx, "id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(returns) == 0 {
+		return nil, entities.NewReturnNotFoundError(id.String())
+	}
+	return returns[0], nil
+}
+
+// GetReturnsByOrderID получает заявки на возврат по заказу
+func (r *ReturnRepository) GetReturnsByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.ReturnRequest, error) {
+	return r.queryReturns(ctx, "order_id = $1", orderID)
+}
+
+// ResolveReturn сохраняет решение по заявке на возврат
+func (r *ReturnRepository) ResolveReturn(ctx context.Context, ret *entities.ReturnRequest) error {
+	query, args := andTenant(ctx, `
+		UPDATE return_requests
+		SET status = $2, resolution_note = $3, updated_at = $4
+		WHERE id = $1 AND status = 'requested'`, ret.ID, ret.Status, ret.ResolutionNote, ret.UpdatedAt)
+
+	var rowsAffected int64
+	err := withTenant(ctx, r.db, func(q querier) error {
+		result, err := q.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to update return: %w", err)
+		}
+
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return entities.NewValidationError("return %s is not awaiting a decision", ret.ID)
+	}
+
+	return nil
+}
+
+// CreateRefund сохраняет возврат средств и обновляет статусы заказа и заявки
+func (r *ReturnRepository) CreateRefund(ctx context.Context, refund *entities.Refund, fromStatus, orderStatus entities.OrderStatus) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setTenant(ctx, tx); err != nil {
+		return err
+	}
+
+	currentStatus, err := lockOrder(ctx, tx, refund.OrderID)
+	if err != nil {
+		return err
+	}
+	// Переход проверялся от прочитанного статуса; параллельное изменение заказа не перезаписываем
+	if currentStatus != string(fromStatus) {
+		return entities.NewInvalidStatusTransitionError(entities.OrderStatus(currentStatus), orderStatus)
+	}
+
+	// Повторно проверяем остаток под блокировкой заказа
+	var totalAmount, refunded float64
+	err = tx.QueryRowContext(ctx, `
+		SELECT o.total_amount, COALESCE((SELECT SUM(amount) FROM refunds WHERE order_id = o.id), 0)
+		FROM orders o
+		WHERE o.id = $1 AND o.`+orderPartition, refund.OrderID).Scan(&totalAmount, &refunded)
+	if err != nil {
+		return fmt.Errorf("failed to get refunded amount: %w", err)
+	}
+
+	left := math.Round((totalAmount-refunded)*100) / 100
+	if math.Round(refund.Amount*100) > math.Round(left*100) {
+		return entities.NewValidationError("refund amount %.2f exceeds refundable %.2f", refund.Amount, left)
+	}
+
+	if refund.ReturnID != nil {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE return_requests SET status = 'refunded', updated_at = NOW()
+			WHERE id = $1 AND order_id = $2 AND status = 'approved'`,
+			*refund.ReturnID, refund.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to update return: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return entities.NewValidationError("return %s is not approved", *refund.ReturnID)
+		}
+	}
+
+	query := `
+		INSERT INTO refunds (id, order_id, return_id, amount, currency, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = tx.ExecContext(ctx, query,
+		refund.ID, refund.OrderID, refund.ReturnID, refund.Amount, refund.Currency, refund.Reason, refund.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert refund: %w", err)
+	}
+
+	var customerID uuid.UUID
+	if orderStatus != fromStatus {
+		err = tx.QueryRowContext(ctx, `UPDATE orders SET status = $2, updated_at = NOW() WHERE id = $1 AND `+orderPartition+` RETURNING customer_id`,
+			refund.OrderID, orderStatus).Scan(&customerID)
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if orderStatus != fromStatus {
+		r.router.MarkWritten(orderKey(refund.OrderID), customerKey(customerID))
+	}
+	return nil
+}
+
+// GetRefundsByOrderID получает возвраты средств по заказу
+func (r *ReturnRepository) GetRefundsByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Refund, error) {
+	query, args := andTenant(ctx, `
+		SELECT id, order_id, return_id, amount, currency, COALESCE(reason, ''), created_at
+		FROM refunds
+		WHERE order_id = $1`, orderID)
+
+	refunds := make([]*entities.Refund, 0)
+	err := withTenant(ctx, r.db, func(q querier) error {
+		rows, err := q.QueryContext(ctx, query+` ORDER BY created_at`, args...)
+		if err != nil {
+			return fmt.Errorf("failed to get refunds: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var refund entities.Refund
+			var returnID uuid.NullUUID
+			err := rows.Scan(&refund.ID, &refund.OrderID, &returnID, &refund.Amount,
+				&refund.Currency, &refund.Reason, &refund.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to scan refund: %w", err)
+			}
+			if returnID.Valid {
+				refund.ReturnID = &returnID.UUID
+			}
+			refunds = append(refunds, &refund)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
+
+// queryReturns получает заявки на возврат по условию вместе с позициями
+func (r *ReturnRepository) queryReturns(ctx context.Context, where string, arg interface{}) ([]*entities.ReturnRequest, error) {
+	var returns []*entities.ReturnRequest
+	err := withTenant(ctx, r.db, func(q querier) error {
+		var err error
+		returns, err = r.queryReturnsWith(ctx, q, where, arg)
+		return err
+	})
+	return returns, err
+}
+
+// queryReturnsWith получает заявки на возврат через db или транзакцию арендатора
+func (r *ReturnRepository) queryReturnsWith(ctx context.Context, q querier, where string, arg interface{}) ([]*entities.ReturnRequest, error) {
+	query, args := andTenant(ctx, `
+		SELECT id, order_id, status, COALESCE(reason, ''), COALESCE(resolution_note, ''), created_at, updated_at
+		FROM return_requests
+		WHERE `+where, arg)
+
+	rows, err := q.QueryContext(ctx, query+` ORDER BY created_at`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get returns: %w", err)
+	}
+	defer rows.Close()
+
+	returns := make([]*entities.ReturnRequest, 0)
+	byID := make(map[uuid.UUID]*entities.ReturnRequest)
+	ids := make([]string, 0)
+	for rows.Next() {
+		var ret entities.ReturnRequest
+		err := rows.Scan(&ret.ID, &ret.OrderID, &ret.Status, &ret.Reason,
+			&ret.ResolutionNote, &ret.CreatedAt, &ret.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan return: %w", err)
+		}
+		ret.Items = make([]entities.ReturnItem, 0)
+		returns = append(returns, &ret)
+		byID[ret.ID] = &ret
+		ids = append(ids, ret.ID.String())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate returns: %w", err)
+	}
+
+	if len(returns) == 0 {
+		return returns, nil
+	}
+
+	itemQuery, itemArgs := andTenant(ctx, `
+		SELECT id, return_id, order_item_id, quantity, reason
+		FROM return_items
+		WHERE return_id = ANY($1::uuid[])`, pq.Array(ids))
+
+	itemRows, err := q.QueryContext(ctx, itemQuery, itemArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get return items: %w", err)
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item entities.ReturnItem
+		if err := itemRows.Scan(&item.ID, &item.ReturnID, &item.OrderItemID, &item.Quantity, &item.Reason); err != nil {
+			return nil, fmt.Errorf("failed to scan return item: %w", err)
+		}
+		if ret, exists := byID[item.ReturnID]; exists {
+			ret.Items = append(ret.Items, item)
+		}
+	}
+
+	return returns, itemRows.Err()
+}
+
+// returnableQuantities возвращает количество, доступное к возврату по позициям заказа
+func (r *ReturnRepository) returnableQuantities(ctx context.Context, tx *sql.Tx, orderID uuid.UUID) (map[uuid.UUID]int, error) {
+	query := `
+		SELECT oi.id, oi.quantity - COALESCE((
+			SELECT SUM(ri.quantity)
+			FROM return_items ri
+			JOIN return_requests rr ON rr.id = ri.return_id
+			WHERE ri.order_item_id = oi.id AND rr.status <> 'rejected'
+		), 0)
+		FROM order_items oi
+		WHERE oi.order_id = $1 AND oi.order_created_at = (SELECT created_at FROM order_keys WHERE id = $1)`
+
+	rows, err := tx.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	returnable := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var itemID uuid.UUID
+		var left int
+		if err := rows.Scan(&itemID, &left); err != nil {
+			return nil, err
+		}
+		returnable[itemID] = left
+	}
+
+	return returnable, rows.Err()
+}
+
+// lockOrder блокирует строку заказа до конца транзакции и возвращает его статус
+func lockOrder(ctx context.Context, tx *sql.Tx, orderID uuid.UUID) (string, error) {
+	var status string
+	err := tx.QueryRowContext(ctx, `SELECT status FROM orders WHERE id = $1 AND `+orderPartition+` FOR UPDATE`, orderID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", entities.NewOrderNotFoundError(orderID.String())
+		}
+		return "", fmt.Errorf("failed to lock order: %w", err)
+	}
+	return status, nil
+}
@@ -0,0 +1,272 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// ShipmentRepository реализация репозитория отправлений для PostgreSQL
+type ShipmentRepository struct {
+	db     *sql.DB
+	router *DBRouter
+}
+
+// NewShipmentRepository создает новый репозиторий отправлений
+func NewShipmentRepository(db *sql.DB) *ShipmentRepository {
+	return &ShipmentRepository{
+		db: db,
+	}
+}
+
+// WithReadYourWrites возвращает репозиторий, отмечающий в маршрутизаторе реплик заказы, статус которых изменило отправление,
+// чтобы их чтения шли в основную БД
+func (r *ShipmentRepository) WithReadYourWrites(router *DBRouter) *ShipmentRepository {
+	withRouter := *r
+	withRouter.router = router
+	return &withRouter
+}
+
+// Create сохраняет отправление и обновляет статус заказа
+func (r *ShipmentRepository) Create(ctx context.Context, shipment *entities.Shipment, fromStatus, orderStatus entities.OrderStatus) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setTenant(ctx, tx); err != nil {
+		return err
+	}
+
+	// Блокируем заказ, чтобы параллельные отправления не превысили количество
+	currentStatus, err := lockOrder(ctx, tx, shipment.OrderID)
+	if err != nil {
+		return err
+	}
+	// Переход проверялся от прочитанного статуса; параллельное изменение (например, отмена) не перезаписываем
+	if currentStatus != string(fromStatus) {
+		return entities.NewInvalidStatusTransitionError(entities.OrderStatus(currentStatus), orderStatus)
+	}
+
+	remaining, err := r.remainingQuantities(ctx, tx, shipment.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get remaining quantities: %w", err)
+	}
+
+	for _, item := range shipment.Items {
+		if item.Quantity > remaining[item.OrderItemID] {
+			return entities.NewValidationError("order item %s: quantity %d exceeds remaining %d",
+				item.OrderItemID, item.Quantity, remaining[item.OrderItemID])
+		}
+		remaining[item.OrderItemID] -= item.Quantity
+	}
+
+	query := `
+		INSERT INTO shipments (id, order_id, carrier, tracking_number, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = tx.ExecContext(ctx, query,
+		shipment.ID, shipment.OrderID, shipment.Carrier, shipment.TrackingNumber,
+		shipment.Status, shipment.CreatedAt, shipment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert shipment: %w", err)
+	}
+
+	itemQuery := `
+		INSERT INTO shipment_items (id, shipment_id, order_item_id, quantity)
+		VALUES ($1, $2, $3, $4)`
+
+	for _, item := range shipment.Items {
+		if _, err := tx.ExecContext(ctx, itemQuery, item.ID, item.ShipmentID, item.OrderItemID, item.Quantity); err != nil {
+			return fmt.Errorf("failed to insert shipment item: %w", err)
+		}
+	}
+
+	// Отправленные товары списываются сразу: при отмене заказа на склад вернется только остаток резерва
+	if err := commitShipmentReservations(ctx, tx, shipment); err != nil {
+		return err
+	}
+
+	var customerID uuid.UUID
+	if orderStatus != fromStatus {
+		err = tx.QueryRowContext(ctx, `UPDATE orders SET status = $2, updated_at = NOW() WHERE id = $1 AND `+orderPartition+` RETURNING customer_id`,
+			shipment.OrderID, orderStatus).Scan(&customerID)
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if orderStatus != fromStatus {
+		r.router.MarkWritten(orderKey(shipment.OrderID), customerKey(customerID))
+	}
+	return nil
+}
+
+// UpdateStatus сохраняет статус отправления и при необходимости обновляет статус заказа
+func (r *ShipmentRepository) UpdateStatus(ctx context.Context, shipment *entities.Shipment, fromStatus entities.ShipmentStatus, orderFrom, orderTo entities.OrderStatus) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setTenant(ctx, tx); err != nil {
+		return err
+	}
+
+	currentStatus, err := lockOrder(ctx, tx, shipment.OrderID)
+	if err != nil {
+		return err
+	}
+	if currentStatus != string(orderFrom) {
+		return entities.NewInvalidStatusTransitionError(entities.OrderStatus(currentStatus), orderTo)
+	}
+
+	// Статус отправления проверяется под блокировкой заказа: параллельное изменение не перезаписываем
+	query, args := andTenant(ctx, `SELECT status FROM shipments WHERE id = $1 AND order_id = $2`, shipment.ID, shipment.OrderID)
+	var shipmentStatus entities.ShipmentStatus
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&shipmentStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return entities.NewShipmentNotFoundError(shipment.ID.String())
+		}
+		return fmt.Errorf("failed to get shipment: %w", err)
+	}
+	if shipmentStatus != fromStatus {
+		return entities.NewInvalidShipmentTransitionError(shipmentStatus, shipment.Status)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE shipments SET status = $2, updated_at = $3 WHERE id = $1`,
+		shipment.ID, shipment.Status, shipment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update shipment status: %w", err)
+	}
+
+	var customerID uuid.UUID
+	if orderTo != orderFrom {
+		err = tx.QueryRowContext(ctx, `UPDATE orders SET status = $2, updated_at = NOW() WHERE id = $1 AND `+orderPartition+` RETURNING customer_id`,
+			shipment.OrderID, orderTo).Scan(&customerID)
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if orderTo != orderFrom {
+		r.router.MarkWritten(orderKey(shipment.OrderID), customerKey(customerID))
+	}
+	return nil
+}
+
+// GetByOrderID получает отправления заказа вместе с позициями
+func (r *ShipmentRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Shipment, error) {
+	var shipments []*entities.Shipment
+	err := withTenant(ctx, r.db, func(q querier) error {
+		var err error
+		shipments, err = r.getByOrderID(ctx, q, orderID)
+		return err
+	})
+	return shipments, err
+}
+
+// getByOrderID получает отправления заказа через db или транзакцию арендатора
+func (r *ShipmentRepository) getByOrderID(ctx context.Context, q querier, orderID uuid.UUID) ([]*entities.Shipment, error) {
+	query, args := andTenant(ctx, `
+		SELECT id, order_id, carrier, tracking_number, status, created_at, updated_at
+		FROM shipments
+		WHERE order_id = $1`, orderID)
+
+	rows, err := q.QueryContext(ctx, query+` ORDER BY created_at`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+	defer rows.Close()
+
+	shipments := make([]*entities.Shipment, 0)
+	byID := make(map[uuid.UUID]*entities.Shipment)
+	for rows.Next() {
+		var shipment entities.Shipment
+		err := rows.Scan(
+			&shipment.ID, &shipment.OrderID, &shipment.Carrier, &shipment.TrackingNumber,
+			&shipment.Status, &shipment.CreatedAt, &shipment.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan shipment: %w", err)
+		}
+		shipment.Items = make([]entities.ShipmentItem, 0)
+		shipments = append(shipments, &shipment)
+		byID[shipment.ID] = &shipment
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate shipments: %w", err)
+	}
+
+	if len(shipments) == 0 {
+		return shipments, nil
+	}
+
+	itemQuery, itemArgs := andTenantOf(ctx, "s", `
+		SELECT si.id, si.shipment_id, si.order_item_id, si.quantity
+		FROM shipment_items si
+		JOIN shipments s ON s.id = si.shipment_id
+		WHERE s.order_id = $1`, orderID)
+
+	itemRows, err := q.QueryContext(ctx, itemQuery, itemArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipment items: %w", err)
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item entities.ShipmentItem
+		if err := itemRows.Scan(&item.ID, &item.ShipmentID, &item.OrderItemID, &item.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan shipment item: %w", err)
+		}
+		if shipment, exists := byID[item.ShipmentID]; exists {
+			shipment.Items = append(shipment.Items, item)
+		}
+	}
+
+	return shipments, itemRows.Err()
+}
+
+// remainingQuantities возвращает неотправленное количество по позициям заказа
+func (r *ShipmentRepository) remainingQuantities(ctx context.Context, tx *sql.Tx, orderID uuid.UUID) (map[uuid.UUID]int, error) {
+	query := `
+		SELECT oi.id, oi.quantity - COALESCE((
+			SELECT SUM(si.quantity)
+			FROM shipment_items si
+			JOIN shipments s ON s.id = si.shipment_id
+			WHERE si.order_item_id = oi.id AND s.status <> 'returned'
+		), 0)
+		FROM order_items oi
+		WHERE oi.order_id = $1 AND oi.order_created_at = (SELECT created_at FROM order_keys WHERE id = $1)`
+
+	rows, err := tx.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	remaining := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var itemID uuid.UUID
+		var left int
+		if err := rows.Scan(&itemID, &left); err != nil {
+			return nil, err
+		}
+		remaining[itemID] = left
+	}
+
+	return remaining, rows.Err()
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// querier общий интерфейс для *sql.DB и *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// setTenant включает row-level security арендатора из контекста до конца транзакции.
+// Без арендатора строки арендаторских таблиц видны только роли с BYPASSRLS (фоновые задачи).
+func setTenant(ctx context.Context, tx *sql.Tx) error {
+	tenantID := entities.TenantIDFromContext(ctx)
+	if tenantID == "" {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('app.tenant_id', $1, true)`, tenantID); err != nil {
+		return fmt.Errorf("failed to set tenant: %w", err)
+	}
+	return nil
+}
+
+// tenantCondition возвращает условие по арендатору из контекста с параметром $argIndex
+// или пустую строку для запроса без арендатора
+func tenantCondition(ctx context.Context, argIndex int) (string, []interface{}) {
+	tenantID := entities.TenantIDFromContext(ctx)
+	if tenantID == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("tenant_id = $%d", argIndex), []interface{}{tenantID}
+}
+
+// andTenant дополняет WHERE запроса условием по арендатору
+func andTenant(ctx context.Context, query string, args ...interface{}) (string, []interface{}) {
+	condition, tenantArgs := tenantCondition(ctx, len(args)+1)
+	if condition == "" {
+		return query, args
+	}
+	return query + " AND " + condition, append(args, tenantArgs...)
+}
+
+// andTenantOf дополняет WHERE запроса условием по арендатору таблицы alias (для запросов с соединениями)
+func andTenantOf(ctx context.Context, alias, query string, args ...interface{}) (string, []interface{}) {
+	condition, tenantArgs := tenantCondition(ctx, len(args)+1)
+	if condition == "" {
+		return query, args
+	}
+	return query + " AND " + alias + "." + condition, append(args, tenantArgs...)
+}
+
+// withTenant выполняет fn в транзакции с арендатором из контекста; без арендатора - напрямую в db
+// (такие запросы видят строки арендаторов только под ролью с BYPASSRLS)
+func withTenant(ctx context.Context, db *sql.DB, fn func(q querier) error) error {
+	if entities.TenantIDFromContext(ctx) == "" {
+		return fn(db)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setTenant(ctx, tx); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// assignTenant привязывает новый заказ к арендатору из контекста.
+// Заказ другого арендатора не может быть создан в его рамках.
+func assignTenant(ctx context.Context, order *entities.Order) error {
+	tenantID := entities.TenantIDFromContext(ctx)
+	switch {
+	case order.TenantID == "" && tenantID != "":
+		order.TenantID = tenantID
+	case order.TenantID == "":
+		order.TenantID = entities.DefaultTenantID
+	case tenantID != "" && order.TenantID != tenantID:
+		return fmt.Errorf("order belongs to tenant %s, request is scoped to tenant %s", order.TenantID, tenantID)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// WebhookRepository реализация репозитория webhooks для PostgreSQL
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository создает новый репозиторий webhooks
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+const webhookColumns = `id, url, event_types, secret, active, failure_count, disabled_at, created_at, updated_at, include_pii, tenant_id`
+
+// Create создает подписку
+func (r *WebhookRepository) Create(ctx context.Context, s *entities.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (` + webhookColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	if s.TenantID == "" {
+		s.TenantID = entities.DefaultTenantID
+	}
+	_, err := r.db.ExecContext(ctx, query,
+		s.ID, s.URL, pq.Array(s.EventTypes), s.Secret, s.Active, s.FailureCount, s.DisabledAt, s.CreatedAt, s.UpdatedAt,
+		s.IncludePII, s.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает подписку по ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscription, error) {
+	query, args := andTenant(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE id = $1`, id)
+	subscriptions, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(subscriptions) == 0 {
+		return nil, entities.NewWebhookNotFoundError(id.String())
+	}
+	return subscriptions[0], nil
+}
+
+// List получает список подписок арендатора
+func (r *WebhookRepository) List(ctx context.Context, limit, offset int) ([]*entities.WebhookSubscription, error) {
+	query, args := andTenant(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE TRUE`)
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	return r.query(ctx, query, append(args, limit, offset)...)
+}
+
+// ListActiveForEvent получает активные подписки арендатора на тип события
+func (r *WebhookRepository) ListActiveForEvent(ctx context.Context, eventType string) ([]*entities.WebhookSubscription, error) {
+	query, args := andTenant(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions
+		WHERE active AND (cardinality(event_types) = 0 OR $1 = ANY(event_types))`, eventType)
+	return r.query(ctx, query, args...)
+}
+
+// Delete удаляет подписку
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query, args := andTenant(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.NewWebhookNotFoundError(id.String())
+	}
+
+	return nil
+}
+
+// UpdateHealth сохраняет счетчик неудач и признак активности подписки
+func (r *WebhookRepository) UpdateHealth(ctx context.Context, s *entities.WebhookSubscription) error {
+	query := `
+		UPDATE webhook_subscriptions
+		SET active = $2, failure_count = $3, disabled_at = $4, updated_at = $5
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, s.ID, s.Active, s.FailureCount, s.DisabledAt, s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.NewWebhookNotFoundError(s.ID.String())
+	}
+
+	return nil
+}
+
+// RecordDelivery сохраняет попытку доставки
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, d *entities.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, attempt, status_code, success, error, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	statusCode := sql.NullInt64{Int64: int64(d.StatusCode), Valid: d.StatusCode != 0}
+	errorText := sql.NullString{String: d.Error, Valid: d.Error != ""}
+
+	_, err := r.db.ExecContext(ctx, query,
+		d.ID, d.SubscriptionID, d.EventID, d.EventType, d.Attempt, statusCode, d.Success, errorText, d.DurationMs, d.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries получает журнал доставки подписки
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit, offset int) ([]*entities.WebhookDelivery, error) {
+	query := `
+		SELECT id, subscription_id, event_id, event_type, attempt, status_code, success, error, duration_ms, created_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, subscriptionID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*entities.WebhookDelivery, 0)
+	for rows.Next() {
+		var d entities.WebhookDelivery
+		var statusCode sql.NullInt64
+		var errorText sql.NullString
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Attempt,
+			&statusCode, &d.Success, &errorText, &d.DurationMs, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		d.StatusCode = int(statusCode.Int64)
+		d.Error = errorText.String
+		deliveries = append(deliveries, &d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// query выполняет запрос подписок
+func (r *WebhookRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entities.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*entities.WebhookSubscription, 0)
+	for rows.Next() {
+		var s entities.WebhookSubscription
+		var disabledAt sql.NullTime
+		if err := rows.Scan(&s.ID, &s.URL, pq.Array(&s.EventTypes), &s.Secret, &s.Active,
+			&s.FailureCount, &disabledAt, &s.CreatedAt, &s.UpdatedAt, &s.IncludePII, &s.TenantID); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		if disabledAt.Valid {
+			s.DisabledAt = &disabledAt.Time
+		}
+		subscriptions = append(subscriptions, &s)
+	}
+
+	return subscriptions, rows.Err()
+}
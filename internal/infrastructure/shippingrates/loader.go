@@ -0,0 +1,52 @@
+package shippingrates
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// File описывает формат файла с тарифами доставки
+type File struct {
+	Currency string                              `json:"currency" yaml:"currency"`
+	Methods  []entities.ShippingMethodDefinition `json:"methods" yaml:"methods"`
+}
+
+// LoadTable загружает и проверяет тарифы доставки из YAML/JSON файла.
+// Если путь не указан, доставка не начисляется и возвращается nil.
+func LoadTable(path string) (*entities.ShippingTable, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shipping rates file: %w", err)
+	}
+
+	var file File
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported shipping rates file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse shipping rates file: %w", err)
+	}
+
+	table, err := entities.NewShippingTable(file.Currency, file.Methods)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shipping rates: %w", err)
+	}
+
+	return table, nil
+}
@@ -0,0 +1,30 @@
+package shippingrates
+
+import (
+	"testing"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+func TestLoadTable_ExampleConfig(t *testing.T) {
+	table, err := LoadTable("../../../configs/shipping-rates.yaml")
+	if err != nil {
+		t.Fatalf("Expected example config to be valid, got %v", err)
+	}
+
+	quotes := table.Quotes(&entities.Address{Country: "US"}, entities.ShippingParcel{Weight: 1, ItemCount: 1})
+	if len(quotes) == 0 {
+		t.Error("Expected example config to quote shipping to US")
+	}
+}
+
+func TestLoadTable_EmptyPath(t *testing.T) {
+	table, err := LoadTable("")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if table != nil {
+		t.Error("Expected no shipping table for empty path")
+	}
+}
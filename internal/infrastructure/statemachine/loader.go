@@ -0,0 +1,66 @@
+package statemachine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// File описывает формат файла с машинами состояний заказа
+type File struct {
+	Default  entities.StateMachineDefinition            `json:"default" yaml:"default"`
+	Channels map[string]entities.StateMachineDefinition `json:"channels,omitempty" yaml:"channels"`
+}
+
+// LoadRegistry загружает и проверяет машины состояний из YAML/JSON файла.
+// Если путь не указан, возвращается стандартный жизненный цикл заказа.
+func LoadRegistry(path string) (*entities.StateMachineRegistry, error) {
+	if path == "" {
+		return entities.NewStateMachineRegistry(entities.DefaultStateMachine(), nil), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state machine file: %w", err)
+	}
+
+	var file File
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported state machine file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse state machine file: %w", err)
+	}
+
+	return NewRegistry(file)
+}
+
+// NewRegistry строит реестр машин состояний из описания файла
+func NewRegistry(file File) (*entities.StateMachineRegistry, error) {
+	defaultMachine, err := entities.NewStateMachine(file.Default)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default state machine: %w", err)
+	}
+
+	channels := make(map[string]*entities.StateMachine, len(file.Channels))
+	for channel, def := range file.Channels {
+		sm, err := entities.NewStateMachine(def)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state machine for channel %s: %w", channel, err)
+		}
+		channels[channel] = sm
+	}
+
+	return entities.NewStateMachineRegistry(defaultMachine, channels), nil
+}
@@ -0,0 +1,34 @@
+package statemachine
+
+import (
+	"testing"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+func TestLoadRegistry_ExampleConfig(t *testing.T) {
+	registry, err := LoadRegistry("../../../configs/order-state-machine.yaml")
+	if err != nil {
+		t.Fatalf("Expected example config to be valid, got %v", err)
+	}
+
+	digital := registry.ForChannel("digital")
+	if _, allowed := digital.Transition(entities.OrderStatusConfirmed, entities.OrderStatusDelivered); !allowed {
+		t.Error("Expected digital channel to skip shipping")
+	}
+
+	if _, allowed := registry.Default().Transition(entities.OrderStatusConfirmed, entities.OrderStatusDelivered); allowed {
+		t.Error("Expected default flow to require shipping")
+	}
+}
+
+func TestLoadRegistry_EmptyPath(t *testing.T) {
+	registry, err := LoadRegistry("")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if registry.Default() != entities.DefaultStateMachine() {
+		t.Error("Expected built-in state machine for empty path")
+	}
+}
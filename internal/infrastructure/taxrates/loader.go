@@ -0,0 +1,51 @@
+package taxrates
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// File описывает формат файла с налоговыми ставками
+type File struct {
+	Jurisdictions []entities.TaxJurisdiction `json:"jurisdictions" yaml:"jurisdictions"`
+}
+
+// LoadTable загружает и проверяет таблицу ставок из YAML/JSON файла.
+// Если путь не указан, налоги не рассчитываются и возвращается nil.
+func LoadTable(path string) (*entities.TaxTable, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tax rates file: %w", err)
+	}
+
+	var file File
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported tax rates file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tax rates file: %w", err)
+	}
+
+	table, err := entities.NewTaxTable(file.Jurisdictions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tax rates: %w", err)
+	}
+
+	return table, nil
+}
@@ -0,0 +1,25 @@
+package taxrates
+
+import "testing"
+
+func TestLoadTable_ExampleConfig(t *testing.T) {
+	table, err := LoadTable("../../../configs/tax-rates.yaml")
+	if err != nil {
+		t.Fatalf("Expected example config to be valid, got %v", err)
+	}
+
+	if len(table.Jurisdictions()) == 0 {
+		t.Error("Expected example config to define jurisdictions")
+	}
+}
+
+func TestLoadTable_EmptyPath(t *testing.T) {
+	table, err := LoadTable("")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if table != nil {
+		t.Error("Expected no tax table for empty path")
+	}
+}
@@ -0,0 +1,129 @@
+package tenants
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jwtLeeway допустимое расхождение часов при проверке exp и nbf
+const jwtLeeway = 30 * time.Second
+
+// JWTVerifier проверяет JWT, подписанные HS256 общим секретом, и достает ID арендатора из claim
+type JWTVerifier struct {
+	secret        []byte
+	claim         string
+	customerClaim string
+	now           func() time.Time
+}
+
+// NewJWTVerifier создает проверку JWT; claim - имя claim с ID арендатора
+func NewJWTVerifier(secret, claim string) (*JWTVerifier, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("jwt secret must be at least 32 bytes")
+	}
+	if claim == "" {
+		return nil, errors.New("jwt tenant claim is required")
+	}
+	return &JWTVerifier{secret: []byte(secret), claim: claim, now: time.Now}, nil
+}
+
+// WithCustomerClaim возвращает проверку, которая также достает из claim ID клиента,
+// от имени которого выдан токен; пустое имя - токены клиентов не принимаются
+func (v *JWTVerifier) WithCustomerClaim(claim string) *JWTVerifier {
+	withCustomer := *v
+	withCustomer.customerClaim = claim
+	return &withCustomer
+}
+
+// TenantID проверяет подпись и сроки токена и возвращает ID арендатора
+func (v *JWTVerifier) TenantID(token string) (string, error) {
+	claims, err := v.verify(token)
+	if err != nil {
+		return "", err
+	}
+
+	tenantID, _ := claims[v.claim].(string)
+	if tenantID == "" {
+		return "", fmt.Errorf("jwt has no %s claim", v.claim)
+	}
+	return tenantID, nil
+}
+
+// CustomerID проверяет токен и возвращает ID клиента из него; false - токен выдан не клиенту
+func (v *JWTVerifier) CustomerID(token string) (uuid.UUID, bool) {
+	if v.customerClaim == "" {
+		return uuid.Nil, false
+	}
+	claims, err := v.verify(token)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	value, _ := claims[v.customerClaim].(string)
+	customerID, err := uuid.Parse(value)
+	if err != nil || customerID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return customerID, true
+}
+
+// verify проверяет подпись и сроки токена и возвращает его claims
+func (v *JWTVerifier) verify(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed jwt")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid jwt header: %w", err)
+	}
+	// Принимаем только HS256, чтобы исключить подмену алгоритма ("none", RS256 с публичным ключом)
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt signature encoding: %w", err)
+	}
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, errors.New("invalid jwt signature")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid jwt claims: %w", err)
+	}
+
+	now := v.now()
+	if exp, ok := claims["exp"].(float64); ok && now.After(time.Unix(int64(exp), 0).Add(jwtLeeway)) {
+		return nil, errors.New("jwt is expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(jwtLeeway).Before(time.Unix(int64(nbf), 0)) {
+		return nil, errors.New("jwt is not valid yet")
+	}
+
+	return claims, nil
+}
+
+// decodeSegment декодирует base64url JSON сегмент токена
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
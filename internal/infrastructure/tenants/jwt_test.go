@@ -0,0 +1,95 @@
+package tenants
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func signToken(t *testing.T, alg string, claims map[string]interface{}, secret string) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTVerifier_TenantID(t *testing.T) {
+	verifier, err := NewJWTVerifier(testSecret, "tenant_id")
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+	now := time.Now()
+
+	token := signToken(t, "HS256", map[string]interface{}{"tenant_id": "acme", "exp": now.Add(time.Hour).Unix()}, testSecret)
+	tenantID, err := verifier.TenantID(token)
+	if err != nil || tenantID != "acme" {
+		t.Fatalf("Expected acme, got %q, %v", tenantID, err)
+	}
+
+	invalid := map[string]string{
+		"wrong secret":  signToken(t, "HS256", map[string]interface{}{"tenant_id": "acme"}, "another-secret-another-secret-xx"),
+		"alg none":      signToken(t, "none", map[string]interface{}{"tenant_id": "acme"}, testSecret),
+		"expired":       signToken(t, "HS256", map[string]interface{}{"tenant_id": "acme", "exp": now.Add(-time.Hour).Unix()}, testSecret),
+		"not yet valid": signToken(t, "HS256", map[string]interface{}{"tenant_id": "acme", "nbf": now.Add(time.Hour).Unix()}, testSecret),
+		"missing claim": signToken(t, "HS256", map[string]interface{}{"sub": "user"}, testSecret),
+		"malformed":     "not-a-jwt",
+		"bad signature": token[:len(token)-2] + "xx",
+	}
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if tenantID, err := verifier.TenantID(token); err == nil {
+				t.Errorf("Expected error, got tenant %q", tenantID)
+			}
+		})
+	}
+}
+
+func TestNewJWTVerifier_ShortSecret(t *testing.T) {
+	if _, err := NewJWTVerifier("short", "tenant_id"); err == nil {
+		t.Error("Expected error for short secret")
+	}
+}
+
+func TestJWTVerifier_CustomerID(t *testing.T) {
+	verifier, err := NewJWTVerifier(testSecret, "tenant_id")
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+	customerID := uuid.New()
+	token := signToken(t, "HS256", map[string]interface{}{"tenant_id": "acme", "customer_id": customerID.String()}, testSecret)
+
+	if _, ok := verifier.CustomerID(token); ok {
+		t.Error("Expected customer tokens to be ignored without customer claim")
+	}
+
+	verifier = verifier.WithCustomerClaim("customer_id")
+	if got, ok := verifier.CustomerID(token); !ok || got != customerID {
+		t.Errorf("Expected customer %s, got %s (%v)", customerID, got, ok)
+	}
+
+	invalid := map[string]string{
+		"storefront token": signToken(t, "HS256", map[string]interface{}{"tenant_id": "acme"}, testSecret),
+		"not a uuid":       signToken(t, "HS256", map[string]interface{}{"tenant_id": "acme", "customer_id": "buyer"}, testSecret),
+		"wrong secret":     signToken(t, "HS256", map[string]interface{}{"tenant_id": "acme", "customer_id": customerID.String()}, "another-secret-another-secret-xx"),
+	}
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if got, ok := verifier.CustomerID(token); ok {
+				t.Errorf("Expected no customer, got %s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package tenants
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/infrastructure/statemachine"
+)
+
+// File описывает формат файла с арендаторами
+type File struct {
+	Tenants []TenantFile `json:"tenants" yaml:"tenants"`
+}
+
+// TenantFile настройки одного арендатора
+type TenantFile struct {
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name,omitempty" yaml:"name"`
+	// APIKeysSHA256 SHA-256 (hex) API ключей; сами ключи в файле не хранятся
+	APIKeysSHA256   []string `json:"api_keys_sha256,omitempty" yaml:"api_keys_sha256"`
+	DefaultCurrency string   `json:"default_currency,omitempty" yaml:"default_currency"`
+	// StateMachineFile файл машин состояний арендатора; относительный путь - от файла арендаторов
+	StateMachineFile string                `json:"state_machine_file,omitempty" yaml:"state_machine_file"`
+	Limits           entities.TenantLimits `json:"limits" yaml:"limits"`
+}
+
+// LoadTenants загружает и проверяет арендаторов из YAML/JSON файла.
+// Если путь не указан, возвращается пустой список.
+func LoadTenants(path string) ([]*entities.Tenant, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file: %w", err)
+	}
+
+	var file File
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported tenants file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tenants file: %w", err)
+	}
+
+	return NewTenants(file, filepath.Dir(path))
+}
+
+// NewTenants строит арендаторов из описания файла; baseDir - каталог для относительных путей
+func NewTenants(file File, baseDir string) ([]*entities.Tenant, error) {
+	tenants := make([]*entities.Tenant, 0, len(file.Tenants))
+	for _, def := range file.Tenants {
+		tenant := &entities.Tenant{
+			ID:     def.ID,
+			Name:   def.Name,
+			Limits: def.Limits,
+		}
+		if err := entities.ValidateTenantID(def.ID); err != nil {
+			return nil, err
+		}
+
+		if def.DefaultCurrency != "" {
+			tenant.DefaultCurrency = entities.NormalizeCurrency(def.DefaultCurrency)
+			if err := entities.ValidateCurrency(tenant.DefaultCurrency); err != nil {
+				return nil, fmt.Errorf("tenant %s: %w", def.ID, err)
+			}
+		}
+
+		if def.Limits.MaxOrderAmount < 0 || def.Limits.MaxItems < 0 {
+			return nil, fmt.Errorf("tenant %s: limits must not be negative", def.ID)
+		}
+
+		for _, hash := range def.APIKeysSHA256 {
+			tenant.APIKeyHashes = append(tenant.APIKeyHashes, strings.ToLower(strings.TrimSpace(hash)))
+		}
+
+		if def.StateMachineFile != "" {
+			path := def.StateMachineFile
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(baseDir, path)
+			}
+			registry, err := statemachine.LoadRegistry(path)
+			if err != nil {
+				return nil, fmt.Errorf("tenant %s: %w", def.ID, err)
+			}
+			tenant.StateMachines = registry
+		}
+
+		tenants = append(tenants, tenant)
+	}
+
+	return tenants, nil
+}
@@ -0,0 +1,58 @@
+package tenants
+
+import (
+	"testing"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+func TestLoadTenants_ExampleConfig(t *testing.T) {
+	tenants, err := LoadTenants("../../../configs/tenants.example.yaml")
+	if err != nil {
+		t.Fatalf("Expected example config to be valid, got %v", err)
+	}
+
+	directory, err := entities.NewTenantDirectory(tenants, nil)
+	if err != nil {
+		t.Fatalf("Expected valid directory, got %v", err)
+	}
+
+	acme, err := directory.Authenticate("acme-dev-key", "")
+	if err != nil {
+		t.Fatalf("Expected acme key to authenticate, got %v", err)
+	}
+	if acme.ID != "acme" || acme.DefaultCurrency != "EUR" || acme.Limits.MaxItems != 50 {
+		t.Errorf("Unexpected acme tenant: %+v", acme)
+	}
+
+	if directory.Get("globex").StateMachines == nil {
+		t.Error("Expected globex to have its own state machines")
+	}
+}
+
+func TestLoadTenants_EmptyPath(t *testing.T) {
+	tenants, err := LoadTenants("")
+	if err != nil || tenants != nil {
+		t.Fatalf("Expected no tenants, got %v, %v", tenants, err)
+	}
+}
+
+func TestNewTenants_Invalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		tenant TenantFile
+	}{
+		{"invalid id", TenantFile{ID: "ACME"}},
+		{"invalid currency", TenantFile{ID: "acme", DefaultCurrency: "euro"}},
+		{"negative limit", TenantFile{ID: "acme", Limits: entities.TenantLimits{MaxItems: -1}}},
+		{"missing state machine", TenantFile{ID: "acme", StateMachineFile: "missing.yaml"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewTenants(File{Tenants: []TenantFile{tc.tenant}}, t.TempDir()); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// Заголовки запроса доставки
+const (
+	HeaderSignature = "X-Webhook-Signature" // sha256=<hex HMAC-SHA256>
+	HeaderTimestamp = "X-Webhook-Timestamp" // Unix время подписи
+	HeaderEventType = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Event-ID"
+)
+
+// Sender отправляет события подписчикам HTTP POST запросом с подписью HMAC-SHA256
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender создает отправителя webhooks с таймаутом запроса
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Sign вычисляет подпись тела запроса: HMAC-SHA256 от "<timestamp>.<body>" в hex.
+// Получатель проверяет ее тем же секретом и отклоняет устаревший timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись запроса
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Send доставляет событие подписчику. Ответ вне диапазона 2xx считается ошибкой,
+// статус ответа возвращается и в этом случае.
+func (s *Sender) Send(ctx context.Context, subscription *entities.WebhookSubscription, event *entities.OrderEvent) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kafka-order-service-webhooks/1.0")
+	req.Header.Set(HeaderEventType, event.EventType)
+	req.Header.Set(HeaderEventID, event.EventID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+func TestSender_SendSignsPayload(t *testing.T) {
+	secret := "0123456789abcdef-secret"
+	var received entities.OrderEvent
+	var signatureValid bool
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		signatureValid = Verify(secret, timestamp, body, r.Header.Get(HeaderSignature))
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	subscription := entities.NewWebhookSubscription(receiver.URL, nil, secret)
+	event := entities.NewOrder(uuid.New(), "test@example.com").ToEvent(entities.EventOrderCreated)
+
+	status, err := NewSender(time.Second).Send(context.Background(), subscription, event)
+	if err != nil {
+		t.Fatalf("Expected successful delivery, got %v", err)
+	}
+	if status != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", status)
+	}
+	if !signatureValid {
+		t.Error("Expected receiver to verify signature")
+	}
+	if received.EventID != event.EventID || received.EventType != entities.EventOrderCreated {
+		t.Errorf("Unexpected payload: %+v", received)
+	}
+}
+
+func TestSender_SendNon2xx(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	subscription := entities.NewWebhookSubscription(receiver.URL, nil, "0123456789abcdef-secret")
+	event := entities.NewOrder(uuid.New(), "test@example.com").ToEvent(entities.EventOrderCreated)
+
+	status, err := NewSender(time.Second).Send(context.Background(), subscription, event)
+	if err == nil {
+		t.Fatal("Expected error for 503 response")
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", status)
+	}
+}
+
+func TestVerify_RejectsTamperedBody(t *testing.T) {
+	signature := Sign("secret", 1700000000, []byte(`{"a":1}`))
+	if Verify("secret", 1700000000, []byte(`{"a":2}`), signature) {
+		t.Error("Expected tampered body to fail verification")
+	}
+	if Verify("other", 1700000000, []byte(`{"a":1}`), signature) {
+		t.Error("Expected wrong secret to fail verification")
+	}
+}
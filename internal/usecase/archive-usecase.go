@@ -0,0 +1,334 @@
+package usecase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// archiveFileSuffix расширение файлов архива: gzip NDJSON, одна запись заказа на строку
+const archiveFileSuffix = ".ndjson.gz"
+
+// restoreBatchSize количество заказов, восстанавливаемых одной транзакцией
+const restoreBatchSize = 500
+
+// ArchiveStorage хранилище файлов архива; имена файлов - пути через "/"
+type ArchiveStorage interface {
+	Put(ctx context.Context, name string, data []byte) error
+	Get(ctx context.Context, name string) ([]byte, error)
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// ArchivePolicy определяет, какие заказы и какими порциями уходят в архив
+type ArchivePolicy struct {
+	// Заказ архивируется, если не менялся дольше After
+	After    time.Duration
+	Statuses []entities.OrderStatus
+	// Заказов в одном файле и максимум файлов за прогон
+	BatchSize  int
+	MaxBatches int
+}
+
+// ArchiveOrdersResponse представляет результат прогона архивации
+type ArchiveOrdersResponse struct {
+	Archived int      `json:"archived"`
+	Deleted  int64    `json:"deleted"`
+	Files    []string `json:"files"`
+}
+
+// ArchiveOrdersUseCase выгружает старые заказы в финальных статусах в файлы
+// по месяцам создания и удаляет их из БД после проверки файла
+type ArchiveOrdersUseCase struct {
+	archiveRepo repositories.OrderArchiveRepository
+	storage     ArchiveStorage
+	policy      ArchivePolicy
+	logger      Logger
+	now         func() time.Time
+}
+
+// NewArchiveOrdersUseCase создает новый use case архивации заказов
+func NewArchiveOrdersUseCase(
+	archiveRepo repositories.OrderArchiveRepository,
+	storage ArchiveStorage,
+	policy ArchivePolicy,
+	logger Logger,
+) *ArchiveOrdersUseCase {
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = 1000
+	}
+	if policy.MaxBatches <= 0 {
+		policy.MaxBatches = 1
+	}
+	return &ArchiveOrdersUseCase{
+		archiveRepo: archiveRepo,
+		storage:     storage,
+		policy:      policy,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Execute архивирует заказы порциями до исчерпания или лимита порций
+func (uc *ArchiveOrdersUseCase) Execute(ctx context.Context) (*ArchiveOrdersResponse, error) {
+	if len(uc.policy.Statuses) == 0 || uc.policy.After <= 0 {
+		return nil, entities.NewValidationError("archive policy requires statuses and a positive age")
+	}
+
+	now := uc.now().UTC()
+	cutoff := now.Add(-uc.policy.After)
+	response := &ArchiveOrdersResponse{Files: make([]string, 0)}
+
+	for batch := 0; batch < uc.policy.MaxBatches; batch++ {
+		orders, err := uc.archiveRepo.ListArchivable(ctx, uc.policy.Statuses, cutoff, uc.policy.BatchSize)
+		if err != nil {
+			return response, fmt.Errorf("failed to list archivable orders: %w", err)
+		}
+		if len(orders) == 0 {
+			break
+		}
+
+		for _, partition := range partitionArchive(orders) {
+			name := fmt.Sprintf("%s/orders-%s-%s-%s%s", partition.name, partition.name,
+				now.Format("20060102T150405Z"), uuid.New().String()[:8], archiveFileSuffix)
+
+			if err := uc.writeVerified(ctx, name, partition.orders); err != nil {
+				uc.logger.Error("Order archive failed, orders kept", "file", name, "orders", len(partition.orders), "error", err)
+				return response, err
+			}
+
+			deleted, err := uc.archiveRepo.DeleteArchived(ctx, partition.orders)
+			if err != nil {
+				return response, fmt.Errorf("failed to delete archived orders of %s: %w", name, err)
+			}
+			if skipped := int64(len(partition.orders)) - deleted; skipped > 0 {
+				// Изменившиеся после выгрузки заказы останутся в БД и попадут в архив позже
+				uc.logger.Warn("Archived orders changed before delete", "file", name, "kept", skipped)
+			}
+
+			response.Archived += len(partition.orders)
+			response.Deleted += deleted
+			response.Files = append(response.Files, name)
+			uc.logger.Info("Orders archived", "file", name, "orders", len(partition.orders), "deleted", deleted)
+		}
+
+		if len(orders) < uc.policy.BatchSize {
+			break
+		}
+	}
+
+	return response, nil
+}
+
+// writeVerified записывает файл архива и перечитывает его из хранилища: удалять заказы
+// можно только если файл совпадает побайтно и содержит все записи
+func (uc *ArchiveOrdersUseCase) writeVerified(ctx context.Context, name string, orders []*entities.ArchivedOrder) error {
+	data, err := encodeArchive(orders)
+	if err != nil {
+		return fmt.Errorf("failed to encode archive %s: %w", name, err)
+	}
+	if err := uc.storage.Put(ctx, name, data); err != nil {
+		return fmt.Errorf("failed to store archive %s: %w", name, err)
+	}
+
+	stored, err := uc.storage.Get(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to read back archive %s: %w", name, err)
+	}
+	if sha256.Sum256(stored) != sha256.Sum256(data) {
+		return fmt.Errorf("archive %s verification failed: stored file differs", name)
+	}
+
+	decoded, err := decodeArchive(stored)
+	if err != nil {
+		return fmt.Errorf("archive %s verification failed: %w", name, err)
+	}
+	if len(decoded) != len(orders) {
+		return fmt.Errorf("archive %s verification failed: %d records, expected %d", name, len(decoded), len(orders))
+	}
+	expected := make(map[uuid.UUID]bool, len(orders))
+	for _, order := range orders {
+		expected[order.ID] = true
+	}
+	for _, order := range decoded {
+		if !expected[order.ID] {
+			return fmt.Errorf("archive %s verification failed: unexpected order %s", name, order.ID)
+		}
+		delete(expected, order.ID)
+	}
+	if len(expected) > 0 {
+		return fmt.Errorf("archive %s verification failed: %d orders missing", name, len(expected))
+	}
+
+	return nil
+}
+
+// archivePartition заказы одного месяца создания
+type archivePartition struct {
+	name   string
+	orders []*entities.ArchivedOrder
+}
+
+// partitionArchive раскладывает заказы по месяцам создания
+func partitionArchive(orders []*entities.ArchivedOrder) []archivePartition {
+	byName := make(map[string]*archivePartition)
+	var names []string
+	for _, order := range orders {
+		name := entities.ArchivePartition(order.CreatedAt)
+		partition, ok := byName[name]
+		if !ok {
+			partition = &archivePartition{name: name}
+			byName[name] = partition
+			names = append(names, name)
+		}
+		partition.orders = append(partition.orders, order)
+	}
+
+	sort.Strings(names)
+	partitions := make([]archivePartition, len(names))
+	for i, name := range names {
+		partitions[i] = *byName[name]
+	}
+	return partitions
+}
+
+// encodeArchive кодирует заказы в gzip NDJSON
+func encodeArchive(orders []*entities.ArchivedOrder) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	for _, order := range orders {
+		if err := encoder.Encode(order); err != nil {
+			return nil, err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeArchive читает и проверяет записи gzip NDJSON файла архива
+func decodeArchive(data []byte) ([]*entities.ArchivedOrder, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip: %w", err)
+	}
+	defer gz.Close()
+
+	var orders []*entities.ArchivedOrder
+	decoder := json.NewDecoder(gz)
+	for {
+		var order entities.ArchivedOrder
+		err := decoder.Decode(&order)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid record %d: %w", len(orders)+1, err)
+		}
+		if err := order.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid record %d: %w", len(orders)+1, err)
+		}
+		orders = append(orders, &order)
+	}
+	return orders, nil
+}
+
+// RestoreOrdersRequest представляет запрос восстановления заказов из архива
+type RestoreOrdersRequest struct {
+	// Файл архива или префикс (например месяц 2024-01) - все файлы с этим префиксом
+	Name string `json:"name"`
+	// Только прочитать и проверить файлы, не загружая заказы
+	DryRun bool `json:"dry_run"`
+}
+
+// RestoreOrdersResponse представляет результат восстановления
+type RestoreOrdersResponse struct {
+	Files    []string `json:"files"`
+	Read     int      `json:"read"`
+	Restored int      `json:"restored"`
+	// Заказы, которые уже есть в БД
+	Skipped int `json:"skipped"`
+}
+
+// RestoreOrdersUseCase загружает заказы из файлов архива обратно в БД
+type RestoreOrdersUseCase struct {
+	archiveRepo repositories.OrderArchiveRepository
+	storage     ArchiveStorage
+	logger      Logger
+}
+
+// NewRestoreOrdersUseCase создает новый use case восстановления заказов
+func NewRestoreOrdersUseCase(archiveRepo repositories.OrderArchiveRepository, storage ArchiveStorage, logger Logger) *RestoreOrdersUseCase {
+	return &RestoreOrdersUseCase{
+		archiveRepo: archiveRepo,
+		storage:     storage,
+		logger:      logger,
+	}
+}
+
+// Execute восстанавливает заказы из файла или всех файлов с префиксом
+func (uc *RestoreOrdersUseCase) Execute(ctx context.Context, req *RestoreOrdersRequest) (*RestoreOrdersResponse, error) {
+	if req == nil || strings.TrimSpace(req.Name) == "" {
+		return nil, entities.NewValidationError("archive name is required")
+	}
+
+	files := []string{req.Name}
+	if !strings.HasSuffix(req.Name, archiveFileSuffix) {
+		names, err := uc.storage.List(ctx, req.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list archives: %w", err)
+		}
+		files = files[:0]
+		for _, name := range names {
+			if strings.HasSuffix(name, archiveFileSuffix) {
+				files = append(files, name)
+			}
+		}
+		if len(files) == 0 {
+			return nil, entities.NewValidationError("no archive files match %q", req.Name)
+		}
+	}
+
+	response := &RestoreOrdersResponse{Files: files}
+	for _, name := range files {
+		data, err := uc.storage.Get(ctx, name)
+		if err != nil {
+			return response, fmt.Errorf("failed to read archive %s: %w", name, err)
+		}
+		orders, err := decodeArchive(data)
+		if err != nil {
+			return response, fmt.Errorf("archive %s is corrupted: %w", name, err)
+		}
+		response.Read += len(orders)
+		if req.DryRun {
+			continue
+		}
+
+		for start := 0; start < len(orders); start += restoreBatchSize {
+			end := min(start+restoreBatchSize, len(orders))
+			restored, err := uc.archiveRepo.Restore(ctx, orders[start:end])
+			if err != nil {
+				return response, fmt.Errorf("failed to restore archive %s: %w", name, err)
+			}
+			response.Restored += restored
+			response.Skipped += end - start - restored
+		}
+		uc.logger.Info("Orders restored from archive", "file", name, "orders", len(orders))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+type memoryArchiveStorage struct {
+	files map[string][]byte
+	// corrupt подменяет прочитанные данные, имитируя поврежденную запись
+	corrupt bool
+}
+
+func newMemoryArchiveStorage() *memoryArchiveStorage {
+	return &memoryArchiveStorage{files: make(map[string][]byte)}
+}
+
+func (s *memoryArchiveStorage) Put(_ context.Context, name string, data []byte) error {
+	s.files[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *memoryArchiveStorage) Get(_ context.Context, name string) ([]byte, error) {
+	data, ok := s.files[name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	if s.corrupt {
+		return data[:len(data)/2], nil
+	}
+	return data, nil
+}
+
+func (s *memoryArchiveStorage) List(_ context.Context, prefix string) ([]string, error) {
+	var names []string
+	for name := range s.files {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+type stubArchiveRepository struct {
+	archivable []*entities.ArchivedOrder
+	stored     map[uuid.UUID]bool
+	deleted    []uuid.UUID
+}
+
+func (r *stubArchiveRepository) ListArchivable(_ context.Context, _ []entities.OrderStatus, _ time.Time, limit int) ([]*entities.ArchivedOrder, error) {
+	var orders []*entities.ArchivedOrder
+	for _, order := range r.archivable {
+		if r.stored[order.ID] && len(orders) < limit {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (r *stubArchiveRepository) DeleteArchived(_ context.Context, orders []*entities.ArchivedOrder) (int64, error) {
+	for _, order := range orders {
+		delete(r.stored, order.ID)
+		r.deleted = append(r.deleted, order.ID)
+	}
+	return int64(len(orders)), nil
+}
+
+func (r *stubArchiveRepository) Restore(_ context.Context, orders []*entities.ArchivedOrder) (int, error) {
+	restored := 0
+	for _, order := range orders {
+		if !r.stored[order.ID] {
+			r.stored[order.ID] = true
+			restored++
+		}
+	}
+	return restored, nil
+}
+
+func newStubArchiveRepository(createdAt ...time.Time) *stubArchiveRepository {
+	repo := &stubArchiveRepository{stored: make(map[uuid.UUID]bool)}
+	for _, created := range createdAt {
+		id := uuid.New()
+		row, _ := json.Marshal(map[string]string{"id": id.String(), "status": "delivered"})
+		repo.archivable = append(repo.archivable, &entities.ArchivedOrder{
+			ID:        id,
+			Status:    entities.OrderStatus("delivered"),
+			CreatedAt: created,
+			UpdatedAt: created,
+			Order:     row,
+			Related:   map[string][]json.RawMessage{"order_items": {json.RawMessage(`{"quantity":1}`)}},
+		})
+		repo.stored[id] = true
+	}
+	return repo
+}
+
+func testArchivePolicy() ArchivePolicy {
+	return ArchivePolicy{
+		After:      24 * time.Hour,
+		Statuses:   []entities.OrderStatus{"delivered"},
+		BatchSize:  2,
+		MaxBatches: 10,
+	}
+}
+
+func TestArchiveOrdersPartitionsByMonthAndDeletes(t *testing.T) {
+	repo := newStubArchiveRepository(
+		time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC),
+	)
+	storage := newMemoryArchiveStorage()
+	uc := NewArchiveOrdersUseCase(repo, storage, testArchivePolicy(), nopLogger{})
+
+	resp, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Archived != 3 || resp.Deleted != 3 || len(repo.stored) != 0 {
+		t.Fatalf("expected 3 archived and deleted, got %+v, left %d", resp, len(repo.stored))
+	}
+
+	january, _ := storage.List(context.Background(), "2024-01/")
+	february, _ := storage.List(context.Background(), "2024-02/")
+	if len(january) == 0 || len(february) != 1 {
+		t.Fatalf("expected files per month, got %v", resp.Files)
+	}
+	for _, name := range resp.Files {
+		orders, err := decodeArchive(storage.files[name])
+		if err != nil {
+			t.Fatalf("archive %s not readable: %v", name, err)
+		}
+		for _, order := range orders {
+			if !strings.HasPrefix(name, entities.ArchivePartition(order.CreatedAt)+"/") {
+				t.Errorf("order created %s stored in %s", order.CreatedAt, name)
+			}
+		}
+	}
+}
+
+func TestArchiveOrdersKeepsRowsWhenVerificationFails(t *testing.T) {
+	repo := newStubArchiveRepository(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))
+	storage := newMemoryArchiveStorage()
+	storage.corrupt = true
+	uc := NewArchiveOrdersUseCase(repo, storage, testArchivePolicy(), nopLogger{})
+
+	if _, err := uc.Execute(context.Background()); err == nil {
+		t.Fatal("expected verification error")
+	}
+	if len(repo.deleted) != 0 || len(repo.stored) != 1 {
+		t.Fatalf("orders must not be deleted after failed verification, deleted %v", repo.deleted)
+	}
+}
+
+func TestRestoreOrdersRoundTrip(t *testing.T) {
+	repo := newStubArchiveRepository(
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+	)
+	storage := newMemoryArchiveStorage()
+	if _, err := NewArchiveOrdersUseCase(repo, storage, testArchivePolicy(), nopLogger{}).Execute(context.Background()); err != nil {
+		t.Fatalf("archive failed: %v", err)
+	}
+
+	restore := NewRestoreOrdersUseCase(repo, storage, nopLogger{})
+	dry, err := restore.Execute(context.Background(), &RestoreOrdersRequest{Name: "2024-03", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if dry.Read != 2 || dry.Restored != 0 || len(repo.stored) != 0 {
+		t.Fatalf("dry run must only read, got %+v", dry)
+	}
+
+	resp, err := restore.Execute(context.Background(), &RestoreOrdersRequest{Name: "2024-03"})
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if resp.Restored != 2 || resp.Skipped != 0 || len(repo.stored) != 2 {
+		t.Fatalf("expected 2 restored, got %+v", resp)
+	}
+
+	again, err := restore.Execute(context.Background(), &RestoreOrdersRequest{Name: resp.Files[0]})
+	if err != nil {
+		t.Fatalf("repeated restore failed: %v", err)
+	}
+	if again.Restored != 0 || again.Skipped != again.Read {
+		t.Fatalf("repeated restore must skip existing orders, got %+v", again)
+	}
+
+	_, err = restore.Execute(context.Background(), &RestoreOrdersRequest{Name: "2023-"})
+	var validationErr entities.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error for unknown prefix, got %v", err)
+	}
+}
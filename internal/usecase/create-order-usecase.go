@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	_ "time"
@@ -14,12 +15,19 @@ import (
 
 // CreateOrderRequest представляет запрос на создание заказа
 type CreateOrderRequest struct {
-	CustomerID uuid.UUID                `json:"customer_id" validate:"required"`
+	// Без customer_id при автосоздании клиентов заказ оформляется на клиента из токена или на нового клиента по email
+	CustomerID uuid.UUID                `json:"customer_id"`
 	Email      string                   `json:"email" validate:"required,email"`
 	Items      []CreateOrderItemRequest `json:"items" validate:"required,min=1"`
 	Currency   string                   `json:"currency,omitempty"`
 	Metadata   map[string]interface{}   `json:"metadata,omitempty"`
 
+	// Коды купонов, применяемых к заказу
+	CouponCodes []string `json:"coupon_codes,omitempty"`
+
+	// Способ доставки; по умолчанию standard при указанном адресе доставки
+	ShippingMethod entities.ShippingMethod `json:"shipping_method,omitempty"`
+
 	// Адреса (опционально)
 	ShippingAddress *CreateAddressRequest `json:"shipping_address,omitempty"`
 	BillingAddress  *CreateAddressRequest `json:"billing_address,omitempty"`
@@ -28,9 +36,17 @@ type CreateOrderRequest struct {
 // CreateOrderItemRequest представляет элемент в запросе создания заказа
 type CreateOrderItemRequest struct {
 	ProductID uuid.UUID `json:"product_id" validate:"required"`
-	Name      string    `json:"name" validate:"required"`
-	Price     float64   `json:"price" validate:"required,gt=0"`
 	Quantity  int       `json:"quantity" validate:"required,gt=0"`
+
+	// Название и цена обязательны без каталога товаров. С каталогом они
+	// берутся из товара, а указанная цена должна совпадать с ценой каталога.
+	Name  string  `json:"name,omitempty"`
+	Price float64 `json:"price,omitempty"`
+
+	// Налоговая категория товара, по умолчанию standard
+	TaxCategory string `json:"tax_category,omitempty"`
+	// Вес единицы товара в кг для расчета доставки
+	Weight float64 `json:"weight,omitempty"`
 }
 
 // CreateAddressRequest представляет адрес в запросе
@@ -62,21 +78,60 @@ type Logger interface {
 
 // CreateOrderUseCase представляет use case создания заказа
 type CreateOrderUseCase struct {
-	orderRepo repositories.OrderRepository
-	publisher EventPublisher
-	logger    Logger
+	orderRepo     repositories.OrderRepository
+	publisher     EventPublisher
+	riskEngine    *RiskEngine
+	customers     *CustomerService
+	catalog       *CatalogService
+	promotions    *PromotionService
+	taxes         *entities.TaxTable
+	shipping      *entities.ShippingTable
+	currencies    *CurrencyService
+	inventory     *InventoryService
+	stateMachines *entities.StateMachineRegistry
+	logger        Logger
 }
 
-// NewCreateOrderUseCase создает новый use case для создания заказа
+// NewCreateOrderUseCase создает новый use case для создания заказа.
+// Если riskEngine не передан, оценка риска не выполняется;
+// если не передан customers, клиенты не создаются автоматически;
+// если не передан catalog, название и цена позиций берутся из запроса;
+// если не передан promotions, заказы с купонами отклоняются;
+// если не передана таблица taxes, налоги не начисляются;
+// если не передана таблица shipping, доставка не начисляется;
+// если не передан currencies, итог в отчетной валюте не фиксируется;
+// если не передан inventory, наличие товаров на складе не проверяется.
 func NewCreateOrderUseCase(
 	orderRepo repositories.OrderRepository,
 	publisher EventPublisher,
+	riskEngine *RiskEngine,
+	customers *CustomerService,
+	catalog *CatalogService,
+	promotions *PromotionService,
+	taxes *entities.TaxTable,
+	shipping *entities.ShippingTable,
+	currencies *CurrencyService,
+	inventory *InventoryService,
+	stateMachines *entities.StateMachineRegistry,
 	logger Logger,
 ) *CreateOrderUseCase {
+	if stateMachines == nil {
+		stateMachines = entities.NewStateMachineRegistry(entities.DefaultStateMachine(), nil)
+	}
+
 	return &CreateOrderUseCase{
-		orderRepo: orderRepo,
-		publisher: publisher,
-		logger:    logger,
+		orderRepo:     orderRepo,
+		publisher:     publisher,
+		riskEngine:    riskEngine,
+		customers:     customers,
+		catalog:       catalog,
+		promotions:    promotions,
+		taxes:         taxes,
+		shipping:      shipping,
+		currencies:    currencies,
+		inventory:     inventory,
+		stateMachines: stateMachines,
+		logger:        logger,
 	}
 }
 
@@ -88,12 +143,29 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, req *CreateOrderReque
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	// Создание заказа
-	order := entities.NewOrder(req.CustomerID, req.Email)
+	// Определение клиента; новый клиент сохраняется вместе с заказом
+	var resolution *CustomerResolution
+	customerID := req.CustomerID
+	if uc.customers != nil {
+		resolved, err := uc.customers.ResolveCustomer(ctx, req.CustomerID, req.Email)
+		if err != nil {
+			uc.logger.Error("Failed to resolve customer", "error", err, "customer_id", req.CustomerID)
+			return nil, fmt.Errorf("failed to resolve customer: %w", err)
+		}
+		resolution = resolved
+		customerID = resolved.CustomerID
+	}
+
+	// Создание заказа в рамках арендатора запроса
+	order := entities.NewOrder(customerID, req.Email)
+	tenant, _ := entities.TenantFromContext(ctx)
+	order.TenantID = entities.TenantIDOrDefault(entities.TenantIDFromContext(ctx))
 
-	// Установка валюты если указана
+	// Установка валюты: из запроса или валюта арендатора по умолчанию
 	if req.Currency != "" {
-		order.Currency = req.Currency
+		order.Currency = entities.NormalizeCurrency(req.Currency)
+	} else if tenant != nil && tenant.DefaultCurrency != "" {
+		order.Currency = tenant.DefaultCurrency
 	}
 
 	// Добавление метаданных
@@ -103,9 +175,20 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, req *CreateOrderReque
 		}
 	}
 
+	// Название, цена и параметры товаров берутся из каталога
+	items := req.Items
+	if uc.catalog != nil {
+		resolved, err := uc.catalog.ResolveItems(ctx, order.Currency, req.Items)
+		if err != nil {
+			uc.logger.Error("Order items rejected by catalog", "error", err, "customer_id", req.CustomerID)
+			return nil, fmt.Errorf("catalog validation failed: %w", err)
+		}
+		items = resolved
+	}
+
 	// Добавление элементов заказа
-	for _, item := range req.Items {
-		order.AddItem(item.ProductID, item.Name, item.Price, item.Quantity)
+	for _, item := range items {
+		order.AddTaxableItem(item.ProductID, item.Name, item.Price, item.Quantity, item.TaxCategory)
 	}
 
 	// Добавление адресов
@@ -131,26 +214,106 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, req *CreateOrderReque
 		order.SetBillingAddress(address)
 	}
 
+	// Адреса клиента по умолчанию, если в запросе их нет и он сам оформляет заказ
+	if resolution != nil && resolution.Customer != nil {
+		customer := resolution.Customer
+		if order.ShippingAddress == nil && customer.DefaultShippingAddress != nil {
+			order.SetShippingAddress(customerDefaultAddress(customer.DefaultShippingAddress))
+		}
+		if order.BillingAddress == nil && customer.DefaultBillingAddress != nil {
+			order.SetBillingAddress(customerDefaultAddress(customer.DefaultBillingAddress))
+		}
+	}
+
+	// Применение купонов
+	if len(req.CouponCodes) > 0 {
+		if uc.promotions == nil {
+			return nil, entities.NewValidationError("coupon codes are not supported")
+		}
+		if err := uc.promotions.ApplyCoupons(ctx, order, req.CouponCodes); err != nil {
+			uc.logger.Error("Failed to apply coupons", "error", err, "order_id", order.ID)
+			return nil, fmt.Errorf("coupon validation failed: %w", err)
+		}
+	}
+
+	// Расчет налогов по адресу доставки; дальше пересчитываются при изменении заказа
+	if uc.taxes != nil {
+		order.ApplyTaxes(uc.taxes)
+	}
+
+	// Расчет доставки
+	if err := uc.applyShipping(order, req.ShippingMethod, items); err != nil {
+		uc.logger.Error("Failed to calculate shipping", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("shipping calculation failed: %w", err)
+	}
+
 	// Финальная валидация заказа
 	if err := order.Validate(); err != nil {
 		uc.logger.Error("Order validation failed", "error", err, "order_id", order.ID)
 		return nil, fmt.Errorf("order validation failed: %w", err)
 	}
 
+	// Лимиты арендатора (сумма - в валюте заказа)
+	if err := tenant.CheckOrder(order); err != nil {
+		uc.logger.Warn("Order rejected by tenant limits", "error", err, "order_id", order.ID, "tenant_id", order.TenantID)
+		return nil, fmt.Errorf("order validation failed: %w", err)
+	}
+
+	// Проверка наличия товаров на складе
+	if uc.inventory != nil {
+		if err := uc.inventory.CheckAvailability(ctx, order); err != nil {
+			uc.logger.Warn("Order rejected: out of stock", "error", err, "order_id", order.ID)
+			return nil, fmt.Errorf("stock check failed: %w", err)
+		}
+	}
+
+	// Фиксация итога в отчетной валюте
+	if uc.currencies != nil {
+		if err := uc.currencies.ConvertToBase(ctx, order); err != nil {
+			uc.logger.Error("Failed to convert order total", "error", err, "order_id", order.ID, "currency", order.Currency)
+			return nil, fmt.Errorf("currency conversion failed: %w", err)
+		}
+	}
+
+	// Оценка риска: подозрительные заказы задерживаются до проверки
+	if uc.riskEngine != nil {
+		if err := uc.assessRisk(ctx, order); err != nil {
+			uc.logger.Error("Risk assessment failed", "error", err, "order_id", order.ID)
+			return nil, fmt.Errorf("risk assessment failed: %w", err)
+		}
+	}
+
 	// Сохранение заказа в базе данных
-	if err := uc.orderRepo.Create(ctx, order); err != nil {
+	if err := uc.saveOrder(ctx, order, resolution); err != nil {
+		var stockErr entities.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			uc.logger.Warn("Order rejected: out of stock", "error", err, "order_id", order.ID)
+			return nil, fmt.Errorf("stock reservation failed: %w", err)
+		}
 		uc.logger.Error("Failed to create order in database", "error", err, "order_id", order.ID)
 		return nil, fmt.Errorf("failed to save order: %w", err)
 	}
 
+	if resolution != nil && resolution.New != nil {
+		uc.logger.Info("Customer created from order", "customer_id", resolution.New.ID, "order_id", order.ID)
+	}
+
 	uc.logger.Info("Order created successfully",
 		"order_id", order.ID,
 		"customer_id", order.CustomerID,
 		"total_amount", order.TotalAmount,
+		"discount_amount", order.Discount,
+		"tax_amount", order.TaxAmount,
+		"shipping_amount", order.ShippingAmount,
 		"items_count", len(order.Items))
 
 	// Публикация события в Kafka
 	event := order.ToEvent(entities.EventOrderCreated)
+	if len(order.Discounts) > 0 {
+		event.Data["subtotal"] = order.Subtotal
+		event.Data["discount_amount"] = order.Discount
+		event.Data["coupon_codes"] = order.CouponCodes()
+	}
 	if err := uc.publisher.PublishOrderEvent(ctx, event); err != nil {
 		// Событие не критично, логируем ошибку но не возвращаем её
 		uc.logger.Error("Failed to publish order created event",
@@ -163,19 +326,107 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, req *CreateOrderReque
 			"event_id", event.EventID)
 	}
 
+	if order.Status == entities.OrderStatusOnHold {
+		uc.publishHeldEvent(ctx, order)
+	}
+
 	return &CreateOrderResponse{
 		Order:   order,
 		Message: "Order created successfully",
 	}, nil
 }
 
+// saveOrder сохраняет заказ, а нового клиента - в той же транзакции
+func (uc *CreateOrderUseCase) saveOrder(ctx context.Context, order *entities.Order, resolution *CustomerResolution) error {
+	if resolution != nil && resolution.New != nil {
+		return uc.orderRepo.CreateWithCustomer(ctx, order, resolution.New)
+	}
+	return uc.orderRepo.Create(ctx, order)
+}
+
+// applyShipping рассчитывает стоимость доставки выбранным способом.
+// Заказ без способа и адреса доставки (например, цифровые товары) оформляется без доставки.
+func (uc *CreateOrderUseCase) applyShipping(order *entities.Order, method entities.ShippingMethod, items []CreateOrderItemRequest) error {
+	if uc.shipping == nil {
+		if method != "" {
+			return entities.NewValidationError("shipping methods are not configured")
+		}
+		return nil
+	}
+
+	if method == "" {
+		if order.ShippingAddress == nil {
+			return nil
+		}
+		method = entities.ShippingMethodStandard
+	}
+
+	if !strings.EqualFold(order.Currency, uc.shipping.Currency()) {
+		return entities.NewValidationError("shipping rates are not available in %s", order.Currency)
+	}
+
+	quote, err := uc.shipping.Quote(method, order.ShippingAddress, shippingParcel(items))
+	if err != nil {
+		return err
+	}
+
+	order.ApplyShipping(quote)
+	return nil
+}
+
+// assessRisk оценивает риск заказа и при превышении порога переводит его в on_hold
+func (uc *CreateOrderUseCase) assessRisk(ctx context.Context, order *entities.Order) error {
+	assessment, err := uc.riskEngine.Assess(ctx, order)
+	if err != nil {
+		return err
+	}
+	order.RiskAssessment = assessment
+
+	if !assessment.ShouldHold() {
+		return nil
+	}
+
+	if _, err := order.TransitionTo(uc.stateMachines.ForOrder(order), entities.OrderStatusOnHold); err != nil {
+		// Рискованный заказ, который нельзя задержать, отклоняется, а не остается pending
+		uc.logger.Error("Cannot hold risky order",
+			"error", err,
+			"order_id", order.ID,
+			"risk_score", assessment.Score)
+		return entities.NewValidationError("order requires review but cannot be held: %v", err)
+	}
+
+	uc.logger.Warn("Order held for review",
+		"order_id", order.ID,
+		"risk_score", assessment.Score,
+		"triggered_rules", assessment.TriggeredRuleNames())
+
+	return nil
+}
+
+// publishHeldEvent публикует событие задержки заказа со сработавшими правилами
+func (uc *CreateOrderUseCase) publishHeldEvent(ctx context.Context, order *entities.Order) {
+	event := order.ToEvent(entities.EventOrderHeld)
+	if order.RiskAssessment != nil {
+		event.Data["risk_score"] = order.RiskAssessment.Score
+		event.Data["risk_cutoff"] = order.RiskAssessment.Cutoff
+		event.Data["triggered_rules"] = order.RiskAssessment.Triggered
+	}
+
+	if err := uc.publisher.PublishOrderEvent(ctx, event); err != nil {
+		uc.logger.Error("Failed to publish order held event",
+			"error", err,
+			"order_id", order.ID,
+			"event_id", event.EventID)
+	}
+}
+
 // validateRequest валидирует входящий запрос
 func (uc *CreateOrderUseCase) validateRequest(req *CreateOrderRequest) error {
 	if req == nil {
 		return entities.NewValidationError("request cannot be nil")
 	}
 
-	if req.CustomerID == uuid.Nil {
+	if req.CustomerID == uuid.Nil && uc.customers == nil {
 		return entities.NewValidationError("customer_id is required")
 	}
 
@@ -188,6 +439,12 @@ func (uc *CreateOrderUseCase) validateRequest(req *CreateOrderRequest) error {
 		return entities.NewValidationError("invalid email format")
 	}
 
+	if req.Currency != "" {
+		if err := entities.ValidateCurrency(entities.NormalizeCurrency(req.Currency)); err != nil {
+			return err
+		}
+	}
+
 	if len(req.Items) == 0 {
 		return entities.NewValidationError("at least one item is required")
 	}
@@ -197,15 +454,23 @@ func (uc *CreateOrderUseCase) validateRequest(req *CreateOrderRequest) error {
 		if item.ProductID == uuid.Nil {
 			return entities.NewValidationError("item %d: product_id is required", i)
 		}
-		if item.Name == "" {
-			return entities.NewValidationError("item %d: name is required", i)
-		}
-		if item.Price <= 0 {
-			return entities.NewValidationError("item %d: price must be greater than 0", i)
+		// С каталогом название и цена необязательны: они берутся из товара
+		if uc.catalog == nil {
+			if item.Name == "" {
+				return entities.NewValidationError("item %d: name is required", i)
+			}
+			if item.Price <= 0 {
+				return entities.NewValidationError("item %d: price must be greater than 0", i)
+			}
+		} else if item.Price < 0 {
+			return entities.NewValidationError("item %d: price cannot be negative", i)
 		}
 		if item.Quantity <= 0 {
 			return entities.NewValidationError("item %d: quantity must be greater than 0", i)
 		}
+		if item.Weight < 0 {
+			return entities.NewValidationError("item %d: weight cannot be negative", i)
+		}
 	}
 
 	return nil
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// createdOrderRepository запоминает сохраненные заказы и клиентов, созданных вместе с ними
+type createdOrderRepository struct {
+	repositories.OrderRepository
+	created   []*entities.Order
+	customers []*entities.Customer
+}
+
+func (r *createdOrderRepository) Create(_ context.Context, order *entities.Order) error {
+	r.created = append(r.created, order)
+	return nil
+}
+
+func (r *createdOrderRepository) CreateWithCustomer(ctx context.Context, order *entities.Order, customer *entities.Customer) error {
+	r.customers = append(r.customers, customer)
+	return r.Create(ctx, order)
+}
+
+func riskyOrderRequest(channel string) *CreateOrderRequest {
+	return &CreateOrderRequest{
+		CustomerID: uuid.New(),
+		Email:      "buyer@example.com",
+		Items:      []CreateOrderItemRequest{{ProductID: uuid.New(), Name: "Laptop", Price: 5000, Quantity: 1}},
+		Metadata:   map[string]interface{}{entities.MetadataSalesChannel: channel},
+	}
+}
+
+func TestCreateOrder_RiskyOrderHeldOrRejected(t *testing.T) {
+	digital, err := entities.NewStateMachine(entities.StateMachineDefinition{
+		Initial: entities.OrderStatusPending,
+		States: []entities.StateDefinition{
+			{Name: entities.OrderStatusPending},
+			{Name: entities.OrderStatusDelivered, Final: true},
+		},
+		Transitions: []entities.TransitionDefinition{
+			{From: entities.OrderStatusPending, To: entities.OrderStatusDelivered, Event: entities.EventOrderDelivered},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	registry := entities.NewStateMachineRegistry(entities.DefaultStateMachine(), map[string]*entities.StateMachine{"digital": digital})
+
+	repo := &createdOrderRepository{}
+	publisher := &recordingPublisher{}
+	engine := NewRiskEngine(50, AmountRiskRule{MaxAmount: 1000, Score: 50})
+	uc := NewCreateOrderUseCase(repo, publisher, engine, nil, nil, nil, nil, nil, nil, nil, registry, nopLogger{})
+
+	resp, err := uc.Execute(context.Background(), riskyOrderRequest("web"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Order.Status != entities.OrderStatusOnHold {
+		t.Fatalf("expected risky order on hold, got %s", resp.Order.Status)
+	}
+
+	// Машина канала digital не умеет задерживать заказы
+	if _, err := uc.Execute(context.Background(), riskyOrderRequest("digital")); err == nil {
+		t.Fatal("expected risky digital order to be rejected")
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected only the held order saved, got %d", len(repo.created))
+	}
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// CurrencyService переводит суммы заказов в отчетную валюту
+type CurrencyService struct {
+	rateRepo          repositories.ExchangeRateRepository
+	reportingCurrency string
+	logger            Logger
+}
+
+// NewCurrencyService создает сервис пересчета в отчетную валюту
+func NewCurrencyService(rateRepo repositories.ExchangeRateRepository, reportingCurrency string, logger Logger) *CurrencyService {
+	return &CurrencyService{
+		rateRepo:          rateRepo,
+		reportingCurrency: entities.NormalizeCurrency(reportingCurrency),
+		logger:            logger,
+	}
+}
+
+// ReportingCurrency возвращает отчетную валюту
+func (s *CurrencyService) ReportingCurrency() string {
+	return s.reportingCurrency
+}
+
+// ConvertToBase фиксирует итог заказа в отчетной валюте по курсу на момент создания
+func (s *CurrencyService) ConvertToBase(ctx context.Context, order *entities.Order) error {
+	rate := entities.IdentityRate(order.Currency)
+
+	if order.Currency != s.reportingCurrency {
+		found, err := s.rateRepo.GetRate(ctx, order.Currency, s.reportingCurrency, order.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to get exchange rate: %w", err)
+		}
+		if found == nil {
+			return entities.NewValidationError("no exchange rate from %s to %s", order.Currency, s.reportingCurrency)
+		}
+		rate = found
+	}
+
+	return order.ApplyExchangeRate(rate)
+}
+
+// ImportRates сохраняет курсы, загруженные из файла
+func (s *CurrencyService) ImportRates(ctx context.Context, rates []*entities.ExchangeRate) error {
+	for _, rate := range rates {
+		if err := rate.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.rateRepo.Upsert(ctx, rates); err != nil {
+		return fmt.Errorf("failed to save exchange rates: %w", err)
+	}
+
+	s.logger.Info("Exchange rates imported",
+		"count", len(rates),
+		"reporting_currency", s.reportingCurrency)
+
+	return nil
+}
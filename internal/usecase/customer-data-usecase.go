@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// maxErasureReasonLength ограничение длины причины удаления данных
+const maxErasureReasonLength = 500
+
+// EraseCustomerRequest представляет запрос на удаление персональных данных клиента
+type EraseCustomerRequest struct {
+	CustomerID uuid.UUID `json:"-"`
+	Reason     string    `json:"reason"`
+}
+
+// EraseCustomerResponse представляет результат удаления персональных данных
+type EraseCustomerResponse struct {
+	Erasure *entities.CustomerErasure `json:"erasure"`
+	Message string                    `json:"message"`
+}
+
+// EraseCustomerUseCase обезличивает персональные данные клиента (GDPR) и публикует
+// событие customer.erased, чтобы получатели событий удалили свои копии
+type EraseCustomerUseCase struct {
+	customerRepo repositories.CustomerRepository
+	publisher    EventPublisher
+	logger       Logger
+}
+
+// NewEraseCustomerUseCase создает новый use case удаления данных клиента
+func NewEraseCustomerUseCase(customerRepo repositories.CustomerRepository, publisher EventPublisher, logger Logger) *EraseCustomerUseCase {
+	return &EraseCustomerUseCase{
+		customerRepo: customerRepo,
+		publisher:    publisher,
+		logger:       logger,
+	}
+}
+
+// Execute обезличивает данные клиента. Повторный вызов безопасен: уже обезличенные
+// строки не меняются, а событие публикуется снова
+func (uc *EraseCustomerUseCase) Execute(ctx context.Context, req *EraseCustomerRequest) (*EraseCustomerResponse, error) {
+	if req == nil || req.CustomerID == uuid.Nil {
+		return nil, entities.NewValidationError("customer_id is required")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) > maxErasureReasonLength {
+		return nil, entities.NewValidationError("reason must not exceed %d characters", maxErasureReasonLength)
+	}
+
+	erasure := entities.NewCustomerErasure(req.CustomerID, reason)
+	if err := uc.customerRepo.Erase(ctx, erasure); err != nil {
+		return nil, fmt.Errorf("failed to erase customer data: %w", err)
+	}
+
+	uc.logger.Info("Customer data erased",
+		"customer_id", erasure.CustomerID,
+		"erasure_id", erasure.ID,
+		"orders", erasure.OrdersAnonymized,
+		"addresses", erasure.AddressesAnonymized)
+
+	event := erasure.ToEvent()
+	event.TenantID = entities.TenantIDFromContext(ctx)
+	if err := uc.publisher.PublishOrderEvent(ctx, event); err != nil {
+		// Данные уже обезличены; повторный запрос опубликует событие снова
+		uc.logger.Error("Failed to publish customer erased event",
+			"error", err,
+			"customer_id", erasure.CustomerID,
+			"event_id", event.EventID)
+		return &EraseCustomerResponse{
+			Erasure: erasure,
+			Message: "Customer data erased, but the customer.erased event was not published; repeat the request",
+		}, nil
+	}
+
+	return &EraseCustomerResponse{
+		Erasure: erasure,
+		Message: "Customer data erased",
+	}, nil
+}
+
+// CustomerDataExport выгрузка всех данных клиента по запросу субъекта данных
+type CustomerDataExport struct {
+	CustomerID uuid.UUID                   `json:"customer_id"`
+	ExportedAt time.Time                   `json:"exported_at"`
+	Customer   *entities.Customer          `json:"customer,omitempty"`
+	Orders     []*entities.Order           `json:"orders"`
+	Erasures   []*entities.CustomerErasure `json:"erasures"`
+}
+
+// ExportCustomerDataUseCase собирает данные клиента и все его заказы в один JSON документ
+type ExportCustomerDataUseCase struct {
+	customerRepo repositories.CustomerRepository
+	orderRepo    repositories.OrderRepository
+	logger       Logger
+}
+
+// NewExportCustomerDataUseCase создает новый use case выгрузки данных клиента
+func NewExportCustomerDataUseCase(customerRepo repositories.CustomerRepository, orderRepo repositories.OrderRepository, logger Logger) *ExportCustomerDataUseCase {
+	return &ExportCustomerDataUseCase{
+		customerRepo: customerRepo,
+		orderRepo:    orderRepo,
+		logger:       logger,
+	}
+}
+
+// Execute выгружает клиента, все его заказы с позициями и адресами и журнал удалений.
+// Клиент без записи в customers выгружается по заказам
+func (uc *ExportCustomerDataUseCase) Execute(ctx context.Context, customerID uuid.UUID) (*CustomerDataExport, error) {
+	if customerID == uuid.Nil {
+		return nil, entities.NewValidationError("customer_id is required")
+	}
+
+	export := &CustomerDataExport{
+		CustomerID: customerID,
+		ExportedAt: time.Now().UTC(),
+		Orders:     make([]*entities.Order, 0),
+	}
+
+	customer, err := uc.customerRepo.GetByID(ctx, customerID)
+	var notFoundErr entities.CustomerNotFoundError
+	switch {
+	case err == nil:
+		export.Customer = customer
+	case !errors.As(err, &notFoundErr):
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	filters := repositories.OrderFilters{CustomerID: &customerID, SortBy: "created_at", SortOrder: "asc"}
+	err = uc.orderRepo.Stream(ctx, filters, 0, func(order *entities.Order) error {
+		export.Orders = append(export.Orders, order)
+		return nil
+	})
+	if err != nil {
+		uc.logger.Error("Failed to export customer orders", "error", err, "customer_id", customerID)
+		return nil, fmt.Errorf("failed to export customer orders: %w", err)
+	}
+
+	if export.Customer == nil && len(export.Orders) == 0 {
+		return nil, entities.NewCustomerNotFoundError(customerID.String())
+	}
+
+	export.Erasures, err = uc.customerRepo.ListErasures(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer erasures: %w", err)
+	}
+
+	uc.logger.Info("Customer data exported", "customer_id", customerID, "orders", len(export.Orders))
+	return export, nil
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// CustomerService определяет клиентов оформляемых заказов
+type CustomerService struct {
+	customerRepo repositories.CustomerRepository
+	logger       Logger
+}
+
+// NewCustomerService создает сервис клиентов
+func NewCustomerService(customerRepo repositories.CustomerRepository, logger Logger) *CustomerService {
+	return &CustomerService{
+		customerRepo: customerRepo,
+		logger:       logger,
+	}
+}
+
+// CustomerResolution клиент, на которого оформляется заказ
+type CustomerResolution struct {
+	// CustomerID ID клиента заказа
+	CustomerID uuid.UUID
+	// Customer существующий клиент; заполняется, только если запрос выполняется от его имени
+	Customer *entities.Customer
+	// New новый клиент, который сохраняется в одной транзакции с заказом
+	New *entities.Customer
+}
+
+// ResolveCustomer определяет клиента заказа, ничего не сохраняя. Без customerID
+// заказ оформляется на аутентифицированного клиента, иначе клиент ищется по email:
+// новый создается, только если email свободен, а на чужой email оформляется гостевой заказ.
+// Клиент с переданным customerID создается, если его нет и email свободен.
+func (s *CustomerService) ResolveCustomer(ctx context.Context, customerID uuid.UUID, email string) (*CustomerResolution, error) {
+	authenticatedID, authenticated := entities.CustomerIDFromContext(ctx)
+	if authenticated {
+		if customerID != uuid.Nil && customerID != authenticatedID {
+			return nil, entities.NewForbiddenError("orders can only be placed for the authenticated customer")
+		}
+		customerID = authenticatedID
+	}
+
+	if customerID == uuid.Nil {
+		existing, err := s.customerRepo.GetByEmail(ctx, email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find customer: %w", err)
+		}
+		if existing != nil {
+			s.logger.Info("Email belongs to an existing customer, order is placed as a guest order")
+			return &CustomerResolution{CustomerID: uuid.New()}, nil
+		}
+		return s.newCustomer(entities.NewCustomer(email, ""))
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, customerID)
+	if err == nil {
+		resolution := &CustomerResolution{CustomerID: customerID}
+		if authenticated {
+			resolution.Customer = customer
+		}
+		return resolution, nil
+	}
+	var notFoundErr entities.CustomerNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	existing, err := s.customerRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find customer: %w", err)
+	}
+	if existing != nil {
+		s.logger.Warn("Customer not created: email belongs to another customer", "customer_id", customerID)
+		return &CustomerResolution{CustomerID: customerID}, nil
+	}
+
+	customer = entities.NewCustomer(email, "")
+	customer.ID = customerID
+	return s.newCustomer(customer)
+}
+
+// newCustomer проверяет нового клиента, который будет сохранен вместе с заказом
+func (s *CustomerService) newCustomer(customer *entities.Customer) (*CustomerResolution, error) {
+	if err := customer.Validate(); err != nil {
+		return nil, err
+	}
+	return &CustomerResolution{CustomerID: customer.ID, New: customer}, nil
+}
+
+// CreateCustomerRequest представляет запрос на создание клиента
+type CreateCustomerRequest struct {
+	Email                  string                `json:"email" validate:"required,email"`
+	Name                   string                `json:"name"`
+	DefaultShippingAddress *CreateAddressRequest `json:"default_shipping_address,omitempty"`
+	DefaultBillingAddress  *CreateAddressRequest `json:"default_billing_address,omitempty"`
+}
+
+// CustomerResponse представляет ответ с клиентом и статистикой его заказов
+type CustomerResponse struct {
+	Customer *entities.Customer      `json:"customer"`
+	Stats    *entities.CustomerStats `json:"stats,omitempty"`
+	Message  string                  `json:"message,omitempty"`
+}
+
+// CreateCustomerUseCase представляет use case создания клиента
+type CreateCustomerUseCase struct {
+	customerRepo repositories.CustomerRepository
+	logger       Logger
+}
+
+// NewCreateCustomerUseCase создает новый use case для создания клиента
+func NewCreateCustomerUseCase(customerRepo repositories.CustomerRepository, logger Logger) *CreateCustomerUseCase {
+	return &CreateCustomerUseCase{
+		customerRepo: customerRepo,
+		logger:       logger,
+	}
+}
+
+// Execute выполняет создание клиента
+func (uc *CreateCustomerUseCase) Execute(ctx context.Context, req *CreateCustomerRequest) (*CustomerResponse, error) {
+	if req == nil {
+		return nil, entities.NewValidationError("request cannot be nil")
+	}
+	if !validateEmail(strings.TrimSpace(req.Email)) {
+		return nil, entities.NewValidationError("invalid email format")
+	}
+
+	customer := entities.NewCustomer(req.Email, req.Name)
+	customer.DefaultShippingAddress = req.DefaultShippingAddress.toAddress("shipping")
+	customer.DefaultBillingAddress = req.DefaultBillingAddress.toAddress("billing")
+
+	if err := customer.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := uc.customerRepo.Create(ctx, customer); err != nil {
+		uc.logger.Error("Failed to create customer", "error", err, "customer_id", customer.ID)
+		return nil, fmt.Errorf("failed to save customer: %w", err)
+	}
+
+	uc.logger.Info("Customer created successfully", "customer_id", customer.ID)
+
+	return &CustomerResponse{
+		Customer: customer,
+		Message:  "Customer created successfully",
+	}, nil
+}
+
+// GetCustomerUseCase представляет use case получения клиента со статистикой
+type GetCustomerUseCase struct {
+	customerRepo repositories.CustomerRepository
+	logger       Logger
+}
+
+// NewGetCustomerUseCase создает новый use case для получения клиента
+func NewGetCustomerUseCase(customerRepo repositories.CustomerRepository, logger Logger) *GetCustomerUseCase {
+	return &GetCustomerUseCase{
+		customerRepo: customerRepo,
+		logger:       logger,
+	}
+}
+
+// Execute получает клиента и статистику его заказов за все время
+func (uc *GetCustomerUseCase) Execute(ctx context.Context, customerID uuid.UUID) (*CustomerResponse, error) {
+	customer, err := uc.customerRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	stats, err := uc.customerRepo.Stats(ctx, customerID)
+	if err != nil {
+		uc.logger.Error("Failed to get customer stats", "error", err, "customer_id", customerID)
+		return nil, fmt.Errorf("failed to get customer stats: %w", err)
+	}
+
+	return &CustomerResponse{Customer: customer, Stats: stats}, nil
+}
+
+// ListCustomerOrdersRequest представляет запрос заказов клиента
+type ListCustomerOrdersRequest struct {
+	CustomerID uuid.UUID `json:"customer_id"`
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+}
+
+// ListCustomerOrdersUseCase представляет use case получения заказов клиента
+type ListCustomerOrdersUseCase struct {
+	orderRepo repositories.OrderRepository
+	logger    Logger
+}
+
+// NewListCustomerOrdersUseCase создает новый use case для получения заказов клиента
+func NewListCustomerOrdersUseCase(orderRepo repositories.OrderRepository, logger Logger) *ListCustomerOrdersUseCase {
+	return &ListCustomerOrdersUseCase{
+		orderRepo: orderRepo,
+		logger:    logger,
+	}
+}
+
+// Execute получает заказы клиента, начиная с последних
+func (uc *ListCustomerOrdersUseCase) Execute(ctx context.Context, req *ListCustomerOrdersRequest) (*ListOrdersResponse, error) {
+	if req == nil || req.CustomerID == uuid.Nil {
+		return nil, entities.NewValidationError("customer_id is required")
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	orders, err := uc.orderRepo.GetByCustomerID(ctx, req.CustomerID, req.Limit, req.Offset)
+	if err != nil {
+		uc.logger.Error("Failed to get customer orders", "error", err, "customer_id", req.CustomerID)
+		return nil, fmt.Errorf("failed to get customer orders: %w", err)
+	}
+
+	total, err := uc.orderRepo.Count(ctx, repositories.OrderFilters{CustomerID: &req.CustomerID})
+	if err != nil {
+		uc.logger.Error("Failed to count customer orders", "error", err, "customer_id", req.CustomerID)
+		return nil, fmt.Errorf("failed to count customer orders: %w", err)
+	}
+
+	return &ListOrdersResponse{
+		Orders:     orders,
+		TotalCount: total,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	}, nil
+}
+
+// toAddress преобразует адрес запроса в адрес сущности; nil для пустого адреса
+func (a *CreateAddressRequest) toAddress(addressType string) *entities.Address {
+	if a == nil {
+		return nil
+	}
+	return &entities.Address{
+		Type:    addressType,
+		Street:  a.Street,
+		City:    a.City,
+		State:   a.State,
+		Country: a.Country,
+		ZipCode: a.ZipCode,
+	}
+}
+
+// customerDefaultAddress копирует адрес клиента по умолчанию для нового заказа
+func customerDefaultAddress(address *entities.Address) *entities.Address {
+	copied := *address
+	return &copied
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+type stubCustomerRepository struct {
+	repositories.CustomerRepository
+	erased []*entities.CustomerErasure
+	err    error
+}
+
+func (r *stubCustomerRepository) Erase(_ context.Context, erasure *entities.CustomerErasure) error {
+	if r.err != nil {
+		return r.err
+	}
+	erasure.OrdersAnonymized = 2
+	r.erased = append(r.erased, erasure)
+	return nil
+}
+
+type recordingPublisher struct {
+	events []*entities.OrderEvent
+	err    error
+}
+
+func (p *recordingPublisher) PublishOrderEvent(_ context.Context, event *entities.OrderEvent) error {
+	p.events = append(p.events, event)
+	return p.err
+}
+
+func TestEraseCustomerPublishesEvent(t *testing.T) {
+	repo := &stubCustomerRepository{}
+	publisher := &recordingPublisher{}
+	customerID := uuid.New()
+
+	resp, err := NewEraseCustomerUseCase(repo, publisher, nopLogger{}).Execute(context.Background(),
+		&EraseCustomerRequest{CustomerID: customerID, Reason: " GDPR request "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.erased) != 1 || resp.Erasure.Reason != "GDPR request" || resp.Erasure.OrdersAnonymized != 2 {
+		t.Fatalf("unexpected erasure: %+v", resp.Erasure)
+	}
+	if len(publisher.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(publisher.events))
+	}
+	event := publisher.events[0]
+	if event.EventType != entities.EventCustomerErased || event.CustomerID != customerID ||
+		event.EventID != resp.Erasure.EventID || event.OrderID != uuid.Nil {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestEraseCustomerErrors(t *testing.T) {
+	publisher := &recordingPublisher{}
+
+	notFound := &stubCustomerRepository{err: entities.NewCustomerNotFoundError("x")}
+	_, err := NewEraseCustomerUseCase(notFound, publisher, nopLogger{}).Execute(context.Background(),
+		&EraseCustomerRequest{CustomerID: uuid.New()})
+	var notFoundErr entities.CustomerNotFoundError
+	if !errors.As(err, &notFoundErr) || len(publisher.events) != 0 {
+		t.Fatalf("expected not found without event, got %v", err)
+	}
+
+	// Данные уже обезличены: ошибка публикации не делает запрос неуспешным
+	publisher.err = errors.New("kafka is down")
+	resp, err := NewEraseCustomerUseCase(&stubCustomerRepository{}, publisher, nopLogger{}).Execute(context.Background(),
+		&EraseCustomerRequest{CustomerID: uuid.New()})
+	if err != nil || resp.Erasure == nil {
+		t.Fatalf("expected erasure despite publish error, got %v", err)
+	}
+}
+
+func TestErasedEmail(t *testing.T) {
+	customerID := uuid.New()
+	email := entities.ErasedEmail(customerID)
+	if !entities.IsErasedEmail(email) || entities.IsErasedEmail("user@example.com") {
+		t.Errorf("unexpected erased email detection for %s", email)
+	}
+	if entities.NormalizeEmail(email) != email {
+		t.Errorf("erased email must be canonical: %s", email)
+	}
+}
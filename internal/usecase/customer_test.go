@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// memoryCustomerRepository хранит клиентов в памяти; email уникален без учета регистра
+type memoryCustomerRepository struct {
+	repositories.CustomerRepository
+	customers map[uuid.UUID]*entities.Customer
+	stats     map[uuid.UUID]*entities.CustomerStats
+	statsErr  error
+}
+
+func newMemoryCustomerRepository(customers ...*entities.Customer) *memoryCustomerRepository {
+	repo := &memoryCustomerRepository{
+		customers: make(map[uuid.UUID]*entities.Customer),
+		stats:     make(map[uuid.UUID]*entities.CustomerStats),
+	}
+	for _, customer := range customers {
+		repo.customers[customer.ID] = customer
+	}
+	return repo
+}
+
+func (r *memoryCustomerRepository) Create(ctx context.Context, customer *entities.Customer) error {
+	if existing, _ := r.GetByEmail(ctx, customer.Email); existing != nil || r.customers[customer.ID] != nil {
+		return entities.NewValidationError("customer with email %s or ID %s already exists", customer.Email, customer.ID)
+	}
+	r.customers[customer.ID] = customer
+	return nil
+}
+
+func (r *memoryCustomerRepository) GetByID(_ context.Context, id uuid.UUID) (*entities.Customer, error) {
+	customer, ok := r.customers[id]
+	if !ok {
+		return nil, entities.NewCustomerNotFoundError(id.String())
+	}
+	return customer, nil
+}
+
+func (r *memoryCustomerRepository) GetByEmail(_ context.Context, email string) (*entities.Customer, error) {
+	for _, customer := range r.customers {
+		if strings.EqualFold(customer.Email, email) {
+			return customer, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryCustomerRepository) Stats(_ context.Context, customerID uuid.UUID) (*entities.CustomerStats, error) {
+	if r.statsErr != nil {
+		return nil, r.statsErr
+	}
+	if stats, ok := r.stats[customerID]; ok {
+		return stats, nil
+	}
+	return &entities.CustomerStats{}, nil
+}
+
+func customerOrderRequest(customerID uuid.UUID, email string) *CreateOrderRequest {
+	return &CreateOrderRequest{
+		CustomerID: customerID,
+		Email:      email,
+		Items:      []CreateOrderItemRequest{{ProductID: uuid.New(), Name: "Book", Price: 20, Quantity: 1}},
+	}
+}
+
+func TestCreateOrder_CreatesCustomerFromEmail(t *testing.T) {
+	customers := newMemoryCustomerRepository()
+	orders := &createdOrderRepository{}
+	uc := NewCreateOrderUseCase(orders, &recordingPublisher{}, nil, NewCustomerService(customers, nopLogger{}),
+		nil, nil, nil, nil, nil, nil, nil, nopLogger{})
+
+	resp, err := uc.Execute(context.Background(), customerOrderRequest(uuid.Nil, "New.Buyer@Example.com"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(orders.customers) != 1 {
+		t.Fatalf("expected one customer saved with the order, got %d", len(orders.customers))
+	}
+	customer := orders.customers[0]
+	if resp.Order.CustomerID != customer.ID {
+		t.Fatalf("expected order %s assigned to the created customer", resp.Order.ID)
+	}
+	if customer.Email != "new.buyer@example.com" {
+		t.Errorf("expected normalized email, got %s", customer.Email)
+	}
+}
+
+func TestCreateOrder_RejectedOrderCreatesNoCustomer(t *testing.T) {
+	customers := newMemoryCustomerRepository()
+	orders := &createdOrderRepository{}
+	uc := NewCreateOrderUseCase(orders, &recordingPublisher{}, nil, NewCustomerService(customers, nopLogger{}),
+		nil, nil, nil, nil, nil, nil, nil, nopLogger{})
+
+	req := customerOrderRequest(uuid.Nil, "new.buyer@example.com")
+	req.CouponCodes = []string{"SPRING"}
+	if _, err := uc.Execute(context.Background(), req); err == nil {
+		t.Fatal("expected coupon rejection")
+	}
+	if len(orders.customers) != 0 || len(customers.customers) != 0 {
+		t.Fatalf("expected no customers, got %d saved with orders and %d in the repository",
+			len(orders.customers), len(customers.customers))
+	}
+}
+
+func TestCreateOrder_DoesNotAttachExistingCustomerByEmail(t *testing.T) {
+	existing := entities.NewCustomer("buyer@example.com", "Buyer")
+	existing.DefaultShippingAddress = &entities.Address{Type: "shipping", Street: "Main St", City: "Minsk", Country: "BY", ZipCode: "220030"}
+	customers := newMemoryCustomerRepository(existing)
+	orders := &createdOrderRepository{}
+	uc := NewCreateOrderUseCase(orders, &recordingPublisher{}, nil, NewCustomerService(customers, nopLogger{}),
+		nil, nil, nil, nil, nil, nil, nil, nopLogger{})
+
+	for _, customerID := range []uuid.UUID{uuid.Nil, existing.ID} {
+		resp, err := uc.Execute(context.Background(), customerOrderRequest(customerID, "Buyer@Example.com"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if customerID == uuid.Nil && resp.Order.CustomerID == existing.ID {
+			t.Errorf("expected guest order, got existing customer %s", existing.ID)
+		}
+		if resp.Order.ShippingAddress != nil {
+			t.Errorf("expected no default address for unauthenticated caller, got %+v", resp.Order.ShippingAddress)
+		}
+	}
+
+	if len(orders.customers) != 0 {
+		t.Fatalf("expected no new customers, got %d", len(orders.customers))
+	}
+}
+
+func TestCreateOrder_AuthenticatedCustomerGetsDefaultAddresses(t *testing.T) {
+	existing := entities.NewCustomer("buyer@example.com", "Buyer")
+	existing.DefaultShippingAddress = &entities.Address{Type: "shipping", Street: "Main St", City: "Minsk", Country: "BY", ZipCode: "220030"}
+	customers := newMemoryCustomerRepository(existing)
+	orders := &createdOrderRepository{}
+	uc := NewCreateOrderUseCase(orders, &recordingPublisher{}, nil, NewCustomerService(customers, nopLogger{}),
+		nil, nil, nil, nil, nil, nil, nil, nopLogger{})
+	ctx := entities.ContextWithCustomerID(context.Background(), existing.ID)
+
+	resp, err := uc.Execute(ctx, customerOrderRequest(uuid.Nil, "buyer@example.com"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Order.CustomerID != existing.ID {
+		t.Fatalf("expected authenticated customer %s, got %s", existing.ID, resp.Order.CustomerID)
+	}
+	if resp.Order.ShippingAddress == nil || resp.Order.ShippingAddress.City != "Minsk" {
+		t.Errorf("expected default shipping address, got %+v", resp.Order.ShippingAddress)
+	}
+
+	if _, err := uc.Execute(ctx, customerOrderRequest(uuid.New(), "buyer@example.com")); !errors.As(err, &entities.ForbiddenError{}) {
+		t.Errorf("expected forbidden for another customer, got %v", err)
+	}
+}
+
+func TestGetCustomer_LifetimeStats(t *testing.T) {
+	customer := entities.NewCustomer("buyer@example.com", "Buyer")
+	repo := newMemoryCustomerRepository(customer)
+	first := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo.stats[customer.ID] = &entities.CustomerStats{OrderCount: 3, TotalSpent: 150.5, FirstOrderAt: &first, LastOrderAt: &last}
+	uc := NewGetCustomerUseCase(repo, nopLogger{})
+
+	resp, err := uc.Execute(context.Background(), customer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Customer.ID != customer.ID || resp.Stats.OrderCount != 3 || resp.Stats.TotalSpent != 150.5 {
+		t.Fatalf("unexpected response: %+v %+v", resp.Customer, resp.Stats)
+	}
+	if !resp.Stats.FirstOrderAt.Equal(first) || !resp.Stats.LastOrderAt.Equal(last) {
+		t.Errorf("unexpected order dates: %v - %v", resp.Stats.FirstOrderAt, resp.Stats.LastOrderAt)
+	}
+
+	if _, err := uc.Execute(context.Background(), uuid.New()); !errors.As(err, &entities.CustomerNotFoundError{}) {
+		t.Errorf("expected customer not found, got %v", err)
+	}
+
+	repo.statsErr = errors.New("database unavailable")
+	if _, err := uc.Execute(context.Background(), customer.ID); err == nil {
+		t.Error("expected stats error")
+	}
+}
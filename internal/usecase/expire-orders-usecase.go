@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// ExpiryReason причина отмены просроченного заказа
+const ExpiryReason = "expired"
+
+// ExpiryPolicy определяет время жизни заказа в статусе pending
+type ExpiryPolicy struct {
+	DefaultTTL  time.Duration
+	CurrencyTTL map[string]time.Duration
+	ChannelTTL  map[string]time.Duration
+}
+
+// NewExpiryPolicy создает политику; коды валют в currencyTTL приводятся к верхнему регистру,
+// как валюта заказа в TTLFor
+func NewExpiryPolicy(defaultTTL time.Duration, currencyTTL, channelTTL map[string]time.Duration) ExpiryPolicy {
+	normalized := make(map[string]time.Duration, len(currencyTTL))
+	for currency, ttl := range currencyTTL {
+		normalized[strings.ToUpper(strings.TrimSpace(currency))] = ttl
+	}
+	return ExpiryPolicy{
+		DefaultTTL:  defaultTTL,
+		CurrencyTTL: normalized,
+		ChannelTTL:  channelTTL,
+	}
+}
+
+// TTLFor возвращает TTL для заказа: канал продаж > валюта > значение по умолчанию
+func (p ExpiryPolicy) TTLFor(order *entities.Order) time.Duration {
+	if channel, ok := order.Metadata[entities.MetadataSalesChannel].(string); ok {
+		if ttl, exists := p.ChannelTTL[channel]; exists {
+			return ttl
+		}
+	}
+
+	if ttl, exists := p.CurrencyTTL[strings.ToUpper(order.Currency)]; exists {
+		return ttl
+	}
+
+	return p.DefaultTTL
+}
+
+// MinTTL возвращает минимальный TTL среди всех правил
+func (p ExpiryPolicy) MinTTL() time.Duration {
+	min := p.DefaultTTL
+	for _, ttl := range p.CurrencyTTL {
+		if ttl < min {
+			min = ttl
+		}
+	}
+	for _, ttl := range p.ChannelTTL {
+		if ttl < min {
+			min = ttl
+		}
+	}
+	return min
+}
+
+// ExpirePendingOrdersResponse представляет результат прогона отмены просроченных заказов
+type ExpirePendingOrdersResponse struct {
+	Checked int `json:"checked"`
+	Expired int `json:"expired"`
+	Failed  int `json:"failed"`
+}
+
+// ExpirePendingOrdersUseCase отменяет заказы, слишком долго находящиеся в статусе pending
+type ExpirePendingOrdersUseCase struct {
+	orderRepo      repositories.OrderRepository
+	updateStatusUC *UpdateOrderStatusUseCase
+	policy         ExpiryPolicy
+	batchSize      int
+	logger         Logger
+}
+
+// NewExpirePendingOrdersUseCase создает новый use case для отмены просроченных заказов
+func NewExpirePendingOrdersUseCase(
+	orderRepo repositories.OrderRepository,
+	updateStatusUC *UpdateOrderStatusUseCase,
+	policy ExpiryPolicy,
+	batchSize int,
+	logger Logger,
+) *ExpirePendingOrdersUseCase {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &ExpirePendingOrdersUseCase{
+		orderRepo:      orderRepo,
+		updateStatusUC: updateStatusUC,
+		policy:         policy,
+		batchSize:      batchSize,
+		logger:         logger,
+	}
+}
+
+// Execute находит и отменяет просроченные pending заказы
+func (uc *ExpirePendingOrdersUseCase) Execute(ctx context.Context) (*ExpirePendingOrdersResponse, error) {
+	now := time.Now()
+	cutoff := now.Add(-uc.policy.MinTTL()).Format(time.RFC3339)
+	status := entities.OrderStatusPending
+
+	response := &ExpirePendingOrdersResponse{}
+
+	// Отмененные заказы выпадают из выборки, поэтому смещение растет
+	// только на количество пропущенных (еще не просроченных) заказов
+	offset := 0
+	for {
+		orders, err := uc.orderRepo.List(ctx, repositories.OrderFilters{
+			Status:    &status,
+			DateTo:    &cutoff,
+			Limit:     uc.batchSize,
+			Offset:    offset,
+			SortBy:    "created_at",
+			SortOrder: "asc",
+		})
+		if err != nil {
+			uc.logger.Error("Failed to list pending orders for expiry", "error", err)
+			return response, fmt.Errorf("failed to list pending orders: %w", err)
+		}
+
+		for _, order := range orders {
+			response.Checked++
+
+			ttl := uc.policy.TTLFor(order)
+			if now.Sub(order.CreatedAt) < ttl {
+				offset++
+				continue
+			}
+
+			_, err := uc.updateStatusUC.Execute(ctx, &UpdateOrderStatusRequest{
+				OrderID:   order.ID,
+				NewStatus: entities.OrderStatusCancelled,
+				Reason:    ExpiryReason,
+			})
+			if err != nil {
+				uc.logger.Warn("Failed to expire pending order",
+					"error", err,
+					"order_id", order.ID,
+					"ttl", ttl.String())
+				response.Failed++
+				offset++
+				continue
+			}
+
+			response.Expired++
+		}
+
+		if len(orders) < uc.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if response.Expired > 0 || response.Failed > 0 {
+		uc.logger.Info("Pending orders expiry completed",
+			"checked", response.Checked,
+			"expired", response.Expired,
+			"failed", response.Failed)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// pendingOrderRepository хранит заказы в памяти; List учитывает статус, DateTo,
+// сортировку по created_at и смещение, как выборка просроченных заказов
+type pendingOrderRepository struct {
+	repositories.OrderRepository
+	orders     map[uuid.UUID]*entities.Order
+	failUpdate map[uuid.UUID]bool
+}
+
+func newPendingOrderRepository(orders ...*entities.Order) *pendingOrderRepository {
+	repo := &pendingOrderRepository{orders: make(map[uuid.UUID]*entities.Order), failUpdate: make(map[uuid.UUID]bool)}
+	for _, order := range orders {
+		repo.orders[order.ID] = order
+	}
+	return repo
+}
+
+func (r *pendingOrderRepository) List(_ context.Context, filters repositories.OrderFilters) ([]*entities.Order, error) {
+	var cutoff time.Time
+	if filters.DateTo != nil {
+		parsed, err := time.Parse(time.RFC3339, *filters.DateTo)
+		if err != nil {
+			return nil, err
+		}
+		cutoff = parsed
+	}
+
+	var matched []*entities.Order
+	for _, order := range r.orders {
+		if filters.Status != nil && order.Status != *filters.Status {
+			continue
+		}
+		if !cutoff.IsZero() && order.CreatedAt.After(cutoff) {
+			continue
+		}
+		copied := *order
+		matched = append(matched, &copied)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].CreatedAt.Before(matched[j].CreatedAt) })
+
+	if filters.Offset >= len(matched) {
+		return nil, nil
+	}
+	matched = matched[filters.Offset:]
+	if len(matched) > filters.Limit {
+		matched = matched[:filters.Limit]
+	}
+	return matched, nil
+}
+
+func (r *pendingOrderRepository) GetByID(_ context.Context, id uuid.UUID) (*entities.Order, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, entities.NewOrderNotFoundError(id.String())
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (r *pendingOrderRepository) Update(_ context.Context, order *entities.Order) error {
+	if r.failUpdate[order.ID] {
+		return errors.New("database unavailable")
+	}
+	copied := *order
+	r.orders[order.ID] = &copied
+	return nil
+}
+
+func pendingOrder(age time.Duration, currency, channel string) *entities.Order {
+	order := entities.NewOrder(uuid.New(), "buyer@example.com")
+	order.CreatedAt = time.Now().Add(-age)
+	order.Currency = currency
+	if channel != "" {
+		order.Metadata[entities.MetadataSalesChannel] = channel
+	}
+	return order
+}
+
+func testExpiryPolicy() ExpiryPolicy {
+	return ExpiryPolicy{
+		DefaultTTL:  time.Hour,
+		CurrencyTTL: map[string]time.Duration{"EUR": 30 * time.Minute, "GBP": 2 * time.Hour},
+		ChannelTTL:  map[string]time.Duration{"marketplace": 72 * time.Hour, "web": 20 * time.Minute},
+	}
+}
+
+func TestExpiryPolicy_TTLFor(t *testing.T) {
+	policy := testExpiryPolicy()
+
+	tests := []struct {
+		name     string
+		currency string
+		channel  string
+		want     time.Duration
+	}{
+		{name: "default", currency: "USD", want: time.Hour},
+		{name: "currency", currency: "EUR", want: 30 * time.Minute},
+		{name: "currency case-insensitive", currency: "gbp", want: 2 * time.Hour},
+		{name: "channel over currency", currency: "EUR", channel: "marketplace", want: 72 * time.Hour},
+		{name: "channel over default", currency: "USD", channel: "web", want: 20 * time.Minute},
+		{name: "unknown channel falls back to currency", currency: "EUR", channel: "pos", want: 30 * time.Minute},
+		{name: "unknown channel falls back to default", currency: "USD", channel: "pos", want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.TTLFor(pendingOrder(0, tt.currency, tt.channel)); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewExpiryPolicy_NormalizesCurrency(t *testing.T) {
+	policy := NewExpiryPolicy(time.Hour, map[string]time.Duration{"usd": 12 * time.Hour, " Eur ": 30 * time.Minute}, nil)
+
+	if got := policy.TTLFor(pendingOrder(0, "USD", "")); got != 12*time.Hour {
+		t.Fatalf("expected lower-case currency key to match, got %s", got)
+	}
+	if got := policy.TTLFor(pendingOrder(0, "eur", "")); got != 30*time.Minute {
+		t.Fatalf("expected mixed-case currency key to match, got %s", got)
+	}
+}
+
+func TestExpiryPolicy_MinTTL(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ExpiryPolicy
+		want   time.Duration
+	}{
+		{name: "default only", policy: ExpiryPolicy{DefaultTTL: time.Hour}, want: time.Hour},
+		{name: "currency shorter", policy: ExpiryPolicy{
+			DefaultTTL:  time.Hour,
+			CurrencyTTL: map[string]time.Duration{"EUR": 30 * time.Minute},
+		}, want: 30 * time.Minute},
+		{name: "channel shortest", policy: testExpiryPolicy(), want: 20 * time.Minute},
+		{name: "longer rules ignored", policy: ExpiryPolicy{
+			DefaultTTL: time.Hour,
+			ChannelTTL: map[string]time.Duration{"marketplace": 72 * time.Hour},
+		}, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.MinTTL(); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExpirePendingOrders_PagesPastSkippedAndFailed(t *testing.T) {
+	policy := ExpiryPolicy{
+		DefaultTTL:  time.Hour,
+		CurrencyTTL: map[string]time.Duration{"EUR": 30 * time.Minute},
+		ChannelTTL:  map[string]time.Duration{"marketplace": 72 * time.Hour},
+	}
+
+	young := pendingOrder(5*time.Hour, "USD", "marketplace") // TTL канала не истек
+	expired := pendingOrder(4*time.Hour, "USD", "")          // TTL по умолчанию истек
+	failing := pendingOrder(3*time.Hour, "USD", "")          // ошибка сохранения
+	expiredEUR := pendingOrder(46*time.Minute, "EUR", "")    // TTL валюты истек
+	notYet := pendingOrder(45*time.Minute, "USD", "")        // TTL по умолчанию не истек
+	beforeCutoff := pendingOrder(10*time.Minute, "USD", "")  // моложе минимального TTL, не выбирается
+	confirmed := pendingOrder(10*time.Hour, "USD", "")
+	confirmed.Status = entities.OrderStatusConfirmed
+
+	repo := newPendingOrderRepository(young, expired, failing, expiredEUR, notYet, beforeCutoff, confirmed)
+	repo.failUpdate[failing.ID] = true
+	publisher := &recordingPublisher{}
+	updateUC := NewUpdateOrderStatusUseCase(repo, publisher, nil, nopLogger{})
+	uc := NewExpirePendingOrdersUseCase(repo, updateUC, policy, 2, nopLogger{})
+
+	resp, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Checked != 5 || resp.Expired != 2 || resp.Failed != 1 {
+		t.Fatalf("expected 5 checked, 2 expired, 1 failed, got %+v", resp)
+	}
+
+	wantStatus := map[uuid.UUID]entities.OrderStatus{
+		young.ID:        entities.OrderStatusPending,
+		expired.ID:      entities.OrderStatusCancelled,
+		failing.ID:      entities.OrderStatusPending,
+		expiredEUR.ID:   entities.OrderStatusCancelled,
+		notYet.ID:       entities.OrderStatusPending,
+		beforeCutoff.ID: entities.OrderStatusPending,
+		confirmed.ID:    entities.OrderStatusConfirmed,
+	}
+	for id, want := range wantStatus {
+		if got := repo.orders[id].Status; got != want {
+			t.Errorf("order %s: expected %s, got %s", id, want, got)
+		}
+	}
+
+	if len(publisher.events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(publisher.events))
+	}
+	if reason := repo.orders[expired.ID].Metadata["status_change_reason"]; reason != ExpiryReason {
+		t.Fatalf("expected expiry reason in metadata, got %v", reason)
+	}
+}
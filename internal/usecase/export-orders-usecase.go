@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// ExportFormat формат выгрузки заказов
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"    // RFC 4180
+	ExportFormatNDJSON ExportFormat = "ndjson" // JSON объект на строку
+	ExportFormatExcel  ExportFormat = "excel"  // CSV для Excel: BOM, CRLF, экранирование формул
+)
+
+// ParseExportFormat разбирает формат выгрузки; пусто - CSV
+func ParseExportFormat(value string) (ExportFormat, error) {
+	switch format := ExportFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return ExportFormatCSV, nil
+	case ExportFormatCSV, ExportFormatNDJSON, ExportFormatExcel:
+		return format, nil
+	default:
+		return "", entities.NewValidationError("unsupported export format: %s", value)
+	}
+}
+
+// OrderExportWriter пишет строки выгрузки в конкретном формате
+type OrderExportWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []interface{}) error
+	Flush() error
+}
+
+// orderExportColumn колонка выгрузки; item - значение берется из позиции заказа
+type orderExportColumn struct {
+	name  string
+	item  bool
+	value func(order *entities.Order, item *entities.OrderItem) interface{}
+}
+
+// orderExportColumns колонки, доступные для выгрузки
+var orderExportColumns = []orderExportColumn{
+	{name: "id", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.ID.String() }},
+	{name: "customer_id", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.CustomerID.String() }},
+	{name: "email", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.Email }},
+	{name: "status", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return string(o.Status) }},
+	{name: "currency", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.Currency }},
+	{name: "subtotal", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.Subtotal }},
+	{name: "discount_amount", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.Discount }},
+	{name: "tax_amount", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.TaxAmount }},
+	{name: "shipping_amount", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.ShippingAmount }},
+	{name: "total_amount", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.TotalAmount }},
+	{name: "base_currency", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.BaseCurrency }},
+	{name: "total_amount_base", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.TotalAmountBase }},
+	{name: "shipping_method", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return string(o.ShippingMethod) }},
+	{name: "shipping_country", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return addressField(o.ShippingAddress, "country") }},
+	{name: "shipping_city", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return addressField(o.ShippingAddress, "city") }},
+	{name: "shipping_zip_code", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return addressField(o.ShippingAddress, "zip_code") }},
+	{name: "billing_country", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return addressField(o.BillingAddress, "country") }},
+	{name: "item_count", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return len(o.Items) }},
+	{name: "created_at", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.CreatedAt }},
+	{name: "updated_at", value: func(o *entities.Order, _ *entities.OrderItem) interface{} { return o.UpdatedAt }},
+
+	{name: "item_id", item: true, value: func(_ *entities.Order, i *entities.OrderItem) interface{} { return i.ID.String() }},
+	{name: "item_product_id", item: true, value: func(_ *entities.Order, i *entities.OrderItem) interface{} { return i.ProductID.String() }},
+	{name: "item_name", item: true, value: func(_ *entities.Order, i *entities.OrderItem) interface{} { return i.Name }},
+	{name: "item_price", item: true, value: func(_ *entities.Order, i *entities.OrderItem) interface{} { return i.Price }},
+	{name: "item_quantity", item: true, value: func(_ *entities.Order, i *entities.OrderItem) interface{} { return i.Quantity }},
+	{name: "item_total", item: true, value: func(_ *entities.Order, i *entities.OrderItem) interface{} { return i.Total }},
+	{name: "item_discount_amount", item: true, value: func(_ *entities.Order, i *entities.OrderItem) interface{} { return i.DiscountAmount }},
+	{name: "item_tax_category", item: true, value: func(_ *entities.Order, i *entities.OrderItem) interface{} { return i.TaxCategory }},
+	{name: "item_tax_amount", item: true, value: func(_ *entities.Order, i *entities.OrderItem) interface{} { return i.TaxAmount }},
+}
+
+// Колонки по умолчанию; при разбиении по позициям к ним добавляются defaultItemExportColumns
+var (
+	defaultOrderExportColumns = []string{
+		"id", "customer_id", "email", "status", "currency", "subtotal", "discount_amount",
+		"tax_amount", "shipping_amount", "total_amount", "total_amount_base", "created_at",
+	}
+	defaultItemExportColumns = []string{"item_product_id", "item_name", "item_price", "item_quantity", "item_total"}
+)
+
+// addressField возвращает поле адреса или пустую строку без адреса
+func addressField(address *entities.Address, field string) string {
+	if address == nil {
+		return ""
+	}
+	switch field {
+	case "country":
+		return address.Country
+	case "city":
+		return address.City
+	case "zip_code":
+		return address.ZipCode
+	default:
+		return ""
+	}
+}
+
+// ExportOrdersRequest представляет запрос выгрузки заказов
+type ExportOrdersRequest struct {
+	// Фильтры и сортировка как у списка заказов; Limit 0 - без ограничения
+	Filters ListOrdersRequest `json:"filters"`
+	// Колонки в порядке вывода; пусто - колонки по умолчанию
+	Columns []string `json:"columns,omitempty"`
+	// Одна строка на позицию заказа вместо строки на заказ
+	FlattenItems bool `json:"flatten_items"`
+}
+
+// ExportOrdersUseCase представляет use case потоковой выгрузки заказов
+type ExportOrdersUseCase struct {
+	orderRepo repositories.OrderRepository
+	batchSize int
+	logger    Logger
+}
+
+// NewExportOrdersUseCase создает новый use case выгрузки заказов
+func NewExportOrdersUseCase(orderRepo repositories.OrderRepository, batchSize int, logger Logger) *ExportOrdersUseCase {
+	return &ExportOrdersUseCase{
+		orderRepo: orderRepo,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Execute пишет заказы по фильтрам в writer и возвращает количество строк.
+// Ошибки валидации возвращаются до первой записи в writer.
+func (uc *ExportOrdersUseCase) Execute(ctx context.Context, req *ExportOrdersRequest, writer OrderExportWriter) (int64, error) {
+	if req == nil {
+		return 0, entities.NewValidationError("request cannot be nil")
+	}
+
+	filters := req.Filters
+	if filters.Limit < 0 {
+		filters.Limit = 0
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+	if filters.SortBy == "" {
+		filters.SortBy = "created_at"
+	}
+	if filters.SortOrder == "" {
+		filters.SortOrder = "desc"
+	}
+	if err := validateOrderFilters(&filters); err != nil {
+		return 0, err
+	}
+
+	columns, err := resolveExportColumns(req.Columns, req.FlattenItems)
+	if err != nil {
+		return 0, err
+	}
+
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.name
+	}
+	if err := writer.WriteHeader(names); err != nil {
+		return 0, fmt.Errorf("failed to write export header: %w", err)
+	}
+
+	var rows int64
+	values := make([]interface{}, len(columns))
+	writeRow := func(order *entities.Order, item *entities.OrderItem) error {
+		for i, column := range columns {
+			if column.item && item == nil {
+				values[i] = nil
+				continue
+			}
+			values[i] = column.value(order, item)
+		}
+		rows++
+		return writer.WriteRow(values)
+	}
+
+	err = uc.orderRepo.Stream(ctx, repositories.OrderFilters{
+		CustomerID: filters.CustomerID,
+		Status:     filters.Status,
+		Email:      filters.Email,
+		MinAmount:  filters.MinAmount,
+		MaxAmount:  filters.MaxAmount,
+		DateFrom:   filters.DateFrom,
+		DateTo:     filters.DateTo,
+		Currency:   filters.Currency,
+		Limit:      filters.Limit,
+		Offset:     filters.Offset,
+		SortBy:     filters.SortBy,
+		SortOrder:  filters.SortOrder,
+	}, uc.batchSize, func(order *entities.Order) error {
+		if !req.FlattenItems || len(order.Items) == 0 {
+			return writeRow(order, nil)
+		}
+		for i := range order.Items {
+			if err := writeRow(order, &order.Items[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		uc.logger.Error("Failed to export orders", "error", err, "rows", rows)
+		return rows, fmt.Errorf("failed to export orders: %w", err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return rows, fmt.Errorf("failed to flush export: %w", err)
+	}
+
+	uc.logger.Info("Orders exported", "rows", rows, "flatten_items", req.FlattenItems)
+	return rows, nil
+}
+
+// resolveExportColumns подбирает колонки по именам; колонки позиций требуют разбиения по позициям
+func resolveExportColumns(names []string, flattenItems bool) ([]orderExportColumn, error) {
+	if len(names) == 0 {
+		names = defaultOrderExportColumns
+		if flattenItems {
+			names = append(append([]string(nil), defaultOrderExportColumns...), defaultItemExportColumns...)
+		}
+	}
+
+	byName := make(map[string]orderExportColumn, len(orderExportColumns))
+	for _, column := range orderExportColumns {
+		byName[column.name] = column
+	}
+
+	columns := make([]orderExportColumn, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		column, ok := byName[name]
+		if !ok {
+			return nil, entities.NewValidationError("unknown export column: %s", name)
+		}
+		if column.item && !flattenItems {
+			return nil, entities.NewValidationError("column %s requires items=true", name)
+		}
+		seen[name] = true
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil, entities.NewValidationError("at least one export column is required")
+	}
+
+	return columns, nil
+}
+
+// OrderExportColumns возвращает имена всех доступных колонок выгрузки
+func OrderExportColumns() []string {
+	names := make([]string, len(orderExportColumns))
+	for i, column := range orderExportColumns {
+		names[i] = column.name
+	}
+	return names
+}
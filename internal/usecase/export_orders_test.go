@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type streamOrderRepository struct {
+	repositories.OrderRepository
+	orders  []*entities.Order
+	filters repositories.OrderFilters
+}
+
+func (r *streamOrderRepository) Stream(_ context.Context, filters repositories.OrderFilters, _ int, fn func(order *entities.Order) error) error {
+	r.filters = filters
+	for _, order := range r.orders {
+		if err := fn(order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type recordingExportWriter struct {
+	header []string
+	rows   [][]interface{}
+}
+
+func (w *recordingExportWriter) WriteHeader(columns []string) error {
+	w.header = columns
+	return nil
+}
+
+func (w *recordingExportWriter) WriteRow(values []interface{}) error {
+	w.rows = append(w.rows, append([]interface{}(nil), values...))
+	return nil
+}
+
+func (w *recordingExportWriter) Flush() error { return nil }
+
+func TestExportOrders_FlattenItems(t *testing.T) {
+	repo := &streamOrderRepository{orders: []*entities.Order{
+		{ID: uuid.New(), Email: "a@example.com", Items: []entities.OrderItem{
+			{Name: "Widget", Quantity: 2},
+			{Name: "Gadget", Quantity: 1},
+		}},
+		{ID: uuid.New(), Email: "b@example.com"},
+	}}
+	writer := &recordingExportWriter{}
+	uc := NewExportOrdersUseCase(repo, 100, nopLogger{})
+
+	rows, err := uc.Execute(context.Background(), &ExportOrdersRequest{
+		Columns:      []string{"email", "item_name", "item_quantity"},
+		FlattenItems: true,
+	}, writer)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if rows != 3 || len(writer.rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", rows)
+	}
+	if writer.rows[1][0] != "a@example.com" || writer.rows[1][1] != "Gadget" || writer.rows[1][2] != 1 {
+		t.Errorf("Unexpected second row %v", writer.rows[1])
+	}
+	if writer.rows[2][1] != nil {
+		t.Errorf("Expected empty item columns for order without items, got %v", writer.rows[2])
+	}
+	if repo.filters.SortBy != "created_at" || repo.filters.SortOrder != "desc" || repo.filters.Limit != 0 {
+		t.Errorf("Unexpected stream filters %+v", repo.filters)
+	}
+}
+
+func TestExportOrders_InvalidColumns(t *testing.T) {
+	uc := NewExportOrdersUseCase(&streamOrderRepository{}, 100, nopLogger{})
+
+	for _, columns := range [][]string{{"unknown"}, {"id", "item_name"}} {
+		writer := &recordingExportWriter{}
+		_, err := uc.Execute(context.Background(), &ExportOrdersRequest{Columns: columns}, writer)
+
+		var validationErr entities.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("Expected validation error for %v, got %v", columns, err)
+		}
+		if writer.header != nil {
+			t.Errorf("Expected nothing written for %v", columns)
+		}
+	}
+}
@@ -193,8 +193,13 @@ func (uc *ListOrdersUseCase) validateAndSetDefaults(req *ListOrdersRequest) erro
 		req.SortOrder = "desc"
 	}
 
+	return validateOrderFilters(req)
+}
+
+// validateOrderFilters проверяет сортировку и диапазон сумм фильтров заказов
+func validateOrderFilters(req *ListOrdersRequest) error {
 	// Валидация сортировки
-	validSortFields := []string{"created_at", "updated_at", "total_amount", "status"}
+	validSortFields := []string{"created_at", "updated_at", "total_amount", "total_amount_base", "status"}
 	isValidSortBy := false
 	for _, field := range validSortFields {
 		if req.SortBy == field {
@@ -224,4 +229,58 @@ func (uc *ListOrdersUseCase) validateAndSetDefaults(req *ListOrdersRequest) erro
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// OrderStatsResponse представляет статистику заказов в отчетной валюте
+type OrderStatsResponse struct {
+	ReportingCurrency string                           `json:"reporting_currency"`
+	Stats             []*repositories.OrderStatusStats `json:"stats"`
+}
+
+// OrderStatsUseCase представляет use case статистики заказов по статусам
+type OrderStatsUseCase struct {
+	orderRepo         repositories.OrderRepository
+	reportingCurrency string
+	logger            Logger
+}
+
+// NewOrderStatsUseCase создает новый use case статистики заказов
+func NewOrderStatsUseCase(
+	orderRepo repositories.OrderRepository,
+	reportingCurrency string,
+	logger Logger,
+) *OrderStatsUseCase {
+	return &OrderStatsUseCase{
+		orderRepo:         orderRepo,
+		reportingCurrency: entities.NormalizeCurrency(reportingCurrency),
+		logger:            logger,
+	}
+}
+
+// Execute считает статистику по фильтрам списка заказов; пагинация не учитывается
+func (uc *OrderStatsUseCase) Execute(ctx context.Context, req *ListOrdersRequest) (*OrderStatsResponse, error) {
+	if req == nil {
+		req = &ListOrdersRequest{}
+	}
+
+	filters := repositories.OrderFilters{
+		CustomerID: req.CustomerID,
+		Status:     req.Status,
+		Email:      req.Email,
+		MinAmount:  req.MinAmount,
+		MaxAmount:  req.MaxAmount,
+		DateFrom:   req.DateFrom,
+		DateTo:     req.DateTo,
+		Currency:   req.Currency,
+	}
+
+	stats, err := uc.orderRepo.Statistics(ctx, filters)
+	if err != nil {
+		uc.logger.Error("Failed to get order statistics", "error", err)
+		return nil, fmt.Errorf("failed to get order statistics: %w", err)
+	}
+
+	return &OrderStatsResponse{
+		ReportingCurrency: uc.reportingCurrency,
+		Stats:             stats,
+	}, nil
+}
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// OutOfStockReason причина отмены заказа, товары которого не удалось зарезервировать
+const OutOfStockReason = "out of stock"
+
+// InventoryService резервирует и списывает товары заказов со склада
+type InventoryService struct {
+	inventoryRepo  repositories.InventoryRepository
+	orderRepo      repositories.OrderRepository
+	updateStatusUC *UpdateOrderStatusUseCase
+	logger         Logger
+}
+
+// NewInventoryService создает сервис складских остатков.
+// updateStatusUC отменяет заказы, которые не удалось зарезервировать; без него
+// нехватка товара возвращается как ошибка.
+func NewInventoryService(
+	inventoryRepo repositories.InventoryRepository,
+	orderRepo repositories.OrderRepository,
+	updateStatusUC *UpdateOrderStatusUseCase,
+	logger Logger,
+) *InventoryService {
+	return &InventoryService{
+		inventoryRepo:  inventoryRepo,
+		orderRepo:      orderRepo,
+		updateStatusUC: updateStatusUC,
+		logger:         logger,
+	}
+}
+
+// CheckAvailability проверяет, что товаров заказа достаточно на складах.
+// Проверка не блокирует остатки и лишь рано отклоняет заказ: окончательно товары
+// резервируются в транзакции создания заказа.
+func (s *InventoryService) CheckAvailability(ctx context.Context, order *entities.Order) error {
+	productIDs := make([]uuid.UUID, 0, len(order.Items))
+	for productID := range order.RequestedQuantities() {
+		productIDs = append(productIDs, productID)
+	}
+
+	levels, err := s.inventoryRepo.GetStockLevels(ctx, productIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get stock levels: %w", err)
+	}
+
+	return order.CheckStock(levels)
+}
+
+// ReserveOrder резервирует товары заказа. Заказ, товаров которого не хватает,
+// отменяется с причиной OutOfStockReason, и по нему публикуется событие отмены.
+func (s *InventoryService) ReserveOrder(ctx context.Context, orderID uuid.UUID) ([]*entities.StockReservation, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	reservations, err := s.inventoryRepo.ReserveOrder(ctx, order)
+	if err != nil {
+		var stockErr entities.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			s.logger.Warn("Not enough stock to reserve order",
+				"order_id", orderID,
+				"product_id", stockErr.ProductID,
+				"requested", stockErr.Requested,
+				"available", stockErr.Available)
+			return nil, s.cancelOutOfStock(ctx, order, err)
+		}
+		return nil, fmt.Errorf("failed to reserve stock: %w", err)
+	}
+
+	s.logger.Info("Stock reserved for order",
+		"order_id", orderID,
+		"reservations_count", len(reservations))
+
+	return reservations, nil
+}
+
+// cancelOutOfStock отменяет заказ, товары которого не удалось зарезервировать
+func (s *InventoryService) cancelOutOfStock(ctx context.Context, order *entities.Order, stockErr error) error {
+	if order.Status == entities.OrderStatusCancelled {
+		return nil
+	}
+	if s.updateStatusUC == nil {
+		return fmt.Errorf("failed to reserve stock: %w", stockErr)
+	}
+
+	_, err := s.updateStatusUC.Execute(ctx, &UpdateOrderStatusRequest{
+		OrderID:   order.ID,
+		NewStatus: entities.OrderStatusCancelled,
+		Reason:    OutOfStockReason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel out-of-stock order: %w", err)
+	}
+
+	s.logger.Warn("Order cancelled: not enough stock", "order_id", order.ID)
+	return nil
+}
+
+// CommitOrder списывает зарезервированные товары отгруженного заказа
+func (s *InventoryService) CommitOrder(ctx context.Context, orderID uuid.UUID) error {
+	count, err := s.inventoryRepo.CommitOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to commit stock reservations: %w", err)
+	}
+
+	s.logger.Info("Stock reservations committed", "order_id", orderID, "reservations_count", count)
+	return nil
+}
+
+// ReleaseOrder снимает резервы отмененного заказа
+func (s *InventoryService) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
+	count, err := s.inventoryRepo.ReleaseOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to release stock reservations: %w", err)
+	}
+
+	s.logger.Info("Stock reservations released", "order_id", orderID, "reservations_count", count)
+	return nil
+}
+
+// AdjustStockRequest представляет запрос на изменение остатка товара
+type AdjustStockRequest struct {
+	ProductID uuid.UUID `json:"-"`
+	Warehouse string    `json:"warehouse,omitempty"` // Пусто - склад по умолчанию
+	Delta     int       `json:"delta" validate:"required"`
+	Reason    string    `json:"reason" validate:"required"`
+}
+
+// AdjustStockResponse представляет ответ изменения остатка
+type AdjustStockResponse struct {
+	StockLevel *entities.StockLevel `json:"stock_level"`
+	Available  int                  `json:"available"`
+}
+
+// AdjustStockUseCase представляет use case ручного изменения остатков (приемка, инвентаризация)
+type AdjustStockUseCase struct {
+	inventoryRepo repositories.InventoryRepository
+	logger        Logger
+}
+
+// NewAdjustStockUseCase создает новый use case изменения остатков
+func NewAdjustStockUseCase(inventoryRepo repositories.InventoryRepository, logger Logger) *AdjustStockUseCase {
+	return &AdjustStockUseCase{
+		inventoryRepo: inventoryRepo,
+		logger:        logger,
+	}
+}
+
+// Execute изменяет остаток товара на складе
+func (uc *AdjustStockUseCase) Execute(ctx context.Context, req *AdjustStockRequest) (*AdjustStockResponse, error) {
+	if req == nil || req.ProductID == uuid.Nil {
+		return nil, entities.NewValidationError("product_id is required")
+	}
+	if req.Delta == 0 {
+		return nil, entities.NewValidationError("delta cannot be zero")
+	}
+	if req.Reason == "" {
+		return nil, entities.NewValidationError("reason is required")
+	}
+	if err := entities.CheckSharedCatalogAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	warehouse := entities.NormalizeWarehouse(req.Warehouse)
+	level, err := uc.inventoryRepo.AdjustStock(ctx, req.ProductID, warehouse, req.Delta)
+	if err != nil {
+		uc.logger.Error("Failed to adjust stock", "error", err, "product_id", req.ProductID, "warehouse", warehouse)
+		return nil, fmt.Errorf("failed to adjust stock: %w", err)
+	}
+
+	uc.logger.Info("Stock adjusted",
+		"product_id", req.ProductID,
+		"warehouse", warehouse,
+		"delta", req.Delta,
+		"reason", req.Reason,
+		"on_hand", level.OnHand,
+		"reserved", level.Reserved)
+
+	return &AdjustStockResponse{
+		StockLevel: level,
+		Available:  level.Available(),
+	}, nil
+}
+
+// GetStockRequest представляет запрос остатков товара
+type GetStockRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+}
+
+// GetStockResponse представляет остатки товара по складам и в сумме
+type GetStockResponse struct {
+	ProductID uuid.UUID              `json:"product_id"`
+	Levels    []*entities.StockLevel `json:"levels"`
+	OnHand    int                    `json:"on_hand"`
+	Reserved  int                    `json:"reserved"`
+	Available int                    `json:"available"`
+}
+
+// GetStockUseCase представляет use case получения остатков товара
+type GetStockUseCase struct {
+	inventoryRepo repositories.InventoryRepository
+	logger        Logger
+}
+
+// NewGetStockUseCase создает новый use case получения остатков
+func NewGetStockUseCase(inventoryRepo repositories.InventoryRepository, logger Logger) *GetStockUseCase {
+	return &GetStockUseCase{
+		inventoryRepo: inventoryRepo,
+		logger:        logger,
+	}
+}
+
+// Execute получает остатки товара по всем складам
+func (uc *GetStockUseCase) Execute(ctx context.Context, req *GetStockRequest) (*GetStockResponse, error) {
+	if req == nil || req.ProductID == uuid.Nil {
+		return nil, entities.NewValidationError("product_id is required")
+	}
+
+	levels, err := uc.inventoryRepo.GetStockLevels(ctx, []uuid.UUID{req.ProductID})
+	if err != nil {
+		uc.logger.Error("Failed to get stock levels", "error", err, "product_id", req.ProductID)
+		return nil, fmt.Errorf("failed to get stock levels: %w", err)
+	}
+
+	resp := &GetStockResponse{
+		ProductID: req.ProductID,
+		Levels:    levels,
+	}
+	if resp.Levels == nil {
+		resp.Levels = []*entities.StockLevel{}
+	}
+	for _, level := range levels {
+		resp.OnHand += level.OnHand
+		resp.Reserved += level.Reserved
+		resp.Available += level.Available()
+	}
+
+	return resp, nil
+}
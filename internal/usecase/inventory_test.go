@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// shortInventoryRepository не может зарезервировать ни одного товара
+type shortInventoryRepository struct {
+	repositories.InventoryRepository
+}
+
+func (shortInventoryRepository) ReserveOrder(_ context.Context, _ *entities.Order) ([]*entities.StockReservation, error) {
+	return nil, entities.NewInsufficientStockError(uuid.New().String(), 2, 1)
+}
+
+func TestReserveOrder_CancelsOrderWhenOutOfStock(t *testing.T) {
+	order := pendingOrder(time.Minute, "USD", "")
+	repo := newPendingOrderRepository(order)
+	publisher := &recordingPublisher{}
+	updateUC := NewUpdateOrderStatusUseCase(repo, publisher, nil, nopLogger{})
+	service := NewInventoryService(shortInventoryRepository{}, repo, updateUC, nopLogger{})
+
+	reservations, err := service.ReserveOrder(context.Background(), order.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reservations) != 0 {
+		t.Fatalf("expected no reservations, got %d", len(reservations))
+	}
+	if got := repo.orders[order.ID].Status; got != entities.OrderStatusCancelled {
+		t.Fatalf("expected cancelled order, got %s", got)
+	}
+	if reason := repo.orders[order.ID].Metadata["status_change_reason"]; reason != OutOfStockReason {
+		t.Fatalf("expected out-of-stock reason, got %v", reason)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].EventType != entities.EventOrderCancelled {
+		t.Fatalf("expected one cancellation event, got %+v", publisher.events)
+	}
+}
+
+func TestReserveOrder_ReturnsErrorWithoutStatusUpdates(t *testing.T) {
+	order := pendingOrder(time.Minute, "USD", "")
+	repo := newPendingOrderRepository(order)
+	service := NewInventoryService(shortInventoryRepository{}, repo, nil, nopLogger{})
+
+	if _, err := service.ReserveOrder(context.Background(), order.ID); err == nil {
+		t.Fatal("expected out-of-stock error")
+	}
+	if got := repo.orders[order.ID].Status; got != entities.OrderStatusPending {
+		t.Fatalf("expected pending order, got %s", got)
+	}
+}
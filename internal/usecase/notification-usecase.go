@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// EmailSender интерфейс транспорта email (SMTP, файлы, no-op)
+type EmailSender interface {
+	Send(ctx context.Context, message *entities.EmailMessage) error
+}
+
+// NotificationRenderer интерфейс шаблонизатора уведомлений.
+// Для неизвестного языка используется entities.DefaultLocale.
+type NotificationRenderer interface {
+	Render(locale, template string, data interface{}) (subject, body string, err error)
+}
+
+// NotificationData данные, доступные в шаблонах уведомлений
+type NotificationData struct {
+	Order  *entities.Order
+	Event  *entities.OrderEvent
+	Locale string
+}
+
+// NotificationService отправляет клиентам email уведомления по событиям заказа
+type NotificationService struct {
+	orderRepo        repositories.OrderRepository
+	notificationRepo repositories.NotificationRepository
+	renderer         NotificationRenderer
+	sender           EmailSender
+	logger           Logger
+}
+
+// NewNotificationService создает сервис уведомлений
+func NewNotificationService(
+	orderRepo repositories.OrderRepository,
+	notificationRepo repositories.NotificationRepository,
+	renderer NotificationRenderer,
+	sender EmailSender,
+	logger Logger,
+) *NotificationService {
+	return &NotificationService{
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		renderer:         renderer,
+		sender:           sender,
+		logger:           logger,
+	}
+}
+
+// Notify отправляет уведомление по событию заказа.
+// События без шаблона и уже отправленные по заказу шаблоны пропускаются.
+func (s *NotificationService) Notify(ctx context.Context, event *entities.OrderEvent) error {
+	template, ok := entities.NotificationTemplateFor(event.EventType)
+	if !ok {
+		return nil
+	}
+
+	sent, err := s.notificationRepo.Exists(ctx, event.OrderID, template)
+	if err != nil {
+		return fmt.Errorf("failed to check notification: %w", err)
+	}
+	if sent {
+		s.logger.Info("Notification already sent, skipping",
+			"order_id", event.OrderID,
+			"template", template,
+			"event_id", event.EventID)
+		return nil
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, event.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.Email == "" || entities.IsErasedEmail(order.Email) {
+		s.logger.Warn("Order has no email, notification skipped", "order_id", order.ID, "template", template)
+		return nil
+	}
+
+	locale := order.Locale()
+	subject, body, err := s.renderer.Render(locale, template, &NotificationData{
+		Order:  order,
+		Event:  event,
+		Locale: locale,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render notification %s: %w", template, err)
+	}
+
+	message := &entities.EmailMessage{
+		To:       order.Email,
+		Subject:  subject,
+		HTMLBody: body,
+	}
+	if err := s.sender.Send(ctx, message); err != nil {
+		return fmt.Errorf("failed to send notification %s: %w", template, err)
+	}
+
+	// Письмо уже ушло: ошибка записи приведет максимум к повторной отправке при redelivery
+	recorded, err := s.notificationRepo.Record(ctx, &entities.Notification{
+		ID:        uuid.New(),
+		OrderID:   order.ID,
+		EventID:   event.EventID,
+		Template:  template,
+		Recipient: order.Email,
+		Locale:    locale,
+		Subject:   subject,
+		SentAt:    time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record notification: %w", err)
+	}
+	if !recorded {
+		s.logger.Warn("Notification was sent concurrently", "order_id", order.ID, "template", template)
+	}
+
+	s.logger.Info("Notification sent",
+		"order_id", order.ID,
+		"template", template)
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// stubOrderRepository отдает один заказ, остальные методы не используются
+type stubOrderRepository struct {
+	repositories.OrderRepository
+	order *entities.Order
+}
+
+func (r *stubOrderRepository) GetByID(_ context.Context, id uuid.UUID) (*entities.Order, error) {
+	if r.order == nil || r.order.ID != id {
+		return nil, entities.NewOrderNotFoundError(id.String())
+	}
+	return r.order, nil
+}
+
+type memoryNotificationRepository struct {
+	mu   sync.Mutex
+	sent map[string]*entities.Notification
+}
+
+func (r *memoryNotificationRepository) key(orderID uuid.UUID, template string) string {
+	return orderID.String() + "/" + template
+}
+
+func (r *memoryNotificationRepository) Exists(_ context.Context, orderID uuid.UUID, template string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.sent[r.key(orderID, template)]
+	return ok, nil
+}
+
+func (r *memoryNotificationRepository) Record(_ context.Context, n *entities.Notification) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sent[r.key(n.OrderID, n.Template)]; ok {
+		return false, nil
+	}
+	r.sent[r.key(n.OrderID, n.Template)] = n
+	return true, nil
+}
+
+func (r *memoryNotificationRepository) ListByOrder(_ context.Context, _ uuid.UUID) ([]*entities.Notification, error) {
+	return nil, nil
+}
+
+type stubRenderer struct{}
+
+func (stubRenderer) Render(locale, template string, _ interface{}) (string, string, error) {
+	return locale + ":" + template, "<p>" + template + "</p>", nil
+}
+
+type recordingEmailSender struct {
+	messages []*entities.EmailMessage
+}
+
+func (s *recordingEmailSender) Send(_ context.Context, message *entities.EmailMessage) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestNotificationService_Notify(t *testing.T) {
+	order := entities.NewOrder(uuid.New(), "test@example.com")
+	order.Metadata[entities.MetadataLocale] = "ru-RU"
+
+	sender := &recordingEmailSender{}
+	repo := &memoryNotificationRepository{sent: make(map[string]*entities.Notification)}
+	service := NewNotificationService(&stubOrderRepository{order: order}, repo, stubRenderer{}, sender, nopLogger{})
+
+	event := order.ToEvent(entities.EventOrderCreated)
+	for i := 0; i < 2; i++ {
+		// Повторная доставка того же события из Kafka
+		if err := service.Notify(context.Background(), event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(sender.messages) != 1 {
+		t.Fatalf("Expected 1 email after redelivery, got %d", len(sender.messages))
+	}
+	if sender.messages[0].To != "test@example.com" || sender.messages[0].Subject != "ru:order_created" {
+		t.Errorf("Unexpected message: %+v", sender.messages[0])
+	}
+
+	// События без шаблона не отправляются
+	if err := service.Notify(context.Background(), order.ToEvent(entities.EventOrderShipmentCreated)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.Notify(context.Background(), order.ToEvent(entities.EventOrderConfirmed)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sender.messages) != 2 {
+		t.Errorf("Expected 2 emails, got %d", len(sender.messages))
+	}
+}
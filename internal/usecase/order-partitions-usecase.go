@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// PartitionPolicy определяет горизонт создания и срок хранения месячных партиций заказов
+type PartitionPolicy struct {
+	// Партиции создаются заранее на текущий и MonthsAhead следующих месяцев
+	MonthsAhead int
+	// Партиции старше RetentionMonths полных месяцев до текущего отсоединяются; 0 - хранятся все
+	RetentionMonths int
+	// DropExpired удаляет отсоединенные партиции вместе со связанными строками заказов
+	DropExpired bool
+}
+
+// MaintainOrderPartitionsResponse представляет результат обслуживания партиций (месяцы в формате 2006-01)
+type MaintainOrderPartitionsResponse struct {
+	Created []string `json:"created"`
+	Expired []string `json:"expired"`
+	Dropped bool     `json:"dropped"`
+}
+
+// MaintainOrderPartitionsUseCase создает партиции заказов на будущие месяцы
+// и отсоединяет или удаляет партиции с истекшим сроком хранения
+type MaintainOrderPartitionsUseCase struct {
+	partitionRepo repositories.OrderPartitionRepository
+	policy        PartitionPolicy
+	logger        Logger
+	now           func() time.Time
+}
+
+// NewMaintainOrderPartitionsUseCase создает новый use case обслуживания партиций заказов
+func NewMaintainOrderPartitionsUseCase(
+	partitionRepo repositories.OrderPartitionRepository,
+	policy PartitionPolicy,
+	logger Logger,
+) *MaintainOrderPartitionsUseCase {
+	return &MaintainOrderPartitionsUseCase{
+		partitionRepo: partitionRepo,
+		policy:        policy,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// Execute создает недостающие партиции горизонта и обрабатывает истекшие
+func (uc *MaintainOrderPartitionsUseCase) Execute(ctx context.Context) (*MaintainOrderPartitionsResponse, error) {
+	if uc.policy.MonthsAhead < 0 || uc.policy.RetentionMonths < 0 {
+		return nil, entities.NewValidationError("partition policy requires non-negative months ahead and retention")
+	}
+
+	months, err := uc.partitionRepo.ListMonths(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order partitions: %w", err)
+	}
+	existing := make(map[time.Time]bool, len(months))
+	for _, month := range months {
+		existing[month] = true
+	}
+
+	now := uc.now().UTC()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	response := &MaintainOrderPartitionsResponse{
+		Created: make([]string, 0),
+		Expired: make([]string, 0),
+		Dropped: uc.policy.DropExpired,
+	}
+
+	for i := 0; i <= uc.policy.MonthsAhead; i++ {
+		month := current.AddDate(0, i, 0)
+		if existing[month] {
+			continue
+		}
+
+		created, err := uc.partitionRepo.CreateMonth(ctx, month)
+		if err != nil {
+			return response, err
+		}
+		if !created {
+			// Строки месяца в партиции по умолчанию: заказы продолжат писаться туда
+			uc.logger.Warn("Order partition not created", "month", month.Format("2006-01"))
+			continue
+		}
+		response.Created = append(response.Created, month.Format("2006-01"))
+		uc.logger.Info("Order partition created", "month", month.Format("2006-01"))
+	}
+
+	if uc.policy.RetentionMonths == 0 {
+		return response, nil
+	}
+
+	cutoff := current.AddDate(0, -uc.policy.RetentionMonths, 0)
+	for _, month := range months {
+		if !month.Before(cutoff) {
+			break
+		}
+
+		detached, err := uc.partitionRepo.DetachMonth(ctx, month, uc.policy.DropExpired)
+		if err != nil {
+			return response, err
+		}
+		if !detached {
+			continue
+		}
+		response.Expired = append(response.Expired, month.Format("2006-01"))
+		uc.logger.Info("Order partition expired", "month", month.Format("2006-01"), "dropped", uc.policy.DropExpired)
+	}
+
+	return response, nil
+}
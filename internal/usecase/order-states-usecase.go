@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// GetOrderStatesRequest представляет запрос описания машин состояний
+type GetOrderStatesRequest struct {
+	Channel string               `json:"channel,omitempty"` // Канал продаж; пусто - все машины
+	From    entities.OrderStatus `json:"from,omitempty"`    // Вернуть только переходы из этого статуса
+}
+
+// OrderStateMachineView представление машины состояний для UI
+type OrderStateMachineView struct {
+	Channel     string                          `json:"channel,omitempty"`
+	Initial     entities.OrderStatus            `json:"initial"`
+	States      []entities.StateDefinition      `json:"states"`
+	Transitions []entities.TransitionDefinition `json:"transitions"`
+}
+
+// GetOrderStatesResponse представляет ответ с машинами состояний
+type GetOrderStatesResponse struct {
+	Default  *OrderStateMachineView  `json:"default,omitempty"`
+	Channels []OrderStateMachineView `json:"channels,omitempty"`
+}
+
+// GetOrderStatesUseCase возвращает описание машин состояний и допустимых действий
+type GetOrderStatesUseCase struct {
+	stateMachines *entities.StateMachineRegistry
+	logger        Logger
+}
+
+// NewGetOrderStatesUseCase создает новый use case для получения машин состояний
+func NewGetOrderStatesUseCase(stateMachines *entities.StateMachineRegistry, logger Logger) *GetOrderStatesUseCase {
+	return &GetOrderStatesUseCase{
+		stateMachines: stateMachines,
+		logger:        logger,
+	}
+}
+
+// Execute выполняет получение машин состояний
+func (uc *GetOrderStatesUseCase) Execute(ctx context.Context, req *GetOrderStatesRequest) (*GetOrderStatesResponse, error) {
+	if req == nil {
+		req = &GetOrderStatesRequest{}
+	}
+
+	// Арендатор запроса может иметь собственный жизненный цикл заказа
+	stateMachines := uc.stateMachines.ForTenant(entities.TenantIDFromContext(ctx))
+
+	if req.Channel != "" {
+		view, err := uc.buildView(req.Channel, stateMachines.ForChannel(req.Channel), req.From)
+		if err != nil {
+			return nil, err
+		}
+		return &GetOrderStatesResponse{Channels: []OrderStateMachineView{*view}}, nil
+	}
+
+	defaultView, err := uc.buildView("", stateMachines.Default(), req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetOrderStatesResponse{Default: defaultView}
+	for _, channel := range stateMachines.Channels() {
+		view, err := uc.buildView(channel, stateMachines.ForChannel(channel), req.From)
+		if err != nil {
+			return nil, err
+		}
+		response.Channels = append(response.Channels, *view)
+	}
+
+	return response, nil
+}
+
+// buildView строит представление машины состояний, при необходимости фильтруя переходы
+func (uc *GetOrderStatesUseCase) buildView(channel string, sm *entities.StateMachine, from entities.OrderStatus) (*OrderStateMachineView, error) {
+	def := sm.Definition()
+	view := &OrderStateMachineView{
+		Channel:     channel,
+		Initial:     def.Initial,
+		States:      def.States,
+		Transitions: def.Transitions,
+	}
+
+	if from != "" {
+		if !sm.HasState(from) {
+			return nil, entities.NewValidationError("unknown status: %s", from)
+		}
+		view.Transitions = sm.AllowedTransitions(from)
+	}
+
+	if view.Transitions == nil {
+		view.Transitions = make([]entities.TransitionDefinition, 0)
+	}
+
+	return view, nil
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"kafka-order-service/internal/domain/entities"
+)
+
+// OrderStreamFilter отбирает события для подписчика потока: по заказу или по клиенту
+// в рамках арендатора
+type OrderStreamFilter struct {
+	TenantID   string
+	OrderID    uuid.UUID
+	CustomerID uuid.UUID
+}
+
+// Matches проверяет, относится ли событие к подписке
+func (f OrderStreamFilter) Matches(event *entities.OrderEvent) bool {
+	if f.TenantID != "" && entities.TenantIDOrDefault(event.TenantID) != f.TenantID {
+		return false
+	}
+	if f.OrderID != uuid.Nil && event.OrderID != f.OrderID {
+		return false
+	}
+	if f.CustomerID != uuid.Nil && event.CustomerID != f.CustomerID {
+		return false
+	}
+	return true
+}
+
+// OrderStreamSubscription подписка на поток событий заказов
+type OrderStreamSubscription struct {
+	// Replay события после Last-Event-ID из истории, отправляются до Events
+	Replay []*entities.OrderEvent
+	// Reset Last-Event-ID нет в истории: клиенту нужно перечитать состояние заказа
+	Reset bool
+	// Events новые события; канал закрывается, если подписчик не успевает их читать
+	Events <-chan *entities.OrderEvent
+
+	broker     *OrderEventBroker
+	subscriber *streamSubscriber
+}
+
+// Close отменяет подписку
+func (s *OrderStreamSubscription) Close() {
+	s.broker.remove(s.subscriber)
+}
+
+// streamSubscriber получатель событий брокера
+type streamSubscriber struct {
+	filter OrderStreamFilter
+	events chan *entities.OrderEvent
+}
+
+// OrderEventBroker раздает события заказов подписчикам потоков.
+// Publish никогда не блокируется: подписчик с переполненным буфером отключается
+// и при переподключении догоняет события из истории по Last-Event-ID.
+type OrderEventBroker struct {
+	mu          sync.Mutex
+	history     []*entities.OrderEvent // кольцевой буфер последних событий
+	next        int
+	full        bool
+	subscribers map[*streamSubscriber]struct{}
+	bufferSize  int
+	logger      Logger
+}
+
+// NewOrderEventBroker создает брокер с историей из historySize событий
+// и буфером bufferSize событий на подписчика
+func NewOrderEventBroker(historySize, bufferSize int, logger Logger) *OrderEventBroker {
+	if historySize <= 0 {
+		historySize = 1
+	}
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+
+	return &OrderEventBroker{
+		history:     make([]*entities.OrderEvent, historySize),
+		subscribers: make(map[*streamSubscriber]struct{}),
+		bufferSize:  bufferSize,
+		logger:      logger,
+	}
+}
+
+// Publish сохраняет событие в истории и раздает его подписчикам
+func (b *OrderEventBroker) Publish(event *entities.OrderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.history[b.next] = event
+	b.next = (b.next + 1) % len(b.history)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	for subscriber := range b.subscribers {
+		if !subscriber.filter.Matches(event) {
+			continue
+		}
+		select {
+		case subscriber.events <- event:
+		default:
+			b.logger.Warn("Order stream subscriber is too slow, disconnecting",
+				"order_id", subscriber.filter.OrderID,
+				"customer_id", subscriber.filter.CustomerID)
+			delete(b.subscribers, subscriber)
+			close(subscriber.events)
+		}
+	}
+}
+
+// Subscribe подписывает на события. Если lastEventID задан, в Replay попадают
+// подходящие события после него; история и подписка фиксируются атомарно.
+func (b *OrderEventBroker) Subscribe(filter OrderStreamFilter, lastEventID uuid.UUID) *OrderStreamSubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscriber := &streamSubscriber{
+		filter: filter,
+		events: make(chan *entities.OrderEvent, b.bufferSize),
+	}
+	b.subscribers[subscriber] = struct{}{}
+
+	subscription := &OrderStreamSubscription{
+		Events:     subscriber.events,
+		broker:     b,
+		subscriber: subscriber,
+	}
+
+	if lastEventID != uuid.Nil {
+		found := false
+		for _, event := range b.snapshot() {
+			if found && filter.Matches(event) {
+				subscription.Replay = append(subscription.Replay, event)
+			}
+			if event.EventID == lastEventID {
+				found = true
+			}
+		}
+		subscription.Reset = !found
+	}
+
+	return subscription
+}
+
+// PurgeCustomer удаляет из истории события клиента и отключает подписчиков потока клиента
+// после удаления его персональных данных. Возвращает количество удаленных событий.
+func (b *OrderEventBroker) PurgeCustomer(customerID uuid.UUID) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	kept := make([]*entities.OrderEvent, 0, len(b.history))
+	for _, event := range b.snapshot() {
+		if event.CustomerID != customerID {
+			kept = append(kept, event)
+		}
+	}
+	purged := len(b.snapshot()) - len(kept)
+
+	history := make([]*entities.OrderEvent, len(b.history))
+	copy(history, kept)
+	b.history = history
+	b.next = len(kept) % len(history)
+	b.full = len(kept) == len(history)
+
+	for subscriber := range b.subscribers {
+		if subscriber.filter.CustomerID == customerID {
+			delete(b.subscribers, subscriber)
+			close(subscriber.events)
+		}
+	}
+
+	return purged
+}
+
+// Subscribers возвращает число активных подписчиков
+func (b *OrderEventBroker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// remove отключает подписчика, если брокер еще не отключил его сам
+func (b *OrderEventBroker) remove(subscriber *streamSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[subscriber]; ok {
+		delete(b.subscribers, subscriber)
+		close(subscriber.events)
+	}
+}
+
+// snapshot возвращает историю от старых событий к новым; вызывается под блокировкой
+func (b *OrderEventBroker) snapshot() []*entities.OrderEvent {
+	if !b.full {
+		return b.history[:b.next]
+	}
+	events := make([]*entities.OrderEvent, 0, len(b.history))
+	events = append(events, b.history[b.next:]...)
+	return append(events, b.history[:b.next]...)
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type stubPartitionRepository struct {
+	months map[time.Time]bool
+	// blocked месяцы, занятые строками партиции по умолчанию
+	blocked  map[time.Time]bool
+	detached []time.Time
+	dropped  bool
+}
+
+func newStubPartitionRepository(months ...time.Time) *stubPartitionRepository {
+	repo := &stubPartitionRepository{months: make(map[time.Time]bool), blocked: make(map[time.Time]bool)}
+	for _, month := range months {
+		repo.months[month] = true
+	}
+	return repo
+}
+
+func (r *stubPartitionRepository) ListMonths(_ context.Context) ([]time.Time, error) {
+	var months []time.Time
+	for month := range r.months {
+		months = append(months, month)
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Before(months[j]) })
+	return months, nil
+}
+
+func (r *stubPartitionRepository) CreateMonth(_ context.Context, month time.Time) (bool, error) {
+	if r.months[month] || r.blocked[month] {
+		return false, nil
+	}
+	r.months[month] = true
+	return true, nil
+}
+
+func (r *stubPartitionRepository) DetachMonth(_ context.Context, month time.Time, drop bool) (bool, error) {
+	if !r.months[month] {
+		return false, nil
+	}
+	delete(r.months, month)
+	r.detached = append(r.detached, month)
+	r.dropped = drop
+	return true, nil
+}
+
+func utcMonth(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestMaintainOrderPartitionsCreatesAheadAndExpires(t *testing.T) {
+	repo := newStubPartitionRepository(utcMonth(2024, 1), utcMonth(2024, 2), utcMonth(2024, 3), utcMonth(2024, 4), utcMonth(2024, 5))
+	repo.blocked[utcMonth(2024, 7)] = true
+	uc := NewMaintainOrderPartitionsUseCase(repo, PartitionPolicy{MonthsAhead: 2, RetentionMonths: 2, DropExpired: true}, nopLogger{})
+	uc.now = func() time.Time { return time.Date(2024, 5, 31, 23, 30, 0, 0, time.UTC) }
+
+	resp, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(resp.Created, []string{"2024-06"}) {
+		t.Fatalf("expected 2024-06 created (2024-07 blocked by default partition), got %v", resp.Created)
+	}
+	if !reflect.DeepEqual(resp.Expired, []string{"2024-01", "2024-02"}) || !repo.dropped {
+		t.Fatalf("expected 2024-01 and 2024-02 dropped, got %v", resp.Expired)
+	}
+	if !repo.months[utcMonth(2024, 3)] {
+		t.Fatal("expected 2024-03 kept within retention")
+	}
+}
+
+func TestMaintainOrderPartitionsKeepsAllWithoutRetention(t *testing.T) {
+	repo := newStubPartitionRepository(utcMonth(2020, 1))
+	uc := NewMaintainOrderPartitionsUseCase(repo, PartitionPolicy{MonthsAhead: 0}, nopLogger{})
+	uc.now = func() time.Time { return time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC) }
+
+	resp, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Created, []string{"2024-12"}) || len(resp.Expired) != 0 || len(repo.detached) != 0 {
+		t.Fatalf("expected only current month created, got %+v", resp)
+	}
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+type stubReportRepository struct {
+	query repositories.OrderReportQuery
+	rows  []*repositories.OrderReportRow
+}
+
+func (r *stubReportRepository) OrderReport(_ context.Context, query repositories.OrderReportQuery) ([]*repositories.OrderReportRow, error) {
+	r.query = query
+	return r.rows, nil
+}
+
+func (r *stubReportRepository) RefreshDailyStats(context.Context) (int, error) {
+	return 0, nil
+}
+
+func TestOrderReport_GroupsAndAverages(t *testing.T) {
+	month := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := &stubReportRepository{rows: []*repositories.OrderReportRow{
+		{PeriodStart: &month, Currency: "EUR", OrderCount: 3, Revenue: 100, RevenueBase: 110, Unconverted: 1, ItemQuantity: 7},
+		{PeriodStart: &month, Currency: "USD", OrderCount: 1, Revenue: 50, RevenueBase: 50, ItemQuantity: 2},
+	}}
+	uc := NewOrderReportUseCase(repo, "usd", nopLogger{})
+
+	dateFrom := "2025-03-01T23:30:00-02:00"
+	response, err := uc.Execute(context.Background(), &OrderReportRequest{
+		GroupBy:  []string{"month", "currency"},
+		DateFrom: &dateFrom,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if repo.query.Period != repositories.ReportPeriodMonth || !repo.query.GroupByCurrency || repo.query.GroupByStatus {
+		t.Errorf("Unexpected report query %+v", repo.query)
+	}
+	if repo.query.DateFrom == nil || repo.query.DateFrom.Format(reportDateLayout) != "2025-03-02" {
+		t.Errorf("Expected date_from to be the UTC day 2025-03-02, got %v", repo.query.DateFrom)
+	}
+
+	eur := response.Rows[0]
+	if *eur.Period != "2025-03-01" || *eur.Currency != "EUR" || eur.Status != nil {
+		t.Errorf("Unexpected dimensions %+v", eur)
+	}
+	if eur.AverageOrderValueBase != 55 || eur.Revenue == nil || *eur.AverageOrderValue != 33.33 {
+		t.Errorf("Unexpected averages %+v", eur)
+	}
+	if response.Totals.OrderCount != 4 || response.Totals.RevenueBase != 160 || response.Totals.Revenue != nil {
+		t.Errorf("Unexpected totals %+v", response.Totals)
+	}
+}
+
+func TestOrderReport_Validation(t *testing.T) {
+	uc := NewOrderReportUseCase(&stubReportRepository{}, "USD", nopLogger{})
+	from, to := "2025-02-01", "2025-01-01"
+
+	requests := []*OrderReportRequest{
+		{GroupBy: []string{"day", "month"}},
+		{GroupBy: []string{"region"}},
+		{DateFrom: &from, DateTo: &to},
+	}
+	for _, req := range requests {
+		_, err := uc.Execute(context.Background(), req)
+
+		var validationErr entities.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("Expected validation error for %+v, got %v", req, err)
+		}
+	}
+}
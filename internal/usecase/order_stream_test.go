@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"testing"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+func TestOrderEventBroker_FanOut(t *testing.T) {
+	broker := NewOrderEventBroker(10, 10, nopLogger{})
+	order := entities.NewOrder(uuid.New(), "test@example.com")
+	other := entities.NewOrder(uuid.New(), "other@example.com")
+
+	byOrder := broker.Subscribe(OrderStreamFilter{OrderID: order.ID}, uuid.Nil)
+	byCustomer := broker.Subscribe(OrderStreamFilter{CustomerID: order.CustomerID}, uuid.Nil)
+	defer byOrder.Close()
+	defer byCustomer.Close()
+
+	broker.Publish(other.ToEvent(entities.EventOrderCreated))
+	broker.Publish(order.ToEvent(entities.EventOrderConfirmed))
+
+	for _, subscription := range []*OrderStreamSubscription{byOrder, byCustomer} {
+		select {
+		case event := <-subscription.Events:
+			if event.OrderID != order.ID || event.EventType != entities.EventOrderConfirmed {
+				t.Errorf("Unexpected event: %+v", event)
+			}
+		default:
+			t.Fatal("Expected event to be delivered")
+		}
+		if len(subscription.Events) != 0 {
+			t.Errorf("Expected other order events to be filtered out")
+		}
+	}
+}
+
+func TestOrderEventBroker_ReplayAfterLastEventID(t *testing.T) {
+	broker := NewOrderEventBroker(3, 10, nopLogger{})
+	order := entities.NewOrder(uuid.New(), "test@example.com")
+
+	events := []*entities.OrderEvent{
+		order.ToEvent(entities.EventOrderCreated),
+		order.ToEvent(entities.EventOrderConfirmed),
+		order.ToEvent(entities.EventOrderShipped),
+		order.ToEvent(entities.EventOrderDelivered),
+	}
+	for _, event := range events {
+		broker.Publish(event)
+	}
+
+	subscription := broker.Subscribe(OrderStreamFilter{OrderID: order.ID}, events[1].EventID)
+	defer subscription.Close()
+	if subscription.Reset || len(subscription.Replay) != 2 {
+		t.Fatalf("Expected 2 replayed events, got %d (reset=%v)", len(subscription.Replay), subscription.Reset)
+	}
+	if subscription.Replay[0].EventID != events[2].EventID || subscription.Replay[1].EventID != events[3].EventID {
+		t.Error("Expected replay in publish order")
+	}
+
+	// Первое событие вытеснено из истории
+	expired := broker.Subscribe(OrderStreamFilter{OrderID: order.ID}, events[0].EventID)
+	defer expired.Close()
+	if !expired.Reset || len(expired.Replay) != 0 {
+		t.Errorf("Expected reset for expired Last-Event-ID, got %+v", expired)
+	}
+}
+
+func TestOrderEventBroker_DisconnectsSlowSubscriber(t *testing.T) {
+	broker := NewOrderEventBroker(10, 1, nopLogger{})
+	order := entities.NewOrder(uuid.New(), "test@example.com")
+
+	slow := broker.Subscribe(OrderStreamFilter{}, uuid.Nil)
+	broker.Publish(order.ToEvent(entities.EventOrderCreated))
+	broker.Publish(order.ToEvent(entities.EventOrderConfirmed))
+
+	if broker.Subscribers() != 0 {
+		t.Fatalf("Expected slow subscriber to be disconnected")
+	}
+	<-slow.Events
+	if _, ok := <-slow.Events; ok {
+		t.Error("Expected events channel to be closed")
+	}
+
+	// Повторное закрытие после отключения брокером безопасно
+	slow.Close()
+}
+
+func TestOrderEventBroker_PurgeCustomer(t *testing.T) {
+	broker := NewOrderEventBroker(4, 10, nopLogger{})
+	erased := entities.NewOrder(uuid.New(), "erased@example.com")
+	other := entities.NewOrder(uuid.New(), "other@example.com")
+
+	byCustomer := broker.Subscribe(OrderStreamFilter{CustomerID: erased.CustomerID}, uuid.Nil)
+	byOther := broker.Subscribe(OrderStreamFilter{CustomerID: other.CustomerID}, uuid.Nil)
+	defer byOther.Close()
+
+	first := other.ToEvent(entities.EventOrderCreated)
+	broker.Publish(first)
+	broker.Publish(erased.ToEvent(entities.EventOrderCreated))
+	broker.Publish(erased.ToEvent(entities.EventOrderConfirmed))
+	last := other.ToEvent(entities.EventOrderConfirmed)
+	broker.Publish(last)
+
+	if purged := broker.PurgeCustomer(erased.CustomerID); purged != 2 {
+		t.Fatalf("Expected 2 purged events, got %d", purged)
+	}
+	if broker.Subscribers() != 1 {
+		t.Errorf("Expected erased customer subscriber to be disconnected, got %d subscribers", broker.Subscribers())
+	}
+	for range byCustomer.Events {
+	}
+
+	replay := broker.Subscribe(OrderStreamFilter{}, first.EventID)
+	defer replay.Close()
+	if replay.Reset || len(replay.Replay) != 1 || replay.Replay[0].EventID != last.EventID {
+		t.Fatalf("Expected only other customer events in history, got %+v", replay.Replay)
+	}
+
+	// История продолжает работать как кольцевой буфер после очистки
+	for i := 0; i < 3; i++ {
+		broker.Publish(other.ToEvent(entities.EventOrderShipped))
+	}
+	if reset := broker.Subscribe(OrderStreamFilter{}, first.EventID); !reset.Reset {
+		t.Error("Expected overwritten event to require reset")
+	}
+}
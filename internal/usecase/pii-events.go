@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// PIIProtector шифрует персональные данные событий (см. infrastructure/pii.Keyring)
+type PIIProtector interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(value string) (string, error)
+	// BlindIndex возвращает токен значения; пустая строка, если шифрование выключено
+	BlindIndex(value string) string
+}
+
+// eventPIIFields ключи OrderEvent.Data с персональными данными
+var eventPIIFields = []string{"email"}
+
+// ProtectEventPII возвращает копию события с зашифрованными персональными данными.
+// Так события хранятся в Kafka; получатели с ключами расшифровывают их RevealEventPII.
+func ProtectEventPII(event *entities.OrderEvent, protector PIIProtector) (*entities.OrderEvent, error) {
+	return mapEventPII(event, func(key, value string, data map[string]interface{}) error {
+		encrypted, err := protector.Encrypt(value)
+		if err != nil {
+			return err
+		}
+		data[key] = encrypted
+		return nil
+	})
+}
+
+// RevealEventPII возвращает копию события с расшифрованными персональными данными
+func RevealEventPII(event *entities.OrderEvent, protector PIIProtector) (*entities.OrderEvent, error) {
+	return mapEventPII(event, func(key, value string, data map[string]interface{}) error {
+		plaintext, err := protector.Decrypt(value)
+		if err != nil {
+			return err
+		}
+		data[key] = plaintext
+		return nil
+	})
+}
+
+// TokenizeEventPII возвращает копию события, в которой персональные данные заменены
+// токенами <key>_token: получатель может сопоставлять события одного клиента, не видя данных.
+// Без шифрования событие не меняется.
+func TokenizeEventPII(event *entities.OrderEvent, protector PIIProtector) (*entities.OrderEvent, error) {
+	return mapEventPII(event, func(key, value string, data map[string]interface{}) error {
+		plaintext, err := protector.Decrypt(value)
+		if err != nil {
+			return err
+		}
+		token := protector.BlindIndex(entities.NormalizeEmail(plaintext))
+		if token == "" {
+			data[key] = plaintext
+			return nil
+		}
+		delete(data, key)
+		data[key+"_token"] = token
+		return nil
+	})
+}
+
+// mapEventPII копирует событие и применяет fn к каждому строковому полю с персональными данными
+func mapEventPII(event *entities.OrderEvent, fn func(key, value string, data map[string]interface{}) error) (*entities.OrderEvent, error) {
+	result := *event
+	if event.Data == nil {
+		return &result, nil
+	}
+
+	result.Data = make(map[string]interface{}, len(event.Data))
+	for key, value := range event.Data {
+		result.Data[key] = value
+	}
+	for _, key := range eventPIIFields {
+		value, ok := result.Data[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		if err := fn(key, value, result.Data); err != nil {
+			return nil, fmt.Errorf("failed to process event field %s: %w", key, err)
+		}
+	}
+	return &result, nil
+}
+
+// PIIProtectingPublisher шифрует персональные данные событий перед публикацией
+type PIIProtectingPublisher struct {
+	publisher EventPublisher
+	protector PIIProtector
+}
+
+// NewPIIProtectingPublisher создает публикатор, шифрующий персональные данные событий
+func NewPIIProtectingPublisher(publisher EventPublisher, protector PIIProtector) *PIIProtectingPublisher {
+	return &PIIProtectingPublisher{
+		publisher: publisher,
+		protector: protector,
+	}
+}
+
+// PublishOrderEvent публикует копию события с зашифрованными персональными данными
+func (p *PIIProtectingPublisher) PublishOrderEvent(ctx context.Context, event *entities.OrderEvent) error {
+	protected, err := ProtectEventPII(event, p.protector)
+	if err != nil {
+		return err
+	}
+	return p.publisher.PublishOrderEvent(ctx, protected)
+}
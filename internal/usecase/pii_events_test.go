@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// prefixProtector "шифрует" значения префиксом enc:
+type prefixProtector struct{}
+
+func (prefixProtector) Encrypt(plaintext string) (string, error) {
+	if strings.HasPrefix(plaintext, "enc:") {
+		return plaintext, nil
+	}
+	return "enc:" + plaintext, nil
+}
+
+func (prefixProtector) Decrypt(value string) (string, error) {
+	return strings.TrimPrefix(value, "enc:"), nil
+}
+
+func (prefixProtector) BlindIndex(value string) string { return "idx:" + value }
+
+// recordingSender запоминает события, отправленные каждой подписке
+type recordingSender struct {
+	mu   sync.Mutex
+	sent map[string]*entities.OrderEvent
+}
+
+func (s *recordingSender) Send(_ context.Context, subscription *entities.WebhookSubscription, event *entities.OrderEvent) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent[subscription.URL] = event
+	return 200, nil
+}
+
+func TestProtectEventPII_RoundTrip(t *testing.T) {
+	event := entities.NewOrder(uuid.New(), "User@Example.com").ToEvent(entities.EventOrderCreated)
+
+	protected, err := ProtectEventPII(event, prefixProtector{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if protected.Data["email"] != "enc:User@Example.com" {
+		t.Errorf("Expected encrypted email, got %v", protected.Data["email"])
+	}
+	if event.Data["email"] != "User@Example.com" {
+		t.Error("Expected original event to stay unchanged")
+	}
+
+	revealed, _ := RevealEventPII(protected, prefixProtector{})
+	if revealed.Data["email"] != "User@Example.com" {
+		t.Errorf("Expected revealed email, got %v", revealed.Data["email"])
+	}
+
+	tokenized, _ := TokenizeEventPII(protected, prefixProtector{})
+	if _, ok := tokenized.Data["email"]; ok {
+		t.Error("Expected email to be removed from tokenized event")
+	}
+	if tokenized.Data["email_token"] != "idx:user@example.com" {
+		t.Errorf("Expected token of normalized email, got %v", tokenized.Data["email_token"])
+	}
+}
+
+func TestWebhookDispatcher_PIIBySubscription(t *testing.T) {
+	repo := &memoryWebhookRepository{}
+	partner := entities.NewWebhookSubscription("https://partner.example.com/hook", nil, "0123456789abcdef-secret")
+	internal := entities.NewWebhookSubscription("https://crm.example.com/hook", nil, "0123456789abcdef-secret")
+	internal.IncludePII = true
+	_ = repo.Create(context.Background(), partner)
+	_ = repo.Create(context.Background(), internal)
+
+	sender := &recordingSender{sent: make(map[string]*entities.OrderEvent)}
+	dispatcher := NewWebhookDispatcher(repo, sender, WebhookRetryPolicy{MaxAttempts: 1}, prefixProtector{}, nopLogger{})
+
+	event := entities.NewOrder(uuid.New(), "user@example.com").ToEvent(entities.EventOrderCreated)
+	protected, _ := ProtectEventPII(event, prefixProtector{})
+	if err := dispatcher.Dispatch(context.Background(), protected); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := sender.sent[internal.URL].Data["email"]; got != "user@example.com" {
+		t.Errorf("Expected plaintext email for include_pii subscription, got %v", got)
+	}
+	partnerEvent := sender.sent[partner.URL]
+	if _, ok := partnerEvent.Data["email"]; ok || partnerEvent.Data["email_token"] != "idx:user@example.com" {
+		t.Errorf("Expected tokenized email for partner, got %v", partnerEvent.Data)
+	}
+}
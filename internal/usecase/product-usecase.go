@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// CatalogService проверяет позиции заказа по каталогу товаров
+type CatalogService struct {
+	productRepo repositories.ProductRepository
+	logger      Logger
+}
+
+// NewCatalogService создает сервис каталога товаров
+func NewCatalogService(productRepo repositories.ProductRepository, logger Logger) *CatalogService {
+	return &CatalogService{
+		productRepo: productRepo,
+		logger:      logger,
+	}
+}
+
+// ResolveItems заполняет название, цену, налоговую категорию и вес позиций из каталога.
+// Неизвестные и неактивные товары, а также расхождение цены с каталогом отклоняются.
+func (s *CatalogService) ResolveItems(ctx context.Context, currency string, items []CreateOrderItemRequest) ([]CreateOrderItemRequest, error) {
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]*entities.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	resolved := make([]CreateOrderItemRequest, 0, len(items))
+	for i, item := range items {
+		product, exists := byID[item.ProductID]
+		if !exists {
+			return nil, entities.NewValidationError("item %d: unknown product %s", i, item.ProductID)
+		}
+
+		price, err := product.CheckOrderable(currency, item.Price)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		item.Name = product.Name
+		item.Price = price
+		item.TaxCategory = product.TaxCategory
+		item.Weight = product.Weight
+		resolved = append(resolved, item)
+	}
+
+	return resolved, nil
+}
+
+// ImportProducts сохраняет товары, загруженные из файла, обновляя существующие по артикулу
+func (s *CatalogService) ImportProducts(ctx context.Context, products []*entities.Product) error {
+	for _, product := range products {
+		if err := product.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.productRepo.UpsertBySKU(ctx, products); err != nil {
+		return fmt.Errorf("failed to save products: %w", err)
+	}
+
+	s.logger.Info("Product catalog imported", "count", len(products))
+	return nil
+}
+
+// SaveProductRequest представляет запрос на создание или изменение товара
+type SaveProductRequest struct {
+	ID          uuid.UUID          `json:"-"`
+	SKU         string             `json:"sku" validate:"required"`
+	Name        string             `json:"name" validate:"required"`
+	Prices      map[string]float64 `json:"prices" validate:"required"`
+	Active      *bool              `json:"active,omitempty"` // По умолчанию true
+	TaxCategory string             `json:"tax_category,omitempty"`
+	Weight      float64            `json:"weight,omitempty"`
+}
+
+// ProductResponse представляет ответ с товаром
+type ProductResponse struct {
+	Product *entities.Product `json:"product"`
+	Message string            `json:"message,omitempty"`
+}
+
+// toProduct переносит поля запроса в товар
+func (req *SaveProductRequest) toProduct(product *entities.Product) {
+	product.SKU = entities.NormalizeSKU(req.SKU)
+	product.Name = strings.TrimSpace(req.Name)
+	product.Prices = make(map[string]float64, len(req.Prices))
+	for currency, price := range req.Prices {
+		product.SetPrice(currency, price)
+	}
+	product.Active = req.Active == nil || *req.Active
+	product.TaxCategory = req.TaxCategory
+	product.Weight = req.Weight
+}
+
+// CreateProductUseCase представляет use case создания товара
+type CreateProductUseCase struct {
+	productRepo repositories.ProductRepository
+	logger      Logger
+}
+
+// NewCreateProductUseCase создает новый use case для создания товара
+func NewCreateProductUseCase(productRepo repositories.ProductRepository, logger Logger) *CreateProductUseCase {
+	return &CreateProductUseCase{
+		productRepo: productRepo,
+		logger:      logger,
+	}
+}
+
+// Execute выполняет создание товара
+func (uc *CreateProductUseCase) Execute(ctx context.Context, req *SaveProductRequest) (*ProductResponse, error) {
+	if req == nil {
+		return nil, entities.NewValidationError("request cannot be nil")
+	}
+	if err := entities.CheckSharedCatalogAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	product := entities.NewProduct(req.SKU, req.Name)
+	req.toProduct(product)
+
+	if err := product.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := uc.productRepo.Create(ctx, product); err != nil {
+		uc.logger.Error("Failed to create product", "error", err, "sku", product.SKU)
+		return nil, fmt.Errorf("failed to save product: %w", err)
+	}
+
+	uc.logger.Info("Product created successfully", "product_id", product.ID, "sku", product.SKU)
+
+	return &ProductResponse{
+		Product: product,
+		Message: "Product created successfully",
+	}, nil
+}
+
+// UpdateProductUseCase представляет use case изменения товара
+type UpdateProductUseCase struct {
+	productRepo repositories.ProductRepository
+	logger      Logger
+}
+
+// NewUpdateProductUseCase создает новый use case для изменения товара
+func NewUpdateProductUseCase(productRepo repositories.ProductRepository, logger Logger) *UpdateProductUseCase {
+	return &UpdateProductUseCase{
+		productRepo: productRepo,
+		logger:      logger,
+	}
+}
+
+// Execute заменяет данные и цены товара
+func (uc *UpdateProductUseCase) Execute(ctx context.Context, req *SaveProductRequest) (*ProductResponse, error) {
+	if req == nil || req.ID == uuid.Nil {
+		return nil, entities.NewValidationError("product id is required")
+	}
+	if err := entities.CheckSharedCatalogAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	req.toProduct(product)
+	product.UpdatedAt = time.Now()
+
+	if err := product.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := uc.productRepo.Update(ctx, product); err != nil {
+		uc.logger.Error("Failed to update product", "error", err, "product_id", product.ID)
+		return nil, fmt.Errorf("failed to save product: %w", err)
+	}
+
+	uc.logger.Info("Product updated successfully", "product_id", product.ID, "sku", product.SKU)
+
+	return &ProductResponse{
+		Product: product,
+		Message: "Product updated successfully",
+	}, nil
+}
+
+// GetProductUseCase представляет use case получения товара
+type GetProductUseCase struct {
+	productRepo repositories.ProductRepository
+	logger      Logger
+}
+
+// NewGetProductUseCase создает новый use case для получения товара
+func NewGetProductUseCase(productRepo repositories.ProductRepository, logger Logger) *GetProductUseCase {
+	return &GetProductUseCase{
+		productRepo: productRepo,
+		logger:      logger,
+	}
+}
+
+// Execute получает товар по ID
+func (uc *GetProductUseCase) Execute(ctx context.Context, id uuid.UUID) (*ProductResponse, error) {
+	product, err := uc.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	return &ProductResponse{Product: product}, nil
+}
+
+// DeleteProductUseCase представляет use case удаления товара
+type DeleteProductUseCase struct {
+	productRepo repositories.ProductRepository
+	logger      Logger
+}
+
+// NewDeleteProductUseCase создает новый use case для удаления товара
+func NewDeleteProductUseCase(productRepo repositories.ProductRepository, logger Logger) *DeleteProductUseCase {
+	return &DeleteProductUseCase{
+		productRepo: productRepo,
+		logger:      logger,
+	}
+}
+
+// Execute удаляет товар. Уже оформленные заказы хранят название и цену в позициях.
+func (uc *DeleteProductUseCase) Execute(ctx context.Context, id uuid.UUID) error {
+	if err := entities.CheckSharedCatalogAccess(ctx); err != nil {
+		return err
+	}
+	if err := uc.productRepo.Delete(ctx, id); err != nil {
+		uc.logger.Error("Failed to delete product", "error", err, "product_id", id)
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+
+	uc.logger.Info("Product deleted", "product_id", id)
+	return nil
+}
+
+// ListProductsRequest представляет запрос списка товаров
+type ListProductsRequest struct {
+	ActiveOnly bool `json:"active_only"`
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+}
+
+// ListProductsResponse представляет ответ со списком товаров
+type ListProductsResponse struct {
+	Products []*entities.Product `json:"products"`
+}
+
+// ListProductsUseCase представляет use case получения списка товаров
+type ListProductsUseCase struct {
+	productRepo repositories.ProductRepository
+	logger      Logger
+}
+
+// NewListProductsUseCase создает новый use case для получения списка товаров
+func NewListProductsUseCase(productRepo repositories.ProductRepository, logger Logger) *ListProductsUseCase {
+	return &ListProductsUseCase{
+		productRepo: productRepo,
+		logger:      logger,
+	}
+}
+
+// Execute выполняет получение списка товаров
+func (uc *ListProductsUseCase) Execute(ctx context.Context, req *ListProductsRequest) (*ListProductsResponse, error) {
+	if req == nil {
+		req = &ListProductsRequest{}
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	products, err := uc.productRepo.List(ctx, req.ActiveOnly, req.Limit, req.Offset)
+	if err != nil {
+		uc.logger.Error("Failed to list products", "error", err)
+		return nil, fmt.Errorf("failed to list products: %w", err)
+	}
+
+	return &ListProductsResponse{Products: products}, nil
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// PromotionService проверяет купоны и применяет скидки к заказу
+type PromotionService struct {
+	promotionRepo repositories.PromotionRepository
+	logger        Logger
+}
+
+// NewPromotionService создает сервис применения купонов
+func NewPromotionService(promotionRepo repositories.PromotionRepository, logger Logger) *PromotionService {
+	return &PromotionService{
+		promotionRepo: promotionRepo,
+		logger:        logger,
+	}
+}
+
+// ApplyCoupons проверяет коды купонов и применяет скидки к заказу.
+// Лимиты использования окончательно проверяются при сохранении заказа.
+func (s *PromotionService) ApplyCoupons(ctx context.Context, order *entities.Order, codes []string) error {
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if code = entities.NormalizeCouponCode(code); code != "" {
+			normalized = append(normalized, code)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	found, err := s.promotionRepo.GetByCodes(ctx, normalized)
+	if err != nil {
+		return fmt.Errorf("failed to get promotions: %w", err)
+	}
+
+	byCode := make(map[string]*entities.Promotion, len(found))
+	for _, p := range found {
+		byCode[p.Code] = p
+	}
+
+	now := time.Now()
+	promotions := make([]*entities.Promotion, 0, len(normalized))
+	for _, code := range normalized {
+		p, exists := byCode[code]
+		if !exists {
+			return entities.NewValidationError("unknown coupon code: %s", code)
+		}
+		if err := p.CheckAvailable(now); err != nil {
+			return err
+		}
+
+		if p.PerCustomerLimit > 0 {
+			used, err := s.promotionRepo.CountCustomerRedemptions(ctx, p.ID, order.CustomerID)
+			if err != nil {
+				return err
+			}
+			if used >= p.PerCustomerLimit {
+				return entities.NewValidationError("coupon %s usage limit per customer reached", code)
+			}
+		}
+
+		promotions = append(promotions, p)
+	}
+
+	if err := order.ApplyPromotions(promotions); err != nil {
+		return err
+	}
+
+	s.logger.Info("Coupons applied",
+		"order_id", order.ID,
+		"coupon_codes", order.CouponCodes(),
+		"subtotal", order.Subtotal,
+		"discount_amount", order.Discount)
+
+	return nil
+}
+
+// CreatePromotionRequest представляет запрос на создание промоакции
+type CreatePromotionRequest struct {
+	Code             string                 `json:"code" validate:"required"`
+	Name             string                 `json:"name"`
+	Type             entities.PromotionType `json:"type" validate:"required"`
+	Value            float64                `json:"value,omitempty"`
+	BuyQuantity      int                    `json:"buy_quantity,omitempty"`
+	GetQuantity      int                    `json:"get_quantity,omitempty"`
+	ProductIDs       []uuid.UUID            `json:"product_ids,omitempty"`
+	MinSubtotal      float64                `json:"min_subtotal,omitempty"`
+	StartsAt         *time.Time             `json:"starts_at,omitempty"`
+	EndsAt           *time.Time             `json:"ends_at,omitempty"`
+	UsageLimit       int                    `json:"usage_limit,omitempty"`
+	PerCustomerLimit int                    `json:"per_customer_limit,omitempty"`
+	Stackable        bool                   `json:"stackable"`
+}
+
+// CreatePromotionResponse представляет ответ создания промоакции
+type CreatePromotionResponse struct {
+	Promotion *entities.Promotion `json:"promotion"`
+	Message   string              `json:"message"`
+}
+
+// CreatePromotionUseCase представляет use case создания промоакции
+type CreatePromotionUseCase struct {
+	promotionRepo repositories.PromotionRepository
+	logger        Logger
+}
+
+// NewCreatePromotionUseCase создает новый use case для создания промоакции
+func NewCreatePromotionUseCase(promotionRepo repositories.PromotionRepository, logger Logger) *CreatePromotionUseCase {
+	return &CreatePromotionUseCase{
+		promotionRepo: promotionRepo,
+		logger:        logger,
+	}
+}
+
+// Execute выполняет создание промоакции
+func (uc *CreatePromotionUseCase) Execute(ctx context.Context, req *CreatePromotionRequest) (*CreatePromotionResponse, error) {
+	if req == nil {
+		return nil, entities.NewValidationError("request cannot be nil")
+	}
+
+	promotion := entities.NewPromotion(req.Code, req.Name, req.Type)
+	promotion.Value = req.Value
+	promotion.BuyQuantity = req.BuyQuantity
+	promotion.GetQuantity = req.GetQuantity
+	promotion.ProductIDs = req.ProductIDs
+	promotion.MinSubtotal = req.MinSubtotal
+	promotion.StartsAt = req.StartsAt
+	promotion.EndsAt = req.EndsAt
+	promotion.UsageLimit = req.UsageLimit
+	promotion.PerCustomerLimit = req.PerCustomerLimit
+	promotion.Stackable = req.Stackable
+
+	if err := promotion.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := uc.promotionRepo.Create(ctx, promotion); err != nil {
+		uc.logger.Error("Failed to create promotion", "error", err, "code", promotion.Code)
+		return nil, fmt.Errorf("failed to save promotion: %w", err)
+	}
+
+	uc.logger.Info("Promotion created successfully",
+		"promotion_id", promotion.ID,
+		"code", promotion.Code,
+		"type", promotion.Type)
+
+	return &CreatePromotionResponse{
+		Promotion: promotion,
+		Message:   "Promotion created successfully",
+	}, nil
+}
+
+// ListPromotionsRequest представляет запрос списка промоакций
+type ListPromotionsRequest struct {
+	ActiveOnly bool `json:"active_only"`
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+}
+
+// ListPromotionsResponse представляет ответ со списком промоакций
+type ListPromotionsResponse struct {
+	Promotions []*entities.Promotion `json:"promotions"`
+}
+
+// ListPromotionsUseCase представляет use case получения списка промоакций
+type ListPromotionsUseCase struct {
+	promotionRepo repositories.PromotionRepository
+	logger        Logger
+}
+
+// NewListPromotionsUseCase создает новый use case для получения списка промоакций
+func NewListPromotionsUseCase(promotionRepo repositories.PromotionRepository, logger Logger) *ListPromotionsUseCase {
+	return &ListPromotionsUseCase{
+		promotionRepo: promotionRepo,
+		logger:        logger,
+	}
+}
+
+// Execute выполняет получение списка промоакций
+func (uc *ListPromotionsUseCase) Execute(ctx context.Context, req *ListPromotionsRequest) (*ListPromotionsResponse, error) {
+	if req == nil {
+		req = &ListPromotionsRequest{}
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	promotions, err := uc.promotionRepo.List(ctx, req.ActiveOnly, req.Limit, req.Offset)
+	if err != nil {
+		uc.logger.Error("Failed to list promotions", "error", err)
+		return nil, fmt.Errorf("failed to list promotions: %w", err)
+	}
+
+	return &ListPromotionsResponse{Promotions: promotions}, nil
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// reportDateLayout формат дат отчета
+const reportDateLayout = "2006-01-02"
+
+// OrderReportRequest представляет запрос отчета по заказам
+type OrderReportRequest struct {
+	// Измерения: status, currency, country и не больше одного периода day, week, month.
+	// Пусто - группировка по статусу.
+	GroupBy []string `json:"group_by,omitempty"`
+	// Дни по UTC включительно: YYYY-MM-DD или RFC3339
+	DateFrom *string `json:"date_from,omitempty"`
+	DateTo   *string `json:"date_to,omitempty"`
+	Status   *string `json:"status,omitempty"`
+	Currency *string `json:"currency,omitempty"`
+	Country  *string `json:"country,omitempty"`
+}
+
+// OrderReportEntry агрегаты группы отчета; поля измерений заполнены, только если по ним группировали
+type OrderReportEntry struct {
+	Period   *string `json:"period,omitempty"` // начало периода, YYYY-MM-DD
+	Status   *string `json:"status,omitempty"`
+	Currency *string `json:"currency,omitempty"`
+	Country  *string `json:"country,omitempty"` // пусто - заказы без адреса доставки
+
+	OrderCount int64 `json:"order_count"`
+	// Выручка и средний чек в валюте заказов - только когда валюта группы известна
+	Revenue           *float64 `json:"revenue,omitempty"`
+	AverageOrderValue *float64 `json:"average_order_value,omitempty"`
+	// Выручка и средний чек в отчетной валюте; средний чек без неконвертированных заказов
+	RevenueBase           float64 `json:"revenue_base"`
+	AverageOrderValueBase float64 `json:"average_order_value_base"`
+	Unconverted           int64   `json:"unconverted"`
+	ItemQuantity          int64   `json:"item_quantity"`
+}
+
+// OrderReportResponse представляет отчет по заказам
+type OrderReportResponse struct {
+	ReportingCurrency string              `json:"reporting_currency"`
+	GroupBy           []string            `json:"group_by"`
+	Rows              []*OrderReportEntry `json:"rows"`
+	Totals            *OrderReportEntry   `json:"totals"`
+}
+
+// OrderReportUseCase представляет use case отчетов по заказам
+type OrderReportUseCase struct {
+	reportRepo        repositories.ReportRepository
+	reportingCurrency string
+	logger            Logger
+}
+
+// NewOrderReportUseCase создает новый use case отчетов по заказам
+func NewOrderReportUseCase(
+	reportRepo repositories.ReportRepository,
+	reportingCurrency string,
+	logger Logger,
+) *OrderReportUseCase {
+	return &OrderReportUseCase{
+		reportRepo:        reportRepo,
+		reportingCurrency: entities.NormalizeCurrency(reportingCurrency),
+		logger:            logger,
+	}
+}
+
+// Execute строит отчет по заказам
+func (uc *OrderReportUseCase) Execute(ctx context.Context, req *OrderReportRequest) (*OrderReportResponse, error) {
+	if req == nil {
+		req = &OrderReportRequest{}
+	}
+
+	query, groupBy, err := buildOrderReportQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := uc.reportRepo.OrderReport(ctx, query)
+	if err != nil {
+		uc.logger.Error("Failed to get order report", "error", err)
+		return nil, fmt.Errorf("failed to get order report: %w", err)
+	}
+
+	// Выручка в валюте заказов складывается, только если валюта одна на группу
+	rowCurrencyKnown := query.GroupByCurrency || query.Currency != nil
+	response := &OrderReportResponse{
+		ReportingCurrency: uc.reportingCurrency,
+		GroupBy:           groupBy,
+		Rows:              make([]*OrderReportEntry, 0, len(rows)),
+	}
+
+	var totals repositories.OrderReportRow
+	for _, row := range rows {
+		response.Rows = append(response.Rows, newOrderReportEntry(row, query, rowCurrencyKnown))
+
+		totals.OrderCount += row.OrderCount
+		totals.Revenue += row.Revenue
+		totals.RevenueBase += row.RevenueBase
+		totals.Unconverted += row.Unconverted
+		totals.ItemQuantity += row.ItemQuantity
+	}
+	response.Totals = newOrderReportEntry(&totals, repositories.OrderReportQuery{}, query.Currency != nil)
+
+	return response, nil
+}
+
+// buildOrderReportQuery проверяет запрос и переводит его в запрос к репозиторию
+func buildOrderReportQuery(req *OrderReportRequest) (repositories.OrderReportQuery, []string, error) {
+	var query repositories.OrderReportQuery
+
+	groupBy := make([]string, 0, len(req.GroupBy))
+	for _, dimension := range req.GroupBy {
+		dimension = strings.ToLower(strings.TrimSpace(dimension))
+		switch dimension {
+		case "":
+			continue
+		case "status":
+			query.GroupByStatus = true
+		case "currency":
+			query.GroupByCurrency = true
+		case "country":
+			query.GroupByCountry = true
+		case string(repositories.ReportPeriodDay), string(repositories.ReportPeriodWeek), string(repositories.ReportPeriodMonth):
+			if query.Period != "" && query.Period != repositories.ReportPeriod(dimension) {
+				return query, nil, entities.NewValidationError("only one of day, week, month can be used in group_by")
+			}
+			query.Period = repositories.ReportPeriod(dimension)
+		default:
+			return query, nil, entities.NewValidationError("unsupported group_by dimension: %s", dimension)
+		}
+		groupBy = append(groupBy, dimension)
+	}
+	if len(groupBy) == 0 {
+		query.GroupByStatus = true
+		groupBy = append(groupBy, "status")
+	}
+
+	var err error
+	if query.DateFrom, err = parseReportDate("date_from", req.DateFrom); err != nil {
+		return query, nil, err
+	}
+	if query.DateTo, err = parseReportDate("date_to", req.DateTo); err != nil {
+		return query, nil, err
+	}
+	if query.DateFrom != nil && query.DateTo != nil && query.DateFrom.After(*query.DateTo) {
+		return query, nil, entities.NewValidationError("date_from cannot be after date_to")
+	}
+
+	if req.Currency != nil {
+		currency := entities.NormalizeCurrency(*req.Currency)
+		if err := entities.ValidateCurrency(currency); err != nil {
+			return query, nil, err
+		}
+		query.Currency = &currency
+	}
+	query.Status = req.Status
+	query.Country = req.Country
+
+	return query, groupBy, nil
+}
+
+// parseReportDate разбирает дату отчета; у времени берется день по UTC
+func parseReportDate(field string, value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(reportDateLayout, *value)
+	if err != nil {
+		moment, rfcErr := time.Parse(time.RFC3339, *value)
+		if rfcErr != nil {
+			return nil, entities.NewValidationError("%s must be a date (YYYY-MM-DD) or RFC3339 time", field)
+		}
+		moment = moment.UTC()
+		date = time.Date(moment.Year(), moment.Month(), moment.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	return &date, nil
+}
+
+// newOrderReportEntry переводит строку репозитория в строку отчета со средними значениями
+func newOrderReportEntry(row *repositories.OrderReportRow, query repositories.OrderReportQuery, currencyKnown bool) *OrderReportEntry {
+	entry := &OrderReportEntry{
+		OrderCount:   row.OrderCount,
+		RevenueBase:  roundMoney(row.RevenueBase),
+		Unconverted:  row.Unconverted,
+		ItemQuantity: row.ItemQuantity,
+	}
+
+	if query.Period != "" && row.PeriodStart != nil {
+		period := row.PeriodStart.Format(reportDateLayout)
+		entry.Period = &period
+	}
+	if query.GroupByStatus {
+		entry.Status = &row.Status
+	}
+	if query.GroupByCurrency {
+		entry.Currency = &row.Currency
+	}
+	if query.GroupByCountry {
+		entry.Country = &row.Country
+	}
+
+	if converted := row.OrderCount - row.Unconverted; converted > 0 {
+		entry.AverageOrderValueBase = roundMoney(row.RevenueBase / float64(converted))
+	}
+	if currencyKnown {
+		revenue := roundMoney(row.Revenue)
+		entry.Revenue = &revenue
+		if row.OrderCount > 0 {
+			average := roundMoney(row.Revenue / float64(row.OrderCount))
+			entry.AverageOrderValue = &average
+		}
+	}
+
+	return entry
+}
+
+// roundMoney округляет сумму до копеек
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// RefreshOrderReportsUseCase представляет use case пересчета дневного среза отчетов
+type RefreshOrderReportsUseCase struct {
+	reportRepo repositories.ReportRepository
+	logger     Logger
+}
+
+// NewRefreshOrderReportsUseCase создает новый use case пересчета дневного среза
+func NewRefreshOrderReportsUseCase(reportRepo repositories.ReportRepository, logger Logger) *RefreshOrderReportsUseCase {
+	return &RefreshOrderReportsUseCase{
+		reportRepo: reportRepo,
+		logger:     logger,
+	}
+}
+
+// Execute пересчитывает устаревшие дни среза и возвращает их количество
+func (uc *RefreshOrderReportsUseCase) Execute(ctx context.Context) (int, error) {
+	days, err := uc.reportRepo.RefreshDailyStats(ctx)
+	if err != nil {
+		uc.logger.Error("Failed to refresh order reports", "error", err)
+		return 0, fmt.Errorf("failed to refresh order reports: %w", err)
+	}
+
+	if days > 0 {
+		uc.logger.Info("Order reports refreshed", "days", days)
+	}
+	return days, nil
+}
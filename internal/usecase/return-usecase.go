@@ -0,0 +1,418 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// CreateReturnRequest представляет запрос на создание заявки на возврат
+type CreateReturnRequest struct {
+	OrderID uuid.UUID                 `json:"order_id" validate:"required"`
+	Reason  string                    `json:"reason,omitempty"`
+	Items   []CreateReturnItemRequest `json:"items" validate:"required,min=1"`
+}
+
+// CreateReturnItemRequest представляет позицию заказа в заявке на возврат
+type CreateReturnItemRequest struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Quantity    int       `json:"quantity" validate:"required,gt=0"`
+	Reason      string    `json:"reason,omitempty"` // Пусто - причина заявки
+}
+
+// CreateReturnResponse представляет ответ создания заявки на возврат
+type CreateReturnResponse struct {
+	Return  *entities.ReturnRequest `json:"return"`
+	Message string                  `json:"message"`
+}
+
+// CreateReturnUseCase представляет use case создания заявки на возврат
+type CreateReturnUseCase struct {
+	orderRepo  repositories.OrderRepository
+	returnRepo repositories.ReturnRepository
+	publisher  EventPublisher
+	logger     Logger
+}
+
+// NewCreateReturnUseCase создает новый use case для создания заявки на возврат
+func NewCreateReturnUseCase(
+	orderRepo repositories.OrderRepository,
+	returnRepo repositories.ReturnRepository,
+	publisher EventPublisher,
+	logger Logger,
+) *CreateReturnUseCase {
+	return &CreateReturnUseCase{
+		orderRepo:  orderRepo,
+		returnRepo: returnRepo,
+		publisher:  publisher,
+		logger:     logger,
+	}
+}
+
+// Execute выполняет создание заявки на возврат
+func (uc *CreateReturnUseCase) Execute(ctx context.Context, req *CreateReturnRequest) (*CreateReturnResponse, error) {
+	if req == nil || req.OrderID == uuid.Nil {
+		return nil, entities.NewValidationError("order_id is required")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil {
+		uc.logger.Error("Failed to get order", "error", err, "order_id", req.OrderID)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if !order.CanRequestReturn() {
+		return nil, entities.NewValidationError("order in status %s cannot be returned", order.Status)
+	}
+
+	ret := entities.NewReturnRequest(order.ID, req.Reason)
+	for _, item := range req.Items {
+		ret.AddItem(item.OrderItemID, item.Quantity, item.Reason)
+	}
+
+	if err := ret.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	existing, err := uc.returnRepo.GetReturnsByOrderID(ctx, order.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get order returns", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("failed to get returns: %w", err)
+	}
+
+	if err := order.ValidateReturn(existing, ret); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := uc.returnRepo.CreateReturn(ctx, ret); err != nil {
+		uc.logger.Error("Failed to create return", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("failed to save return: %w", err)
+	}
+
+	uc.logger.Info("Return requested successfully",
+		"order_id", order.ID,
+		"return_id", ret.ID,
+		"items_count", len(ret.Items))
+
+	event := order.ToEvent(entities.EventOrderReturnRequested)
+	event.Data["return_id"] = ret.ID.String()
+	event.Data["return_items"] = ret.Items
+	event.Data["return_amount"] = ret.RefundAmount(order)
+	if err := uc.publisher.PublishOrderEvent(ctx, event); err != nil {
+		uc.logger.Error("Failed to publish return requested event",
+			"error", err,
+			"order_id", order.ID,
+			"event_id", event.EventID)
+	}
+
+	return &CreateReturnResponse{
+		Return:  ret,
+		Message: "Return requested successfully",
+	}, nil
+}
+
+// ResolveReturnRequest представляет запрос решения по заявке на возврат
+type ResolveReturnRequest struct {
+	OrderID  uuid.UUID `json:"order_id" validate:"required"`
+	ReturnID uuid.UUID `json:"return_id" validate:"required"`
+	Approve  bool      `json:"approve"`
+	Note     string    `json:"note,omitempty"`
+}
+
+// ResolveReturnResponse представляет ответ решения по заявке на возврат
+type ResolveReturnResponse struct {
+	Return  *entities.ReturnRequest `json:"return"`
+	Message string                  `json:"message"`
+}
+
+// ResolveReturnUseCase представляет use case одобрения или отклонения заявки на возврат
+type ResolveReturnUseCase struct {
+	orderRepo  repositories.OrderRepository
+	returnRepo repositories.ReturnRepository
+	logger     Logger
+}
+
+// NewResolveReturnUseCase создает новый use case для решения по заявке на возврат
+func NewResolveReturnUseCase(orderRepo repositories.OrderRepository, returnRepo repositories.ReturnRepository, logger Logger) *ResolveReturnUseCase {
+	return &ResolveReturnUseCase{
+		orderRepo:  orderRepo,
+		returnRepo: returnRepo,
+		logger:     logger,
+	}
+}
+
+// Execute выполняет одобрение или отклонение заявки
+func (uc *ResolveReturnUseCase) Execute(ctx context.Context, req *ResolveReturnRequest) (*ResolveReturnResponse, error) {
+	if req == nil || req.ReturnID == uuid.Nil {
+		return nil, entities.NewValidationError("return_id is required")
+	}
+
+	// Заказ ищется в рамках арендатора: заявку чужого заказа решить нельзя
+	order, err := uc.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil {
+		uc.logger.Error("Failed to get order", "error", err, "order_id", req.OrderID)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	ret, err := uc.returnRepo.GetReturnByID(ctx, req.ReturnID)
+	if err != nil {
+		uc.logger.Error("Failed to get return", "error", err, "return_id", req.ReturnID)
+		return nil, fmt.Errorf("failed to get return: %w", err)
+	}
+
+	if ret.OrderID != order.ID {
+		return nil, entities.NewReturnNotFoundError(req.ReturnID.String())
+	}
+
+	if req.Approve {
+		err = ret.Approve(req.Note)
+	} else {
+		err = ret.Reject(req.Note)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := uc.returnRepo.ResolveReturn(ctx, ret); err != nil {
+		uc.logger.Error("Failed to resolve return", "error", err, "return_id", ret.ID)
+		return nil, fmt.Errorf("failed to save return: %w", err)
+	}
+
+	uc.logger.Info("Return resolved",
+		"order_id", ret.OrderID,
+		"return_id", ret.ID,
+		"status", ret.Status)
+
+	return &ResolveReturnResponse{
+		Return:  ret,
+		Message: fmt.Sprintf("Return %s", ret.Status),
+	}, nil
+}
+
+// IssueRefundRequest представляет запрос на возврат средств
+type IssueRefundRequest struct {
+	OrderID  uuid.UUID  `json:"order_id" validate:"required"`
+	ReturnID *uuid.UUID `json:"return_id,omitempty"` // Одобренная заявка на возврат
+	Amount   float64    `json:"amount,omitempty"`    // Пусто - стоимость позиций заявки
+	Reason   string     `json:"reason,omitempty"`
+}
+
+// IssueRefundResponse представляет ответ возврата средств
+type IssueRefundResponse struct {
+	Refund           *entities.Refund     `json:"refund"`
+	OrderStatus      entities.OrderStatus `json:"order_status"`
+	RefundableAmount float64              `json:"refundable_amount"`
+	Message          string               `json:"message"`
+}
+
+// IssueRefundUseCase представляет use case возврата средств по заказу
+type IssueRefundUseCase struct {
+	orderRepo     repositories.OrderRepository
+	returnRepo    repositories.ReturnRepository
+	publisher     EventPublisher
+	stateMachines *entities.StateMachineRegistry
+	logger        Logger
+}
+
+// NewIssueRefundUseCase создает новый use case для возврата средств
+func NewIssueRefundUseCase(
+	orderRepo repositories.OrderRepository,
+	returnRepo repositories.ReturnRepository,
+	publisher EventPublisher,
+	stateMachines *entities.StateMachineRegistry,
+	logger Logger,
+) *IssueRefundUseCase {
+	if stateMachines == nil {
+		stateMachines = entities.NewStateMachineRegistry(entities.DefaultStateMachine(), nil)
+	}
+
+	return &IssueRefundUseCase{
+		orderRepo:     orderRepo,
+		returnRepo:    returnRepo,
+		publisher:     publisher,
+		stateMachines: stateMachines,
+		logger:        logger,
+	}
+}
+
+// Execute выполняет возврат средств и пересчет статуса заказа
+func (uc *IssueRefundUseCase) Execute(ctx context.Context, req *IssueRefundRequest) (*IssueRefundResponse, error) {
+	if req == nil || req.OrderID == uuid.Nil {
+		return nil, entities.NewValidationError("order_id is required")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil {
+		uc.logger.Error("Failed to get order", "error", err, "order_id", req.OrderID)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	amount := req.Amount
+	if req.ReturnID != nil {
+		ret, err := uc.returnRepo.GetReturnByID(ctx, *req.ReturnID)
+		if err != nil {
+			uc.logger.Error("Failed to get return", "error", err, "return_id", *req.ReturnID)
+			return nil, fmt.Errorf("failed to get return: %w", err)
+		}
+		if ret.OrderID != order.ID {
+			return nil, entities.NewReturnNotFoundError(req.ReturnID.String())
+		}
+		if ret.Status != entities.ReturnStatusApproved {
+			return nil, entities.NewValidationError("return %s is %s, only approved returns can be refunded", ret.ID, ret.Status)
+		}
+		if amount == 0 {
+			amount = ret.RefundAmount(order)
+		}
+	}
+
+	existing, err := uc.returnRepo.GetRefundsByOrderID(ctx, order.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get order refunds", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("failed to get refunds: %w", err)
+	}
+
+	newStatus, err := order.RefundStatusAfter(existing, amount)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Производный статус заказа проходит через машину состояний
+	oldStatus := order.Status
+	var transition entities.TransitionDefinition
+	if newStatus != oldStatus {
+		transition, err = order.TransitionTo(uc.stateMachines.ForOrder(order), newStatus)
+		if err != nil {
+			uc.logger.Error("Order cannot be refunded in current status",
+				"error", err,
+				"order_id", order.ID,
+				"status", oldStatus)
+			return nil, fmt.Errorf("status update failed: %w", err)
+		}
+	}
+
+	refund := entities.NewRefund(order, req.ReturnID, amount, req.Reason)
+	if err := uc.returnRepo.CreateRefund(ctx, refund, oldStatus, order.Status); err != nil {
+		uc.logger.Error("Failed to create refund", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("failed to save refund: %w", err)
+	}
+
+	refunds := append(existing, refund)
+	refundedTotal := entities.RefundedAmount(refunds)
+
+	uc.logger.Info("Refund issued successfully",
+		"order_id", order.ID,
+		"refund_id", refund.ID,
+		"amount", refund.Amount,
+		"refunded_total", refundedTotal,
+		"order_status", order.Status)
+
+	event := order.ToEvent(entities.EventOrderRefundIssued)
+	uc.addRefundData(event, refund, refundedTotal)
+	uc.publish(ctx, event)
+
+	if newStatus != oldStatus {
+		statusEvent := order.ToEvent(transition.Event)
+		statusEvent.Data["old_status"] = string(oldStatus)
+		uc.addRefundData(statusEvent, refund, refundedTotal)
+		uc.publish(ctx, statusEvent)
+	}
+
+	return &IssueRefundResponse{
+		Refund:           refund,
+		OrderStatus:      order.Status,
+		RefundableAmount: order.RefundableAmount(refunds),
+		Message:          "Refund issued successfully",
+	}, nil
+}
+
+// addRefundData добавляет сведения о возврате средств в событие
+func (uc *IssueRefundUseCase) addRefundData(event *entities.OrderEvent, refund *entities.Refund, refundedTotal float64) {
+	event.Data["refund_id"] = refund.ID.String()
+	event.Data["refund_amount"] = refund.Amount
+	event.Data["refunded_total"] = refundedTotal
+	if refund.ReturnID != nil {
+		event.Data["return_id"] = refund.ReturnID.String()
+	}
+	if refund.Reason != "" {
+		event.Data["reason"] = refund.Reason
+	}
+}
+
+// publish публикует событие, ошибки публикации не критичны
+func (uc *IssueRefundUseCase) publish(ctx context.Context, event *entities.OrderEvent) {
+	if err := uc.publisher.PublishOrderEvent(ctx, event); err != nil {
+		uc.logger.Error("Failed to publish refund event",
+			"error", err,
+			"order_id", event.OrderID,
+			"event_type", event.EventType,
+			"event_id", event.EventID)
+	}
+}
+
+// ListReturnsRequest представляет запрос возвратов по заказу
+type ListReturnsRequest struct {
+	OrderID uuid.UUID `json:"order_id" validate:"required"`
+}
+
+// ListReturnsResponse представляет заявки на возврат и возвраты средств по заказу
+type ListReturnsResponse struct {
+	Returns          []*entities.ReturnRequest `json:"returns"`
+	Refunds          []*entities.Refund        `json:"refunds"`
+	RefundedAmount   float64                   `json:"refunded_amount"`
+	RefundableAmount float64                   `json:"refundable_amount"`
+}
+
+// ListReturnsUseCase представляет use case получения возвратов по заказу
+type ListReturnsUseCase struct {
+	orderRepo  repositories.OrderRepository
+	returnRepo repositories.ReturnRepository
+	logger     Logger
+}
+
+// NewListReturnsUseCase создает новый use case для получения возвратов
+func NewListReturnsUseCase(
+	orderRepo repositories.OrderRepository,
+	returnRepo repositories.ReturnRepository,
+	logger Logger,
+) *ListReturnsUseCase {
+	return &ListReturnsUseCase{
+		orderRepo:  orderRepo,
+		returnRepo: returnRepo,
+		logger:     logger,
+	}
+}
+
+// Execute выполняет получение возвратов по заказу
+func (uc *ListReturnsUseCase) Execute(ctx context.Context, req *ListReturnsRequest) (*ListReturnsResponse, error) {
+	if req == nil || req.OrderID == uuid.Nil {
+		return nil, entities.NewValidationError("order_id is required")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil {
+		uc.logger.Error("Failed to get order", "error", err, "order_id", req.OrderID)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	returns, err := uc.returnRepo.GetReturnsByOrderID(ctx, order.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get returns", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("failed to get returns: %w", err)
+	}
+
+	refunds, err := uc.returnRepo.GetRefundsByOrderID(ctx, order.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get refunds", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("failed to get refunds: %w", err)
+	}
+
+	return &ListReturnsResponse{
+		Returns:          returns,
+		Refunds:          refunds,
+		RefundedAmount:   entities.RefundedAmount(refunds),
+		RefundableAmount: order.RefundableAmount(refunds),
+	}, nil
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// untouchedReturnRepository отмечает любое обращение к заявкам
+type untouchedReturnRepository struct {
+	repositories.ReturnRepository
+	touched bool
+}
+
+func (r *untouchedReturnRepository) GetReturnByID(_ context.Context, id uuid.UUID) (*entities.ReturnRequest, error) {
+	r.touched = true
+	return nil, entities.NewReturnNotFoundError(id.String())
+}
+
+func TestResolveReturn_OrderOfAnotherTenant(t *testing.T) {
+	// Заказ другого арендатора не находится репозиторием заказов
+	returns := &untouchedReturnRepository{}
+	uc := NewResolveReturnUseCase(&stubOrderRepository{}, returns, nopLogger{})
+
+	_, err := uc.Execute(context.Background(), &ResolveReturnRequest{OrderID: uuid.New(), ReturnID: uuid.New(), Approve: true})
+	if !errors.As(err, &entities.OrderNotFoundError{}) {
+		t.Fatalf("expected order not found, got %v", err)
+	}
+	if returns.touched {
+		t.Fatal("expected return not loaded for an order outside the tenant")
+	}
+}
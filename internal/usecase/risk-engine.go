@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// RiskRule правило оценки риска заказа.
+// Возвращает nil, если правило не сработало.
+type RiskRule interface {
+	Name() string
+	Evaluate(ctx context.Context, order *entities.Order) (*entities.RiskRuleHit, error)
+}
+
+// RiskEngine суммирует баллы сработавших правил и сравнивает их с порогом
+type RiskEngine struct {
+	rules  []RiskRule
+	cutoff int
+}
+
+// NewRiskEngine создает движок оценки риска
+func NewRiskEngine(cutoff int, rules ...RiskRule) *RiskEngine {
+	return &RiskEngine{
+		rules:  rules,
+		cutoff: cutoff,
+	}
+}
+
+// Assess оценивает риск заказа
+func (e *RiskEngine) Assess(ctx context.Context, order *entities.Order) (*entities.RiskAssessment, error) {
+	hits := make([]entities.RiskRuleHit, 0)
+	for _, rule := range e.rules {
+		hit, err := rule.Evaluate(ctx, order)
+		if err != nil {
+			return nil, fmt.Errorf("risk rule %s failed: %w", rule.Name(), err)
+		}
+		if hit != nil {
+			hits = append(hits, *hit)
+		}
+	}
+
+	return entities.NewRiskAssessment(e.cutoff, hits), nil
+}
+
+// AmountRiskRule срабатывает на заказы с суммой выше порога
+type AmountRiskRule struct {
+	MaxAmount float64
+	Score     int
+}
+
+// Name возвращает имя правила
+func (r AmountRiskRule) Name() string { return "high_amount" }
+
+// Evaluate проверяет сумму заказа в отчетной валюте (если она зафиксирована)
+func (r AmountRiskRule) Evaluate(ctx context.Context, order *entities.Order) (*entities.RiskRuleHit, error) {
+	amount := order.ReportingAmount()
+	if r.MaxAmount <= 0 || amount <= r.MaxAmount {
+		return nil, nil
+	}
+	return &entities.RiskRuleHit{
+		Rule:   r.Name(),
+		Score:  r.Score,
+		Reason: fmt.Sprintf("total amount %.2f exceeds %.2f", amount, r.MaxAmount),
+	}, nil
+}
+
+// QuantityRiskRule срабатывает на заказы с большим количеством товара
+type QuantityRiskRule struct {
+	MaxQuantity int
+	Score       int
+}
+
+// Name возвращает имя правила
+func (r QuantityRiskRule) Name() string { return "high_quantity" }
+
+// Evaluate проверяет количество товаров в заказе
+func (r QuantityRiskRule) Evaluate(ctx context.Context, order *entities.Order) (*entities.RiskRuleHit, error) {
+	count := order.GetItemCount()
+	if r.MaxQuantity <= 0 || count <= r.MaxQuantity {
+		return nil, nil
+	}
+	return &entities.RiskRuleHit{
+		Rule:   r.Name(),
+		Score:  r.Score,
+		Reason: fmt.Sprintf("item quantity %d exceeds %d", count, r.MaxQuantity),
+	}, nil
+}
+
+// EmailDomainRiskRule срабатывает на email из списка подозрительных доменов
+type EmailDomainRiskRule struct {
+	Domains []string
+	Score   int
+}
+
+// Name возвращает имя правила
+func (r EmailDomainRiskRule) Name() string { return "email_domain" }
+
+// Evaluate проверяет домен email заказа
+func (r EmailDomainRiskRule) Evaluate(ctx context.Context, order *entities.Order) (*entities.RiskRuleHit, error) {
+	at := strings.LastIndex(order.Email, "@")
+	if at < 0 {
+		return nil, nil
+	}
+	domain := strings.ToLower(order.Email[at+1:])
+
+	for _, listed := range r.Domains {
+		listed = strings.ToLower(strings.TrimSpace(listed))
+		if listed != "" && (domain == listed || strings.HasSuffix(domain, "."+listed)) {
+			return &entities.RiskRuleHit{
+				Rule:   r.Name(),
+				Score:  r.Score,
+				Reason: fmt.Sprintf("email domain %s is listed", domain),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// CountryMismatchRiskRule срабатывает, если страны доставки и оплаты различаются
+type CountryMismatchRiskRule struct {
+	Score int
+}
+
+// Name возвращает имя правила
+func (r CountryMismatchRiskRule) Name() string { return "country_mismatch" }
+
+// Evaluate сравнивает страны адресов доставки и оплаты
+func (r CountryMismatchRiskRule) Evaluate(ctx context.Context, order *entities.Order) (*entities.RiskRuleHit, error) {
+	if order.ShippingAddress == nil || order.BillingAddress == nil {
+		return nil, nil
+	}
+
+	shipping := strings.TrimSpace(order.ShippingAddress.Country)
+	billing := strings.TrimSpace(order.BillingAddress.Country)
+	if strings.EqualFold(shipping, billing) {
+		return nil, nil
+	}
+
+	return &entities.RiskRuleHit{
+		Rule:   r.Name(),
+		Score:  r.Score,
+		Reason: fmt.Sprintf("billing country %s differs from shipping country %s", billing, shipping),
+	}, nil
+}
+
+// VelocityRiskRule срабатывает, если клиент делает слишком много заказов за период
+type VelocityRiskRule struct {
+	OrderRepo repositories.OrderRepository
+	Window    time.Duration
+	MaxOrders int
+	Score     int
+}
+
+// Name возвращает имя правила
+func (r VelocityRiskRule) Name() string { return "customer_velocity" }
+
+// Evaluate считает заказы клиента за окно времени
+func (r VelocityRiskRule) Evaluate(ctx context.Context, order *entities.Order) (*entities.RiskRuleHit, error) {
+	if r.MaxOrders <= 0 || r.Window <= 0 {
+		return nil, nil
+	}
+
+	since := time.Now().Add(-r.Window).Format(time.RFC3339)
+	count, err := r.OrderRepo.Count(ctx, repositories.OrderFilters{
+		CustomerID: &order.CustomerID,
+		DateFrom:   &since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Текущий заказ еще не сохранен, поэтому учитываем его отдельно
+	if int(count)+1 <= r.MaxOrders {
+		return nil, nil
+	}
+
+	return &entities.RiskRuleHit{
+		Rule:   r.Name(),
+		Score:  r.Score,
+		Reason: fmt.Sprintf("%d orders within %s exceeds %d", count+1, r.Window, r.MaxOrders),
+	}, nil
+}
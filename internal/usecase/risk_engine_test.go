@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"kafka-order-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+func TestRiskEngine_Assess(t *testing.T) {
+	engine := NewRiskEngine(50,
+		AmountRiskRule{MaxAmount: 1000, Score: 30},
+		QuantityRiskRule{MaxQuantity: 10, Score: 20},
+		EmailDomainRiskRule{Domains: []string{"mailinator.com"}, Score: 50},
+		CountryMismatchRiskRule{Score: 25},
+	)
+
+	t.Run("clean order", func(t *testing.T) {
+		order := entities.NewOrder(uuid.New(), "test@example.com")
+		order.AddItem(uuid.New(), "Test Product", 10.0, 1)
+
+		assessment, err := engine.Assess(context.Background(), order)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if assessment.Score != 0 || assessment.ShouldHold() {
+			t.Errorf("Expected zero score without hold, got %d", assessment.Score)
+		}
+	})
+
+	t.Run("score accumulates to hold", func(t *testing.T) {
+		order := entities.NewOrder(uuid.New(), "test@example.com")
+		order.AddItem(uuid.New(), "Test Product", 100.0, 11)
+
+		assessment, err := engine.Assess(context.Background(), order)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if assessment.Score != 50 {
+			t.Errorf("Expected score 50, got %d", assessment.Score)
+		}
+
+		if !assessment.ShouldHold() {
+			t.Error("Expected order to be held")
+		}
+
+		if len(assessment.Triggered) != 2 {
+			t.Errorf("Expected 2 triggered rules, got %v", assessment.TriggeredRuleNames())
+		}
+	})
+
+	t.Run("email subdomain and country mismatch", func(t *testing.T) {
+		order := entities.NewOrder(uuid.New(), "test@eu.Mailinator.com")
+		order.AddItem(uuid.New(), "Test Product", 10.0, 1)
+		order.SetShippingAddress(&entities.Address{Country: "BY"})
+		order.SetBillingAddress(&entities.Address{Country: "US"})
+
+		assessment, err := engine.Assess(context.Background(), order)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if assessment.Score != 75 {
+			t.Errorf("Expected score 75, got %d (%v)", assessment.Score, assessment.TriggeredRuleNames())
+		}
+	})
+}
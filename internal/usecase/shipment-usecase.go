@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// CreateShipmentRequest представляет запрос на создание отправления
+type CreateShipmentRequest struct {
+	OrderID        uuid.UUID                   `json:"order_id" validate:"required"`
+	Carrier        string                      `json:"carrier" validate:"required"`
+	TrackingNumber string                      `json:"tracking_number" validate:"required"`
+	Items          []CreateShipmentItemRequest `json:"items,omitempty"` // Пусто - отправить все оставшееся
+}
+
+// CreateShipmentItemRequest представляет позицию заказа в запросе отправления
+type CreateShipmentItemRequest struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Quantity    int       `json:"quantity" validate:"required,gt=0"`
+}
+
+// CreateShipmentResponse представляет ответ создания отправления
+type CreateShipmentResponse struct {
+	Shipment    *entities.Shipment   `json:"shipment"`
+	OrderStatus entities.OrderStatus `json:"order_status"`
+	Message     string               `json:"message"`
+}
+
+// CreateShipmentUseCase представляет use case создания отправления
+type CreateShipmentUseCase struct {
+	orderRepo     repositories.OrderRepository
+	shipmentRepo  repositories.ShipmentRepository
+	publisher     EventPublisher
+	stateMachines *entities.StateMachineRegistry
+	logger        Logger
+}
+
+// NewCreateShipmentUseCase создает новый use case для создания отправления
+func NewCreateShipmentUseCase(
+	orderRepo repositories.OrderRepository,
+	shipmentRepo repositories.ShipmentRepository,
+	publisher EventPublisher,
+	stateMachines *entities.StateMachineRegistry,
+	logger Logger,
+) *CreateShipmentUseCase {
+	if stateMachines == nil {
+		stateMachines = entities.NewStateMachineRegistry(entities.DefaultStateMachine(), nil)
+	}
+
+	return &CreateShipmentUseCase{
+		orderRepo:     orderRepo,
+		shipmentRepo:  shipmentRepo,
+		publisher:     publisher,
+		stateMachines: stateMachines,
+		logger:        logger,
+	}
+}
+
+// Execute выполняет создание отправления и пересчет статуса заказа
+func (uc *CreateShipmentUseCase) Execute(ctx context.Context, req *CreateShipmentRequest) (*CreateShipmentResponse, error) {
+	if req == nil || req.OrderID == uuid.Nil {
+		return nil, entities.NewValidationError("order_id is required")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil {
+		uc.logger.Error("Failed to get order", "error", err, "order_id", req.OrderID)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	existing, err := uc.shipmentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get order shipments", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+
+	shipment := entities.NewShipment(order.ID, req.Carrier, req.TrackingNumber)
+	if len(req.Items) == 0 {
+		remaining := order.RemainingToShip(existing)
+		for _, item := range order.Items {
+			if left := remaining[item.ID]; left > 0 {
+				shipment.AddItem(item.ID, left)
+			}
+		}
+	} else {
+		for _, item := range req.Items {
+			shipment.AddItem(item.OrderItemID, item.Quantity)
+		}
+	}
+
+	if err := shipment.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	newStatus, err := order.ShipmentStatusAfter(existing, shipment)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Производный статус заказа проходит через машину состояний
+	oldStatus := order.Status
+	var transition entities.TransitionDefinition
+	if newStatus != oldStatus {
+		transition, err = order.TransitionTo(uc.stateMachines.ForOrder(order), newStatus)
+		if err != nil {
+			uc.logger.Error("Order cannot be shipped in current status",
+				"error", err,
+				"order_id", order.ID,
+				"status", oldStatus)
+			return nil, fmt.Errorf("status update failed: %w", err)
+		}
+	}
+
+	if err := uc.shipmentRepo.Create(ctx, shipment, oldStatus, order.Status); err != nil {
+		uc.logger.Error("Failed to create shipment", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("failed to save shipment: %w", err)
+	}
+
+	uc.logger.Info("Shipment created successfully",
+		"order_id", order.ID,
+		"shipment_id", shipment.ID,
+		"carrier", shipment.Carrier,
+		"tracking_number", shipment.TrackingNumber,
+		"order_status", order.Status)
+
+	event := order.ToEvent(entities.EventOrderShipmentCreated)
+	event.Data["shipment_id"] = shipment.ID.String()
+	event.Data["carrier"] = shipment.Carrier
+	event.Data["tracking_number"] = shipment.TrackingNumber
+	event.Data["shipment_items"] = shipment.Items
+	uc.publish(ctx, event)
+
+	if newStatus != oldStatus {
+		statusEvent := order.ToEvent(transition.Event)
+		statusEvent.Data["old_status"] = string(oldStatus)
+		statusEvent.Data["shipment_id"] = shipment.ID.String()
+		statusEvent.Data["carrier"] = shipment.Carrier
+		statusEvent.Data["tracking_number"] = shipment.TrackingNumber
+		uc.publish(ctx, statusEvent)
+	}
+
+	return &CreateShipmentResponse{
+		Shipment:    shipment,
+		OrderStatus: order.Status,
+		Message:     "Shipment created successfully",
+	}, nil
+}
+
+// publish публикует событие, ошибки публикации не критичны
+func (uc *CreateShipmentUseCase) publish(ctx context.Context, event *entities.OrderEvent) {
+	if err := uc.publisher.PublishOrderEvent(ctx, event); err != nil {
+		uc.logger.Error("Failed to publish shipment event",
+			"error", err,
+			"order_id", event.OrderID,
+			"event_type", event.EventType,
+			"event_id", event.EventID)
+	}
+}
+
+// ListShipmentsRequest представляет запрос отправлений заказа
+type ListShipmentsRequest struct {
+	OrderID uuid.UUID `json:"order_id" validate:"required"`
+}
+
+// ListShipmentsResponse представляет ответ со списком отправлений
+type ListShipmentsResponse struct {
+	Shipments []*entities.Shipment `json:"shipments"`
+}
+
+// ListShipmentsUseCase представляет use case получения отправлений заказа
+type ListShipmentsUseCase struct {
+	orderRepo    repositories.OrderRepository
+	shipmentRepo repositories.ShipmentRepository
+	logger       Logger
+}
+
+// NewListShipmentsUseCase создает новый use case для получения отправлений
+func NewListShipmentsUseCase(orderRepo repositories.OrderRepository, shipmentRepo repositories.ShipmentRepository, logger Logger) *ListShipmentsUseCase {
+	return &ListShipmentsUseCase{
+		orderRepo:    orderRepo,
+		shipmentRepo: shipmentRepo,
+		logger:       logger,
+	}
+}
+
+// Execute выполняет получение отправлений заказа
+func (uc *ListShipmentsUseCase) Execute(ctx context.Context, req *ListShipmentsRequest) (*ListShipmentsResponse, error) {
+	if req == nil || req.OrderID == uuid.Nil {
+		return nil, entities.NewValidationError("order_id is required")
+	}
+
+	// Заказ ищется в рамках арендатора: отправления чужого заказа не отдаются
+	order, err := uc.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil {
+		uc.logger.Error("Failed to get order", "error", err, "order_id", req.OrderID)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	shipments, err := uc.shipmentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get shipments", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+
+	return &ListShipmentsResponse{Shipments: shipments}, nil
+}
+
+// UpdateShipmentStatusRequest представляет запрос изменения статуса отправления
+type UpdateShipmentStatusRequest struct {
+	OrderID    uuid.UUID               `json:"order_id" validate:"required"`
+	ShipmentID uuid.UUID               `json:"shipment_id" validate:"required"`
+	Status     entities.ShipmentStatus `json:"status" validate:"required"`
+}
+
+// UpdateShipmentStatusResponse представляет ответ изменения статуса отправления
+type UpdateShipmentStatusResponse struct {
+	Shipment    *entities.Shipment   `json:"shipment"`
+	OrderStatus entities.OrderStatus `json:"order_status"`
+	Message     string               `json:"message"`
+}
+
+// UpdateShipmentStatusUseCase представляет use case изменения статуса отправления
+type UpdateShipmentStatusUseCase struct {
+	orderRepo     repositories.OrderRepository
+	shipmentRepo  repositories.ShipmentRepository
+	publisher     EventPublisher
+	stateMachines *entities.StateMachineRegistry
+	logger        Logger
+}
+
+// NewUpdateShipmentStatusUseCase создает новый use case для изменения статуса отправления
+func NewUpdateShipmentStatusUseCase(
+	orderRepo repositories.OrderRepository,
+	shipmentRepo repositories.ShipmentRepository,
+	publisher EventPublisher,
+	stateMachines *entities.StateMachineRegistry,
+	logger Logger,
+) *UpdateShipmentStatusUseCase {
+	if stateMachines == nil {
+		stateMachines = entities.NewStateMachineRegistry(entities.DefaultStateMachine(), nil)
+	}
+
+	return &UpdateShipmentStatusUseCase{
+		orderRepo:     orderRepo,
+		shipmentRepo:  shipmentRepo,
+		publisher:     publisher,
+		stateMachines: stateMachines,
+		logger:        logger,
+	}
+}
+
+// Execute выполняет изменение статуса отправления. Когда доставлены все отправления
+// отгруженного заказа, заказ переводится в delivered, если это разрешает его машина состояний.
+func (uc *UpdateShipmentStatusUseCase) Execute(ctx context.Context, req *UpdateShipmentStatusRequest) (*UpdateShipmentStatusResponse, error) {
+	if req == nil || req.OrderID == uuid.Nil || req.ShipmentID == uuid.Nil {
+		return nil, entities.NewValidationError("order_id and shipment_id are required")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil {
+		uc.logger.Error("Failed to get order", "error", err, "order_id", req.OrderID)
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	shipments, err := uc.shipmentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		uc.logger.Error("Failed to get order shipments", "error", err, "order_id", order.ID)
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+
+	var shipment *entities.Shipment
+	for _, candidate := range shipments {
+		if candidate.ID == req.ShipmentID {
+			shipment = candidate
+			break
+		}
+	}
+	if shipment == nil {
+		return nil, entities.NewShipmentNotFoundError(req.ShipmentID.String())
+	}
+
+	oldShipmentStatus := shipment.Status
+	if err := shipment.UpdateStatus(req.Status); err != nil {
+		return nil, fmt.Errorf("status update failed: %w", err)
+	}
+
+	oldStatus := order.Status
+	var transition entities.TransitionDefinition
+	sm := uc.stateMachines.ForOrder(order)
+	if _, allowed := sm.Transition(oldStatus, entities.OrderStatusDelivered); allowed &&
+		oldStatus == entities.OrderStatusShipped && entities.AllShipmentsDelivered(shipments) {
+		transition, err = order.TransitionTo(sm, entities.OrderStatusDelivered)
+		if err != nil {
+			uc.logger.Error("Order cannot be delivered", "error", err, "order_id", order.ID)
+			return nil, fmt.Errorf("status update failed: %w", err)
+		}
+	}
+
+	if err := uc.shipmentRepo.UpdateStatus(ctx, shipment, oldShipmentStatus, oldStatus, order.Status); err != nil {
+		uc.logger.Error("Failed to update shipment status", "error", err, "order_id", order.ID, "shipment_id", shipment.ID)
+		return nil, fmt.Errorf("failed to save shipment: %w", err)
+	}
+
+	uc.logger.Info("Shipment status updated",
+		"order_id", order.ID,
+		"shipment_id", shipment.ID,
+		"old_status", oldShipmentStatus,
+		"new_status", shipment.Status,
+		"order_status", order.Status)
+
+	event := order.ToEvent(entities.EventOrderShipmentUpdated)
+	event.Data["shipment_id"] = shipment.ID.String()
+	event.Data["old_shipment_status"] = string(oldShipmentStatus)
+	event.Data["shipment_status"] = string(shipment.Status)
+	event.Data["carrier"] = shipment.Carrier
+	event.Data["tracking_number"] = shipment.TrackingNumber
+	uc.publish(ctx, event)
+
+	if order.Status != oldStatus {
+		statusEvent := order.ToEvent(transition.Event)
+		statusEvent.Data["old_status"] = string(oldStatus)
+		statusEvent.Data["shipment_id"] = shipment.ID.String()
+		uc.publish(ctx, statusEvent)
+	}
+
+	return &UpdateShipmentStatusResponse{
+		Shipment:    shipment,
+		OrderStatus: order.Status,
+		Message:     "Shipment status updated successfully",
+	}, nil
+}
+
+// publish публикует событие, ошибки публикации не критичны
+func (uc *UpdateShipmentStatusUseCase) publish(ctx context.Context, event *entities.OrderEvent) {
+	if err := uc.publisher.PublishOrderEvent(ctx, event); err != nil {
+		uc.logger.Error("Failed to publish shipment event",
+			"error", err,
+			"order_id", event.OrderID,
+			"event_type", event.EventType,
+			"event_id", event.EventID)
+	}
+}
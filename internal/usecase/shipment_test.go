@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// memoryShipmentRepository хранит отправления в памяти и, как PostgreSQL-реализация,
+// сохраняет статус заказа вместе со статусом отправления
+type memoryShipmentRepository struct {
+	repositories.ShipmentRepository
+	orders    *pendingOrderRepository
+	shipments map[uuid.UUID]*entities.Shipment
+}
+
+func (r *memoryShipmentRepository) GetByOrderID(_ context.Context, orderID uuid.UUID) ([]*entities.Shipment, error) {
+	var shipments []*entities.Shipment
+	for _, shipment := range r.shipments {
+		if shipment.OrderID == orderID {
+			copied := *shipment
+			shipments = append(shipments, &copied)
+		}
+	}
+	return shipments, nil
+}
+
+func (r *memoryShipmentRepository) UpdateStatus(_ context.Context, shipment *entities.Shipment, fromStatus entities.ShipmentStatus, orderFrom, orderTo entities.OrderStatus) error {
+	stored := r.shipments[shipment.ID]
+	if stored.Status != fromStatus {
+		return entities.NewInvalidShipmentTransitionError(stored.Status, shipment.Status)
+	}
+	copied := *shipment
+	r.shipments[shipment.ID] = &copied
+	r.orders.orders[shipment.OrderID].Status = orderTo
+	return nil
+}
+
+func TestUpdateShipmentStatus_DeliversOrderAfterLastShipment(t *testing.T) {
+	order := entities.NewOrder(uuid.New(), "buyer@example.com")
+	order.Status = entities.OrderStatusShipped
+	orders := newPendingOrderRepository(order)
+
+	first := entities.NewShipment(order.ID, "DHL", "TRK-1")
+	second := entities.NewShipment(order.ID, "DHL", "TRK-2")
+	repo := &memoryShipmentRepository{orders: orders, shipments: map[uuid.UUID]*entities.Shipment{
+		first.ID: first, second.ID: second,
+	}}
+	publisher := &recordingPublisher{}
+	uc := NewUpdateShipmentStatusUseCase(orders, repo, publisher, nil, nopLogger{})
+	ctx := context.Background()
+
+	resp, err := uc.Execute(ctx, &UpdateShipmentStatusRequest{OrderID: order.ID, ShipmentID: first.ID, Status: entities.ShipmentStatusDelivered})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.OrderStatus != entities.OrderStatusShipped {
+		t.Fatalf("expected order to stay shipped while a shipment is undelivered, got %s", resp.OrderStatus)
+	}
+
+	if _, err := uc.Execute(ctx, &UpdateShipmentStatusRequest{OrderID: order.ID, ShipmentID: first.ID, Status: entities.ShipmentStatusReturned}); err == nil {
+		t.Fatal("expected delivered shipment to be final")
+	}
+
+	resp, err = uc.Execute(ctx, &UpdateShipmentStatusRequest{OrderID: order.ID, ShipmentID: second.ID, Status: entities.ShipmentStatusDelivered})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.OrderStatus != entities.OrderStatusDelivered || orders.orders[order.ID].Status != entities.OrderStatusDelivered {
+		t.Fatalf("expected order delivered after last shipment, got %s", resp.OrderStatus)
+	}
+
+	var types []string
+	for _, event := range publisher.events {
+		types = append(types, event.EventType)
+	}
+	want := []string{entities.EventOrderShipmentUpdated, entities.EventOrderShipmentUpdated, entities.EventOrderDelivered}
+	if len(types) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("expected events %v, got %v", want, types)
+		}
+	}
+}
+
+func TestUpdateShipmentStatus_UnknownShipment(t *testing.T) {
+	order := entities.NewOrder(uuid.New(), "buyer@example.com")
+	orders := newPendingOrderRepository(order)
+	repo := &memoryShipmentRepository{orders: orders, shipments: map[uuid.UUID]*entities.Shipment{}}
+	uc := NewUpdateShipmentStatusUseCase(orders, repo, &recordingPublisher{}, nil, nopLogger{})
+
+	_, err := uc.Execute(context.Background(), &UpdateShipmentStatusRequest{OrderID: order.ID, ShipmentID: uuid.New(), Status: entities.ShipmentStatusInTransit})
+	var notFound entities.ShipmentNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected shipment not found, got %v", err)
+	}
+}
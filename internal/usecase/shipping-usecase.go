@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"kafka-order-service/internal/domain/entities"
+)
+
+// QuoteShippingRequest представляет запрос расчета доставки для корзины
+type QuoteShippingRequest struct {
+	Country string                  `json:"country"`
+	State   string                  `json:"state,omitempty"`
+	Method  entities.ShippingMethod `json:"method,omitempty"`
+	Parcel  entities.ShippingParcel `json:"parcel"`
+}
+
+// QuoteShippingResponse представляет стоимость доставки доступными способами
+type QuoteShippingResponse struct {
+	Quotes   []entities.ShippingQuote `json:"quotes"`
+	Currency string                   `json:"currency"`
+}
+
+// QuoteShippingUseCase представляет use case расчета доставки до оформления заказа
+type QuoteShippingUseCase struct {
+	shipping *entities.ShippingTable
+	logger   Logger
+}
+
+// NewQuoteShippingUseCase создает новый use case расчета доставки.
+// Если таблица тарифов не передана, расчет недоступен.
+func NewQuoteShippingUseCase(shipping *entities.ShippingTable, logger Logger) *QuoteShippingUseCase {
+	return &QuoteShippingUseCase{
+		shipping: shipping,
+		logger:   logger,
+	}
+}
+
+// Execute рассчитывает доставку выбранным способом или всеми доступными
+func (uc *QuoteShippingUseCase) Execute(ctx context.Context, req *QuoteShippingRequest) (*QuoteShippingResponse, error) {
+	if uc.shipping == nil {
+		return nil, entities.NewValidationError("shipping methods are not configured")
+	}
+	if req == nil {
+		return nil, entities.NewValidationError("request cannot be nil")
+	}
+	if req.Parcel.Weight < 0 || req.Parcel.ItemCount < 0 {
+		return nil, entities.NewValidationError("parcel weight and item count cannot be negative")
+	}
+
+	var address *entities.Address
+	if strings.TrimSpace(req.Country) != "" {
+		address = &entities.Address{Country: req.Country, State: req.State}
+	}
+
+	response := &QuoteShippingResponse{Currency: uc.shipping.Currency()}
+
+	if req.Method != "" {
+		quote, err := uc.shipping.Quote(req.Method, address, req.Parcel)
+		if err != nil {
+			return nil, err
+		}
+		response.Quotes = []entities.ShippingQuote{*quote}
+		return response, nil
+	}
+
+	response.Quotes = uc.shipping.Quotes(address, req.Parcel)
+	if len(response.Quotes) == 0 {
+		return nil, entities.NewValidationError("no shipping methods available for this destination and parcel")
+	}
+
+	return response, nil
+}
+
+// shippingParcel считает вес и количество единиц позиций запроса
+func shippingParcel(items []CreateOrderItemRequest) entities.ShippingParcel {
+	var parcel entities.ShippingParcel
+	for _, item := range items {
+		parcel.Weight += item.Weight * float64(item.Quantity)
+		parcel.ItemCount += item.Quantity
+	}
+	return parcel
+}
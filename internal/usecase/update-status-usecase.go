@@ -26,21 +26,29 @@ type UpdateOrderStatusResponse struct {
 
 // UpdateOrderStatusUseCase представляет use case обновления статуса заказа
 type UpdateOrderStatusUseCase struct {
-	orderRepo repositories.OrderRepository
-	publisher EventPublisher
-	logger    Logger
+	orderRepo     repositories.OrderRepository
+	publisher     EventPublisher
+	stateMachines *entities.StateMachineRegistry
+	logger        Logger
 }
 
-// NewUpdateOrderStatusUseCase создает новый use case для обновления статуса
+// NewUpdateOrderStatusUseCase создает новый use case для обновления статуса.
+// Если реестр машин состояний не передан, используется стандартный жизненный цикл.
 func NewUpdateOrderStatusUseCase(
 	orderRepo repositories.OrderRepository,
 	publisher EventPublisher,
+	stateMachines *entities.StateMachineRegistry,
 	logger Logger,
 ) *UpdateOrderStatusUseCase {
+	if stateMachines == nil {
+		stateMachines = entities.NewStateMachineRegistry(entities.DefaultStateMachine(), nil)
+	}
+
 	return &UpdateOrderStatusUseCase{
-		orderRepo: orderRepo,
-		publisher: publisher,
-		logger:    logger,
+		orderRepo:     orderRepo,
+		publisher:     publisher,
+		stateMachines: stateMachines,
+		logger:        logger,
 	}
 }
 
@@ -62,8 +70,9 @@ func (uc *UpdateOrderStatusUseCase) Execute(ctx context.Context, req *UpdateOrde
 	// Сохраняем старый статус для ответа
 	oldStatus := order.Status
 
-	// Обновляем статус
-	if err := order.UpdateStatus(req.NewStatus); err != nil {
+	// Обновляем статус по машине состояний канала продаж заказа
+	transition, err := order.TransitionTo(uc.stateMachines.ForOrder(order), req.NewStatus)
+	if err != nil {
 		uc.logger.Error("Failed to update order status", 
 			"error", err, 
 			"order_id", req.OrderID,
@@ -92,24 +101,12 @@ func (uc *UpdateOrderStatusUseCase) Execute(ctx context.Context, req *UpdateOrde
 		"new_status", order.Status,
 		"reason", req.Reason)
 
-	// Публикуем событие в зависимости от нового статуса
-	var eventType string
-	switch req.NewStatus {
-	case entities.OrderStatusConfirmed:
-		eventType = entities.EventOrderConfirmed
-	case entities.OrderStatusCancelled:
-		eventType = entities.EventOrderCancelled
-	case entities.OrderStatusShipped:
-		eventType = entities.EventOrderShipped
-	case entities.OrderStatusDelivered:
-		eventType = entities.EventOrderDelivered
-	case entities.OrderStatusRefunded:
-		eventType = entities.EventOrderRefunded
-	default:
-		eventType = "order.status_changed"
+	// Публикуем событие, заданное для перехода в машине состояний
+	eventType := transition.Event
+	if eventType == "" {
+		eventType = entities.EventOrderStatusChanged
 	}
 
-	// Создаем и публикуем событие
 	event := order.ToEvent(eventType)
 	event.Data["old_status"] = string(oldStatus)
 	event.Data["change_reason"] = req.Reason
@@ -149,28 +146,6 @@ func (uc *UpdateOrderStatusUseCase) validateRequest(req *UpdateOrderStatusReques
 		return entities.NewValidationError("new_status is required")
 	}
 
-	// Валидация что статус является валидным
-	validStatuses := []entities.OrderStatus{
-		entities.OrderStatusPending,
-		entities.OrderStatusConfirmed,
-		entities.OrderStatusProcessing,
-		entities.OrderStatusShipped,
-		entities.OrderStatusDelivered,
-		entities.OrderStatusCancelled,
-		entities.OrderStatusRefunded,
-	}
-
-	isValid := false
-	for _, validStatus := range validStatuses {
-		if req.NewStatus == validStatus {
-			isValid = true
-			break
-		}
-	}
-
-	if !isValid {
-		return entities.NewValidationError("invalid status: %s", req.NewStatus)
-	}
-
+	// Допустимость самого статуса проверяет машина состояний заказа
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,378 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/domain/repositories"
+)
+
+// WebhookSender интерфейс доставки события подписчику.
+// Возвращает HTTP статус ответа (0, если ответа нет) и ошибку неуспешной доставки.
+type WebhookSender interface {
+	Send(ctx context.Context, subscription *entities.WebhookSubscription, event *entities.OrderEvent) (int, error)
+}
+
+// WebhookRetryPolicy настройки повторной доставки
+type WebhookRetryPolicy struct {
+	MaxAttempts    int           // Попыток доставки одного события
+	InitialBackoff time.Duration // Пауза перед второй попыткой, дальше удваивается
+	MaxBackoff     time.Duration // Верхняя граница паузы
+	DisableAfter   int           // Неуспешных событий подряд до отключения подписки; 0 - не отключать
+}
+
+// Backoff возвращает паузу перед попыткой attempt (со второй попытки)
+func (p WebhookRetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 2; i < attempt; i++ {
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return backoff
+}
+
+// WebhookDispatcher доставляет события заказов подписчикам с повторами
+type WebhookDispatcher struct {
+	webhookRepo repositories.WebhookRepository
+	sender      WebhookSender
+	policy      WebhookRetryPolicy
+	pii         PIIProtector
+	logger      Logger
+}
+
+// NewWebhookDispatcher создает диспетчер доставки webhooks.
+// pii расшифровывает персональные данные для подписок с IncludePII и заменяет их
+// токенами для остальных; nil - события доставляются как есть.
+func NewWebhookDispatcher(
+	webhookRepo repositories.WebhookRepository,
+	sender WebhookSender,
+	policy WebhookRetryPolicy,
+	pii PIIProtector,
+	logger Logger,
+) *WebhookDispatcher {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+
+	return &WebhookDispatcher{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		policy:      policy,
+		pii:         pii,
+		logger:      logger,
+	}
+}
+
+// Dispatch доставляет событие всем активным подписчикам параллельно.
+// Неуспешная доставка записывается в журнал и не считается ошибкой обработки события.
+func (d *WebhookDispatcher) Dispatch(ctx context.Context, event *entities.OrderEvent) error {
+	// Событие получают только подписчики арендатора заказа
+	if entities.TenantIDFromContext(ctx) == "" {
+		ctx = entities.ContextWithTenant(ctx, &entities.Tenant{ID: entities.TenantIDOrDefault(event.TenantID)})
+	}
+
+	subscriptions, err := d.webhookRepo.ListActiveForEvent(ctx, event.EventType)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+
+	revealed, tokenized := event, event
+	if d.pii != nil && len(subscriptions) > 0 {
+		if revealed, err = RevealEventPII(event, d.pii); err != nil {
+			return err
+		}
+		if tokenized, err = TokenizeEventPII(event, d.pii); err != nil {
+			return err
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, subscription := range subscriptions {
+		payload := tokenized
+		if subscription.IncludePII {
+			payload = revealed
+		}
+
+		wg.Add(1)
+		go func(subscription *entities.WebhookSubscription, payload *entities.OrderEvent) {
+			defer wg.Done()
+			d.deliver(ctx, subscription, payload)
+		}(subscription, payload)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// deliver доставляет событие подписчику с экспоненциальной паузой между попытками
+func (d *WebhookDispatcher) deliver(ctx context.Context, subscription *entities.WebhookSubscription, event *entities.OrderEvent) {
+	for attempt := 1; attempt <= d.policy.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(d.policy.Backoff(attempt)):
+			}
+		}
+
+		start := time.Now()
+		statusCode, err := d.sender.Send(ctx, subscription, event)
+		delivery := &entities.WebhookDelivery{
+			ID:             uuid.New(),
+			SubscriptionID: subscription.ID,
+			EventID:        event.EventID,
+			EventType:      event.EventType,
+			Attempt:        attempt,
+			StatusCode:     statusCode,
+			Success:        err == nil,
+			DurationMs:     time.Since(start).Milliseconds(),
+			CreatedAt:      start,
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		if recordErr := d.webhookRepo.RecordDelivery(ctx, delivery); recordErr != nil {
+			d.logger.Error("Failed to record webhook delivery", "error", recordErr, "subscription_id", subscription.ID)
+		}
+
+		if err == nil {
+			if subscription.FailureCount > 0 {
+				subscription.RecordSuccess()
+				d.updateHealth(ctx, subscription)
+			}
+			return
+		}
+
+		d.logger.Warn("Webhook delivery failed",
+			"error", err,
+			"subscription_id", subscription.ID,
+			"event_id", event.EventID,
+			"attempt", attempt)
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	if subscription.RecordFailure(d.policy.DisableAfter) {
+		d.logger.Warn("Webhook subscription disabled after repeated failures",
+			"subscription_id", subscription.ID,
+			"url", subscription.URL,
+			"failure_count", subscription.FailureCount)
+	}
+	d.updateHealth(ctx, subscription)
+}
+
+// updateHealth сохраняет состояние подписки после доставки
+func (d *WebhookDispatcher) updateHealth(ctx context.Context, subscription *entities.WebhookSubscription) {
+	if err := d.webhookRepo.UpdateHealth(ctx, subscription); err != nil {
+		d.logger.Error("Failed to update webhook subscription", "error", err, "subscription_id", subscription.ID)
+	}
+}
+
+// CreateWebhookRequest представляет запрос на создание подписки
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,url"`
+	EventTypes []string `json:"event_types,omitempty"` // Пусто - все события
+	Secret     string   `json:"secret,omitempty"`      // Пусто - сгенерировать
+	IncludePII bool     `json:"include_pii,omitempty"` // Персональные данные в открытом виде
+}
+
+// CreateWebhookResponse представляет ответ создания подписки; секрет возвращается только здесь
+type CreateWebhookResponse struct {
+	Subscription *entities.WebhookSubscription `json:"subscription"`
+	Secret       string                        `json:"secret"`
+	Message      string                        `json:"message"`
+}
+
+// CreateWebhookUseCase представляет use case создания подписки на webhooks
+type CreateWebhookUseCase struct {
+	webhookRepo repositories.WebhookRepository
+	logger      Logger
+}
+
+// NewCreateWebhookUseCase создает новый use case для создания подписки
+func NewCreateWebhookUseCase(webhookRepo repositories.WebhookRepository, logger Logger) *CreateWebhookUseCase {
+	return &CreateWebhookUseCase{
+		webhookRepo: webhookRepo,
+		logger:      logger,
+	}
+}
+
+// Execute выполняет создание подписки
+func (uc *CreateWebhookUseCase) Execute(ctx context.Context, req *CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	if req == nil {
+		return nil, entities.NewValidationError("request cannot be nil")
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	subscription := entities.NewWebhookSubscription(req.URL, req.EventTypes, secret)
+	subscription.IncludePII = req.IncludePII
+	subscription.TenantID = entities.TenantIDOrDefault(entities.TenantIDFromContext(ctx))
+	if err := subscription.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := uc.webhookRepo.Create(ctx, subscription); err != nil {
+		uc.logger.Error("Failed to create webhook subscription", "error", err, "url", subscription.URL)
+		return nil, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+
+	uc.logger.Info("Webhook subscription created",
+		"subscription_id", subscription.ID,
+		"url", subscription.URL,
+		"event_types", subscription.EventTypes,
+		"include_pii", subscription.IncludePII)
+
+	return &CreateWebhookResponse{
+		Subscription: subscription,
+		Secret:       secret,
+		Message:      "Webhook subscription created successfully",
+	}, nil
+}
+
+// generateWebhookSecret генерирует случайный секрет подписи
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// WebhookResponse представляет ответ с подпиской
+type WebhookResponse struct {
+	Subscription *entities.WebhookSubscription `json:"subscription"`
+}
+
+// ListWebhooksResponse представляет ответ со списком подписок
+type ListWebhooksResponse struct {
+	Subscriptions []*entities.WebhookSubscription `json:"subscriptions"`
+}
+
+// ListWebhookDeliveriesRequest представляет запрос журнала доставки подписки
+type ListWebhookDeliveriesRequest struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	Limit          int       `json:"limit"`
+	Offset         int       `json:"offset"`
+}
+
+// ListWebhookDeliveriesResponse представляет журнал доставки подписки
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []*entities.WebhookDelivery `json:"deliveries"`
+	Limit      int                         `json:"limit"`
+	Offset     int                         `json:"offset"`
+}
+
+// ManageWebhooksUseCase представляет use case просмотра, включения и удаления подписок
+type ManageWebhooksUseCase struct {
+	webhookRepo repositories.WebhookRepository
+	logger      Logger
+}
+
+// NewManageWebhooksUseCase создает новый use case для управления подписками
+func NewManageWebhooksUseCase(webhookRepo repositories.WebhookRepository, logger Logger) *ManageWebhooksUseCase {
+	return &ManageWebhooksUseCase{
+		webhookRepo: webhookRepo,
+		logger:      logger,
+	}
+}
+
+// Get получает подписку по ID
+func (uc *ManageWebhooksUseCase) Get(ctx context.Context, id uuid.UUID) (*WebhookResponse, error) {
+	subscription, err := uc.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return &WebhookResponse{Subscription: subscription}, nil
+}
+
+// List получает список подписок
+func (uc *ManageWebhooksUseCase) List(ctx context.Context, limit, offset int) (*ListWebhooksResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	subscriptions, err := uc.webhookRepo.List(ctx, limit, offset)
+	if err != nil {
+		uc.logger.Error("Failed to list webhook subscriptions", "error", err)
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	return &ListWebhooksResponse{Subscriptions: subscriptions}, nil
+}
+
+// Enable включает отключенную подписку и сбрасывает счетчик неудач
+func (uc *ManageWebhooksUseCase) Enable(ctx context.Context, id uuid.UUID) (*WebhookResponse, error) {
+	subscription, err := uc.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+
+	subscription.Enable()
+	if err := uc.webhookRepo.UpdateHealth(ctx, subscription); err != nil {
+		uc.logger.Error("Failed to enable webhook subscription", "error", err, "subscription_id", id)
+		return nil, fmt.Errorf("failed to enable webhook subscription: %w", err)
+	}
+
+	uc.logger.Info("Webhook subscription enabled", "subscription_id", id)
+	return &WebhookResponse{Subscription: subscription}, nil
+}
+
+// Delete удаляет подписку
+func (uc *ManageWebhooksUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := uc.webhookRepo.Delete(ctx, id); err != nil {
+		uc.logger.Error("Failed to delete webhook subscription", "error", err, "subscription_id", id)
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	uc.logger.Info("Webhook subscription deleted", "subscription_id", id)
+	return nil
+}
+
+// Deliveries получает журнал доставки подписки
+func (uc *ManageWebhooksUseCase) Deliveries(ctx context.Context, req *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 50
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	if _, err := uc.webhookRepo.GetByID(ctx, req.SubscriptionID); err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+
+	deliveries, err := uc.webhookRepo.ListDeliveries(ctx, req.SubscriptionID, req.Limit, req.Offset)
+	if err != nil {
+		uc.logger.Error("Failed to list webhook deliveries", "error", err, "subscription_id", req.SubscriptionID)
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return &ListWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	}, nil
+}
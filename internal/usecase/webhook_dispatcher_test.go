@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"kafka-order-service/internal/domain/entities"
+	"kafka-order-service/internal/infrastructure/webhook"
+
+	"github.com/google/uuid"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+func (nopLogger) Warn(string, ...interface{})  {}
+
+// memoryWebhookRepository хранит подписки и журнал доставки в памяти
+type memoryWebhookRepository struct {
+	mu            sync.Mutex
+	subscriptions []*entities.WebhookSubscription
+	deliveries    []*entities.WebhookDelivery
+}
+
+func (r *memoryWebhookRepository) Create(_ context.Context, s *entities.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions = append(r.subscriptions, s)
+	return nil
+}
+
+func (r *memoryWebhookRepository) GetByID(_ context.Context, id uuid.UUID) (*entities.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.subscriptions {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, entities.NewWebhookNotFoundError(id.String())
+}
+
+func (r *memoryWebhookRepository) List(_ context.Context, _, _ int) ([]*entities.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.subscriptions, nil
+}
+
+func (r *memoryWebhookRepository) ListActiveForEvent(_ context.Context, eventType string) ([]*entities.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*entities.WebhookSubscription
+	for _, s := range r.subscriptions {
+		if s.Active && s.Matches(eventType) {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryWebhookRepository) Delete(_ context.Context, _ uuid.UUID) error { return nil }
+
+func (r *memoryWebhookRepository) UpdateHealth(_ context.Context, _ *entities.WebhookSubscription) error {
+	return nil
+}
+
+func (r *memoryWebhookRepository) RecordDelivery(_ context.Context, d *entities.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, d)
+	return nil
+}
+
+func (r *memoryWebhookRepository) ListDeliveries(_ context.Context, _ uuid.UUID, _, _ int) ([]*entities.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.deliveries, nil
+}
+
+func newTestDispatcher(repo *memoryWebhookRepository, maxAttempts, disableAfter int) *WebhookDispatcher {
+	return NewWebhookDispatcher(repo, webhook.NewSender(time.Second), WebhookRetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		DisableAfter:   disableAfter,
+	}, nil, nopLogger{})
+}
+
+func TestWebhookDispatcher_RetriesUntilSuccess(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	repo := &memoryWebhookRepository{}
+	subscription := entities.NewWebhookSubscription(receiver.URL, nil, "0123456789abcdef-secret")
+	subscription.FailureCount = 2
+	_ = repo.Create(context.Background(), subscription)
+
+	event := entities.NewOrder(uuid.New(), "test@example.com").ToEvent(entities.EventOrderCreated)
+	if err := newTestDispatcher(repo, 5, 10).Dispatch(context.Background(), event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(repo.deliveries) != 3 {
+		t.Fatalf("Expected 3 recorded attempts, got %d", len(repo.deliveries))
+	}
+	last := repo.deliveries[2]
+	if !last.Success || last.Attempt != 3 || last.StatusCode != http.StatusOK {
+		t.Errorf("Unexpected last delivery: %+v", last)
+	}
+	if repo.deliveries[0].Success || repo.deliveries[0].StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected first attempt to fail with 500, got %+v", repo.deliveries[0])
+	}
+	if subscription.FailureCount != 0 {
+		t.Errorf("Expected failure count reset, got %d", subscription.FailureCount)
+	}
+}
+
+func TestWebhookDispatcher_DisablesAfterRepeatedFailures(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	repo := &memoryWebhookRepository{}
+	subscription := entities.NewWebhookSubscription(receiver.URL, []string{entities.EventOrderCreated}, "0123456789abcdef-secret")
+	_ = repo.Create(context.Background(), subscription)
+	dispatcher := newTestDispatcher(repo, 2, 2)
+
+	event := entities.NewOrder(uuid.New(), "test@example.com").ToEvent(entities.EventOrderCreated)
+	_ = dispatcher.Dispatch(context.Background(), event)
+	if !subscription.Active || subscription.FailureCount != 1 {
+		t.Fatalf("Expected subscription active with 1 failure, got active=%v failures=%d", subscription.Active, subscription.FailureCount)
+	}
+
+	_ = dispatcher.Dispatch(context.Background(), event)
+	if subscription.Active || subscription.DisabledAt == nil {
+		t.Fatal("Expected subscription to be disabled")
+	}
+	if len(repo.deliveries) != 4 {
+		t.Errorf("Expected 4 recorded attempts, got %d", len(repo.deliveries))
+	}
+
+	// Отключенная подписка больше не получает события
+	_ = dispatcher.Dispatch(context.Background(), event)
+	if len(repo.deliveries) != 4 {
+		t.Errorf("Expected no deliveries to disabled subscription, got %d", len(repo.deliveries))
+	}
+}
+
+func TestWebhookDispatcher_SkipsUnmatchedEvents(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer receiver.Close()
+
+	repo := &memoryWebhookRepository{}
+	_ = repo.Create(context.Background(), entities.NewWebhookSubscription(receiver.URL, []string{entities.EventOrderShipped}, "0123456789abcdef-secret"))
+
+	event := entities.NewOrder(uuid.New(), "test@example.com").ToEvent(entities.EventOrderCreated)
+	_ = newTestDispatcher(repo, 1, 0).Dispatch(context.Background(), event)
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Errorf("Expected no calls for unmatched event type, got %d", calls)
+	}
+}
+
+func TestWebhookRetryPolicy_Backoff(t *testing.T) {
+	policy := WebhookRetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	expected := map[int]time.Duration{2: time.Second, 3: 2 * time.Second, 4: 4 * time.Second, 5: 5 * time.Second, 9: 5 * time.Second}
+	for attempt, want := range expected {
+		if got := policy.Backoff(attempt); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", attempt, want, got)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+-- migrations/002_order_metadata.down.sql
+
+DROP INDEX IF EXISTS idx_orders_pending_created;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS metadata;
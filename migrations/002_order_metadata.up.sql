@@ -0,0 +1,9 @@
+-- migrations/002_order_metadata.up.sql
+
+-- Метаданные заказа (канал продаж, источник и т.д.)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- Индекс для поиска зависших заказов в статусе pending
+CREATE INDEX IF NOT EXISTS idx_orders_pending_created ON orders(created_at) WHERE status = 'pending';
+
+COMMENT ON COLUMN orders.metadata IS 'Произвольные метаданные заказа';
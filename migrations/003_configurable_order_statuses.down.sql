@@ -0,0 +1,5 @@
+-- migrations/003_configurable_order_statuses.down.sql
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS check_order_status_format;
+ALTER TABLE orders
+    ADD CONSTRAINT orders_status_check CHECK (status IN ('pending', 'confirmed', 'processing', 'shipped', 'delivered', 'cancelled', 'refunded'));
@@ -0,0 +1,7 @@
+-- migrations/003_configurable_order_statuses.up.sql
+
+-- Набор статусов задается конфигурируемой машиной состояний,
+-- поэтому в БД проверяется только формат статуса
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders
+    ADD CONSTRAINT check_order_status_format CHECK (status ~ '^[a-z][a-z_]*$');
@@ -0,0 +1,3 @@
+-- migrations/004_order_risk_assessments.down.sql
+
+DROP TABLE IF EXISTS order_risk_assessments;
@@ -0,0 +1,14 @@
+-- migrations/004_order_risk_assessments.up.sql
+
+-- Результаты оценки риска заказов (антифрод)
+CREATE TABLE IF NOT EXISTS order_risk_assessments (
+    order_id UUID PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    score INTEGER NOT NULL DEFAULT 0,
+    cutoff INTEGER NOT NULL DEFAULT 0,
+    triggered_rules JSONB NOT NULL DEFAULT '[]'::jsonb,
+    assessed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_risk_assessments_score ON order_risk_assessments(score);
+
+COMMENT ON TABLE order_risk_assessments IS 'Оценка риска заказов';
@@ -0,0 +1,5 @@
+-- migrations/005_shipments.down.sql
+
+DROP TRIGGER IF EXISTS update_shipments_updated_at ON shipments;
+DROP TABLE IF EXISTS shipment_items;
+DROP TABLE IF EXISTS shipments;
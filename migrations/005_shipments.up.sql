@@ -0,0 +1,32 @@
+-- migrations/005_shipments.up.sql
+
+-- Отправления (посылки) заказа
+CREATE TABLE IF NOT EXISTS shipments (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    carrier VARCHAR(100) NOT NULL,
+    tracking_number VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('created', 'in_transit', 'delivered', 'returned')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Позиции заказа в отправлении
+CREATE TABLE IF NOT EXISTS shipment_items (
+    id UUID PRIMARY KEY,
+    shipment_id UUID NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipments_order_id ON shipments(order_id);
+CREATE INDEX IF NOT EXISTS idx_shipments_tracking_number ON shipments(carrier, tracking_number);
+CREATE INDEX IF NOT EXISTS idx_shipment_items_shipment_id ON shipment_items(shipment_id);
+CREATE INDEX IF NOT EXISTS idx_shipment_items_order_item_id ON shipment_items(order_item_id);
+
+CREATE TRIGGER update_shipments_updated_at
+    BEFORE UPDATE ON shipments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE shipments IS 'Отправления заказов';
+COMMENT ON TABLE shipment_items IS 'Позиции заказов в отправлениях';
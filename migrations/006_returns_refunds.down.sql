@@ -0,0 +1,6 @@
+-- migrations/006_returns_refunds.down.sql
+
+DROP TABLE IF EXISTS refunds;
+DROP TRIGGER IF EXISTS update_return_requests_updated_at ON return_requests;
+DROP TABLE IF EXISTS return_items;
+DROP TABLE IF EXISTS return_requests;
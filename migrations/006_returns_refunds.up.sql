@@ -0,0 +1,47 @@
+-- migrations/006_returns_refunds.up.sql
+
+-- Заявки на возврат товаров (RMA)
+CREATE TABLE IF NOT EXISTS return_requests (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('requested', 'approved', 'rejected', 'refunded')),
+    reason TEXT,
+    resolution_note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Позиции заказа в заявке на возврат
+CREATE TABLE IF NOT EXISTS return_items (
+    id UUID PRIMARY KEY,
+    return_id UUID NOT NULL REFERENCES return_requests(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    reason TEXT NOT NULL
+);
+
+-- Возвраты денежных средств
+CREATE TABLE IF NOT EXISTS refunds (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    return_id UUID REFERENCES return_requests(id) ON DELETE SET NULL,
+    amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_return_requests_order_id ON return_requests(order_id);
+CREATE INDEX IF NOT EXISTS idx_return_requests_status ON return_requests(status);
+CREATE INDEX IF NOT EXISTS idx_return_items_return_id ON return_items(return_id);
+CREATE INDEX IF NOT EXISTS idx_return_items_order_item_id ON return_items(order_item_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_order_id ON refunds(order_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refunds_return_id ON refunds(return_id) WHERE return_id IS NOT NULL;
+
+CREATE TRIGGER update_return_requests_updated_at
+    BEFORE UPDATE ON return_requests
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE return_requests IS 'Заявки на возврат товаров';
+COMMENT ON TABLE return_items IS 'Позиции заказов в заявках на возврат';
+COMMENT ON TABLE refunds IS 'Возвраты денежных средств по заказам';
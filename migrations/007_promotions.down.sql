@@ -0,0 +1,43 @@
+-- migrations/007_promotions.down.sql
+
+CREATE OR REPLACE FUNCTION update_order_total_on_item_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    affected_order_id UUID;
+    new_total DECIMAL(10,2);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        affected_order_id := OLD.order_id;
+    ELSE
+        affected_order_id := NEW.order_id;
+    END IF;
+    new_total := calculate_order_total(affected_order_id);
+    UPDATE orders SET total_amount = new_total, updated_at = NOW() WHERE id = affected_order_id;
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION calculate_order_total(order_id_param UUID)
+RETURNS DECIMAL(10,2) AS $$
+DECLARE
+    total_sum DECIMAL(10,2);
+BEGIN
+    SELECT COALESCE(SUM(total), 0.00) INTO total_sum
+    FROM order_items
+    WHERE order_id = order_id_param;
+    RETURN total_sum;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Скидки теряются: итог возвращается к сумме позиций
+UPDATE orders SET total_amount = subtotal;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS subtotal;
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS check_item_discount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_amount;
+
+DROP TRIGGER IF EXISTS update_promotions_updated_at ON promotions;
+DROP TABLE IF EXISTS order_discounts;
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,107 @@
+-- migrations/007_promotions.up.sql
+
+-- Промоакции и купоны
+CREATE TABLE IF NOT EXISTS promotions (
+    id UUID PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    type VARCHAR(20) NOT NULL CHECK (type IN ('percentage', 'fixed_amount', 'buy_x_get_y', 'free_shipping')),
+    value DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    buy_quantity INTEGER NOT NULL DEFAULT 0,
+    get_quantity INTEGER NOT NULL DEFAULT 0,
+    product_ids UUID[] NOT NULL DEFAULT '{}',
+    min_subtotal DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    usage_limit INTEGER NOT NULL DEFAULT 0 CHECK (usage_limit >= 0),
+    per_customer_limit INTEGER NOT NULL DEFAULT 0 CHECK (per_customer_limit >= 0),
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_promotion_usage CHECK (usage_limit = 0 OR usage_count <= usage_limit)
+);
+
+-- Использования купонов заказами
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id UUID PRIMARY KEY,
+    promotion_id UUID NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (promotion_id, order_id)
+);
+
+-- Строки скидок заказа
+CREATE TABLE IF NOT EXISTS order_discounts (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id UUID REFERENCES order_items(id) ON DELETE CASCADE,
+    promotion_id UUID NOT NULL REFERENCES promotions(id),
+    code VARCHAR(64) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_active ON promotions(active);
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_customer ON promotion_redemptions(promotion_id, customer_id);
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_order_id ON promotion_redemptions(order_id);
+CREATE INDEX IF NOT EXISTS idx_order_discounts_order_id ON order_discounts(order_id);
+
+CREATE TRIGGER update_promotions_updated_at
+    BEFORE UPDATE ON promotions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Разбивка суммы заказа: subtotal - discount_amount = total_amount
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00;
+ALTER TABLE order_items
+    ADD CONSTRAINT check_item_discount CHECK (discount_amount >= 0 AND discount_amount <= total);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS subtotal DECIMAL(10,2) NOT NULL DEFAULT 0.00;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00;
+
+UPDATE orders SET subtotal = total_amount;
+
+-- Итог заказа считается по позициям за вычетом скидок
+CREATE OR REPLACE FUNCTION calculate_order_total(order_id_param UUID)
+RETURNS DECIMAL(10,2) AS $$
+DECLARE
+    total_sum DECIMAL(10,2);
+BEGIN
+    SELECT COALESCE(SUM(total - discount_amount), 0.00) INTO total_sum
+    FROM order_items
+    WHERE order_id = order_id_param;
+    RETURN total_sum;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_order_total_on_item_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    affected_order_id UUID;
+    new_subtotal DECIMAL(10,2);
+    new_discount DECIMAL(10,2);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        affected_order_id := OLD.order_id;
+    ELSE
+        affected_order_id := NEW.order_id;
+    END IF;
+    SELECT COALESCE(SUM(total), 0.00), COALESCE(SUM(discount_amount), 0.00)
+    INTO new_subtotal, new_discount
+    FROM order_items
+    WHERE order_id = affected_order_id;
+    UPDATE orders
+    SET subtotal = new_subtotal,
+        discount_amount = new_discount,
+        total_amount = new_subtotal - new_discount,
+        updated_at = NOW()
+    WHERE id = affected_order_id;
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON TABLE promotions IS 'Промоакции и купоны';
+COMMENT ON TABLE promotion_redemptions IS 'Использования купонов заказами';
+COMMENT ON TABLE order_discounts IS 'Строки скидок заказов';
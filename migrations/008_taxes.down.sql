@@ -0,0 +1,49 @@
+-- migrations/008_taxes.down.sql
+
+CREATE OR REPLACE FUNCTION update_order_total_on_item_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    affected_order_id UUID;
+    new_subtotal DECIMAL(10,2);
+    new_discount DECIMAL(10,2);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        affected_order_id := OLD.order_id;
+    ELSE
+        affected_order_id := NEW.order_id;
+    END IF;
+    SELECT COALESCE(SUM(total), 0.00), COALESCE(SUM(discount_amount), 0.00)
+    INTO new_subtotal, new_discount
+    FROM order_items
+    WHERE order_id = affected_order_id;
+    UPDATE orders
+    SET subtotal = new_subtotal,
+        discount_amount = new_discount,
+        total_amount = new_subtotal - new_discount,
+        updated_at = NOW()
+    WHERE id = affected_order_id;
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION calculate_order_total(order_id_param UUID)
+RETURNS DECIMAL(10,2) AS $$
+DECLARE
+    total_sum DECIMAL(10,2);
+BEGIN
+    SELECT COALESCE(SUM(total - discount_amount), 0.00) INTO total_sum
+    FROM order_items
+    WHERE order_id = order_id_param;
+    RETURN total_sum;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Налоги теряются: итог возвращается к сумме за вычетом скидок
+UPDATE orders SET total_amount = subtotal - discount_amount;
+
+DROP TABLE IF EXISTS order_item_taxes;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_amount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_inclusive;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_amount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_category;
@@ -0,0 +1,66 @@
+-- migrations/008_taxes.up.sql
+
+-- Налоги позиций: категория товара, сумма и признак включения в цену
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_category VARCHAR(50) NOT NULL DEFAULT 'standard';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00 CHECK (tax_amount >= 0);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00;
+
+-- Строки налогов позиций по ставкам юрисдикций
+CREATE TABLE IF NOT EXISTS order_item_taxes (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    jurisdiction VARCHAR(100) NOT NULL,
+    rate DECIMAL(7,6) NOT NULL CHECK (rate >= 0),
+    amount DECIMAL(10,2) NOT NULL CHECK (amount >= 0),
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_item_taxes_order_id ON order_item_taxes(order_id);
+
+-- Итог заказа: позиции за вычетом скидок плюс налог, начисляемый сверху цены
+CREATE OR REPLACE FUNCTION calculate_order_total(order_id_param UUID)
+RETURNS DECIMAL(10,2) AS $$
+DECLARE
+    total_sum DECIMAL(10,2);
+BEGIN
+    SELECT COALESCE(SUM(total - discount_amount + CASE WHEN tax_inclusive THEN 0 ELSE tax_amount END), 0.00)
+    INTO total_sum
+    FROM order_items
+    WHERE order_id = order_id_param;
+    RETURN total_sum;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_order_total_on_item_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    affected_order_id UUID;
+    new_subtotal DECIMAL(10,2);
+    new_discount DECIMAL(10,2);
+    new_tax DECIMAL(10,2);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        affected_order_id := OLD.order_id;
+    ELSE
+        affected_order_id := NEW.order_id;
+    END IF;
+    SELECT COALESCE(SUM(total), 0.00), COALESCE(SUM(discount_amount), 0.00), COALESCE(SUM(tax_amount), 0.00)
+    INTO new_subtotal, new_discount, new_tax
+    FROM order_items
+    WHERE order_id = affected_order_id;
+    UPDATE orders
+    SET subtotal = new_subtotal,
+        discount_amount = new_discount,
+        tax_amount = new_tax,
+        total_amount = calculate_order_total(affected_order_id),
+        updated_at = NOW()
+    WHERE id = affected_order_id;
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON TABLE order_item_taxes IS 'Строки налогов позиций заказов';
@@ -0,0 +1,21 @@
+-- migrations/009_shipping.down.sql
+
+CREATE OR REPLACE FUNCTION calculate_order_total(order_id_param UUID)
+RETURNS DECIMAL(10,2) AS $$
+DECLARE
+    total_sum DECIMAL(10,2);
+BEGIN
+    SELECT COALESCE(SUM(total - discount_amount + CASE WHEN tax_inclusive THEN 0 ELSE tax_amount END), 0.00)
+    INTO total_sum
+    FROM order_items
+    WHERE order_id = order_id_param;
+    RETURN total_sum;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Доставка теряется: итог возвращается к сумме позиций
+UPDATE orders SET total_amount = total_amount - shipping_amount;
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS check_shipping_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_method;
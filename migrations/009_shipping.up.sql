@@ -0,0 +1,28 @@
+-- migrations/009_shipping.up.sql
+
+-- Способ и стоимость доставки заказа
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_method VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00;
+ALTER TABLE orders
+    ADD CONSTRAINT check_shipping_amount CHECK (shipping_amount >= 0);
+
+-- Итог заказа: позиции за вычетом скидок, налог сверху цены и доставка.
+-- check_order_total и пересчет по позициям используют эту функцию.
+CREATE OR REPLACE FUNCTION calculate_order_total(order_id_param UUID)
+RETURNS DECIMAL(10,2) AS $$
+DECLARE
+    total_sum DECIMAL(10,2);
+    shipping DECIMAL(10,2);
+BEGIN
+    SELECT COALESCE(SUM(total - discount_amount + CASE WHEN tax_inclusive THEN 0 ELSE tax_amount END), 0.00)
+    INTO total_sum
+    FROM order_items
+    WHERE order_id = order_id_param;
+
+    SELECT COALESCE(shipping_amount, 0.00) INTO shipping
+    FROM orders
+    WHERE id = order_id_param;
+
+    RETURN total_sum + COALESCE(shipping, 0.00);
+END;
+$$ LANGUAGE plpgsql;
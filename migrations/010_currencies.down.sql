@@ -0,0 +1,19 @@
+-- migrations/010_currencies.down.sql
+
+DROP FUNCTION IF EXISTS get_order_statistics();
+CREATE OR REPLACE FUNCTION get_order_statistics()
+RETURNS TABLE(status VARCHAR, count BIGINT, total_amount DECIMAL(10,2), avg_amount DECIMAL(10,2)) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT status, COUNT(*)::BIGINT, COALESCE(SUM(total_amount),0), COALESCE(AVG(total_amount),0)
+    FROM orders GROUP BY status ORDER BY count DESC;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_orders_total_amount_base;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS check_order_currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS total_amount_base;
+ALTER TABLE orders DROP COLUMN IF EXISTS exchange_rate;
+ALTER TABLE orders DROP COLUMN IF EXISTS base_currency;
+
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,40 @@
+-- migrations/010_currencies.up.sql
+
+-- Курсы валют к отчетной валюте: 1 currency = rate base_currency
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency VARCHAR(3) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(18,8) NOT NULL CHECK (rate > 0),
+    effective_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (currency, base_currency, effective_at)
+);
+
+-- Итог заказа в отчетной валюте, зафиксированный при создании
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS base_currency VARCHAR(3);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(18,8);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total_amount_base DECIMAL(12,2);
+
+-- Существующие заказы в валюте по умолчанию пересчитываются один к одному
+UPDATE orders
+SET base_currency = currency, exchange_rate = 1, total_amount_base = total_amount
+WHERE currency = 'USD';
+
+-- Код валюты ISO 4217; старые строки не перепроверяются
+ALTER TABLE orders
+    ADD CONSTRAINT check_order_currency CHECK (currency ~ '^[A-Z]{3}$') NOT VALID;
+
+CREATE INDEX IF NOT EXISTS idx_orders_total_amount_base ON orders(total_amount_base);
+
+-- Статистика по статусам в отчетной валюте
+DROP FUNCTION IF EXISTS get_order_statistics();
+CREATE OR REPLACE FUNCTION get_order_statistics()
+RETURNS TABLE(status VARCHAR, count BIGINT, total_amount_base DECIMAL(12,2), avg_amount_base DECIMAL(12,2)) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT o.status, COUNT(*)::BIGINT, COALESCE(SUM(o.total_amount_base),0), COALESCE(AVG(o.total_amount_base),0)
+    FROM orders o GROUP BY o.status ORDER BY 2 DESC;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON TABLE exchange_rates IS 'Курсы валют к отчетной валюте';
@@ -0,0 +1,6 @@
+-- migrations/011_inventory.down.sql
+
+DROP TRIGGER IF EXISTS update_stock_reservations_updated_at ON stock_reservations;
+DROP TRIGGER IF EXISTS update_stock_levels_updated_at ON stock_levels;
+DROP TABLE IF EXISTS stock_reservations;
+DROP TABLE IF EXISTS stock_levels;
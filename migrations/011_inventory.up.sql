@@ -0,0 +1,41 @@
+-- migrations/011_inventory.up.sql
+
+-- Остатки товаров по складам
+CREATE TABLE IF NOT EXISTS stock_levels (
+    product_id UUID NOT NULL,
+    warehouse VARCHAR(50) NOT NULL,
+    on_hand INTEGER NOT NULL DEFAULT 0 CHECK (on_hand >= 0),
+    reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, warehouse),
+    CONSTRAINT check_stock_reserved CHECK (reserved <= on_hand)
+);
+
+-- Резервы товаров под позиции заказов
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    warehouse VARCHAR(50) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('reserved', 'committed', 'released')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (product_id, warehouse) REFERENCES stock_levels(product_id, warehouse)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_order_id ON stock_reservations(order_id);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_product ON stock_reservations(product_id, warehouse) WHERE status = 'reserved';
+
+CREATE TRIGGER update_stock_levels_updated_at
+    BEFORE UPDATE ON stock_levels
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_stock_reservations_updated_at
+    BEFORE UPDATE ON stock_reservations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE stock_levels IS 'Остатки товаров по складам';
+COMMENT ON TABLE stock_reservations IS 'Резервы товаров под заказы';
@@ -0,0 +1,5 @@
+-- migrations/012_products.down.sql
+
+DROP TRIGGER IF EXISTS update_products_updated_at ON products;
+DROP TABLE IF EXISTS product_prices;
+DROP TABLE IF EXISTS products;
@@ -0,0 +1,30 @@
+-- migrations/012_products.up.sql
+
+-- Каталог товаров
+CREATE TABLE IF NOT EXISTS products (
+    id UUID PRIMARY KEY,
+    sku VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    tax_category VARCHAR(50) NOT NULL DEFAULT '',
+    weight DECIMAL(10,3) NOT NULL DEFAULT 0 CHECK (weight >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Цены товаров по валютам
+CREATE TABLE IF NOT EXISTS product_prices (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+    price DECIMAL(10,2) NOT NULL CHECK (price > 0),
+    PRIMARY KEY (product_id, currency)
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_active ON products(active);
+
+CREATE TRIGGER update_products_updated_at
+    BEFORE UPDATE ON products
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE products IS 'Каталог товаров';
+COMMENT ON TABLE product_prices IS 'Цены товаров по валютам';
@@ -0,0 +1,4 @@
+-- migrations/013_customers.down.sql
+
+DROP TRIGGER IF EXISTS update_customers_updated_at ON customers;
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,28 @@
+-- migrations/013_customers.up.sql
+
+-- Клиенты; адреса по умолчанию хранятся в JSONB
+CREATE TABLE IF NOT EXISTS customers (
+    id UUID PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    default_shipping_address JSONB,
+    default_billing_address JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email ON customers(LOWER(email));
+
+-- Клиенты из существующих заказов: email и дата первого заказа.
+-- При совпадении email у разных customer_id клиент создается для первого из них.
+INSERT INTO customers (id, email, created_at, updated_at)
+SELECT DISTINCT ON (customer_id) customer_id, LOWER(email), created_at, created_at
+FROM orders
+ORDER BY customer_id, created_at
+ON CONFLICT DO NOTHING;
+
+CREATE TRIGGER update_customers_updated_at
+    BEFORE UPDATE ON customers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE customers IS 'Клиенты';
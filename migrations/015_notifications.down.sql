@@ -0,0 +1,3 @@
+-- migrations/015_notifications.down.sql
+
+DROP TABLE IF EXISTS order_notifications;
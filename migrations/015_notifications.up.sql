@@ -0,0 +1,16 @@
+-- migrations/015_notifications.up.sql
+
+-- Отправленные уведомления о заказах; один шаблон на заказ
+CREATE TABLE IF NOT EXISTS order_notifications (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    template VARCHAR(100) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    subject TEXT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (order_id, template)
+);
+
+COMMENT ON TABLE order_notifications IS 'Отправленные email уведомления о заказах';
@@ -0,0 +1,30 @@
+-- migrations/016_order_reports.down.sql
+
+DROP TRIGGER IF EXISTS mark_address_order_day_dirty_trigger ON order_addresses;
+DROP TRIGGER IF EXISTS mark_order_day_dirty_trigger ON orders;
+DROP FUNCTION IF EXISTS refresh_order_daily_stats();
+DROP FUNCTION IF EXISTS mark_address_order_day_dirty();
+DROP FUNCTION IF EXISTS mark_order_day_dirty();
+DROP TABLE IF EXISTS order_daily_stats_dirty;
+DROP TABLE IF EXISTS order_daily_stats;
+DROP INDEX IF EXISTS idx_orders_created_day;
+
+DROP VIEW IF EXISTS orders_with_stats;
+CREATE VIEW orders_with_stats AS
+SELECT
+    o.id,
+    o.customer_id,
+    o.email,
+    o.status,
+    o.total_amount,
+    o.currency,
+    o.created_at,
+    o.updated_at,
+    COUNT(oi.id) AS items_count,
+    COALESCE(SUM(oi.quantity),0) AS total_quantity,
+    (COUNT(oa.id) > 0) AS has_shipping_address
+FROM orders o
+LEFT JOIN order_items oi ON o.id = oi.order_id
+LEFT JOIN order_addresses oa ON o.id = oa.order_id AND oa.type='shipping'
+GROUP BY o.id;
+COMMENT ON VIEW orders_with_stats IS 'Заказы с аналитикой';
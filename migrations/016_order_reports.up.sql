@@ -0,0 +1,116 @@
+-- migrations/016_order_reports.up.sql
+
+-- Заказы с аналитикой: LATERAL вместо GROUP BY, чтобы фильтры по заказу доходили до индексов
+DROP VIEW IF EXISTS orders_with_stats;
+CREATE VIEW orders_with_stats AS
+SELECT
+    o.id,
+    o.customer_id,
+    o.email,
+    o.status,
+    o.total_amount,
+    o.currency,
+    o.created_at,
+    o.updated_at,
+    i.items_count,
+    i.total_quantity,
+    (sa.country IS NOT NULL) AS has_shipping_address,
+    o.total_amount_base,
+    COALESCE(sa.country, '') AS shipping_country,
+    (o.created_at AT TIME ZONE 'UTC')::date AS order_day
+FROM orders o
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS items_count, COALESCE(SUM(quantity), 0)::BIGINT AS total_quantity
+    FROM order_items WHERE order_id = o.id
+) i
+LEFT JOIN LATERAL (
+    SELECT country FROM order_addresses WHERE order_id = o.id AND type = 'shipping' LIMIT 1
+) sa ON TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_orders_created_day ON orders(((created_at AT TIME ZONE 'UTC')::date));
+
+-- Дневной срез заказов (день по UTC) для отчетов
+CREATE TABLE IF NOT EXISTS order_daily_stats (
+    day DATE NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    country VARCHAR(100) NOT NULL,
+    order_count BIGINT NOT NULL,
+    revenue DECIMAL(14,2) NOT NULL,
+    revenue_base DECIMAL(14,2) NOT NULL,
+    unconverted BIGINT NOT NULL,
+    item_quantity BIGINT NOT NULL,
+    PRIMARY KEY (day, status, currency, country)
+);
+
+-- Дни, срез которых устарел и еще не пересчитан
+CREATE TABLE IF NOT EXISTS order_daily_stats_dirty (
+    day DATE PRIMARY KEY
+);
+
+-- Любое изменение заказа помечает его день к пересчету
+CREATE OR REPLACE FUNCTION mark_order_day_dirty()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        INSERT INTO order_daily_stats_dirty (day)
+        VALUES ((OLD.created_at AT TIME ZONE 'UTC')::date) ON CONFLICT DO NOTHING;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        INSERT INTO order_daily_stats_dirty (day)
+        VALUES ((NEW.created_at AT TIME ZONE 'UTC')::date) ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER mark_order_day_dirty_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON orders
+    FOR EACH ROW EXECUTE FUNCTION mark_order_day_dirty();
+
+-- Адрес доставки влияет на страну в срезе
+CREATE OR REPLACE FUNCTION mark_address_order_day_dirty()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO order_daily_stats_dirty (day)
+    SELECT (o.created_at AT TIME ZONE 'UTC')::date FROM orders o
+    WHERE o.id = COALESCE(NEW.order_id, OLD.order_id)
+    ON CONFLICT DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER mark_address_order_day_dirty_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON order_addresses
+    FOR EACH ROW EXECUTE FUNCTION mark_address_order_day_dirty();
+
+-- Пересчитывает срез устаревших дней и возвращает их количество
+CREATE OR REPLACE FUNCTION refresh_order_daily_stats()
+RETURNS INT AS $$
+DECLARE
+    days DATE[];
+BEGIN
+    WITH taken AS (DELETE FROM order_daily_stats_dirty RETURNING day)
+    SELECT array_agg(day) INTO days FROM taken;
+    IF days IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    DELETE FROM order_daily_stats WHERE day = ANY(days);
+    INSERT INTO order_daily_stats (day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity)
+    SELECT order_day, status, currency, shipping_country, COUNT(*), COALESCE(SUM(total_amount), 0),
+        COALESCE(SUM(total_amount_base), 0), COUNT(*) FILTER (WHERE total_amount_base IS NULL), SUM(total_quantity)
+    FROM orders_with_stats
+    WHERE order_day = ANY(days)
+    GROUP BY order_day, status, currency, shipping_country;
+
+    RETURN array_length(days, 1);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Существующие заказы попадают в срез при первом пересчете
+INSERT INTO order_daily_stats_dirty (day)
+SELECT DISTINCT (created_at AT TIME ZONE 'UTC')::date FROM orders
+ON CONFLICT DO NOTHING;
+
+COMMENT ON VIEW orders_with_stats IS 'Заказы с аналитикой';
+COMMENT ON TABLE order_daily_stats IS 'Дневной срез заказов для отчетов';
+COMMENT ON TABLE order_daily_stats_dirty IS 'Дни, ожидающие пересчета дневного среза';
@@ -0,0 +1,46 @@
+-- migrations/017_order_archive.down.sql
+
+CREATE OR REPLACE FUNCTION refresh_order_daily_stats()
+RETURNS INT AS $$
+DECLARE
+    days DATE[];
+BEGIN
+    WITH taken AS (DELETE FROM order_daily_stats_dirty RETURNING day)
+    SELECT array_agg(day) INTO days FROM taken;
+    IF days IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    DELETE FROM order_daily_stats WHERE day = ANY(days);
+    INSERT INTO order_daily_stats (day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity)
+    SELECT order_day, status, currency, shipping_country, COUNT(*), COALESCE(SUM(total_amount), 0),
+        COALESCE(SUM(total_amount_base), 0), COUNT(*) FILTER (WHERE total_amount_base IS NULL), SUM(total_quantity)
+    FROM orders_with_stats
+    WHERE order_day = ANY(days)
+    GROUP BY order_day, status, currency, shipping_country;
+
+    RETURN array_length(days, 1);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS order_daily_stats_archived;
+
+CREATE OR REPLACE FUNCTION cleanup_old_orders(older_than_days INT DEFAULT 365)
+RETURNS INT AS $$
+DECLARE deleted_count INT;
+BEGIN
+    DELETE FROM orders
+    WHERE created_at < NOW() - (older_than_days || ' days')::INTERVAL
+      AND status IN ('delivered','cancelled','refunded');
+    GET DIAGNOSTICS deleted_count = ROW_COUNT;
+    RETURN deleted_count;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,64 @@
+-- migrations/017_order_archive.up.sql
+
+-- Вклад заархивированных (удаленных из orders) заказов в дневной срез отчетов
+CREATE TABLE IF NOT EXISTS order_daily_stats_archived (
+    day DATE NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    country VARCHAR(100) NOT NULL,
+    order_count BIGINT NOT NULL,
+    revenue DECIMAL(14,2) NOT NULL,
+    revenue_base DECIMAL(14,2) NOT NULL,
+    unconverted BIGINT NOT NULL,
+    item_quantity BIGINT NOT NULL,
+    PRIMARY KEY (day, status, currency, country)
+);
+
+-- updated_at, явно измененный запросом, сохраняется: восстановление из архива возвращает исходное значение
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.updated_at IS NOT DISTINCT FROM OLD.updated_at THEN
+        NEW.updated_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Пересчет среза учитывает заархивированные заказы
+CREATE OR REPLACE FUNCTION refresh_order_daily_stats()
+RETURNS INT AS $$
+DECLARE
+    days DATE[];
+BEGIN
+    WITH taken AS (DELETE FROM order_daily_stats_dirty RETURNING day)
+    SELECT array_agg(day) INTO days FROM taken;
+    IF days IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    DELETE FROM order_daily_stats WHERE day = ANY(days);
+    INSERT INTO order_daily_stats (day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity)
+    SELECT day, status, currency, country, SUM(order_count), SUM(revenue), SUM(revenue_base), SUM(unconverted), SUM(item_quantity)
+    FROM (
+        SELECT order_day AS day, status, currency, shipping_country AS country, COUNT(*) AS order_count,
+            COALESCE(SUM(total_amount), 0) AS revenue, COALESCE(SUM(total_amount_base), 0) AS revenue_base,
+            COUNT(*) FILTER (WHERE total_amount_base IS NULL) AS unconverted, SUM(total_quantity) AS item_quantity
+        FROM orders_with_stats
+        WHERE order_day = ANY(days)
+        GROUP BY order_day, status, currency, shipping_country
+        UNION ALL
+        SELECT day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity
+        FROM order_daily_stats_archived
+        WHERE day = ANY(days)
+    ) s
+    GROUP BY day, status, currency, country;
+
+    RETURN array_length(days, 1);
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON TABLE order_daily_stats_archived IS 'Вклад заархивированных заказов в дневной срез';
+
+-- Удаление без архива заменено архивацией (cmd/archive, планировщик consumer)
+DROP FUNCTION IF EXISTS cleanup_old_orders(INT);
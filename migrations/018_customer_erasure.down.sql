@@ -0,0 +1,3 @@
+-- migrations/018_customer_erasure.down.sql
+
+DROP TABLE IF EXISTS customer_erasures CASCADE;
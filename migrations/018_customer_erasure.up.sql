@@ -0,0 +1,17 @@
+-- migrations/018_customer_erasure.up.sql
+
+-- Журнал удаления персональных данных клиентов (GDPR). Сами данные не хранятся:
+-- только факт удаления, причина и объем
+CREATE TABLE IF NOT EXISTS customer_erasures (
+    id UUID PRIMARY KEY,
+    customer_id UUID NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    orders_anonymized INTEGER NOT NULL DEFAULT 0,
+    addresses_anonymized INTEGER NOT NULL DEFAULT 0,
+    event_id UUID NOT NULL,
+    erased_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_erasures_customer_id ON customer_erasures(customer_id, erased_at);
+
+COMMENT ON TABLE customer_erasures IS 'Журнал удаления персональных данных клиентов';
@@ -0,0 +1,66 @@
+-- migrations/019_pii_encryption.down.sql
+
+-- Перед откатом данные нужно расшифровать: pii decrypt.
+-- Иначе шифротекст не пройдет проверку email и не поместится в исходные колонки
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS include_pii;
+
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.updated_at IS NOT DISTINCT FROM OLD.updated_at THEN
+        NEW.updated_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS mark_address_order_day_dirty_trigger ON order_addresses;
+CREATE TRIGGER mark_address_order_day_dirty_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON order_addresses
+    FOR EACH ROW EXECUTE FUNCTION mark_address_order_day_dirty();
+
+DROP TRIGGER IF EXISTS mark_order_day_dirty_trigger ON orders;
+CREATE TRIGGER mark_order_day_dirty_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON orders
+    FOR EACH ROW EXECUTE FUNCTION mark_order_day_dirty();
+
+DROP VIEW IF EXISTS orders_with_stats;
+
+ALTER TABLE order_addresses
+    ALTER COLUMN street TYPE VARCHAR(255),
+    ALTER COLUMN city TYPE VARCHAR(100),
+    ALTER COLUMN state TYPE VARCHAR(100),
+    ALTER COLUMN zip_code TYPE VARCHAR(20);
+
+DROP INDEX IF EXISTS idx_orders_email_index;
+ALTER TABLE orders DROP COLUMN IF EXISTS email_index;
+COMMENT ON COLUMN orders.email IS NULL;
+ALTER TABLE orders ALTER COLUMN email TYPE VARCHAR(255);
+ALTER TABLE orders ADD CONSTRAINT check_valid_email CHECK (is_valid_email(email));
+
+CREATE VIEW orders_with_stats AS
+SELECT
+    o.id,
+    o.customer_id,
+    o.email,
+    o.status,
+    o.total_amount,
+    o.currency,
+    o.created_at,
+    o.updated_at,
+    i.items_count,
+    i.total_quantity,
+    (sa.country IS NOT NULL) AS has_shipping_address,
+    o.total_amount_base,
+    COALESCE(sa.country, '') AS shipping_country,
+    (o.created_at AT TIME ZONE 'UTC')::date AS order_day
+FROM orders o
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS items_count, COALESCE(SUM(quantity), 0)::BIGINT AS total_quantity
+    FROM order_items WHERE order_id = o.id
+) i
+LEFT JOIN LATERAL (
+    SELECT country FROM order_addresses WHERE order_id = o.id AND type = 'shipping' LIMIT 1
+) sa ON TRUE;
+
+COMMENT ON VIEW orders_with_stats IS 'Заказы с аналитикой';
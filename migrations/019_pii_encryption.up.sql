@@ -0,0 +1,76 @@
+-- migrations/019_pii_encryption.up.sql
+
+-- Email и адреса заказов хранятся зашифрованными (шифротекст длиннее исходных ограничений).
+-- Представление зависит от orders.email и пересоздается после смены типа
+DROP VIEW IF EXISTS orders_with_stats;
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS check_valid_email;
+ALTER TABLE orders ALTER COLUMN email TYPE TEXT;
+
+-- Blind index: HMAC нормализованного email для поиска по точному совпадению.
+-- NULL у заказов, записанных без шифрования
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS email_index VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_orders_email_index ON orders(email_index);
+
+ALTER TABLE order_addresses
+    ALTER COLUMN street TYPE TEXT,
+    ALTER COLUMN city TYPE TEXT,
+    ALTER COLUMN state TYPE TEXT,
+    ALTER COLUMN zip_code TYPE TEXT;
+
+CREATE VIEW orders_with_stats AS
+SELECT
+    o.id,
+    o.customer_id,
+    o.email,
+    o.status,
+    o.total_amount,
+    o.currency,
+    o.created_at,
+    o.updated_at,
+    i.items_count,
+    i.total_quantity,
+    (sa.country IS NOT NULL) AS has_shipping_address,
+    o.total_amount_base,
+    COALESCE(sa.country, '') AS shipping_country,
+    (o.created_at AT TIME ZONE 'UTC')::date AS order_day
+FROM orders o
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS items_count, COALESCE(SUM(quantity), 0)::BIGINT AS total_quantity
+    FROM order_items WHERE order_id = o.id
+) i
+LEFT JOIN LATERAL (
+    SELECT country FROM order_addresses WHERE order_id = o.id AND type = 'shipping' LIMIT 1
+) sa ON TRUE;
+
+COMMENT ON VIEW orders_with_stats IS 'Заказы с аналитикой';
+
+-- Перешифрование (cmd/pii) не должно помечать дни к пересчету: срез зависит только от этих колонок
+DROP TRIGGER IF EXISTS mark_order_day_dirty_trigger ON orders;
+CREATE TRIGGER mark_order_day_dirty_trigger
+    AFTER INSERT OR DELETE OR UPDATE OF status, currency, total_amount, total_amount_base, created_at ON orders
+    FOR EACH ROW EXECUTE FUNCTION mark_order_day_dirty();
+
+DROP TRIGGER IF EXISTS mark_address_order_day_dirty_trigger ON order_addresses;
+CREATE TRIGGER mark_address_order_day_dirty_trigger
+    AFTER INSERT OR DELETE OR UPDATE OF order_id, type, country ON order_addresses
+    FOR EACH ROW EXECUTE FUNCTION mark_address_order_day_dirty();
+
+-- Служебные изменения (перешифрование) не меняют updated_at: SET LOCAL app.keep_updated_at = 'on'
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF current_setting('app.keep_updated_at', TRUE) = 'on' THEN
+        NEW.updated_at = OLD.updated_at;
+    ELSIF NEW.updated_at IS NOT DISTINCT FROM OLD.updated_at THEN
+        NEW.updated_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Получатели webhook, которым разрешены персональные данные в открытом виде
+ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS include_pii BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN orders.email IS 'Email (шифротекст pii:v1 или открытый текст до перешифрования)';
+COMMENT ON COLUMN orders.email_index IS 'HMAC нормализованного email для поиска';
@@ -0,0 +1,126 @@
+-- migrations/020_multi_tenancy.down.sql
+
+-- Срезы отчетов снова общие: вклад архива суммируется по арендаторам, основной срез пересчитывается
+CREATE TEMP TABLE order_daily_stats_archived_merged AS
+SELECT day, status, currency, country, SUM(order_count) AS order_count, SUM(revenue) AS revenue,
+    SUM(revenue_base) AS revenue_base, SUM(unconverted) AS unconverted, SUM(item_quantity) AS item_quantity
+FROM order_daily_stats_archived
+GROUP BY day, status, currency, country;
+
+DELETE FROM order_daily_stats_archived;
+ALTER TABLE order_daily_stats_archived DROP CONSTRAINT IF EXISTS order_daily_stats_archived_pkey;
+ALTER TABLE order_daily_stats_archived DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE order_daily_stats_archived ADD PRIMARY KEY (day, status, currency, country);
+INSERT INTO order_daily_stats_archived (day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity)
+SELECT day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity
+FROM order_daily_stats_archived_merged;
+DROP TABLE order_daily_stats_archived_merged;
+
+INSERT INTO order_daily_stats_dirty (day)
+SELECT DISTINCT day FROM order_daily_stats
+ON CONFLICT DO NOTHING;
+DELETE FROM order_daily_stats;
+ALTER TABLE order_daily_stats DROP CONSTRAINT IF EXISTS order_daily_stats_pkey;
+ALTER TABLE order_daily_stats DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE order_daily_stats ADD PRIMARY KEY (day, status, currency, country);
+
+CREATE OR REPLACE FUNCTION refresh_order_daily_stats()
+RETURNS INT AS $$
+DECLARE
+    days DATE[];
+BEGIN
+    WITH taken AS (DELETE FROM order_daily_stats_dirty RETURNING day)
+    SELECT array_agg(day) INTO days FROM taken;
+    IF days IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    DELETE FROM order_daily_stats WHERE day = ANY(days);
+    INSERT INTO order_daily_stats (day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity)
+    SELECT day, status, currency, country, SUM(order_count), SUM(revenue), SUM(revenue_base), SUM(unconverted), SUM(item_quantity)
+    FROM (
+        SELECT order_day AS day, status, currency, shipping_country AS country, COUNT(*) AS order_count,
+            COALESCE(SUM(total_amount), 0) AS revenue, COALESCE(SUM(total_amount_base), 0) AS revenue_base,
+            COUNT(*) FILTER (WHERE total_amount_base IS NULL) AS unconverted, SUM(total_quantity) AS item_quantity
+        FROM orders_with_stats
+        WHERE order_day = ANY(days)
+        GROUP BY order_day, status, currency, shipping_country
+        UNION ALL
+        SELECT day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity
+        FROM order_daily_stats_archived
+        WHERE day = ANY(days)
+    ) s
+    GROUP BY day, status, currency, country;
+
+    RETURN array_length(days, 1);
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS mark_order_day_dirty_trigger ON orders;
+CREATE TRIGGER mark_order_day_dirty_trigger
+    AFTER INSERT OR DELETE OR UPDATE OF status, currency, total_amount, total_amount_base, created_at ON orders
+    FOR EACH ROW EXECUTE FUNCTION mark_order_day_dirty();
+
+DROP VIEW IF EXISTS orders_with_stats;
+CREATE VIEW orders_with_stats AS
+SELECT
+    o.id,
+    o.customer_id,
+    o.email,
+    o.status,
+    o.total_amount,
+    o.currency,
+    o.created_at,
+    o.updated_at,
+    i.items_count,
+    i.total_quantity,
+    (sa.country IS NOT NULL) AS has_shipping_address,
+    o.total_amount_base,
+    COALESCE(sa.country, '') AS shipping_country,
+    (o.created_at AT TIME ZONE 'UTC')::date AS order_day
+FROM orders o
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS items_count, COALESCE(SUM(quantity), 0)::BIGINT AS total_quantity
+    FROM order_items WHERE order_id = o.id
+) i
+LEFT JOIN LATERAL (
+    SELECT country FROM order_addresses WHERE order_id = o.id AND type = 'shipping' LIMIT 1
+) sa ON TRUE;
+
+COMMENT ON VIEW orders_with_stats IS 'Заказы с аналитикой';
+
+DROP INDEX IF EXISTS idx_webhook_subscriptions_tenant;
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS tenant_id;
+
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOREACH tbl IN ARRAY ARRAY[
+        'orders', 'order_items', 'order_addresses', 'order_risk_assessments',
+        'shipments', 'shipment_items', 'return_requests', 'return_items', 'refunds',
+        'promotion_redemptions', 'order_discounts', 'order_item_taxes',
+        'stock_reservations', 'order_notifications'
+    ]
+    LOOP
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', tbl);
+        EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', tbl);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', tbl);
+        IF tbl <> 'orders' THEN
+            EXECUTE format('DROP TRIGGER IF EXISTS set_tenant_trigger ON %I', tbl);
+            EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS tenant_id', tbl);
+        END IF;
+    END LOOP;
+END;
+$$;
+
+DROP FUNCTION IF EXISTS set_tenant_from_parent();
+
+DROP TRIGGER IF EXISTS set_order_tenant_trigger ON orders;
+DROP FUNCTION IF EXISTS set_order_tenant();
+DROP INDEX IF EXISTS idx_orders_tenant_created_at;
+DROP INDEX IF EXISTS idx_orders_tenant_status;
+DROP INDEX IF EXISTS idx_orders_tenant_customer_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS tenant_id;
+
+DROP FUNCTION IF EXISTS current_tenant_id();
@@ -0,0 +1,174 @@
+-- migrations/020_multi_tenancy.up.sql
+
+-- Арендатор текущей транзакции: SELECT set_config('app.tenant_id', 'acme', true).
+-- NULL - служебный доступ без привязки к арендатору (планировщики, утилиты)
+CREATE OR REPLACE FUNCTION current_tenant_id()
+RETURNS VARCHAR AS $$
+    SELECT NULLIF(current_setting('app.tenant_id', TRUE), '')
+$$ LANGUAGE sql STABLE;
+
+-- Существующие заказы относятся к арендатору по умолчанию
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE orders ALTER COLUMN tenant_id SET DEFAULT COALESCE(current_tenant_id(), 'default');
+
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_created_at ON orders(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_status ON orders(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_customer_id ON orders(tenant_id, customer_id);
+
+-- Восстановление из архива до арендаторов передает tenant_id = NULL явно
+CREATE OR REPLACE FUNCTION set_order_tenant()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.tenant_id = COALESCE(NEW.tenant_id, current_tenant_id(), 'default');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER set_order_tenant_trigger
+    BEFORE INSERT ON orders
+    FOR EACH ROW EXECUTE FUNCTION set_order_tenant();
+
+-- Дочерние строки получают арендатора родителя: TG_ARGV[0] - таблица родителя, TG_ARGV[1] - колонка ссылки.
+-- Родитель другого арендатора не виден под RLS, и вставка отклоняется проверкой политики
+CREATE OR REPLACE FUNCTION set_tenant_from_parent()
+RETURNS TRIGGER AS $$
+DECLARE
+    parent_tenant VARCHAR(64);
+BEGIN
+    EXECUTE format('SELECT tenant_id FROM %I WHERE id = $1', TG_ARGV[0])
+        INTO parent_tenant
+        USING (to_jsonb(NEW) ->> TG_ARGV[1])::UUID;
+    NEW.tenant_id = COALESCE(parent_tenant, NEW.tenant_id, 'default');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    child RECORD;
+BEGIN
+    FOR child IN
+        SELECT * FROM (VALUES
+            ('order_items', 'orders', 'order_id'),
+            ('order_addresses', 'orders', 'order_id'),
+            ('order_risk_assessments', 'orders', 'order_id'),
+            ('shipments', 'orders', 'order_id'),
+            ('shipment_items', 'shipments', 'shipment_id'),
+            ('return_requests', 'orders', 'order_id'),
+            ('return_items', 'return_requests', 'return_id'),
+            ('refunds', 'orders', 'order_id'),
+            ('promotion_redemptions', 'orders', 'order_id'),
+            ('order_discounts', 'orders', 'order_id'),
+            ('order_item_taxes', 'orders', 'order_id'),
+            ('stock_reservations', 'orders', 'order_id'),
+            ('order_notifications', 'orders', 'order_id')
+        ) AS c(tbl, parent, fk)
+    LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT %L', child.tbl, 'default');
+        EXECUTE format('CREATE TRIGGER set_tenant_trigger BEFORE INSERT OR UPDATE OF %I ON %I
+            FOR EACH ROW EXECUTE FUNCTION set_tenant_from_parent(%L, %L)', child.fk, child.tbl, child.parent, child.fk);
+    END LOOP;
+END;
+$$;
+
+-- Row-level security - страховка на случай запроса без условия по арендатору.
+-- Не действует на суперпользователя: сервис должен подключаться отдельной ролью
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOREACH tbl IN ARRAY ARRAY[
+        'orders', 'order_items', 'order_addresses', 'order_risk_assessments',
+        'shipments', 'shipment_items', 'return_requests', 'return_items', 'refunds',
+        'promotion_redemptions', 'order_discounts', 'order_item_taxes',
+        'stock_reservations', 'order_notifications'
+    ]
+    LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', tbl);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', tbl);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())
+            WITH CHECK (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())', tbl);
+    END LOOP;
+END;
+$$;
+
+-- Подписки webhook принадлежат арендатору и получают только его события
+ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_tenant ON webhook_subscriptions(tenant_id);
+
+-- Представление дополняется колонкой арендатора (новые колонки - только в конце)
+CREATE OR REPLACE VIEW orders_with_stats AS
+SELECT
+    o.id,
+    o.customer_id,
+    o.email,
+    o.status,
+    o.total_amount,
+    o.currency,
+    o.created_at,
+    o.updated_at,
+    i.items_count,
+    i.total_quantity,
+    (sa.country IS NOT NULL) AS has_shipping_address,
+    o.total_amount_base,
+    COALESCE(sa.country, '') AS shipping_country,
+    (o.created_at AT TIME ZONE 'UTC')::date AS order_day,
+    o.tenant_id
+FROM orders o
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS items_count, COALESCE(SUM(quantity), 0)::BIGINT AS total_quantity
+    FROM order_items WHERE order_id = o.id
+) i
+LEFT JOIN LATERAL (
+    SELECT country FROM order_addresses WHERE order_id = o.id AND type = 'shipping' LIMIT 1
+) sa ON TRUE;
+
+-- Срезы отчетов строятся по арендаторам
+ALTER TABLE order_daily_stats ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE order_daily_stats DROP CONSTRAINT IF EXISTS order_daily_stats_pkey;
+ALTER TABLE order_daily_stats ADD PRIMARY KEY (tenant_id, day, status, currency, country);
+
+ALTER TABLE order_daily_stats_archived ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE order_daily_stats_archived DROP CONSTRAINT IF EXISTS order_daily_stats_archived_pkey;
+ALTER TABLE order_daily_stats_archived ADD PRIMARY KEY (tenant_id, day, status, currency, country);
+
+-- Смена арендатора заказа переносит его между срезами
+DROP TRIGGER IF EXISTS mark_order_day_dirty_trigger ON orders;
+CREATE TRIGGER mark_order_day_dirty_trigger
+    AFTER INSERT OR DELETE OR UPDATE OF status, currency, total_amount, total_amount_base, created_at, tenant_id ON orders
+    FOR EACH ROW EXECUTE FUNCTION mark_order_day_dirty();
+
+CREATE OR REPLACE FUNCTION refresh_order_daily_stats()
+RETURNS INT AS $$
+DECLARE
+    days DATE[];
+BEGIN
+    WITH taken AS (DELETE FROM order_daily_stats_dirty RETURNING day)
+    SELECT array_agg(day) INTO days FROM taken;
+    IF days IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    DELETE FROM order_daily_stats WHERE day = ANY(days);
+    INSERT INTO order_daily_stats (tenant_id, day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity)
+    SELECT tenant_id, day, status, currency, country, SUM(order_count), SUM(revenue), SUM(revenue_base), SUM(unconverted), SUM(item_quantity)
+    FROM (
+        SELECT tenant_id, order_day AS day, status, currency, shipping_country AS country, COUNT(*) AS order_count,
+            COALESCE(SUM(total_amount), 0) AS revenue, COALESCE(SUM(total_amount_base), 0) AS revenue_base,
+            COUNT(*) FILTER (WHERE total_amount_base IS NULL) AS unconverted, SUM(total_quantity) AS item_quantity
+        FROM orders_with_stats
+        WHERE order_day = ANY(days)
+        GROUP BY tenant_id, order_day, status, currency, shipping_country
+        UNION ALL
+        SELECT tenant_id, day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity
+        FROM order_daily_stats_archived
+        WHERE day = ANY(days)
+    ) s
+    GROUP BY tenant_id, day, status, currency, country;
+
+    RETURN array_length(days, 1);
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON COLUMN orders.tenant_id IS 'Арендатор (витрина) заказа';
+COMMENT ON FUNCTION current_tenant_id() IS 'Арендатор транзакции из app.tenant_id';
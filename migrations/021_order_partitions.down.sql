@@ -0,0 +1,254 @@
+-- migrations/021_order_partitions.down.sql
+
+-- Заказы и позиции возвращаются в обычные таблицы. Отсоединенные партиции
+-- (detach_order_partition) не переносятся и остаются отдельными таблицами.
+
+DROP VIEW IF EXISTS orders_with_stats;
+
+ALTER TABLE orders RENAME TO orders_partitioned;
+ALTER TABLE order_items RENAME TO order_items_partitioned;
+
+CREATE TABLE orders (
+    LIKE orders_partitioned INCLUDING DEFAULTS INCLUDING COMMENTS
+);
+CREATE TABLE order_items (
+    LIKE order_items_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS
+);
+
+INSERT INTO orders SELECT * FROM orders_partitioned;
+INSERT INTO order_items SELECT * FROM order_items_partitioned;
+ALTER TABLE order_items DROP COLUMN order_created_at;
+
+DO $$
+DECLARE
+    fk RECORD;
+BEGIN
+    FOR fk IN
+        SELECT conrelid::regclass AS tbl, conname
+        FROM pg_constraint
+        WHERE contype = 'f'
+          AND confrelid = 'order_keys'::regclass
+          AND conparentid = 0
+          AND conrelid <> 'order_items_partitioned'::regclass
+    LOOP
+        EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', fk.tbl, fk.conname);
+    END LOOP;
+END;
+$$;
+
+DROP TABLE order_items_partitioned, orders_partitioned;
+DROP TABLE order_keys;
+
+DROP FUNCTION IF EXISTS detach_order_partition(DATE, BOOLEAN);
+DROP FUNCTION IF EXISTS create_order_partition(DATE);
+DROP FUNCTION IF EXISTS sync_order_keys();
+DROP FUNCTION IF EXISTS keep_order_created_at();
+
+ALTER TABLE orders ADD PRIMARY KEY (id);
+ALTER TABLE order_items ADD PRIMARY KEY (id);
+
+ALTER TABLE orders
+    ADD CONSTRAINT check_order_status_format CHECK (status ~ '^[a-z][a-z_]*$');
+ALTER TABLE orders
+    ADD CONSTRAINT check_shipping_amount CHECK (shipping_amount >= 0);
+ALTER TABLE orders
+    ADD CONSTRAINT check_order_currency CHECK (currency ~ '^[A-Z]{3}$') NOT VALID;
+
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders(customer_id);
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders(status);
+CREATE INDEX IF NOT EXISTS idx_orders_email ON orders(email);
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders(created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at ON orders(updated_at);
+CREATE INDEX IF NOT EXISTS idx_orders_total_amount ON orders(total_amount);
+CREATE INDEX IF NOT EXISTS idx_orders_customer_status ON orders(customer_id, status);
+CREATE INDEX IF NOT EXISTS idx_orders_status_created ON orders(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_pending_created ON orders(created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_orders_total_amount_base ON orders(total_amount_base);
+CREATE INDEX IF NOT EXISTS idx_orders_created_day ON orders(((created_at AT TIME ZONE 'UTC')::date));
+CREATE INDEX IF NOT EXISTS idx_orders_email_index ON orders(email_index);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_created_at ON orders(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_status ON orders(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_customer_id ON orders(tenant_id, customer_id);
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items(order_id);
+CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items(product_id);
+
+ALTER TABLE order_items ADD FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE;
+
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOREACH tbl IN ARRAY ARRAY[
+        'order_addresses', 'order_risk_assessments', 'shipments', 'return_requests', 'refunds',
+        'promotion_redemptions', 'order_discounts', 'order_item_taxes', 'stock_reservations',
+        'order_notifications'
+    ]
+    LOOP
+        EXECUTE format('ALTER TABLE %I ADD FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE', tbl);
+        EXECUTE format('DROP TRIGGER IF EXISTS set_tenant_trigger ON %I', tbl);
+        EXECUTE format('CREATE TRIGGER set_tenant_trigger BEFORE INSERT OR UPDATE OF order_id ON %I
+            FOR EACH ROW EXECUTE FUNCTION set_tenant_from_parent(%L, %L)', tbl, 'orders', 'order_id');
+    END LOOP;
+
+    FOREACH tbl IN ARRAY ARRAY['shipment_items', 'return_items', 'order_discounts', 'order_item_taxes', 'stock_reservations']
+    LOOP
+        EXECUTE format('ALTER TABLE %I ADD FOREIGN KEY (order_item_id) REFERENCES order_items(id) ON DELETE CASCADE', tbl);
+    END LOOP;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION calculate_order_total(order_id_param UUID)
+RETURNS DECIMAL(10,2) AS $$
+DECLARE
+    total_sum DECIMAL(10,2);
+    shipping DECIMAL(10,2);
+BEGIN
+    SELECT COALESCE(SUM(total - discount_amount + CASE WHEN tax_inclusive THEN 0 ELSE tax_amount END), 0.00)
+    INTO total_sum
+    FROM order_items
+    WHERE order_id = order_id_param;
+
+    SELECT COALESCE(shipping_amount, 0.00) INTO shipping
+    FROM orders
+    WHERE id = order_id_param;
+
+    RETURN total_sum + COALESCE(shipping, 0.00);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_order_total_on_item_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    affected_order_id UUID;
+    new_subtotal DECIMAL(10,2);
+    new_discount DECIMAL(10,2);
+    new_tax DECIMAL(10,2);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        affected_order_id := OLD.order_id;
+    ELSE
+        affected_order_id := NEW.order_id;
+    END IF;
+    SELECT COALESCE(SUM(total), 0.00), COALESCE(SUM(discount_amount), 0.00), COALESCE(SUM(tax_amount), 0.00)
+    INTO new_subtotal, new_discount, new_tax
+    FROM order_items
+    WHERE order_id = affected_order_id;
+    UPDATE orders
+    SET subtotal = new_subtotal,
+        discount_amount = new_discount,
+        tax_amount = new_tax,
+        total_amount = calculate_order_total(affected_order_id),
+        updated_at = NOW()
+    WHERE id = affected_order_id;
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION mark_address_order_day_dirty()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO order_daily_stats_dirty (day)
+    SELECT (o.created_at AT TIME ZONE 'UTC')::date FROM orders o
+    WHERE o.id = COALESCE(NEW.order_id, OLD.order_id)
+    ON CONFLICT DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER update_orders_updated_at
+    BEFORE UPDATE ON orders
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER set_order_tenant_trigger
+    BEFORE INSERT ON orders
+    FOR EACH ROW EXECUTE FUNCTION set_order_tenant();
+CREATE CONSTRAINT TRIGGER check_order_total_trigger
+    AFTER INSERT OR UPDATE ON orders
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_order_total();
+CREATE TRIGGER mark_order_day_dirty_trigger
+    AFTER INSERT OR DELETE OR UPDATE OF status, currency, total_amount, total_amount_base, created_at, tenant_id ON orders
+    FOR EACH ROW EXECUTE FUNCTION mark_order_day_dirty();
+
+CREATE TRIGGER update_order_total_on_item_change_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON order_items
+    FOR EACH ROW EXECUTE FUNCTION update_order_total_on_item_change();
+CREATE TRIGGER set_tenant_trigger
+    BEFORE INSERT OR UPDATE OF order_id ON order_items
+    FOR EACH ROW EXECUTE FUNCTION set_tenant_from_parent('orders', 'order_id');
+
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOREACH tbl IN ARRAY ARRAY['orders', 'order_items']
+    LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', tbl);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', tbl);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())
+            WITH CHECK (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())', tbl);
+    END LOOP;
+END;
+$$;
+
+CREATE VIEW orders_with_stats AS
+SELECT
+    o.id,
+    o.customer_id,
+    o.email,
+    o.status,
+    o.total_amount,
+    o.currency,
+    o.created_at,
+    o.updated_at,
+    i.items_count,
+    i.total_quantity,
+    (sa.country IS NOT NULL) AS has_shipping_address,
+    o.total_amount_base,
+    COALESCE(sa.country, '') AS shipping_country,
+    (o.created_at AT TIME ZONE 'UTC')::date AS order_day,
+    o.tenant_id
+FROM orders o
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS items_count, COALESCE(SUM(quantity), 0)::BIGINT AS total_quantity
+    FROM order_items WHERE order_id = o.id
+) i
+LEFT JOIN LATERAL (
+    SELECT country FROM order_addresses WHERE order_id = o.id AND type = 'shipping' LIMIT 1
+) sa ON TRUE;
+
+CREATE OR REPLACE FUNCTION refresh_order_daily_stats()
+RETURNS INT AS $$
+DECLARE
+    days DATE[];
+BEGIN
+    WITH taken AS (DELETE FROM order_daily_stats_dirty RETURNING day)
+    SELECT array_agg(day) INTO days FROM taken;
+    IF days IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    DELETE FROM order_daily_stats WHERE day = ANY(days);
+    INSERT INTO order_daily_stats (tenant_id, day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity)
+    SELECT tenant_id, day, status, currency, country, SUM(order_count), SUM(revenue), SUM(revenue_base), SUM(unconverted), SUM(item_quantity)
+    FROM (
+        SELECT tenant_id, order_day AS day, status, currency, shipping_country AS country, COUNT(*) AS order_count,
+            COALESCE(SUM(total_amount), 0) AS revenue, COALESCE(SUM(total_amount_base), 0) AS revenue_base,
+            COUNT(*) FILTER (WHERE total_amount_base IS NULL) AS unconverted, SUM(total_quantity) AS item_quantity
+        FROM orders_with_stats
+        WHERE order_day = ANY(days)
+        GROUP BY tenant_id, order_day, status, currency, shipping_country
+        UNION ALL
+        SELECT tenant_id, day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity
+        FROM order_daily_stats_archived
+        WHERE day = ANY(days)
+    ) s
+    GROUP BY tenant_id, day, status, currency, country;
+
+    RETURN array_length(days, 1);
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON TABLE orders IS 'Основная таблица заказов';
+COMMENT ON TABLE order_items IS 'Элементы заказов';
+COMMENT ON VIEW orders_with_stats IS 'Заказы с аналитикой';
@@ -0,0 +1,417 @@
+-- migrations/021_order_partitions.up.sql
+
+-- Заказы и позиции секционируются по месяцу создания заказа (RANGE, границы месяцев по UTC).
+-- Уникальный ключ секционированной таблицы обязан включать ключ секционирования, поэтому
+-- дочерние таблицы ссылаются на реестр order_keys (id -> created_at, tenant_id), который
+-- ведут триггеры orders. По реестру же запрос по ID заказа выбирает одну партицию.
+-- Миграция переносит данные в новые таблицы: на время переноса запись заказов недоступна.
+
+DROP VIEW IF EXISTS orders_with_stats;
+
+ALTER TABLE orders RENAME TO orders_unpartitioned;
+ALTER TABLE order_items RENAME TO order_items_unpartitioned;
+
+CREATE TABLE orders (
+    LIKE orders_unpartitioned INCLUDING DEFAULTS INCLUDING COMMENTS
+) PARTITION BY RANGE (created_at);
+
+-- Позиция хранит время создания своего заказа и лежит в партиции того же месяца
+CREATE TABLE order_items (
+    LIKE order_items_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS,
+    order_created_at TIMESTAMPTZ NOT NULL
+) PARTITION BY RANGE (order_created_at);
+
+-- Реестр ID заказов: глобальная уникальность ID и цель внешних ключей дочерних таблиц
+CREATE TABLE order_keys (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL
+);
+CREATE INDEX idx_order_keys_created_at ON order_keys(created_at);
+
+-- Создает партиции заказов и позиций месяца; FALSE - уже есть или месяц занят
+-- строками партиции по умолчанию (их нужно перенести вручную)
+CREATE OR REPLACE FUNCTION create_order_partition(month DATE)
+RETURNS BOOLEAN AS $$
+DECLARE
+    month_start DATE := date_trunc('month', month::timestamp)::date;
+    lower_bound TIMESTAMPTZ := month_start::timestamp AT TIME ZONE 'UTC';
+    upper_bound TIMESTAMPTZ := (month_start + INTERVAL '1 month') AT TIME ZONE 'UTC';
+    suffix TEXT := to_char(month_start, 'YYYYMM');
+BEGIN
+    IF to_regclass('orders_p' || suffix) IS NOT NULL THEN
+        RETURN FALSE;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM orders_default WHERE created_at >= lower_bound AND created_at < upper_bound) THEN
+        RAISE WARNING 'orders_default has rows for %, partition orders_p% is not created', month_start, suffix;
+        RETURN FALSE;
+    END IF;
+
+    -- Короткое ожидание блокировок: занятая таблица обрабатывается при следующем запуске
+    PERFORM set_config('lock_timeout', '5s', TRUE);
+    EXECUTE format('CREATE TABLE %I PARTITION OF orders FOR VALUES FROM (%L) TO (%L)',
+        'orders_p' || suffix, lower_bound, upper_bound);
+    EXECUTE format('CREATE TABLE %I PARTITION OF order_items FOR VALUES FROM (%L) TO (%L)',
+        'order_items_p' || suffix, lower_bound, upper_bound);
+    RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Строки вне созданных месяцев (восстановление из архива, ошибочные даты)
+CREATE TABLE orders_default PARTITION OF orders DEFAULT;
+CREATE TABLE order_items_default PARTITION OF order_items DEFAULT;
+
+-- Партиции от первого заказа до трех месяцев вперед
+DO $$
+DECLARE
+    month DATE;
+BEGIN
+    SELECT date_trunc('month', MIN(created_at) AT TIME ZONE 'UTC')::date INTO month FROM orders_unpartitioned;
+    month := COALESCE(month, date_trunc('month', now() AT TIME ZONE 'UTC')::date);
+    WHILE month <= (date_trunc('month', now() AT TIME ZONE 'UTC') + INTERVAL '3 months')::date LOOP
+        PERFORM create_order_partition(month);
+        month := (month + INTERVAL '1 month')::date;
+    END LOOP;
+END;
+$$;
+
+-- Перенос данных до создания триггеров: итоги и срезы отчетов не пересчитываются
+INSERT INTO orders SELECT * FROM orders_unpartitioned;
+INSERT INTO order_items
+SELECT i.*, o.created_at
+FROM order_items_unpartitioned i
+JOIN orders_unpartitioned o ON o.id = i.order_id;
+INSERT INTO order_keys (id, created_at, tenant_id)
+SELECT id, created_at, tenant_id FROM orders_unpartitioned;
+
+-- Внешние ключи дочерних таблиц на старые таблицы удаляются вместе с ними
+DO $$
+DECLARE
+    fk RECORD;
+BEGIN
+    FOR fk IN
+        SELECT conrelid::regclass AS tbl, conname
+        FROM pg_constraint
+        WHERE contype = 'f'
+          AND confrelid IN ('orders_unpartitioned'::regclass, 'order_items_unpartitioned'::regclass)
+          AND conrelid NOT IN ('orders_unpartitioned'::regclass, 'order_items_unpartitioned'::regclass)
+    LOOP
+        EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', fk.tbl, fk.conname);
+    END LOOP;
+END;
+$$;
+
+DROP TABLE order_items_unpartitioned, orders_unpartitioned;
+
+ALTER TABLE orders ADD PRIMARY KEY (id, created_at);
+ALTER TABLE order_items ADD PRIMARY KEY (id, order_created_at);
+
+ALTER TABLE orders
+    ADD CONSTRAINT check_order_status_format CHECK (status ~ '^[a-z][a-z_]*$');
+ALTER TABLE orders
+    ADD CONSTRAINT check_shipping_amount CHECK (shipping_amount >= 0);
+ALTER TABLE orders
+    ADD CONSTRAINT check_order_currency CHECK (currency ~ '^[A-Z]{3}$') NOT VALID;
+
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders(customer_id);
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders(status);
+CREATE INDEX IF NOT EXISTS idx_orders_email ON orders(email);
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders(created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at ON orders(updated_at);
+CREATE INDEX IF NOT EXISTS idx_orders_total_amount ON orders(total_amount);
+CREATE INDEX IF NOT EXISTS idx_orders_customer_status ON orders(customer_id, status);
+CREATE INDEX IF NOT EXISTS idx_orders_status_created ON orders(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_pending_created ON orders(created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_orders_total_amount_base ON orders(total_amount_base);
+CREATE INDEX IF NOT EXISTS idx_orders_created_day ON orders(((created_at AT TIME ZONE 'UTC')::date));
+CREATE INDEX IF NOT EXISTS idx_orders_email_index ON orders(email_index);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_created_at ON orders(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_status ON orders(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_customer_id ON orders(tenant_id, customer_id);
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items(order_id);
+CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items(product_id);
+
+-- Ссылки на заказ идут на реестр. Ссылки на позиции (order_item_id) остаются без
+-- внешнего ключа: позиции удаляются только вместе с заказом, а его удаление каскадно
+-- удаляет и ссылающиеся строки
+ALTER TABLE order_items ADD FOREIGN KEY (order_id) REFERENCES order_keys(id) ON DELETE CASCADE;
+
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOREACH tbl IN ARRAY ARRAY[
+        'order_addresses', 'order_risk_assessments', 'shipments', 'return_requests', 'refunds',
+        'promotion_redemptions', 'order_discounts', 'order_item_taxes', 'stock_reservations',
+        'order_notifications'
+    ]
+    LOOP
+        EXECUTE format('ALTER TABLE %I ADD FOREIGN KEY (order_id) REFERENCES order_keys(id) ON DELETE CASCADE', tbl);
+        -- Арендатор берется из реестра: поиск по ID в orders обходил бы все партиции
+        EXECUTE format('DROP TRIGGER IF EXISTS set_tenant_trigger ON %I', tbl);
+        EXECUTE format('CREATE TRIGGER set_tenant_trigger BEFORE INSERT OR UPDATE OF order_id ON %I
+            FOR EACH ROW EXECUTE FUNCTION set_tenant_from_parent(%L, %L)', tbl, 'order_keys', 'order_id');
+    END LOOP;
+END;
+$$;
+
+-- Реестр следует за заказом; удаление заказа каскадно удаляет его дочерние строки
+CREATE OR REPLACE FUNCTION sync_order_keys()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO order_keys (id, created_at, tenant_id) VALUES (NEW.id, NEW.created_at, NEW.tenant_id);
+    ELSIF TG_OP = 'DELETE' THEN
+        DELETE FROM order_keys WHERE id = OLD.id;
+    ELSE
+        UPDATE order_keys SET tenant_id = NEW.tenant_id WHERE id = NEW.id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Смена created_at перенесла бы заказ в другую партицию как удаление и вставку,
+-- и удаление из реестра забрало бы с собой дочерние строки
+CREATE OR REPLACE FUNCTION keep_order_created_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.created_at IS DISTINCT FROM OLD.created_at THEN
+        RAISE EXCEPTION 'created_at of order % cannot be changed: it defines the order partition', OLD.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Итог заказа: партиции заказа и позиций выбираются по реестру
+CREATE OR REPLACE FUNCTION calculate_order_total(order_id_param UUID)
+RETURNS DECIMAL(10,2) AS $$
+DECLARE
+    order_created TIMESTAMPTZ;
+    total_sum DECIMAL(10,2);
+    shipping DECIMAL(10,2);
+BEGIN
+    SELECT created_at INTO order_created FROM order_keys WHERE id = order_id_param;
+
+    SELECT COALESCE(SUM(total - discount_amount + CASE WHEN tax_inclusive THEN 0 ELSE tax_amount END), 0.00)
+    INTO total_sum
+    FROM order_items
+    WHERE order_id = order_id_param AND order_created_at = order_created;
+
+    SELECT COALESCE(shipping_amount, 0.00) INTO shipping
+    FROM orders
+    WHERE id = order_id_param AND created_at = order_created;
+
+    RETURN total_sum + COALESCE(shipping, 0.00);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_order_total_on_item_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    affected_order_id UUID;
+    affected_created_at TIMESTAMPTZ;
+    new_subtotal DECIMAL(10,2);
+    new_discount DECIMAL(10,2);
+    new_tax DECIMAL(10,2);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        affected_order_id := OLD.order_id;
+        affected_created_at := OLD.order_created_at;
+    ELSE
+        affected_order_id := NEW.order_id;
+        affected_created_at := NEW.order_created_at;
+    END IF;
+    SELECT COALESCE(SUM(total), 0.00), COALESCE(SUM(discount_amount), 0.00), COALESCE(SUM(tax_amount), 0.00)
+    INTO new_subtotal, new_discount, new_tax
+    FROM order_items
+    WHERE order_id = affected_order_id AND order_created_at = affected_created_at;
+    UPDATE orders
+    SET subtotal = new_subtotal,
+        discount_amount = new_discount,
+        tax_amount = new_tax,
+        total_amount = calculate_order_total(affected_order_id),
+        updated_at = NOW()
+    WHERE id = affected_order_id AND created_at = affected_created_at;
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION mark_address_order_day_dirty()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO order_daily_stats_dirty (day)
+    SELECT (k.created_at AT TIME ZONE 'UTC')::date FROM order_keys k
+    WHERE k.id = COALESCE(NEW.order_id, OLD.order_id)
+    ON CONFLICT DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Триггеры секционированных таблиц клонируются на все партиции
+CREATE TRIGGER update_orders_updated_at
+    BEFORE UPDATE ON orders
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER keep_order_created_at_trigger
+    BEFORE UPDATE OF created_at ON orders
+    FOR EACH ROW EXECUTE FUNCTION keep_order_created_at();
+CREATE TRIGGER set_order_tenant_trigger
+    BEFORE INSERT ON orders
+    FOR EACH ROW EXECUTE FUNCTION set_order_tenant();
+CREATE TRIGGER sync_order_keys_trigger
+    AFTER INSERT OR DELETE OR UPDATE OF tenant_id ON orders
+    FOR EACH ROW EXECUTE FUNCTION sync_order_keys();
+CREATE CONSTRAINT TRIGGER check_order_total_trigger
+    AFTER INSERT OR UPDATE ON orders
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_order_total();
+CREATE TRIGGER mark_order_day_dirty_trigger
+    AFTER INSERT OR DELETE OR UPDATE OF status, currency, total_amount, total_amount_base, created_at, tenant_id ON orders
+    FOR EACH ROW EXECUTE FUNCTION mark_order_day_dirty();
+
+CREATE TRIGGER update_order_total_on_item_change_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON order_items
+    FOR EACH ROW EXECUTE FUNCTION update_order_total_on_item_change();
+CREATE TRIGGER set_tenant_trigger
+    BEFORE INSERT OR UPDATE OF order_id ON order_items
+    FOR EACH ROW EXECUTE FUNCTION set_tenant_from_parent('order_keys', 'order_id');
+
+-- Row-level security, как у прежних таблиц
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOREACH tbl IN ARRAY ARRAY['orders', 'order_items', 'order_keys']
+    LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', tbl);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', tbl);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())
+            WITH CHECK (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())', tbl);
+    END LOOP;
+END;
+$$;
+
+-- Позиции заказа ищутся в партиции его месяца
+CREATE VIEW orders_with_stats AS
+SELECT
+    o.id,
+    o.customer_id,
+    o.email,
+    o.status,
+    o.total_amount,
+    o.currency,
+    o.created_at,
+    o.updated_at,
+    i.items_count,
+    i.total_quantity,
+    (sa.country IS NOT NULL) AS has_shipping_address,
+    o.total_amount_base,
+    COALESCE(sa.country, '') AS shipping_country,
+    (o.created_at AT TIME ZONE 'UTC')::date AS order_day,
+    o.tenant_id
+FROM orders o
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS items_count, COALESCE(SUM(quantity), 0)::BIGINT AS total_quantity
+    FROM order_items WHERE order_id = o.id AND order_created_at = o.created_at
+) i
+LEFT JOIN LATERAL (
+    SELECT country FROM order_addresses WHERE order_id = o.id AND type = 'shipping' LIMIT 1
+) sa ON TRUE;
+
+-- Пересчет среза ограничивает заказы диапазоном дней, чтобы читать только их партиции
+CREATE OR REPLACE FUNCTION refresh_order_daily_stats()
+RETURNS INT AS $$
+DECLARE
+    days DATE[];
+    lower_bound TIMESTAMPTZ;
+    upper_bound TIMESTAMPTZ;
+BEGIN
+    WITH taken AS (DELETE FROM order_daily_stats_dirty RETURNING day)
+    SELECT array_agg(day) INTO days FROM taken;
+    IF days IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    SELECT MIN(d)::timestamp AT TIME ZONE 'UTC', (MAX(d) + 1)::timestamp AT TIME ZONE 'UTC'
+    INTO lower_bound, upper_bound
+    FROM unnest(days) AS d;
+
+    DELETE FROM order_daily_stats WHERE day = ANY(days);
+    INSERT INTO order_daily_stats (tenant_id, day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity)
+    SELECT tenant_id, day, status, currency, country, SUM(order_count), SUM(revenue), SUM(revenue_base), SUM(unconverted), SUM(item_quantity)
+    FROM (
+        SELECT tenant_id, order_day AS day, status, currency, shipping_country AS country, COUNT(*) AS order_count,
+            COALESCE(SUM(total_amount), 0) AS revenue, COALESCE(SUM(total_amount_base), 0) AS revenue_base,
+            COUNT(*) FILTER (WHERE total_amount_base IS NULL) AS unconverted, SUM(total_quantity) AS item_quantity
+        FROM orders_with_stats
+        WHERE order_day = ANY(days) AND created_at >= lower_bound AND created_at < upper_bound
+        GROUP BY tenant_id, order_day, status, currency, shipping_country
+        UNION ALL
+        SELECT tenant_id, day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity
+        FROM order_daily_stats_archived
+        WHERE day = ANY(days)
+    ) s
+    GROUP BY tenant_id, day, status, currency, country;
+
+    RETURN array_length(days, 1);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Отсоединяет партиции заказов и позиций месяца; drop_tables - удаляет их вместе с дочерними
+-- строками заказов. Вклад заказов в срез отчетов сохраняется, как при архивации.
+-- Отсоединенные таблицы остаются под прежними именами и могут быть присоединены обратно.
+CREATE OR REPLACE FUNCTION detach_order_partition(month DATE, drop_tables BOOLEAN)
+RETURNS BOOLEAN AS $$
+DECLARE
+    month_start DATE := date_trunc('month', month::timestamp)::date;
+    lower_bound TIMESTAMPTZ := month_start::timestamp AT TIME ZONE 'UTC';
+    upper_bound TIMESTAMPTZ := (month_start + INTERVAL '1 month') AT TIME ZONE 'UTC';
+    orders_partition TEXT := 'orders_p' || to_char(month_start, 'YYYYMM');
+    items_partition TEXT := 'order_items_p' || to_char(month_start, 'YYYYMM');
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_inherits
+        WHERE inhparent = 'orders'::regclass AND inhrelid = to_regclass(orders_partition)
+    ) THEN
+        RETURN FALSE;
+    END IF;
+
+    PERFORM set_config('lock_timeout', '5s', TRUE);
+
+    INSERT INTO order_daily_stats_archived AS a (tenant_id, day, status, currency, country, order_count, revenue, revenue_base, unconverted, item_quantity)
+    SELECT tenant_id, order_day, status, currency, shipping_country, COUNT(*), COALESCE(SUM(total_amount), 0),
+        COALESCE(SUM(total_amount_base), 0), COUNT(*) FILTER (WHERE total_amount_base IS NULL), SUM(total_quantity)
+    FROM orders_with_stats
+    WHERE created_at >= lower_bound AND created_at < upper_bound
+    GROUP BY tenant_id, order_day, status, currency, shipping_country
+    ON CONFLICT (tenant_id, day, status, currency, country) DO UPDATE SET
+        order_count = a.order_count + EXCLUDED.order_count,
+        revenue = a.revenue + EXCLUDED.revenue,
+        revenue_base = a.revenue_base + EXCLUDED.revenue_base,
+        unconverted = a.unconverted + EXCLUDED.unconverted,
+        item_quantity = a.item_quantity + EXCLUDED.item_quantity;
+
+    EXECUTE format('ALTER TABLE orders DETACH PARTITION %I', orders_partition);
+    IF to_regclass(items_partition) IS NOT NULL THEN
+        EXECUTE format('ALTER TABLE order_items DETACH PARTITION %I', items_partition);
+    END IF;
+
+    IF drop_tables THEN
+        EXECUTE format('DROP TABLE %I', orders_partition);
+        EXECUTE format('DROP TABLE IF EXISTS %I', items_partition);
+        -- Адреса, отправления, возвраты и прочие строки заказов удаляются каскадом
+        DELETE FROM order_keys WHERE created_at >= lower_bound AND created_at < upper_bound;
+    END IF;
+
+    RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON TABLE orders IS 'Основная таблица заказов (партиции по месяцу created_at)';
+COMMENT ON TABLE order_items IS 'Элементы заказов (партиции по месяцу заказа)';
+COMMENT ON TABLE order_keys IS 'Реестр ID заказов: месяц партиции и арендатор';
+COMMENT ON COLUMN order_items.order_created_at IS 'created_at заказа - ключ партиции позиции';
+COMMENT ON VIEW orders_with_stats IS 'Заказы с аналитикой';
+COMMENT ON FUNCTION create_order_partition(DATE) IS 'Создает партиции заказов и позиций месяца';
+COMMENT ON FUNCTION detach_order_partition(DATE, BOOLEAN) IS 'Отсоединяет или удаляет партиции заказов и позиций месяца';
@@ -0,0 +1,27 @@
+-- migrations/022_customer_tenants.down.sql
+
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOREACH tbl IN ARRAY ARRAY['customers', 'customer_erasures']
+    LOOP
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', tbl);
+        EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', tbl);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', tbl);
+    END LOOP;
+END;
+$$;
+
+DROP INDEX IF EXISTS idx_customer_erasures_tenant_customer_id;
+CREATE INDEX IF NOT EXISTS idx_customer_erasures_customer_id ON customer_erasures(customer_id, erased_at);
+ALTER TABLE customer_erasures DROP COLUMN IF EXISTS tenant_id;
+
+-- Клиенты разных арендаторов с одним email: остается первый созданный
+DELETE FROM customers c
+USING customers d
+WHERE LOWER(c.email) = LOWER(d.email) AND (c.created_at, c.id) > (d.created_at, d.id);
+
+DROP INDEX IF EXISTS idx_customers_tenant_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email ON customers(LOWER(email));
+ALTER TABLE customers DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,47 @@
+-- migrations/022_customer_tenants.up.sql
+
+-- Клиенты принадлежат арендатору: арендатор существующего клиента - арендатор его первого заказа
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE customers ALTER COLUMN tenant_id SET DEFAULT COALESCE(current_tenant_id(), 'default');
+
+UPDATE customers c
+SET tenant_id = o.tenant_id
+FROM (
+    SELECT DISTINCT ON (customer_id) customer_id, tenant_id
+    FROM orders
+    ORDER BY customer_id, created_at
+) o
+WHERE o.customer_id = c.id AND c.tenant_id <> o.tenant_id;
+
+-- Email уникален в пределах арендатора
+DROP INDEX IF EXISTS idx_customers_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_tenant_email ON customers(tenant_id, LOWER(email));
+
+-- Журнал удалений ведется по арендатору клиента
+ALTER TABLE customer_erasures ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE customer_erasures ALTER COLUMN tenant_id SET DEFAULT COALESCE(current_tenant_id(), 'default');
+
+UPDATE customer_erasures e
+SET tenant_id = c.tenant_id
+FROM customers c
+WHERE c.id = e.customer_id AND e.tenant_id <> c.tenant_id;
+
+DROP INDEX IF EXISTS idx_customer_erasures_customer_id;
+CREATE INDEX IF NOT EXISTS idx_customer_erasures_tenant_customer_id ON customer_erasures(tenant_id, customer_id, erased_at);
+
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOREACH tbl IN ARRAY ARRAY['customers', 'customer_erasures']
+    LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', tbl);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', tbl);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())
+            WITH CHECK (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())', tbl);
+    END LOOP;
+END;
+$$;
+
+COMMENT ON COLUMN customers.tenant_id IS 'Арендатор (витрина) клиента';
@@ -0,0 +1,17 @@
+-- migrations/023_promotion_tenants.down.sql
+
+DROP POLICY IF EXISTS tenant_isolation ON promotions;
+ALTER TABLE promotions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE promotions DISABLE ROW LEVEL SECURITY;
+
+-- Одинаковые коды разных арендаторов получают суффикс с ID промоакции
+UPDATE promotions p
+SET code = LEFT(p.code, 27) || '-' || p.id::text
+WHERE EXISTS (
+    SELECT 1 FROM promotions d
+    WHERE d.code = p.code AND (d.created_at, d.id) < (p.created_at, p.id)
+);
+
+DROP INDEX IF EXISTS idx_promotions_tenant_code;
+ALTER TABLE promotions ADD CONSTRAINT promotions_code_key UNIQUE (code);
+ALTER TABLE promotions DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,17 @@
+-- migrations/023_promotion_tenants.up.sql
+
+-- Промоакции принадлежат арендатору: существующие купоны остаются у арендатора по умолчанию
+ALTER TABLE promotions ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE promotions ALTER COLUMN tenant_id SET DEFAULT COALESCE(current_tenant_id(), 'default');
+
+-- Код купона уникален в пределах арендатора
+ALTER TABLE promotions DROP CONSTRAINT IF EXISTS promotions_code_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promotions_tenant_code ON promotions(tenant_id, code);
+
+ALTER TABLE promotions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE promotions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON promotions
+    USING (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())
+    WITH CHECK (current_tenant_id() IS NULL OR tenant_id = current_tenant_id());
+
+COMMENT ON COLUMN promotions.tenant_id IS 'Арендатор (витрина) промоакции';
@@ -0,0 +1,35 @@
+-- migrations/024_strict_tenant_isolation.down.sql
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'order_service_app') THEN
+        REVOKE ALL ON ALL TABLES IN SCHEMA public FROM order_service_app;
+        REVOKE ALL ON ALL SEQUENCES IN SCHEMA public FROM order_service_app;
+        REVOKE ALL ON SCHEMA public FROM order_service_app;
+        DROP ROLE order_service_app;
+    END IF;
+END;
+$$;
+
+ALTER VIEW orders_with_stats SET (security_invoker = false);
+
+-- Без арендатора политики снова пропускают все строки
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOREACH tbl IN ARRAY ARRAY[
+        'orders', 'order_items', 'order_keys', 'order_addresses', 'order_risk_assessments',
+        'shipments', 'shipment_items', 'return_requests', 'return_items', 'refunds',
+        'promotion_redemptions', 'order_discounts', 'order_item_taxes',
+        'stock_reservations', 'order_notifications',
+        'customers', 'customer_erasures', 'promotions'
+    ]
+    LOOP
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', tbl);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())
+            WITH CHECK (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())', tbl);
+    END LOOP;
+END;
+$$;
@@ -0,0 +1,61 @@
+-- migrations/024_strict_tenant_isolation.up.sql
+
+-- Без арендатора строки арендаторских таблиц недоступны. Фоновые задачи
+-- (consumer, архивация, PII) работают ролью владельца схемы с BYPASSRLS
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOREACH tbl IN ARRAY ARRAY[
+        'orders', 'order_items', 'order_keys', 'order_addresses', 'order_risk_assessments',
+        'shipments', 'shipment_items', 'return_requests', 'return_items', 'refunds',
+        'promotion_redemptions', 'order_discounts', 'order_item_taxes',
+        'stock_reservations', 'order_notifications',
+        'customers', 'customer_erasures', 'promotions'
+    ]
+    LOOP
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', tbl);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (tenant_id = current_tenant_id())
+            WITH CHECK (tenant_id = current_tenant_id())', tbl);
+    END LOOP;
+END;
+$$;
+
+-- Представление проверяет политики от имени вызывающей роли, а не владельца
+ALTER VIEW orders_with_stats SET (security_invoker = true);
+
+-- Групповая роль запросов арендаторов: без BYPASSRLS, только чтение и изменение данных.
+-- Логин-роль создается при развертывании: CREATE ROLE ... LOGIN IN ROLE order_service_app
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'order_service_app') THEN
+        CREATE ROLE order_service_app NOLOGIN NOBYPASSRLS;
+    END IF;
+END;
+$$;
+
+GRANT USAGE ON SCHEMA public TO order_service_app;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO order_service_app;
+
+-- Партиции не выдаются: к ним обращаются только через родительские таблицы с политиками.
+-- Новые таблицы выдаются роли в своих миграциях
+DO $$
+DECLARE
+    rel TEXT;
+BEGIN
+    FOR rel IN
+        SELECT c.relname
+        FROM pg_class c
+        JOIN pg_namespace n ON n.oid = c.relnamespace
+        WHERE n.nspname = 'public'
+          AND c.relkind IN ('r', 'p', 'v')
+          AND NOT c.relispartition
+          AND c.relname <> 'schema_migrations'
+    LOOP
+        EXECUTE format('GRANT SELECT, INSERT, UPDATE, DELETE ON %I TO order_service_app', rel);
+    END LOOP;
+END;
+$$;
+
+COMMENT ON ROLE order_service_app IS 'Запросы арендаторов: row-level security обязательна';
@@ -0,0 +1,14 @@
+-- migrations/025_customer_pii_encryption.down.sql
+
+-- Перед откатом данные нужно расшифровать: pii decrypt.
+-- Иначе шифротекст не поместится в исходные колонки
+COMMENT ON COLUMN order_notifications.recipient IS NULL;
+ALTER TABLE order_notifications ALTER COLUMN recipient TYPE VARCHAR(255);
+
+DROP INDEX IF EXISTS idx_customers_tenant_email_index;
+DROP INDEX IF EXISTS idx_customers_tenant_email;
+ALTER TABLE customers DROP COLUMN IF EXISTS email_index;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_tenant_email ON customers(tenant_id, LOWER(email));
+
+COMMENT ON COLUMN customers.email IS NULL;
+ALTER TABLE customers ALTER COLUMN email TYPE VARCHAR(255);
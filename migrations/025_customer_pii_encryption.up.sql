@@ -0,0 +1,19 @@
+-- migrations/025_customer_pii_encryption.up.sql
+
+-- Email и адреса клиентов, получатели уведомлений хранятся зашифрованными, как данные заказов.
+-- Уже записанные значения шифрует pii rewrap
+ALTER TABLE customers ALTER COLUMN email TYPE TEXT;
+
+-- Blind index: HMAC нормализованного email для поиска и уникальности.
+-- NULL у клиентов, записанных без шифрования: для них email уникален как раньше
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS email_index VARCHAR(64);
+
+DROP INDEX IF EXISTS idx_customers_tenant_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_tenant_email ON customers(tenant_id, LOWER(email)) WHERE email_index IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_tenant_email_index ON customers(tenant_id, email_index) WHERE email_index IS NOT NULL;
+
+ALTER TABLE order_notifications ALTER COLUMN recipient TYPE TEXT;
+
+COMMENT ON COLUMN customers.email IS 'Email (шифротекст pii:v1 или открытый текст до перешифрования)';
+COMMENT ON COLUMN customers.email_index IS 'HMAC нормализованного email для поиска';
+COMMENT ON COLUMN order_notifications.recipient IS 'Email получателя (шифротекст pii:v1 или открытый текст до перешифрования)';
@@ -2,14 +2,29 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Kafka    KafkaConfig
-	Server   ServerConfig
+	Database      DatabaseConfig
+	Kafka         KafkaConfig
+	Server        ServerConfig
+	Expiry        ExpiryConfig
+	Orders        OrdersConfig
+	Fraud         FraudConfig
+	Currency      CurrencyConfig
+	Catalog       CatalogConfig
+	Webhooks      WebhookConfig
+	Notifications NotificationConfig
+	Stream        StreamConfig
+	GRPC          GRPCConfig
+	Reports       ReportsConfig
+	Archive       ArchiveConfig
+	Partitions    PartitionsConfig
+	PII           PIIConfig
+	Tenants       TenantConfig
 }
 
 type DatabaseConfig struct {
@@ -19,6 +34,18 @@ type DatabaseConfig struct {
 	User     string `envconfig:"DB_USER" default:"postgres"`
 	Password string `envconfig:"DB_PASSWORD" default:"postgres"`
 	SSLMode  string `envconfig:"DB_SSL_MODE" default:"disable"`
+	// AppUser роль запросов арендаторов в producer, без BYPASSRLS; пусто - User.
+	// User - владелец схемы с BYPASSRLS: им выполняются миграции и фоновые задачи
+	AppUser     string `envconfig:"DB_APP_USER"`
+	AppPassword string `envconfig:"DB_APP_PASSWORD"`
+	// ReplicaDSNs DSN реплик для чтения заказов (postgres://...), через запятую; пусто - все в основную БД
+	ReplicaDSNs []string `envconfig:"DB_REPLICA_DSNS"`
+	// ReplicaCheckInterval период проверки доступности и отставания реплик
+	ReplicaCheckInterval time.Duration `envconfig:"DB_REPLICA_CHECK_INTERVAL" default:"5s"`
+	// ReplicaMaxLag реплика с большим отставанием не используется; 0 - не проверять
+	ReplicaMaxLag time.Duration `envconfig:"DB_REPLICA_MAX_LAG" default:"5s"`
+	// ReadYourWritesWindow сколько после записи заказа чтения этого заказа и клиента идут в основную БД
+	ReadYourWritesWindow time.Duration `envconfig:"DB_READ_YOUR_WRITES_WINDOW" default:"10s"`
 }
 
 type KafkaConfig struct {
@@ -31,6 +58,154 @@ type ServerConfig struct {
 	Port string `envconfig:"HTTP_PORT" default:"8080"`
 }
 
+// ExpiryConfig настройки автоматической отмены зависших pending заказов.
+// TTL по каналу продаж имеет приоритет над TTL по валюте.
+type ExpiryConfig struct {
+	Enabled     bool                     `envconfig:"ORDER_EXPIRY_ENABLED" default:"true"`
+	Interval    time.Duration            `envconfig:"ORDER_EXPIRY_INTERVAL" default:"1m"`
+	DefaultTTL  time.Duration            `envconfig:"ORDER_EXPIRY_TTL" default:"24h"`
+	CurrencyTTL map[string]time.Duration `envconfig:"ORDER_EXPIRY_CURRENCY_TTL"` // USD:12h,EUR:48h (регистр не важен)
+	ChannelTTL  map[string]time.Duration `envconfig:"ORDER_EXPIRY_CHANNEL_TTL"`  // web:30m,marketplace:72h
+	BatchSize   int                      `envconfig:"ORDER_EXPIRY_BATCH_SIZE" default:"100"`
+	LockKey     int64                    `envconfig:"ORDER_EXPIRY_LOCK_KEY" default:"727001"`
+}
+
+// ReportsConfig настройки пересчета дневного среза отчетов по заказам.
+// Дни, еще не пересчитанные, отчет агрегирует по заказам напрямую.
+type ReportsConfig struct {
+	RollupEnabled  bool          `envconfig:"REPORTS_ROLLUP_ENABLED" default:"true"`
+	RollupInterval time.Duration `envconfig:"REPORTS_ROLLUP_INTERVAL" default:"5m"`
+	LockKey        int64         `envconfig:"REPORTS_ROLLUP_LOCK_KEY" default:"727002"`
+}
+
+// ArchiveConfig настройки архивации старых заказов в gzip NDJSON файлы по месяцам.
+// Если задан ARCHIVE_S3_ENDPOINT, файлы пишутся в S3-совместимое хранилище, иначе в ARCHIVE_DIR.
+type ArchiveConfig struct {
+	Enabled     bool          `envconfig:"ARCHIVE_ENABLED" default:"false"`
+	Interval    time.Duration `envconfig:"ARCHIVE_INTERVAL" default:"1h"`
+	After       time.Duration `envconfig:"ARCHIVE_AFTER" default:"8760h"` // заказ не менялся дольше
+	Statuses    []string      `envconfig:"ARCHIVE_STATUSES" default:"delivered,cancelled,refunded"`
+	BatchSize   int           `envconfig:"ARCHIVE_BATCH_SIZE" default:"1000"`
+	MaxBatches  int           `envconfig:"ARCHIVE_MAX_BATCHES" default:"10"` // за один прогон
+	LockKey     int64         `envconfig:"ARCHIVE_LOCK_KEY" default:"727003"`
+	Dir         string        `envconfig:"ARCHIVE_DIR" default:"./archive"`
+	S3Endpoint  string        `envconfig:"ARCHIVE_S3_ENDPOINT"`
+	S3Bucket    string        `envconfig:"ARCHIVE_S3_BUCKET"`
+	S3Region    string        `envconfig:"ARCHIVE_S3_REGION" default:"us-east-1"`
+	S3Prefix    string        `envconfig:"ARCHIVE_S3_PREFIX" default:"orders"`
+	S3AccessKey string        `envconfig:"ARCHIVE_S3_ACCESS_KEY"`
+	S3SecretKey string        `envconfig:"ARCHIVE_S3_SECRET_KEY"`
+}
+
+// PartitionsConfig настройки обслуживания месячных партиций заказов и позиций.
+// Истекшие партиции отсоединяются (остаются таблицами) или удаляются при ORDER_PARTITIONS_DROP_EXPIRED.
+type PartitionsConfig struct {
+	Enabled         bool          `envconfig:"ORDER_PARTITIONS_ENABLED" default:"true"`
+	Interval        time.Duration `envconfig:"ORDER_PARTITIONS_INTERVAL" default:"1h"`
+	MonthsAhead     int           `envconfig:"ORDER_PARTITIONS_MONTHS_AHEAD" default:"3"`
+	RetentionMonths int           `envconfig:"ORDER_PARTITIONS_RETENTION_MONTHS" default:"0"` // 0 - хранить все
+	DropExpired     bool          `envconfig:"ORDER_PARTITIONS_DROP_EXPIRED" default:"false"`
+	LockKey         int64         `envconfig:"ORDER_PARTITIONS_LOCK_KEY" default:"727004"`
+}
+
+// PIIConfig настройки шифрования персональных данных (email и адреса заказов).
+// Без файла ключей данные хранятся и публикуются открытым текстом.
+type PIIConfig struct {
+	KeyringFile string `envconfig:"PII_KEYRING_FILE"` // YAML/JSON, см. configs/pii-keyring.example.yaml
+}
+
+// TenantConfig настройки арендаторов (витрин). Арендатор запроса определяется по API ключу
+// или по claim JWT. Без файла и секрета все заказы относятся к арендатору "default".
+type TenantConfig struct {
+	File      string `envconfig:"TENANTS_FILE"`      // YAML/JSON, см. configs/tenants.example.yaml
+	JWTSecret string `envconfig:"TENANT_JWT_SECRET"` // HS256, пусто - JWT не принимаются
+	JWTClaim  string `envconfig:"TENANT_JWT_CLAIM" default:"tenant_id"`
+	// Claim с ID клиента в токенах, выданных клиентам; пусто - токены клиентов не принимаются
+	JWTCustomerClaim string `envconfig:"TENANT_JWT_CUSTOMER_CLAIM" default:"customer_id"`
+}
+
+// OrdersConfig настройки жизненного цикла заказов
+type OrdersConfig struct {
+	StateMachineFile  string `envconfig:"ORDER_STATE_MACHINE_FILE"`  // YAML/JSON, пусто - стандартный цикл
+	TaxRatesFile      string `envconfig:"ORDER_TAX_RATES_FILE"`      // YAML/JSON, пусто - без налогов
+	ShippingRatesFile string `envconfig:"ORDER_SHIPPING_RATES_FILE"` // YAML/JSON, пусто - без доставки
+	// Отклонять заказы на товары, которых не хватает на складах
+	RejectOutOfStock bool `envconfig:"ORDER_REJECT_OUT_OF_STOCK" default:"true"`
+	// Брать название и цену позиций из каталога, отклоняя неизвестные товары
+	RequireCatalog bool `envconfig:"ORDER_REQUIRE_CATALOG" default:"false"`
+	// Создавать клиента по email заказа, если его нет
+	AutoCreateCustomers bool `envconfig:"ORDER_AUTO_CREATE_CUSTOMERS" default:"false"`
+	// Размер пачки, которую выгрузка читает из курсора БД за раз
+	ExportBatchSize int `envconfig:"ORDER_EXPORT_BATCH_SIZE" default:"1000"`
+}
+
+// CatalogConfig файл каталога товаров, импортируемый при старте
+type CatalogConfig struct {
+	ProductsFile string `envconfig:"PRODUCT_CATALOG_FILE"` // CSV, пусто - каталог только из БД
+}
+
+// WebhookConfig настройки доставки webhooks подписчикам
+type WebhookConfig struct {
+	Enabled        bool          `envconfig:"WEBHOOKS_ENABLED" default:"true"`
+	GroupID        string        `envconfig:"WEBHOOK_GROUP_ID" default:"order-service-webhooks"`
+	MaxAttempts    int           `envconfig:"WEBHOOK_MAX_ATTEMPTS" default:"5"`
+	InitialBackoff time.Duration `envconfig:"WEBHOOK_INITIAL_BACKOFF" default:"1s"`
+	MaxBackoff     time.Duration `envconfig:"WEBHOOK_MAX_BACKOFF" default:"1m"`
+	DisableAfter   int           `envconfig:"WEBHOOK_DISABLE_AFTER" default:"10"` // 0 - не отключать
+	Timeout        time.Duration `envconfig:"WEBHOOK_TIMEOUT" default:"10s"`
+}
+
+// NotificationConfig настройки email уведомлений клиентам
+type NotificationConfig struct {
+	Transport    string `envconfig:"NOTIFICATION_TRANSPORT" default:"noop"` // noop, file, smtp
+	From         string `envconfig:"NOTIFICATION_FROM" default:"orders@example.com"`
+	TemplatesDir string `envconfig:"NOTIFICATION_TEMPLATES_DIR"` // пусто - встроенные шаблоны
+	MailDir      string `envconfig:"NOTIFICATION_MAIL_DIR" default:"tmp/mail"`
+	SMTPHost     string `envconfig:"SMTP_HOST" default:"localhost"`
+	SMTPPort     int    `envconfig:"SMTP_PORT" default:"587"`
+	SMTPUsername string `envconfig:"SMTP_USERNAME"`
+	SMTPPassword string `envconfig:"SMTP_PASSWORD"`
+}
+
+// StreamConfig настройки SSE потоков событий заказов в producer
+type StreamConfig struct {
+	Enabled     bool          `envconfig:"STREAM_ENABLED" default:"true"`
+	GroupID     string        `envconfig:"STREAM_GROUP_ID" default:"order-service-stream"` // к нему добавляется hostname
+	HistorySize int           `envconfig:"STREAM_HISTORY_SIZE" default:"1000"`             // событий для Last-Event-ID
+	BufferSize  int           `envconfig:"STREAM_BUFFER_SIZE" default:"64"`                // событий на подписчика
+	Heartbeat   time.Duration `envconfig:"STREAM_HEARTBEAT" default:"15s"`
+}
+
+// GRPCConfig настройки gRPC API в producer
+type GRPCConfig struct {
+	Enabled    bool     `envconfig:"GRPC_ENABLED" default:"true"`
+	Port       string   `envconfig:"GRPC_PORT" default:"9090"`
+	AuthTokens []string `envconfig:"GRPC_AUTH_TOKENS"` // пусто - без аутентификации
+}
+
+// CurrencyConfig отчетная валюта и файл курсов, импортируемый при старте
+type CurrencyConfig struct {
+	ReportingCurrency string `envconfig:"REPORTING_CURRENCY" default:"USD"`
+	ExchangeRatesFile string `envconfig:"EXCHANGE_RATES_FILE"` // CSV/JSON, пусто - курсы только из БД
+}
+
+// FraudConfig пороги правил антифрода. Баллы сработавших правил суммируются,
+// заказ с суммой не ниже HoldScore переводится в on_hold.
+type FraudConfig struct {
+	Enabled              bool          `envconfig:"FRAUD_ENABLED" default:"true"`
+	HoldScore            int           `envconfig:"FRAUD_HOLD_SCORE" default:"50"`
+	MaxAmount            float64       `envconfig:"FRAUD_MAX_AMOUNT" default:"5000"` // в отчетной валюте
+	AmountScore          int           `envconfig:"FRAUD_AMOUNT_SCORE" default:"30"`
+	MaxQuantity          int           `envconfig:"FRAUD_MAX_QUANTITY" default:"50"`
+	QuantityScore        int           `envconfig:"FRAUD_QUANTITY_SCORE" default:"20"`
+	EmailDomains         []string      `envconfig:"FRAUD_EMAIL_DOMAINS"`
+	EmailDomainScore     int           `envconfig:"FRAUD_EMAIL_DOMAIN_SCORE" default:"50"`
+	CountryMismatchScore int           `envconfig:"FRAUD_COUNTRY_MISMATCH_SCORE" default:"25"`
+	VelocityWindow       time.Duration `envconfig:"FRAUD_VELOCITY_WINDOW" default:"1h"`
+	VelocityMaxOrders    int           `envconfig:"FRAUD_VELOCITY_MAX_ORDERS" default:"5"`
+	VelocityScore        int           `envconfig:"FRAUD_VELOCITY_SCORE" default:"40"`
+}
+
 func Load() (*Config, error) {
 	var cfg Config
 	err := envconfig.Process("", &cfg)
@@ -38,6 +213,18 @@ func Load() (*Config, error) {
 }
 
 func (d DatabaseConfig) DSN() string {
+	return d.dsn(d.User, d.Password)
+}
+
+// AppDSN строка подключения роли запросов арендаторов; без AppUser совпадает с DSN
+func (d DatabaseConfig) AppDSN() string {
+	if d.AppUser == "" {
+		return d.DSN()
+	}
+	return d.dsn(d.AppUser, d.AppPassword)
+}
+
+func (d DatabaseConfig) dsn(user, password string) string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
+		d.Host, d.Port, user, password, d.Name, d.SSLMode)
 }